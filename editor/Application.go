@@ -12,6 +12,7 @@ import (
 	"github.com/inkyblackness/hacked/editor/about"
 	"github.com/inkyblackness/hacked/editor/animations"
 	"github.com/inkyblackness/hacked/editor/archives"
+	"github.com/inkyblackness/hacked/editor/balancing"
 	"github.com/inkyblackness/hacked/editor/bitmaps"
 	"github.com/inkyblackness/hacked/editor/external"
 	"github.com/inkyblackness/hacked/editor/graphics"
@@ -20,14 +21,18 @@ import (
 	"github.com/inkyblackness/hacked/editor/movies"
 	"github.com/inkyblackness/hacked/editor/objects"
 	"github.com/inkyblackness/hacked/editor/project"
+	"github.com/inkyblackness/hacked/editor/rawdata"
 	"github.com/inkyblackness/hacked/editor/render"
+	"github.com/inkyblackness/hacked/editor/resources"
 	"github.com/inkyblackness/hacked/editor/sounds"
 	"github.com/inkyblackness/hacked/editor/texts"
 	"github.com/inkyblackness/hacked/editor/textures"
+	"github.com/inkyblackness/hacked/editor/themes"
 	"github.com/inkyblackness/hacked/ss1"
 	"github.com/inkyblackness/hacked/ss1/content/archive/level"
 	"github.com/inkyblackness/hacked/ss1/content/bitmap"
 	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/music"
 	"github.com/inkyblackness/hacked/ss1/content/sound"
 	"github.com/inkyblackness/hacked/ss1/content/text"
 	"github.com/inkyblackness/hacked/ss1/edit"
@@ -50,6 +55,7 @@ const (
 type projectState struct {
 	ProjectSettings   *edit.ProjectSettings   `json:",omitempty"`
 	GameStateSettings *edit.GameStateSettings `json:",omitempty"`
+	TestStartSettings *edit.TestStartSettings `json:",omitempty"`
 	OpenWindows       []string                `json:",omitempty"`
 	ActiveLevelIndex  *int                    `json:",omitempty"`
 }
@@ -141,6 +147,7 @@ type Application struct {
 	animationCache   *bitmap.AnimationCache
 	movieCache       *movie.Cache
 	soundEffectCache *sound.EffectCache
+	musicThemeCache  *music.ThemeCache
 
 	mapDisplay *levels.MapDisplay
 
@@ -150,7 +157,9 @@ type Application struct {
 
 	projectService     *edit.ProjectService
 	gameStateService   *edit.GameStateService
+	testStartService   *edit.TestStartService
 	gameObjectsService *edit.GameObjectsService
+	rawDataService     *edit.RawDataService
 
 	projectView      *project.View
 	archiveView      *archives.View
@@ -164,7 +173,11 @@ type Application struct {
 	animationsView   *animations.View
 	moviesView       *movies.View
 	soundEffectsView *sounds.View
+	musicView        *themes.View
 	objectsView      *objects.View
+	balancingView    *balancing.View
+	rawDataView      *rawdata.View
+	resourcesView    *resources.View
 	aboutView        *about.View
 	licensesView     *about.LicensesView
 
@@ -256,7 +269,11 @@ func (app *Application) render() {
 	app.animationsView.Render()
 	app.moviesView.Render()
 	app.soundEffectsView.Render()
+	app.musicView.Render()
 	app.objectsView.Render()
+	app.balancingView.Render()
+	app.rawDataView.Render()
+	app.resourcesView.Render()
 	app.aboutView.Render()
 	app.licensesView.Render()
 
@@ -307,11 +324,15 @@ func (app *Application) initGui() (err error) {
 
 	app.mapDisplay = levels.NewMapDisplay(app.gameObjectsService, app.levelSelection, app.levelEditorService,
 		app.gl, app.GuiScale,
-		app.gameTexture)
+		app.gameTexture, app.musicZoneLabel)
 
 	return
 }
 
+func (app *Application) musicZoneLabel(musicIndex int) string {
+	return app.musicView.ZoneLabel(musicIndex)
+}
+
 func (app *Application) gameTexture(index level.TextureIndex) (*graphics.BitmapTexture, error) {
 	key := resource.KeyOf(ids.LargeTextures.Plus(int(index)), resource.LangAny, 0)
 	return app.textureCache.Texture(key)
@@ -580,6 +601,7 @@ func (app *Application) initModel() {
 	app.messagesCache = text.NewElectronicMessageCache(app.cp, app.mod)
 	app.movieCache = movie.NewCache(app.cp, app.mod)
 	app.soundEffectCache = sound.NewEffectCache(app.mod)
+	app.musicThemeCache = music.NewThemeCache(app.mod)
 
 	app.levels = edit.NewEditableLevels(&app.txnBuilder, app.mod)
 	app.levelSelection = edit.NewLevelSelectionService(app.levels)
@@ -599,6 +621,7 @@ func (app *Application) resourcesChanged(modifiedIDs []resource.ID, failedIDs []
 	app.messagesCache.InvalidateResources(modifiedIDs)
 	app.movieCache.InvalidateResources(modifiedIDs)
 	app.soundEffectCache.InvalidateResources(modifiedIDs)
+	app.musicThemeCache.InvalidateResources(modifiedIDs)
 	app.levels.InvalidateResources(modifiedIDs)
 	app.paletteCache.InvalidateResources(modifiedIDs)
 	app.textureCache.InvalidateResources(modifiedIDs)
@@ -625,20 +648,26 @@ func (app *Application) initView() {
 
 	app.projectService = edit.NewProjectService(&app.txnBuilder, app.mod)
 	app.gameStateService = edit.NewGameStateService(&app.txnBuilder)
+	app.testStartService = edit.NewTestStartService(app.mod, app.levels, app.cp)
+	app.rawDataService = edit.NewRawDataService(&app.txnBuilder, app.mod, app.levels)
 
 	app.projectView = project.NewView(app.projectService, &app.modalState, app.GuiScale, &app.txnBuilder)
-	app.archiveView = archives.NewArchiveView(&app.txnBuilder, app.gameStateService, app.mod, app.textLineCache, app.cp, &app.modalState, app.GuiScale, app)
+	app.archiveView = archives.NewArchiveView(&app.txnBuilder, app.gameStateService, app.testStartService, app.levels, app.levelSelection, app.mod, app.textLineCache, app.cp, &app.modalState, app.GuiScale, app)
 	app.levelControlView = levels.NewControlView(app.levels, app.levelSelection, app.levelEditorService, app.GuiScale, app.textLineCache, app.textureCache, &app.txnBuilder)
-	app.levelTilesView = levels.NewTilesView(app.levelEditorService, app.GuiScale, app.textLineCache, app.textureCache, &app.txnBuilder)
+	app.levelTilesView = levels.NewTilesView(app.levelEditorService, app.GuiScale, app.textLineCache, app.textureCache, &app.txnBuilder, app.musicZoneLabel)
 	app.levelObjectsView = levels.NewObjectsView(app.gameObjectsService, app.levelEditorService, app.levelSelection, app.gameStateService, app.GuiScale, app.textLineCache, app.textureCache, &app.txnBuilder, app.gl)
 	app.messagesView = messages.NewMessagesView(app.mod, app.messagesCache, app.cp, app.movieCache, app.textureCache, &app.modalState, app.clipboard, app.GuiScale, app)
 	app.textsView = texts.NewTextsView(augmentedTextService, &app.modalState, app.clipboard, app.GuiScale)
-	app.bitmapsView = bitmaps.NewBitmapsView(app.mod, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app)
-	app.texturesView = textures.NewTexturesView(app.mod, app.textLineCache, app.cp, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app)
+	app.bitmapsView = bitmaps.NewBitmapsView(app.mod, app.textureCache, app.paletteCache, app.frameCache, &app.modalState, app.clipboard, app.GuiScale, app)
+	app.texturesView = textures.NewTexturesView(app.mod, app.textLineCache, app.cp, app.textureCache, app.paletteCache, app.frameCache, &app.modalState, app.clipboard, app.GuiScale, app)
 	app.animationsView = animations.NewAnimationsView(app.mod, app.textureCache, app.paletteCache, app.animationCache, &app.modalState, app.GuiScale, app)
-	app.moviesView = movies.NewMoviesView(app.mod, app.frameCache, movieService, &app.modalState, app.GuiScale, app)
+	app.moviesView = movies.NewMoviesView(app.mod, app.frameCache, movieService, app.cp, &app.modalState, app.GuiScale, app)
 	app.soundEffectsView = sounds.NewSoundEffectsView(soundEffectService, &app.modalState, app.GuiScale)
-	app.objectsView = objects.NewView(app.mod, app.textLineCache, app.cp, app.textureCache, app.paletteCache, &app.modalState, app.clipboard, app.GuiScale, app)
+	app.musicView = themes.NewMusicView(app.mod, app.musicThemeCache, &app.modalState, app.GuiScale, app)
+	app.objectsView = objects.NewView(app.mod, app.textLineCache, app.cp, app.textureCache, app.paletteCache, app.frameCache, &app.modalState, app.clipboard, app.GuiScale, app)
+	app.balancingView = balancing.NewBalancingView(app.mod, app.textLineCache, app.GuiScale)
+	app.rawDataView = rawdata.NewView(app.rawDataService, app.levelSelection, app.GuiScale)
+	app.resourcesView = resources.NewView(app.mod, app.rawDataService, &app.modalState, app.GuiScale)
 	app.aboutView = about.NewView(app.clipboard, app.GuiScale, app.Version)
 	app.licensesView = about.NewLicensesView(app.GuiScale)
 }
@@ -708,7 +737,11 @@ func (app *Application) renderMainMenu() {
 			windowEntry("Animations", "", app.animationsView.WindowOpen())
 			windowEntry("Movies", "", app.moviesView.WindowOpen())
 			windowEntry("Sound Effects", "", app.soundEffectsView.WindowOpen())
+			windowEntry("Music", "", app.musicView.WindowOpen())
 			windowEntry("Game Objects", "", app.objectsView.WindowOpen())
+			windowEntry("Balancing", "", app.balancingView.WindowOpen())
+			windowEntry("Raw Data Inspector", "", app.rawDataView.WindowOpen())
+			windowEntry("Resource Explorer", "", app.resourcesView.WindowOpen())
 			imgui.EndMenu()
 		}
 		if imgui.BeginMenu("Help") {
@@ -821,6 +854,7 @@ func (app *Application) saveProject() {
 func (app *Application) currentProjectState() projectState {
 	projectSettings := app.projectService.CurrentSettings()
 	gameStateSettings := app.gameStateService.CurrentSettings()
+	testStartSettings := app.testStartService.CurrentSettings()
 
 	windowOpenByName := app.windowOpenByName()
 	var openWindows []string
@@ -833,6 +867,7 @@ func (app *Application) currentProjectState() projectState {
 	return projectState{
 		ProjectSettings:   &projectSettings,
 		GameStateSettings: &gameStateSettings,
+		TestStartSettings: &testStartSettings,
 		OpenWindows:       openWindows,
 		ActiveLevelIndex:  &activeLevelIndex,
 	}
@@ -880,11 +915,17 @@ func (app *Application) restoreProjectState(state projectState, filename string)
 		projectSettings = *state.ProjectSettings
 	}
 	app.projectService.RestoreProject(projectSettings, filename)
+	app.projectView.ShowDamagedFiles()
 	var gameStateSettings edit.GameStateSettings
 	if state.GameStateSettings != nil {
 		gameStateSettings = *state.GameStateSettings
 	}
 	app.gameStateService.RestoreSettings(gameStateSettings)
+	var testStartSettings edit.TestStartSettings
+	if state.TestStartSettings != nil {
+		testStartSettings = *state.TestStartSettings
+	}
+	app.testStartService.RestoreSettings(testStartSettings)
 
 	windowOpenByName := app.windowOpenByName()
 	for _, open := range windowOpenByName {
@@ -940,6 +981,10 @@ func (app *Application) windowOpenByName() map[string]*bool {
 		"animations":   app.animationsView.WindowOpen(),
 		"movies":       app.moviesView.WindowOpen(),
 		"soundEffects": app.soundEffectsView.WindowOpen(),
+		"music":        app.musicView.WindowOpen(),
 		"gameObjects":  app.objectsView.WindowOpen(),
+		"balancing":    app.balancingView.WindowOpen(),
+		"rawData":      app.rawDataView.WindowOpen(),
+		"resources":    app.resourcesView.WindowOpen(),
 	}
 }
@@ -2,10 +2,11 @@ package animations
 
 import (
 	"bytes"
-	"encoding/binary"
 	"fmt"
 	"image"
 	"image/gif"
+	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
 
@@ -15,9 +16,9 @@ import (
 	"github.com/inkyblackness/hacked/editor/graphics"
 	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/bitmap/apng"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/resource"
-	"github.com/inkyblackness/hacked/ss1/serial/rle"
 	"github.com/inkyblackness/hacked/ss1/world"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
 	"github.com/inkyblackness/hacked/ui/gui"
@@ -126,11 +127,13 @@ func (view *View) renderContent() {
 			if view.cacheFrame(frameKey) {
 				render.TextureImage("Frame", view.imageCache, frameKey,
 					imgui.Vec2{X: float32(anim.Width) * view.guiScale, Y: float32(anim.Height) * view.guiScale})
-				if imgui.Button("Export") {
-					view.requestExport()
+				for _, format := range exportFormats {
+					if imgui.Button("Export " + format.title) {
+						view.requestExport(format)
+					}
+					imgui.SameLine()
 				}
 			}
-			imgui.SameLine()
 			if imgui.Button("Import") {
 				view.requestImport()
 			}
@@ -181,20 +184,19 @@ func (view *View) currentAnimation() (bitmap.Animation, bool, bool) {
 }
 
 func (view *View) requestImport() {
-	info := "File must be an animated GIF file.\nIdeally, it matches the game palette 1:1,\nothers are mapped closest fitting."
-	types := []external.TypeInfo{{Title: "Animation files (*.gif)", Extensions: []string{"gif"}}}
+	info := "File must be an animated GIF or PNG (APNG) file.\nIdeally, it matches the game palette 1:1,\nothers are mapped closest fitting."
+	types := []external.TypeInfo{{Title: "Animation files (*.gif, *.png)", Extensions: []string{"gif", "png"}}}
 	var fileHandler func(string)
 
 	fileHandler = func(filename string) {
-		reader, err := os.Open(filename)
+		data, err := ioutil.ReadFile(filename)
 		if err != nil {
 			external.Import(view.modalStateMachine, "Could not open file.\n"+info, types, fileHandler, true)
 			return
 		}
-		defer func() { _ = reader.Close() }()
-		data, err := gif.DecodeAll(reader)
+		frames, frameTimes, err := decodeAnimationFile(data)
 		if err != nil {
-			external.Import(view.modalStateMachine, "File not recognized as GIF.\n"+info, types, fileHandler, true)
+			external.Import(view.modalStateMachine, "File not recognized as animation.\n"+info, types, fileHandler, true)
 			return
 		}
 
@@ -203,75 +205,87 @@ func (view *View) requestImport() {
 			external.Import(view.modalStateMachine, "Can not import image without having a palette loaded.\n"+info, types, fileHandler, true)
 			return
 		}
+		rawPalette := palette.Palette()
+		bounds := frames[0].Bounds()
 		anim := bitmap.Animation{
-			Width:      int16(data.Config.Width),
-			Height:     int16(data.Config.Height),
+			Width:      int16(bounds.Dx()),
+			Height:     int16(bounds.Dy()),
 			ResourceID: view.model.currentKey.ID.Plus(view.model.currentKey.Index).Plus(-12),
 			IntroFlag:  0,
-		}
-		var frames [][]byte
-		rawPalette := palette.Palette()
-
-		highestBitShift := func(value int16) (result byte) {
-			if value != 0 {
-				for (value >> result) != 1 {
-					result++
-				}
-			}
-			return
+			Entries:    bitmap.AnimationEntriesFromFrameTimes(frameTimes),
 		}
 
-		var prevFrame []byte
-		for index, img := range data.Image {
-			if (img.Bounds().Max.X == data.Config.Width) && (img.Bounds().Max.Y == data.Config.Height) {
-				bitmapper := bitmap.NewBitmapper(&rawPalette)
-				bmp := bitmapper.Map(img)
-				entry := bitmap.AnimationEntry{
-					FirstFrame: byte(index),
-					LastFrame:  byte(index),
-					FrameTime:  int16(data.Delay[index] * 10),
-				}
-				anim.Entries = append(anim.Entries, entry)
-				bmp.Header.Type = bitmap.TypeCompressed8Bit
-				bmp.Header.WidthFactor = highestBitShift(bmp.Header.Width)
-				bmp.Header.HeightFactor = highestBitShift(bmp.Header.Height)
-				bmp.Header.Area = [4]int16{0, 0, anim.Width, anim.Height}
-				bmp.Header.Stride = uint16(bmp.Header.Width)
-
-				buf := bytes.NewBuffer(nil)
-				_ = binary.Write(buf, binary.LittleEndian, &bmp.Header)
-				_ = rle.Compress(buf, bmp.Pixels, prevFrame)
-				prevFrame = bmp.Pixels
-				frames = append(frames, buf.Bytes())
+		bitmapper := bitmap.NewBitmapper(&rawPalette)
+		colorPalette := rawPalette.ColorPalette(false)
+		bitmaps := make([]bitmap.Bitmap, 0, len(frames))
+		for _, img := range frames {
+			var bmp bitmap.Bitmap
+			if paletted, isPaletted := img.(*image.Paletted); isPaletted && external.PaletteMatches(paletted.Palette, colorPalette) {
+				bmp.Header.Width = anim.Width
+				bmp.Header.Height = anim.Height
+				bmp.Pixels = paletted.Pix
+			} else {
+				bmp = bitmapper.Map(img)
 			}
+			bitmaps = append(bitmaps, bmp)
 		}
 
-		view.requestSetAnimation(anim, frames)
+		view.requestSetAnimation(anim, bitmap.EncodeAnimationFrames(bitmaps))
 	}
 
 	external.Import(view.modalStateMachine, info, types, fileHandler, false)
 }
 
-func (view *View) requestExport() {
+// decodeAnimationFile decodes the full frames of either an animated PNG, or a GIF file.
+// Frame times are returned in milliseconds.
+func decodeAnimationFile(data []byte) ([]image.Image, []int16, error) {
+	var frames []image.Image
+	var frameTimes []int16
+	if bytes.HasPrefix(data, []byte("\x89PNG")) {
+		anim, err := apng.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		frames = anim.Image
+		for _, delay := range anim.Delay {
+			frameTimes = append(frameTimes, frameTimeOf(delay.Milliseconds()))
+		}
+	} else {
+		anim, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		frames = bitmap.ComposeGIFFrames(anim)
+		for _, delay := range anim.Delay {
+			frameTimes = append(frameTimes, frameTimeOf(int64(delay)*10))
+		}
+	}
+	if (len(frames) == 0) || (len(frames) > 256) {
+		return nil, nil, errFrameCountNotSupported
+	}
+	return frames, frameTimes, nil
+}
+
+func frameTimeOf(milliseconds int64) int16 {
+	if milliseconds > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	return int16(milliseconds)
+}
+
+func (view *View) requestExport(format exportFormat) {
 	anim, hasAnim, _ := view.currentAnimation()
 	if hasAnim {
-		filename := fmt.Sprintf("Anim%04X.gif", int(view.model.currentKey.ID.Plus(view.model.currentKey.Index)))
-		view.exportTo(filename, anim)
+		filename := fmt.Sprintf("Anim%04X.%s", int(view.model.currentKey.ID.Plus(view.model.currentKey.Index)), format.extension)
+		view.exportTo(filename, anim, format)
 	}
 }
 
-func (view *View) exportTo(filename string, anim bitmap.Animation) {
+func (view *View) exportTo(filename string, anim bitmap.Animation, format exportFormat) {
 	info := "File to be written: " + filename
 	var exportTo func(string)
 
 	exportTo = func(dirname string) {
-		writer, err := os.Create(filepath.Join(dirname, filename))
-		if err != nil {
-			external.Export(view.modalStateMachine, "Could not create file.\n"+info, exportTo, true)
-			return
-		}
-		defer func() { _ = writer.Close() }()
-
 		palTex, err := view.paletteCache.Palette(0)
 		if err != nil {
 			external.Export(view.modalStateMachine, "Could not create file. No palette loaded.\n"+info, exportTo, true)
@@ -279,34 +293,29 @@ func (view *View) exportTo(filename string, anim bitmap.Animation) {
 		}
 
 		colorPalette := palTex.Palette().ColorPalette(false)
-		data := gif.GIF{
-			Config: image.Config{
-				Width:      int(anim.Width),
-				Height:     int(anim.Height),
-				ColorModel: colorPalette,
-			},
-			LoopCount: -1,
+		imageRect := image.Rect(0, 0, int(anim.Width), int(anim.Height))
+		frameTimes := anim.FrameTimes()
+		frames := make([]*image.Paletted, 0, len(frameTimes))
+		for frameIndex := range frameTimes {
+			frameKey := resource.KeyOf(anim.ResourceID, resource.LangAny, frameIndex)
+			if !view.cacheFrame(frameKey) {
+				external.Export(view.modalStateMachine, "Failed to cache frame.\n"+info, exportTo, true)
+				return
+			}
+			frameTex, _ := view.imageCache.Texture(frameKey)
+			frameImg := image.NewPaletted(imageRect, colorPalette)
+			frameImg.Pix = frameTex.PixelData()
+			frames = append(frames, frameImg)
 		}
 
-		imageRect := image.Rect(0, 0, data.Config.Width, data.Config.Height)
-		frameIndex := 0
-		for _, entry := range anim.Entries {
-			for frameIndex <= int(entry.LastFrame) {
-				frameKey := resource.KeyOf(anim.ResourceID, resource.LangAny, frameIndex)
-				if !view.cacheFrame(frameKey) {
-					external.Export(view.modalStateMachine, "Failed to cache frame.\n"+info, exportTo, true)
-					return
-				}
-				frameTex, _ := view.imageCache.Texture(frameKey)
-				frameImg := image.NewPaletted(imageRect, colorPalette)
-				frameImg.Pix = frameTex.PixelData()
-				data.Image = append(data.Image, frameImg)
-				data.Delay = append(data.Delay, int(entry.FrameTime)/10)
-				frameIndex++
-			}
+		writer, err := os.Create(filepath.Join(dirname, filename))
+		if err != nil {
+			external.Export(view.modalStateMachine, "Could not create file.\n"+info, exportTo, true)
+			return
 		}
+		defer func() { _ = writer.Close() }()
 
-		err = gif.EncodeAll(writer, &data)
+		err = format.encode(writer, frames, frameTimes)
 		if err != nil {
 			external.Export(view.modalStateMachine, info, exportTo, true)
 			return
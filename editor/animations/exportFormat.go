@@ -0,0 +1,51 @@
+package animations
+
+import (
+	"image"
+	"image/gif"
+	"io"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1"
+	"github.com/inkyblackness/hacked/ss1/content/bitmap/apng"
+)
+
+const errFrameCountNotSupported ss1.StringError = "frame count not supported"
+
+type exportFormat struct {
+	title     string
+	extension string
+	encode    func(writer io.Writer, frames []*image.Paletted, frameTimes []int16) error
+}
+
+var exportFormats = []exportFormat{
+	{title: "GIF", extension: "gif", encode: encodeGIF},
+	{title: "APNG", extension: "png", encode: encodeAPNG},
+}
+
+func encodeGIF(writer io.Writer, frames []*image.Paletted, frameTimes []int16) error {
+	data := gif.GIF{
+		Image:     frames,
+		LoopCount: -1,
+	}
+	if len(frames) > 0 {
+		data.Config = image.Config{
+			Width:      frames[0].Rect.Dx(),
+			Height:     frames[0].Rect.Dy(),
+			ColorModel: frames[0].Palette,
+		}
+	}
+	for _, frameTime := range frameTimes {
+		data.Delay = append(data.Delay, int(frameTime)/10)
+	}
+	return gif.EncodeAll(writer, &data)
+}
+
+func encodeAPNG(writer io.Writer, frames []*image.Paletted, frameTimes []int16) error {
+	data := apng.APNG{LoopCount: 1}
+	for index, frame := range frames {
+		data.Image = append(data.Image, frame)
+		data.Delay = append(data.Delay, time.Duration(frameTimes[index])*time.Millisecond)
+	}
+	return apng.EncodeAll(writer, &data)
+}
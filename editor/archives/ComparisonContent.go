@@ -0,0 +1,163 @@
+package archives
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// comparisonSource is either the archive of the mod, or a loaded file.
+type comparisonSource struct {
+	filename  string
+	localizer resource.Localizer
+}
+
+func (source comparisonSource) title() string {
+	if source.localizer == nil {
+		return "Mod Archive"
+	}
+	return source.filename
+}
+
+func (view *View) renderComparisonContent() {
+	view.renderComparisonSource("Old", &view.model.comparisonOld)
+	view.renderComparisonSource("New", &view.model.comparisonNew)
+	if imgui.Button("Compare") {
+		comparison := edit.CompareArchives(view.comparisonLocalizer(view.model.comparisonOld),
+			view.comparisonLocalizer(view.model.comparisonNew), view.cp)
+		view.model.comparison = &comparison
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Compares the game state and all levels of the two archives.\n" +
+			"Results are kept until the next comparison, also if the mod changes.")
+	}
+	comparison := view.model.comparison
+	if comparison == nil {
+		return
+	}
+	imgui.Separator()
+	if comparison.IsEmpty() {
+		imgui.Text("No differences.")
+		return
+	}
+	view.renderGameStateDifferences(comparison.State)
+	for _, levelComparison := range comparison.Levels {
+		view.renderLevelDifferences(levelComparison)
+	}
+}
+
+func (view *View) renderComparisonSource(label string, source *comparisonSource) {
+	imgui.PushID(label)
+	imgui.Text(label + ": " + source.title())
+	imgui.SameLine()
+	if imgui.Button("Mod Archive") {
+		*source = comparisonSource{}
+	}
+	imgui.SameLine()
+	if imgui.Button("Load File...") {
+		types := []external.TypeInfo{{Title: "Archive files (*.dat)", Extensions: []string{"dat"}}}
+		external.LoadFile(view.modalStateMachine, types, func(filename string) error {
+			localizer, err := edit.LoadArchiveFile(filename)
+			if err != nil {
+				return err
+			}
+			*source = comparisonSource{filename: filename, localizer: localizer}
+			return nil
+		})
+	}
+	imgui.PopID()
+}
+
+func (view *View) comparisonLocalizer(source comparisonSource) resource.Localizer {
+	if source.localizer == nil {
+		return view.mod
+	}
+	return source.localizer
+}
+
+func (view *View) renderGameStateDifferences(diffs []archive.GameStateDifference) {
+	if len(diffs) == 0 {
+		imgui.Text("Game state: no differences.")
+		return
+	}
+	if !imgui.TreeNodeV(fmt.Sprintf("Game State: %d difference(s)###game-state-diff", len(diffs)), imgui.TreeNodeFlagsFramed) {
+		return
+	}
+	for _, diff := range diffs {
+		imgui.Text(fmt.Sprintf("%s: %s -> %s", view.gameStateDifferenceName(diff), diff.Old, diff.New))
+	}
+	imgui.TreePop()
+}
+
+func (view *View) gameStateDifferenceName(diff archive.GameStateDifference) string {
+	switch diff.Aspect {
+	case archive.AspectGeneral:
+		return diff.Key
+	case archive.AspectBooleanVariable:
+		return fmt.Sprintf("%s %03d (%s)", diff.Aspect, diff.Index, view.gameStateService.BooleanVariable(diff.Index).Name)
+	case archive.AspectIntegerVariable:
+		return fmt.Sprintf("%s %02d (%s)", diff.Aspect, diff.Index, view.gameStateService.IntegerVariable(diff.Index).Name)
+	case archive.AspectAmmo:
+		return fmt.Sprintf("%s %d (%s)", diff.Aspect, diff.Index, view.indexedName(object.ClassAmmo, diff.Index))
+	case archive.AspectGrenade:
+		return fmt.Sprintf("%s %d (%s)", diff.Aspect, diff.Index, view.indexedName(object.ClassGrenade, diff.Index))
+	case archive.AspectPatch:
+		return fmt.Sprintf("%s %d (%s)", diff.Aspect, diff.Index, view.indexedName(object.ClassDrug, diff.Index))
+	case archive.AspectHardware:
+		return fmt.Sprintf("%s %d (%s)", diff.Aspect, diff.Index, view.indexedName(object.ClassHardware, diff.Index))
+	default:
+		return fmt.Sprintf("%s %d", diff.Aspect, diff.Index)
+	}
+}
+
+func (view *View) renderLevelDifferences(comparison edit.LevelComparison) {
+	diff := comparison.Differences
+	title := fmt.Sprintf("Level %d: %d object(s), %d tile(s), %d visited tile(s)###level-diff-%d",
+		comparison.ID, len(diff.Objects), len(diff.Tiles), diff.VisitedTiles, comparison.ID)
+	if !imgui.TreeNodeV(title, imgui.TreeNodeFlagsFramed) {
+		return
+	}
+	for _, objDiff := range diff.Objects {
+		entry := objDiff.New
+		if objDiff.Change.Has(level.ObjectRemoved) {
+			entry = objDiff.Old
+		}
+		text := fmt.Sprintf("#%04d %s: %s", objDiff.ID, view.tripleName(entry.Triple()), objDiff.Change)
+		if objDiff.Change.Has(level.ObjectReplaced) {
+			text += " (was " + view.tripleName(objDiff.Old.Triple()) + ")"
+		}
+		if objDiff.Change.Has(level.ObjectStateChanged) && (objDiff.Old.Hitpoints != objDiff.New.Hitpoints) {
+			text += fmt.Sprintf(" HP %d -> %d", objDiff.Old.Hitpoints, objDiff.New.Hitpoints)
+		}
+		if imgui.Selectable(fmt.Sprintf("%s##object-%d", text, objDiff.ID)) {
+			view.levelSelection.SetCurrentLevelID(comparison.ID)
+			view.levelSelection.SetCurrentSelectedTiles([]level.TilePosition{{X: entry.X.Tile(), Y: entry.Y.Tile()}})
+			view.levelSelection.SetCurrentSelectedObjects([]level.ObjectID{objDiff.ID})
+		}
+		if (len(objDiff.ChangedProperties) > 0) && imgui.IsItemHovered() {
+			tooltip := "Changed properties:"
+			for _, key := range objDiff.ChangedProperties {
+				tooltip += "\n" + key
+			}
+			imgui.SetTooltip(tooltip)
+		}
+	}
+	if (len(diff.Tiles) > 0) && imgui.TreeNodeV(fmt.Sprintf("Changed Tiles (%d)", len(diff.Tiles)), 0) {
+		for _, pos := range diff.Tiles {
+			if imgui.Selectable(fmt.Sprintf("Tile %2d/%2d##tile-%d-%d", pos.X, pos.Y, pos.X, pos.Y)) {
+				view.levelSelection.SetCurrentLevelID(comparison.ID)
+				view.levelSelection.SetCurrentSelectedObjects(nil)
+				view.levelSelection.SetCurrentSelectedTiles([]level.TilePosition{pos})
+			}
+		}
+		imgui.TreePop()
+	}
+	imgui.TreePop()
+}
@@ -0,0 +1,313 @@
+package archives
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+var messageProgressNames = []string{"Not received", "Received", "Read"}
+
+func (view *View) renderTestStartContent() {
+	presets := view.testStarts.Presets()
+	if view.model.testStartIndex >= len(presets) {
+		view.model.testStartIndex = len(presets) - 1
+	}
+	if view.model.testStartIndex < 0 {
+		view.model.testStartIndex = 0
+	}
+
+	imgui.PushItemWidth(-250 * view.guiScale)
+	selectedName := ""
+	if view.model.testStartIndex < len(presets) {
+		selectedName = presets[view.model.testStartIndex].Name
+	}
+	if imgui.BeginCombo("Preset", selectedName) {
+		for index, preset := range presets {
+			if imgui.SelectableV(fmt.Sprintf("%s##%d", preset.Name, index), index == view.model.testStartIndex, 0, imgui.Vec2{}) {
+				view.model.testStartIndex = index
+			}
+		}
+		imgui.EndCombo()
+	}
+	if imgui.Button("New") {
+		view.model.testStartIndex = view.testStarts.AddPreset(archive.NewTestStart(fmt.Sprintf("Test Start %d", len(presets)+1)))
+	}
+	if view.model.testStartIndex >= len(presets) {
+		imgui.PopItemWidth()
+		return
+	}
+	preset := presets[view.model.testStartIndex]
+	imgui.SameLine()
+	if imgui.Button("Duplicate") {
+		duplicate := preset
+		duplicate.Name += " (copy)"
+		duplicate.Weapons = append([]archive.TestStartWeapon{}, preset.Weapons...)
+		duplicate.Messages = append([]archive.TestStartMessage{}, preset.Messages...)
+		duplicate.BooleanVariables = append([]archive.TestStartBooleanVariable{}, preset.BooleanVariables...)
+		duplicate.IntegerVariables = append([]archive.TestStartIntegerVariable{}, preset.IntegerVariables...)
+		view.model.testStartIndex = view.testStarts.AddPreset(duplicate)
+		imgui.PopItemWidth()
+		return
+	}
+	imgui.SameLine()
+	if imgui.Button("Remove") {
+		view.testStarts.RemovePreset(view.model.testStartIndex)
+		imgui.PopItemWidth()
+		return
+	}
+	imgui.SameLine()
+	if imgui.Button("Write Savegame...") {
+		view.requestWriteTestStart(preset)
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Writes an archive with all current levels and the state of this preset.\n" +
+			"Place it as a savegame of the engine to start from there.")
+	}
+
+	imgui.Separator()
+	changed := view.renderTestStartGeneral(&preset)
+	changed = view.renderTestStartInventory(&preset) || changed
+	changed = view.renderTestStartMessages(&preset) || changed
+	changed = view.renderTestStartVariables(&preset) || changed
+	if changed {
+		view.testStarts.SetPreset(view.model.testStartIndex, preset)
+	}
+	imgui.PopItemWidth()
+}
+
+func (view *View) renderTestStartGeneral(preset *archive.TestStart) bool {
+	if !imgui.TreeNodeV("Test Start: General", imgui.TreeNodeFlagsDefaultOpen|imgui.TreeNodeFlagsFramed) {
+		return false
+	}
+	changed := imgui.InputText("Name", &preset.Name)
+	changed = imgui.InputText("Hacker Name", &preset.HackerName) || changed
+	changed = gui.StepSliderInt("Level", &preset.Level, 0, archive.MaxLevels-1) || changed
+	changed = renderCoordinateControls("X", &preset.X) || changed
+	changed = renderCoordinateControls("Y", &preset.Y) || changed
+	changed = imgui.SliderFloat("Yaw (degrees)", &preset.Yaw, 0, 360) || changed
+	if imgui.Button("Take Current Level and Selected Tile") {
+		preset.Level = view.levelSelection.CurrentLevelID()
+		if tiles := view.levelSelection.CurrentSelectedTiles(); len(tiles) > 0 {
+			preset.X = level.CoordinateAt(tiles[0].X, 0x80)
+			preset.Y = level.CoordinateAt(tiles[0].Y, 0x80)
+		}
+		changed = true
+	}
+	if !view.levels.IsTileOnMap(preset.Level, level.TilePosition{X: preset.X.Tile(), Y: preset.Y.Tile()}) {
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.0, Z: 0.0, W: 1.0})
+		imgui.Text("Position is not on the map of the level.")
+		imgui.PopStyleColor()
+	}
+	for index, name := range []string{"Combat", "Mission", "Puzzle", "Cyber"} {
+		changed = gui.StepSliderInt("Difficulty: "+name, &preset.Difficulty[index], 0, 3) || changed
+	}
+	imgui.TreePop()
+	return changed
+}
+
+func renderCoordinateControls(axis string, coord *level.Coordinate) bool {
+	tile := int(coord.Tile())
+	fine := int(coord.Fine())
+	changed := gui.StepSliderInt(axis+" Tile", &tile, 0, 63)
+	changed = gui.StepSliderInt(axis+" Fine", &fine, 0, 0xFF) || changed
+	if changed {
+		*coord = level.CoordinateAt(byte(tile), byte(fine))
+	}
+	return changed
+}
+
+func (view *View) renderTestStartInventory(preset *archive.TestStart) bool {
+	changed := false
+	if imgui.TreeNodeV("Test Start: Weapons", imgui.TreeNodeFlagsFramed) {
+		for index := 0; index < archive.InventoryWeaponSlots; index++ {
+			changed = view.renderTestStartWeaponSlot(preset, index) || changed
+		}
+		imgui.TreePop()
+	}
+	if imgui.TreeNodeV("Test Start: Ammo and Explosives", imgui.TreeNodeFlagsFramed) {
+		for index := range preset.AmmoClips {
+			changed = gui.StepSliderInt(fmt.Sprintf("Clip Count (%s)", view.indexedName(object.ClassAmmo, index)),
+				&preset.AmmoClips[index], 0, 0xFF) || changed
+		}
+		for index := range preset.Grenades {
+			changed = gui.StepSliderInt(fmt.Sprintf("Grenade Count (%s)", view.indexedName(object.ClassGrenade, index)),
+				&preset.Grenades[index], 0, 0xFF) || changed
+		}
+		for index := range preset.Patches {
+			changed = gui.StepSliderInt(fmt.Sprintf("Patch Count (%s)", view.indexedName(object.ClassDrug, index)),
+				&preset.Patches[index], 0, 0xFF) || changed
+		}
+		imgui.TreePop()
+	}
+	if imgui.TreeNodeV("Test Start: Hard-/Software", imgui.TreeNodeFlagsFramed) {
+		for index := range preset.Hardware {
+			changed = gui.StepSliderInt(fmt.Sprintf("Version (%s)", view.indexedName(object.ClassHardware, index)),
+				&preset.Hardware[index], 0, 4) || changed
+		}
+		for index := range preset.OffenseSoftware {
+			changed = gui.StepSliderInt(fmt.Sprintf("Version (%s)", view.tripleName(object.TripleFrom(int(object.ClassSoftware), 0, index))),
+				&preset.OffenseSoftware[index], 0, 0xFF) || changed
+		}
+		for index := range preset.DefenseSoftware {
+			changed = gui.StepSliderInt(fmt.Sprintf("Version (%s)", view.tripleName(object.TripleFrom(int(object.ClassSoftware), 1, index))),
+				&preset.DefenseSoftware[index], 0, 0xFF) || changed
+		}
+		for index := range preset.OneshotSoftware {
+			changed = gui.StepSliderInt(fmt.Sprintf("Count (%s)", view.tripleName(object.TripleFrom(int(object.ClassSoftware), 2, index))),
+				&preset.OneshotSoftware[index], 0, 0xFF) || changed
+		}
+		imgui.TreePop()
+	}
+	return changed
+}
+
+func (view *View) renderTestStartWeaponSlot(preset *archive.TestStart, index int) bool {
+	selectedName := "(none)"
+	if index < len(preset.Weapons) {
+		weapon := preset.Weapons[index]
+		selectedName = view.tripleName(object.TripleFrom(int(object.ClassGun), int(weapon.Subclass), int(weapon.Type)))
+	}
+	changed := false
+	if imgui.BeginCombo(fmt.Sprintf("Weapon %d", index+1), selectedName) {
+		if (index < len(preset.Weapons)) && imgui.SelectableV("(none)", false, 0, imgui.Vec2{}) {
+			preset.Weapons = append(preset.Weapons[:index:index], preset.Weapons[index+1:]...)
+			changed = true
+		}
+		if index <= len(preset.Weapons) {
+			for _, triple := range view.mod.ObjectProperties().TriplesInClass(object.ClassGun) {
+				if imgui.SelectableV(view.tripleName(triple), false, 0, imgui.Vec2{}) {
+					weapon := archive.TestStartWeapon{Subclass: triple.Subclass, Type: triple.Type}
+					if index < len(preset.Weapons) {
+						preset.Weapons[index] = weapon
+					} else {
+						preset.Weapons = append(preset.Weapons, weapon)
+					}
+					changed = true
+				}
+			}
+		}
+		imgui.EndCombo()
+	}
+	return changed
+}
+
+func (view *View) renderTestStartMessages(preset *archive.TestStart) bool {
+	if !imgui.TreeNodeV("Test Start: Messages", imgui.TreeNodeFlagsFramed) {
+		return false
+	}
+	changed := false
+	for _, kind := range archive.MessageKinds() {
+		index := &view.model.testStartMessageIndex[kind]
+		gui.StepSliderInt(kind.String()+" Index", index, 0, kind.Count()-1)
+		received, read := preset.Message(kind, *index)
+		progress := 0
+		if received {
+			progress++
+		}
+		if read {
+			progress++
+		}
+		if imgui.BeginCombo(kind.String()+" State", messageProgressNames[progress]) {
+			for newProgress, name := range messageProgressNames {
+				if imgui.SelectableV(name, newProgress == progress, 0, imgui.Vec2{}) {
+					preset.SetMessage(kind, *index, newProgress > 0, newProgress > 1)
+					changed = true
+				}
+			}
+			imgui.EndCombo()
+		}
+	}
+	imgui.TreePop()
+	return changed
+}
+
+func (view *View) renderTestStartVariables(preset *archive.TestStart) bool {
+	if !imgui.TreeNodeV("Test Start: Variables", imgui.TreeNodeFlagsFramed) {
+		return false
+	}
+	changed := false
+	gui.StepSliderInt("Boolean Variable", &view.model.testStartBooleanIndex, 0, archive.BooleanVarCount-1)
+	imgui.SameLine()
+	if imgui.Button("Add##boolean") {
+		preset.BooleanVariables = append(removeBooleanOverride(preset.BooleanVariables, view.model.testStartBooleanIndex),
+			archive.TestStartBooleanVariable{Index: view.model.testStartBooleanIndex, Value: true})
+		changed = true
+	}
+	for _, variable := range preset.BooleanVariables {
+		value := variable.Value
+		label := fmt.Sprintf("Bool%03d: %s", variable.Index, view.gameStateService.BooleanVariable(variable.Index).Name)
+		if imgui.Checkbox(label, &value) {
+			preset.BooleanVariables = append(removeBooleanOverride(preset.BooleanVariables, variable.Index),
+				archive.TestStartBooleanVariable{Index: variable.Index, Value: value})
+			changed = true
+		}
+		imgui.SameLine()
+		if imgui.Button(fmt.Sprintf("Remove##boolean%d", variable.Index)) {
+			preset.BooleanVariables = removeBooleanOverride(preset.BooleanVariables, variable.Index)
+			changed = true
+		}
+	}
+
+	imgui.Separator()
+	gui.StepSliderInt("Integer Variable", &view.model.testStartIntegerIndex, 0, archive.IntegerVarCount-1)
+	imgui.SameLine()
+	if imgui.Button("Add##integer") {
+		preset.IntegerVariables = append(removeIntegerOverride(preset.IntegerVariables, view.model.testStartIntegerIndex),
+			archive.TestStartIntegerVariable{Index: view.model.testStartIntegerIndex})
+		changed = true
+	}
+	for _, variable := range preset.IntegerVariables {
+		value := int32(variable.Value)
+		label := fmt.Sprintf("Int%02d: %s", variable.Index, view.gameStateService.IntegerVariable(variable.Index).Name)
+		if imgui.InputInt(label, &value) {
+			preset.IntegerVariables = append(removeIntegerOverride(preset.IntegerVariables, variable.Index),
+				archive.TestStartIntegerVariable{Index: variable.Index, Value: int16(value)})
+			changed = true
+		}
+		imgui.SameLine()
+		if imgui.Button(fmt.Sprintf("Remove##integer%d", variable.Index)) {
+			preset.IntegerVariables = removeIntegerOverride(preset.IntegerVariables, variable.Index)
+			changed = true
+		}
+	}
+	imgui.TreePop()
+	return changed
+}
+
+func removeBooleanOverride(list []archive.TestStartBooleanVariable, index int) []archive.TestStartBooleanVariable {
+	var result []archive.TestStartBooleanVariable
+	for _, variable := range list {
+		if variable.Index != index {
+			result = append(result, variable)
+		}
+	}
+	return result
+}
+
+func removeIntegerOverride(list []archive.TestStartIntegerVariable, index int) []archive.TestStartIntegerVariable {
+	var result []archive.TestStartIntegerVariable
+	for _, variable := range list {
+		if variable.Index != index {
+			result = append(result, variable)
+		}
+	}
+	return result
+}
+
+func (view *View) requestWriteTestStart(preset archive.TestStart) {
+	types := []external.TypeInfo{{Title: "Savegame files (*.dat)", Extensions: []string{"dat"}}}
+	external.SaveFile(view.modalStateMachine, types, func(filename string) error {
+		if !strings.HasSuffix(strings.ToLower(filename), ".dat") {
+			filename += ".dat"
+		}
+		return view.testStarts.SaveSavegame(preset, filename)
+	})
+}
@@ -49,6 +49,9 @@ var variableContextList = map[edit.VariableBaseContextIdentifier]struct {
 type View struct {
 	registry         cmd.Registry
 	gameStateService *edit.GameStateService
+	testStarts       *edit.TestStartService
+	levels           *edit.EditableLevels
+	levelSelection   *edit.LevelSelectionService
 	mod              *world.Mod
 	textCache        *text.Cache
 	cp               text.Codepage
@@ -62,12 +65,16 @@ type View struct {
 
 // NewArchiveView returns a new instance.
 func NewArchiveView(registry cmd.Registry,
-	gameStateService *edit.GameStateService, mod *world.Mod,
+	gameStateService *edit.GameStateService, testStarts *edit.TestStartService,
+	levels *edit.EditableLevels, levelSelection *edit.LevelSelectionService, mod *world.Mod,
 	textCache *text.Cache, cp text.Codepage,
 	modalStateMachine gui.ModalStateMachine, guiScale float32, commander cmd.Commander) *View {
 	view := &View{
 		registry:         registry,
 		gameStateService: gameStateService,
+		testStarts:       testStarts,
+		levels:           levels,
+		levelSelection:   levelSelection,
 		mod:              mod,
 		textCache:        textCache,
 		cp:               cp,
@@ -113,6 +120,14 @@ func (view *View) renderContent() {
 		view.renderGameStateContent()
 		imgui.EndTabItem()
 	}
+	if imgui.BeginTabItem("Test Start") {
+		view.renderTestStartContent()
+		imgui.EndTabItem()
+	}
+	if imgui.BeginTabItem("Compare") {
+		view.renderComparisonContent()
+		imgui.EndTabItem()
+	}
 
 	imgui.EndTabBar()
 }
@@ -737,6 +752,15 @@ func (view *View) createVariableControls(readOnly bool, gameState *archive.GameS
 		}
 	}
 
+	if imgui.Button("Scan Level Usage") {
+		view.model.variableUsage = edit.NewGameVariableUsageIndex(view.levels)
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Lists all objects of all levels that read or write the variables.\n" +
+			"The list is not updated automatically. Scan again after modifying levels.")
+	}
+	view.renderVariableUsageSummary()
+
 	isSavegame := gameState.IsSavegame()
 	if imgui.TreeNodeV("Boolean Variables", imgui.TreeNodeFlagsFramed) {
 		intConverter := func(u values.Unifier) int {
@@ -791,6 +815,7 @@ func (view *View) createVariableControls(readOnly bool, gameState *archive.GameS
 			} else if imgui.IsItemHovered() && (len(info.Description) > 0) {
 				imgui.SetTooltip(info.Description)
 			}
+			view.renderVariableUsage(varLabel, view.model.variableUsage.BooleanVariable(varIndex))
 		}
 		imgui.TreePop()
 	}
@@ -872,11 +897,95 @@ func (view *View) createVariableControls(readOnly bool, gameState *archive.GameS
 			} else if imgui.IsItemHovered() && (len(info.Description) > 0) {
 				imgui.SetTooltip(info.Description)
 			}
+			view.renderVariableUsage(varLabel, view.model.variableUsage.IntegerVariable(varIndex))
 		}
 		imgui.TreePop()
 	}
 }
 
+func (view *View) renderVariableUsageSummary() {
+	index := view.model.variableUsage
+	if !index.IsAvailable() {
+		return
+	}
+	writtenOnly := 0
+	readOnly := 0
+	count := func(usage edit.GameVariableUsage) {
+		if usage.IsWrittenOnly() {
+			writtenOnly++
+		}
+		if usage.IsReadOnly() {
+			readOnly++
+		}
+	}
+	for i := 0; i < archive.BooleanVarCount; i++ {
+		count(index.BooleanVariable(i))
+	}
+	for i := 0; i < archive.IntegerVarCount; i++ {
+		count(index.IntegerVariable(i))
+	}
+	imgui.SameLine()
+	imgui.Text(fmt.Sprintf("%d written but never read, %d read but never written", writtenOnly, readOnly))
+}
+
+func (view *View) renderVariableUsage(varLabel string, usage edit.GameVariableUsage) {
+	if !view.model.variableUsage.IsAvailable() {
+		return
+	}
+	imgui.SameLine()
+	summary := fmt.Sprintf("R%d W%d", len(usage.Reads), len(usage.Writes))
+	switch {
+	case usage.IsWrittenOnly():
+		summary += " (never read)"
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 1.0, Z: 0.0, W: 1.0})
+	case usage.IsReadOnly():
+		summary += " (never written)"
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.0, Z: 0.0, W: 1.0})
+	default:
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 1.0, Z: 1.0, W: 0.8})
+	}
+	usagePopup := varLabel + "-Usage"
+	if imgui.Button(summary + "###" + usagePopup + "-Button") {
+		imgui.OpenPopup(usagePopup)
+	}
+	imgui.PopStyleColor()
+	if imgui.BeginPopup(usagePopup) {
+		if !usage.IsUsed() {
+			imgui.Text("Not used by any object.")
+		}
+		accesses := make([]edit.GameVariableAccess, 0, len(usage.Reads)+len(usage.Writes))
+		accesses = append(accesses, usage.Reads...)
+		accesses = append(accesses, usage.Writes...)
+		for index, access := range accesses {
+			if imgui.Selectable(view.variableAccessText(access) + fmt.Sprintf("##%d", index)) {
+				view.jumpToObject(access)
+			}
+		}
+		imgui.EndPopup()
+	}
+}
+
+func (view *View) variableAccessText(access edit.GameVariableAccess) string {
+	objectInfo := hintUnknown
+	lvl := view.levels.Level(access.LevelID)
+	obj := lvl.Object(access.ObjectID)
+	if (obj != nil) && (obj.InUse != 0) {
+		objectInfo = view.tripleName(obj.Triple())
+	}
+	return fmt.Sprintf("%-5s L%02d #%04d %s -- %s", access.Access.Kind, access.LevelID, access.ObjectID,
+		access.Access.Key, objectInfo)
+}
+
+func (view *View) jumpToObject(access edit.GameVariableAccess) {
+	obj := view.levels.Level(access.LevelID).Object(access.ObjectID)
+	if (obj == nil) || (obj.InUse == 0) {
+		return
+	}
+	view.levelSelection.SetCurrentLevelID(access.LevelID)
+	view.levelSelection.SetCurrentSelectedTiles([]level.TilePosition{{X: obj.X.Tile(), Y: obj.Y.Tile()}})
+	view.levelSelection.SetCurrentSelectedObjects([]level.ObjectID{access.ObjectID})
+}
+
 func (view *View) createMessageControls(readOnly bool, gameState *archive.GameState, onChange func()) {
 	view.createMessageControlsFor(readOnly, "EMail",
 		&view.model.emailIndex, archive.EMailCount, gameState.EMailState, onChange)
@@ -1,5 +1,10 @@
 package archives
 
+import (
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/edit"
+)
+
 type viewModel struct {
 	windowOpen   bool
 	restoreFocus bool
@@ -9,6 +14,17 @@ type viewModel struct {
 	emailIndex    int
 	logIndex      int
 	fragmentIndex int
+
+	variableUsage edit.GameVariableUsageIndex
+
+	testStartIndex        int
+	testStartMessageIndex [archive.MessageKindCount]int
+	testStartBooleanIndex int
+	testStartIntegerIndex int
+
+	comparisonOld comparisonSource
+	comparisonNew comparisonSource
+	comparison    *edit.ArchiveComparison
 }
 
 func freshViewModel() viewModel {
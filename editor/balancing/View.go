@@ -0,0 +1,368 @@
+package balancing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/object/balance"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// defaultFireRateUnitsPerSecond is the assumed rate at which the fire rate value of weapons counts down.
+// The actual unit of the fire rate is not known, which is why the view allows to change it.
+const defaultFireRateUnitsPerSecond = 10
+
+const hintUnknown = "???"
+
+// View shows derived combat statistics of weapons and critters.
+type View struct {
+	mod       *world.Mod
+	textCache *text.Cache
+
+	guiScale float32
+
+	model viewModel
+}
+
+// NewBalancingView returns a new instance.
+func NewBalancingView(mod *world.Mod, textCache *text.Cache, guiScale float32) *View {
+	view := &View{
+		mod:       mod,
+		textCache: textCache,
+
+		guiScale: guiScale,
+
+		model: freshViewModel(),
+	}
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *View) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Render renders the view.
+func (view *View) Render() {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 800 * view.guiScale, Y: 500 * view.guiScale}, imgui.ConditionFirstUseEver)
+		if imgui.BeginV("Balancing", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent()
+		}
+		imgui.End()
+	}
+}
+
+// statistics are the derived values of one properties table.
+type statistics struct {
+	armaments []balance.Armament
+	critters  []balance.Critter
+
+	armamentsByKey map[armamentKey]balance.Armament
+	crittersByKey  map[object.Triple]balance.Target
+}
+
+type armamentKey struct {
+	weapon  object.Triple
+	ammo    object.Triple
+	hasAmmo bool
+}
+
+func keyOf(armament balance.Armament) armamentKey {
+	return armamentKey{weapon: armament.Weapon, ammo: armament.Ammo, hasAmmo: armament.HasAmmo}
+}
+
+func statisticsOf(table object.PropertiesTable) statistics {
+	stats := statistics{
+		armaments:      balance.Armaments(table),
+		critters:       balance.Critters(table),
+		armamentsByKey: make(map[armamentKey]balance.Armament),
+		crittersByKey:  make(map[object.Triple]balance.Target),
+	}
+	for _, armament := range stats.armaments {
+		stats.armamentsByKey[keyOf(armament)] = armament
+	}
+	for _, critter := range stats.critters {
+		stats.crittersByKey[critter.Triple] = critter.Target
+	}
+	return stats
+}
+
+func (view *View) renderContent() {
+	imgui.PushItemWidth(-300 * view.guiScale)
+	imgui.SliderFloat("Fire Rate Units per Second", &view.model.fireRateUnitsPerSecond, 1, 100)
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("The fire rate of weapons is the delay between two shots.\n" +
+			"Its unit is not known, adjust this value to match observed behaviour.")
+	}
+	imgui.Checkbox("Compare with Vanilla", &view.model.compareWithVanilla)
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Values that differ from the ones of the world (without the mod) are shown as \"mod (vanilla)\".")
+	}
+	imgui.PopItemWidth()
+
+	current := statisticsOf(view.mod.ObjectProperties())
+	vanilla := statisticsOf(view.mod.World().ObjectProperties())
+
+	imgui.BeginTabBar("balancing-tab")
+	if imgui.BeginTabItem("Weapons") {
+		view.renderWeapons(current, vanilla)
+		imgui.EndTabItem()
+	}
+	if imgui.BeginTabItem("Hits to Kill") {
+		view.renderHitsToKill(current, vanilla)
+		imgui.EndTabItem()
+	}
+	if imgui.BeginTabItem("Armor Matrix") {
+		view.renderArmorMatrix(current, vanilla)
+		imgui.EndTabItem()
+	}
+	if imgui.BeginTabItem("Damage Types") {
+		view.renderDamageTypes(current, vanilla)
+		imgui.EndTabItem()
+	}
+	imgui.EndTabBar()
+}
+
+func (view *View) renderWeapons(current, vanilla statistics) {
+	headers := []string{"Weapon", "Ammo", "Damage", "Penetration", "Damage Types", "Special", "Shots/s", "DPS (unarmored)"}
+	view.beginTable("weapons", headers)
+	for _, armament := range current.armaments {
+		old, hasOld := vanilla.armamentsByKey[keyOf(armament)]
+		cell := func(value func(balance.Armament) string) {
+			view.comparedCell(value(armament), hasOld, func() string { return value(old) })
+		}
+		view.textCell(view.objectName(armament.Weapon))
+		view.textCell(view.ammoName(armament))
+		cell(func(arm balance.Armament) string { return fmt.Sprintf("%d", arm.Attack.Damage) })
+		cell(func(arm balance.Armament) string { return fmt.Sprintf("%d", arm.Attack.Penetration) })
+		cell(func(arm balance.Armament) string { return damageTypesText(arm.Attack.DamageType) })
+		cell(func(arm balance.Armament) string { return specialText(arm.Attack.Special) })
+		cell(func(arm balance.Armament) string { return view.floatText(arm.ShotsPerSecond(view.fireRateUnits())) })
+		cell(func(arm balance.Armament) string {
+			return view.floatText(arm.DamagePerSecond(balance.Target{}, view.fireRateUnits()))
+		})
+	}
+	view.endTable()
+}
+
+func (view *View) renderHitsToKill(current, vanilla statistics) {
+	if len(current.armaments) == 0 {
+		imgui.Text("No weapons available.")
+		return
+	}
+	if view.model.armamentIndex >= len(current.armaments) {
+		view.model.armamentIndex = 0
+	}
+	armament := current.armaments[view.model.armamentIndex]
+	if imgui.BeginCombo("Weapon", view.armamentName(armament)) {
+		for index, entry := range current.armaments {
+			if imgui.SelectableV(view.armamentName(entry)+fmt.Sprintf("##%d", index), index == view.model.armamentIndex, 0, imgui.Vec2{}) {
+				view.model.armamentIndex = index
+			}
+		}
+		imgui.EndCombo()
+	}
+	oldArmament, hasOldArmament := vanilla.armamentsByKey[keyOf(armament)]
+
+	headers := []string{"Critter", "Hitpoints", "Armor", "Toughness", "Damage/Hit", "Hits to Kill", "DPS", "Seconds to Kill"}
+	view.beginTable("hits-to-kill", headers)
+	for _, critter := range current.critters {
+		oldTarget, hasOldTarget := vanilla.crittersByKey[critter.Triple]
+		hasOld := hasOldArmament && hasOldTarget
+		cell := func(value func(balance.Armament, balance.Target) string) {
+			view.comparedCell(value(armament, critter.Target), hasOld, func() string { return value(oldArmament, oldTarget) })
+		}
+		view.textCell(view.objectName(critter.Triple))
+		cell(func(_ balance.Armament, target balance.Target) string { return fmt.Sprintf("%d", target.Hitpoints) })
+		cell(func(_ balance.Armament, target balance.Target) string { return fmt.Sprintf("%d", target.Armor) })
+		cell(func(_ balance.Armament, target balance.Target) string { return toughnessText(target.Toughness) })
+		cell(func(arm balance.Armament, target balance.Target) string {
+			return fmt.Sprintf("%d", arm.Attack.DamageAgainst(target))
+		})
+		cell(func(arm balance.Armament, target balance.Target) string {
+			return hitsText(arm.Attack.HitsToKill(target))
+		})
+		cell(func(arm balance.Armament, target balance.Target) string {
+			return view.floatText(arm.DamagePerSecond(target, view.fireRateUnits()))
+		})
+		cell(func(arm balance.Armament, target balance.Target) string {
+			hits := arm.Attack.HitsToKill(target)
+			shotsPerSecond := arm.ShotsPerSecond(view.fireRateUnits())
+			if (hits == 0) || (shotsPerSecond <= 0) {
+				return "-"
+			}
+			return view.floatText(float64(hits-1) / shotsPerSecond)
+		})
+	}
+	view.endTable()
+}
+
+func (view *View) renderArmorMatrix(current, vanilla statistics) {
+	imgui.Text("Damage per hit of each weapon (rows) against each critter (columns).")
+	headers := []string{"Weapon"}
+	for _, critter := range current.critters {
+		headers = append(headers, view.objectName(critter.Triple))
+	}
+	if imgui.BeginChildV("armor-matrix", imgui.Vec2{}, false, imgui.WindowFlagsHorizontalScrollbar) {
+		view.beginTable("armor-matrix-table", headers)
+		for index := range headers {
+			imgui.SetColumnWidth(index, 120*view.guiScale)
+		}
+		for _, armament := range current.armaments {
+			oldArmament, hasOldArmament := vanilla.armamentsByKey[keyOf(armament)]
+			view.textCell(view.armamentName(armament))
+			for _, critter := range current.critters {
+				oldTarget, hasOldTarget := vanilla.crittersByKey[critter.Triple]
+				view.comparedCell(fmt.Sprintf("%d", armament.Attack.DamageAgainst(critter.Target)), hasOldArmament && hasOldTarget,
+					func() string { return fmt.Sprintf("%d", oldArmament.Attack.DamageAgainst(oldTarget)) })
+			}
+		}
+		view.endTable()
+	}
+	imgui.EndChild()
+}
+
+func (view *View) renderDamageTypes(current, vanilla statistics) {
+	imgui.Text("Damage types each critter is vulnerable to, and its special damage types.")
+	headers := []string{"Critter"}
+	for _, damageType := range object.DamageTypes() {
+		headers = append(headers, damageType.String())
+	}
+	headers = append(headers, "Primary (x2)", "Super (x4)")
+	view.beginTable("damage-types", headers)
+	for _, critter := range current.critters {
+		oldTarget, hasOld := vanilla.crittersByKey[critter.Triple]
+		cell := func(value func(balance.Target) string) {
+			view.comparedCell(value(critter.Target), hasOld, func() string { return value(oldTarget) })
+		}
+		view.textCell(view.objectName(critter.Triple))
+		for _, damageType := range object.DamageTypes() {
+			currentType := damageType
+			cell(func(target balance.Target) string {
+				if target.Vulnerabilities.Has(currentType) {
+					return "X"
+				}
+				return "-"
+			})
+		}
+		cell(func(target balance.Target) string {
+			return fmt.Sprintf("%d", target.SpecialVulnerabilities.PrimaryValue())
+		})
+		cell(func(target balance.Target) string {
+			return fmt.Sprintf("%d", target.SpecialVulnerabilities.SuperValue())
+		})
+	}
+	view.endTable()
+}
+
+func (view *View) beginTable(id string, headers []string) {
+	imgui.ColumnsV(len(headers), id, true)
+	imgui.Separator()
+	for _, header := range headers {
+		imgui.Text(header)
+		imgui.NextColumn()
+	}
+	imgui.Separator()
+}
+
+func (view *View) endTable() {
+	imgui.Columns()
+	imgui.Separator()
+}
+
+func (view *View) textCell(value string) {
+	imgui.Text(value)
+	imgui.NextColumn()
+}
+
+func (view *View) comparedCell(value string, hasOld bool, oldValue func() string) {
+	if view.model.compareWithVanilla && hasOld {
+		if old := oldValue(); old != value {
+			value += " (" + old + ")"
+			imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 1.0, Z: 0.0, W: 1.0})
+			imgui.Text(value)
+			imgui.PopStyleColor()
+			imgui.NextColumn()
+			return
+		}
+	}
+	view.textCell(value)
+}
+
+func (view *View) fireRateUnits() float64 {
+	return float64(view.model.fireRateUnitsPerSecond)
+}
+
+func (view *View) floatText(value float64) string {
+	return fmt.Sprintf("%.1f", value)
+}
+
+func (view *View) armamentName(armament balance.Armament) string {
+	name := view.objectName(armament.Weapon)
+	if armament.HasAmmo {
+		name += " / " + view.objectName(armament.Ammo)
+	}
+	return name
+}
+
+func (view *View) ammoName(armament balance.Armament) string {
+	if !armament.HasAmmo {
+		return "-"
+	}
+	return view.objectName(armament.Ammo)
+}
+
+func (view *View) objectName(triple object.Triple) string {
+	linearIndex := view.mod.ObjectProperties().TripleIndex(triple)
+	if linearIndex >= 0 {
+		key := resource.KeyOf(ids.ObjectShortNames, resource.LangDefault, linearIndex)
+		objName, err := view.textCache.Text(key)
+		if err == nil {
+			return strings.ReplaceAll(strings.ReplaceAll(objName, "\r", ""), "\n", " ")
+		}
+	}
+	return triple.String() + ": " + hintUnknown
+}
+
+func damageTypesText(mask object.DamageTypeMask) string {
+	var names []string
+	for _, damageType := range object.DamageTypes() {
+		if mask.Has(damageType) {
+			names = append(names, damageType.String())
+		}
+	}
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ", ")
+}
+
+func specialText(special object.SpecialDamageType) string {
+	return fmt.Sprintf("%d/%d", special.PrimaryValue(), special.SuperValue())
+}
+
+func toughnessText(toughness int) string {
+	if toughness >= object.ToughnessNoDamage {
+		return "no damage"
+	}
+	return fmt.Sprintf("%d:1", 1<<uint(toughness))
+}
+
+func hitsText(hits int) string {
+	if hits == 0 {
+		return "never"
+	}
+	return fmt.Sprintf("%d", hits)
+}
@@ -0,0 +1,18 @@
+package balancing
+
+type viewModel struct {
+	windowOpen   bool
+	restoreFocus bool
+
+	fireRateUnitsPerSecond float32
+	compareWithVanilla     bool
+
+	armamentIndex int
+}
+
+func freshViewModel() viewModel {
+	return viewModel{
+		fireRateUnitsPerSecond: defaultFireRateUnitsPerSecond,
+		compareWithVanilla:     true,
+	}
+}
@@ -46,6 +46,7 @@ type View struct {
 	mod          *world.Mod
 	imageCache   *graphics.TextureCache
 	paletteCache *graphics.PaletteCache
+	frameCache   *graphics.FrameCache
 
 	modalStateMachine gui.ModalStateMachine
 	clipboard         external.Clipboard
@@ -57,12 +58,13 @@ type View struct {
 
 // NewBitmapsView returns a new instance.
 func NewBitmapsView(mod *world.Mod, imageCache *graphics.TextureCache, paletteCache *graphics.PaletteCache,
-	modalStateMachine gui.ModalStateMachine, clipboard external.Clipboard,
+	frameCache *graphics.FrameCache, modalStateMachine gui.ModalStateMachine, clipboard external.Clipboard,
 	guiScale float32, commander cmd.Commander) *View {
 	view := &View{
 		mod:          mod,
 		imageCache:   imageCache,
 		paletteCache: paletteCache,
+		frameCache:   frameCache,
 
 		modalStateMachine: modalStateMachine,
 		clipboard:         clipboard,
@@ -221,7 +223,7 @@ func (view *View) requestImport(bmpInfo bitmapInfo) {
 		}
 		return palette.Palette(), nil
 	}
-	external.ImportImage(view.modalStateMachine, paletteRetriever, func(bmp bitmap.Bitmap) {
+	external.ImportImage(view.modalStateMachine, view.frameCache, paletteRetriever, func(bmp bitmap.Bitmap) {
 		view.requestSetBitmap(bmp, bmpInfo)
 	})
 }
@@ -0,0 +1,180 @@
+package external
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/audio/dsp"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+const waveformPreviewPoints = 256
+
+type audioProcessingState struct {
+	machine  gui.ModalStateMachine
+	callback func(audio.L8)
+
+	original dsp.Signal
+	options  dsp.Options
+
+	processed        dsp.Signal
+	originalPreview  []float32
+	processedPreview []float32
+	opened           bool
+}
+
+func newAudioProcessingState(machine gui.ModalStateMachine, original dsp.Signal,
+	callback func(audio.L8)) *audioProcessingState {
+	state := &audioProcessingState{
+		machine:  machine,
+		callback: callback,
+
+		original: original,
+		options:  dsp.DefaultOptions(),
+	}
+	state.originalPreview = waveformPreview(original)
+	return state
+}
+
+func (state *audioProcessingState) Render() {
+	if !state.opened {
+		state.opened = true
+		state.updatePreview()
+		imgui.OpenPopup("Process audio")
+	}
+
+	if imgui.BeginPopupModalV("Process audio", nil,
+		imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+		lineHeight := imgui.TextLineHeightWithSpacing()
+		imgui.PushItemWidth(lineHeight * 12)
+		if state.renderControls() {
+			state.updatePreview()
+		}
+		imgui.PopItemWidth()
+		imgui.Separator()
+		previewSize := imgui.Vec2{X: lineHeight * 24, Y: lineHeight * 3}
+		imgui.PlotLinesV("Original", state.originalPreview, 0, signalInfo(state.original), -1, 1, previewSize)
+		imgui.PlotLinesV("Result", state.processedPreview, 0, signalInfo(state.processed), -1, 1, previewSize)
+		imgui.Separator()
+		if imgui.Button("OK") {
+			state.close()
+			state.callback(state.processed.ToL8(state.options.Dither))
+		}
+		imgui.SameLine()
+		if imgui.Button("Cancel") {
+			state.close()
+		}
+		imgui.EndPopup()
+	} else {
+		state.close()
+	}
+}
+
+func (state *audioProcessingState) renderControls() bool {
+	changed := false
+	rateText := func(rate float64) string {
+		if rate <= 0 {
+			return fmt.Sprintf("Keep (%.0f Hz)", state.original.SampleRate)
+		}
+		return fmt.Sprintf("%.0f Hz", rate)
+	}
+	if imgui.BeginCombo("Sample Rate", rateText(state.options.SampleRate)) {
+		for _, rate := range append([]float64{0}, dsp.EngineSampleRates()...) {
+			if imgui.SelectableV(rateText(rate), rate == state.options.SampleRate, 0, imgui.Vec2{}) {
+				state.options.SampleRate = rate
+				changed = true
+			}
+		}
+		imgui.EndCombo()
+	}
+	if imgui.Checkbox("Trim Silence", &state.options.TrimSilence) {
+		changed = true
+	}
+	if state.options.TrimSilence && decibelSlider("Silence Threshold", &state.options.TrimThresholdDB, -96, -12) {
+		changed = true
+	}
+	if imgui.BeginCombo("Normalization", state.options.Normalization.String()) {
+		for _, mode := range dsp.Normalizations() {
+			if imgui.SelectableV(mode.String(), mode == state.options.Normalization, 0, imgui.Vec2{}) {
+				state.options.Normalization = mode
+				changed = true
+			}
+		}
+		imgui.EndCombo()
+	}
+	if (state.options.Normalization != dsp.NormalizationNone) &&
+		decibelSlider("Target Level", &state.options.NormalizationLevelDB, -40, 0) {
+		changed = true
+	}
+	if durationSlider("Fade In", &state.options.FadeIn) {
+		changed = true
+	}
+	if durationSlider("Fade Out", &state.options.FadeOut) {
+		changed = true
+	}
+	imgui.Checkbox("Dither to 8 bits", &state.options.Dither)
+	return changed
+}
+
+func decibelSlider(label string, value *float64, min, max int) bool {
+	intValue := int(math.Round(*value))
+	if gui.StepSliderIntV(label, &intValue, min, max, "%d dB") {
+		*value = float64(intValue)
+		return true
+	}
+	return false
+}
+
+func durationSlider(label string, value *float64) bool {
+	ms := int(math.Round(*value * 1000))
+	if gui.StepSliderIntV(label, &ms, 0, 2000, "%d ms") {
+		*value = float64(ms) / 1000
+		return true
+	}
+	return false
+}
+
+func (state *audioProcessingState) updatePreview() {
+	state.processed = state.options.Process(state.original)
+	state.processedPreview = waveformPreview(state.processed)
+}
+
+func (state *audioProcessingState) HandleFiles(names []string) {
+}
+
+func (state *audioProcessingState) close() {
+	state.machine.SetState(nil)
+	imgui.CloseCurrentPopup()
+}
+
+func signalInfo(signal dsp.Signal) string {
+	return fmt.Sprintf("%.0f Hz, %.2f s, peak %.1f dB, RMS %.1f dB",
+		signal.SampleRate, signal.Duration(),
+		dsp.DecibelsFromAmplitude(signal.Peak()), dsp.DecibelsFromAmplitude(signal.RMS()))
+}
+
+// waveformPreview returns the envelope of the signal, alternating the maximum and minimum of each section.
+func waveformPreview(signal dsp.Signal) []float32 {
+	count := len(signal.Samples)
+	if count == 0 {
+		return []float32{0}
+	}
+	points := waveformPreviewPoints
+	if points > count {
+		points = count
+	}
+	preview := make([]float32, 0, points*2)
+	for point := 0; point < points; point++ {
+		section := signal.Samples[point*count/points : (point+1)*count/points]
+		high, low := -1.0, 1.0
+		for _, sample := range section {
+			high = math.Max(high, sample)
+			low = math.Min(low, sample)
+		}
+		preview = append(preview, float32(high), float32(low))
+	}
+	return preview
+}
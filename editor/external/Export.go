@@ -5,10 +5,13 @@ import (
 	"image/png"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/audio/voc"
 	"github.com/inkyblackness/hacked/ss1/content/audio/wav"
 	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie/avi"
 	"github.com/inkyblackness/hacked/ui/gui"
 )
 
@@ -23,6 +26,7 @@ func Export(machine gui.ModalStateMachine, info string, callback func(string), l
 }
 
 // ExportAudio is a helper wrapper for exporting audio.
+// The format is based on the extension of the filename: Files ending in ".voc" are saved as VOC, all others as WAV.
 func ExportAudio(machine gui.ModalStateMachine, filename string, sound audio.L8) {
 	info := "File to be written: " + filename
 	var dirHandler func(string)
@@ -34,7 +38,11 @@ func ExportAudio(machine gui.ModalStateMachine, filename string, sound audio.L8)
 			return
 		}
 		defer func() { _ = writer.Close() }()
-		err = wav.Save(writer, sound.SampleRate, sound.Samples)
+		save := wav.Save
+		if strings.EqualFold(filepath.Ext(filename), ".voc") {
+			save = voc.Save
+		}
+		err = save(writer, sound.SampleRate, sound.Samples)
 		if err != nil {
 			Export(machine, info, dirHandler, true)
 		}
@@ -43,6 +51,27 @@ func ExportAudio(machine gui.ModalStateMachine, filename string, sound audio.L8)
 	Export(machine, info, dirHandler, false)
 }
 
+// ExportMovie is a helper wrapper for exporting a movie as AVI file.
+func ExportMovie(machine gui.ModalStateMachine, filename string, movie avi.Movie) {
+	info := "File to be written: " + filename
+	var exportTo func(string)
+
+	exportTo = func(dirname string) {
+		writer, err := os.Create(filepath.Join(dirname, filename))
+		if err != nil {
+			Export(machine, "Could not create file.\n"+info, exportTo, true)
+			return
+		}
+		defer func() { _ = writer.Close() }()
+		err = avi.Write(writer, movie)
+		if err != nil {
+			Export(machine, "Could not export movie.\n"+info, exportTo, true)
+		}
+	}
+
+	Export(machine, info, exportTo, false)
+}
+
 // ExportImage is a helper wrapper for exporting single images.
 func ExportImage(machine gui.ModalStateMachine, filename string, bmp bitmap.Bitmap) {
 	info := "File to be written: " + filename
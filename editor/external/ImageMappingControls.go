@@ -0,0 +1,79 @@
+package external
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// ImageMappingControls provide the settings for mapping generic images to the game palette.
+type ImageMappingControls struct {
+	dithering          bitmap.DitherMode
+	excludeTransparent bool
+	excludeCycling     bool
+	firstIndex         int
+	lastIndex          int
+}
+
+// NewImageMappingControls returns controls with default settings.
+func NewImageMappingControls() ImageMappingControls {
+	return ImageMappingControls{
+		dithering:          bitmap.DitherNone,
+		excludeTransparent: true,
+		excludeCycling:     true,
+		firstIndex:         0x00,
+		lastIndex:          0xFF,
+	}
+}
+
+// Render renders the controls. Returns true if any setting was changed.
+func (controls *ImageMappingControls) Render() bool {
+	changed := false
+	if imgui.BeginCombo("Dithering", controls.dithering.String()) {
+		for _, mode := range bitmap.DitherModes() {
+			if imgui.SelectableV(mode.String(), mode == controls.dithering, 0, imgui.Vec2{}) {
+				controls.dithering = mode
+				changed = true
+			}
+		}
+		imgui.EndCombo()
+	}
+	if imgui.Checkbox("Exclude transparent index", &controls.excludeTransparent) {
+		changed = true
+	}
+	if imgui.Checkbox("Exclude color cycling indices", &controls.excludeCycling) {
+		changed = true
+	}
+	if gui.StepSliderIntV("First Index", &controls.firstIndex, 0x00, 0xFF, "0x%02X") {
+		if controls.lastIndex < controls.firstIndex {
+			controls.lastIndex = controls.firstIndex
+		}
+		changed = true
+	}
+	if gui.StepSliderIntV("Last Index", &controls.lastIndex, 0x00, 0xFF, "0x%02X") {
+		if controls.firstIndex > controls.lastIndex {
+			controls.firstIndex = controls.lastIndex
+		}
+		changed = true
+	}
+	imgui.Text(fmt.Sprintf("Usable colors: %d", controls.Options().Indices.Count()))
+	return changed
+}
+
+// Options returns the currently selected settings.
+func (controls ImageMappingControls) Options() bitmap.MapOptions {
+	indices := bitmap.IndexRange(byte(controls.firstIndex), byte(controls.lastIndex))
+	if controls.excludeTransparent {
+		indices = indices.Without(bitmap.TransparentIndices())
+	}
+	if controls.excludeCycling {
+		indices = indices.Without(bitmap.CyclingIndices())
+	}
+	return bitmap.MapOptions{
+		Dithering: controls.dithering,
+		Indices:   indices,
+	}
+}
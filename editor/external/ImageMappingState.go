@@ -0,0 +1,88 @@
+package external
+
+import (
+	"image"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/editor/graphics"
+	"github.com/inkyblackness/hacked/editor/render"
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type imageMappingState struct {
+	machine    gui.ModalStateMachine
+	frameCache *graphics.FrameCache
+	previewKey graphics.FrameCacheKey
+	callback   func(bitmap.Bitmap)
+
+	img     image.Image
+	palette bitmap.Palette
+
+	controls ImageMappingControls
+
+	preview bitmap.Bitmap
+	opened  bool
+}
+
+func newImageMappingState(machine gui.ModalStateMachine, frameCache *graphics.FrameCache,
+	img image.Image, palette bitmap.Palette, callback func(bitmap.Bitmap)) *imageMappingState {
+	return &imageMappingState{
+		machine:    machine,
+		frameCache: frameCache,
+		previewKey: frameCache.AllocateKey(),
+		callback:   callback,
+
+		img:     img,
+		palette: palette,
+
+		controls: NewImageMappingControls(),
+	}
+}
+
+func (state *imageMappingState) Render() {
+	if !state.opened {
+		state.opened = true
+		state.updatePreview()
+		imgui.OpenPopup("Map image")
+	}
+
+	if imgui.BeginPopupModalV("Map image", nil,
+		imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+		lineHeight := imgui.TextLineHeightWithSpacing()
+		imgui.Text("The image does not match the game palette.\nChoose how its colors shall be mapped.")
+		imgui.Separator()
+		imgui.PushItemWidth(lineHeight * 12)
+		if state.controls.Render() {
+			state.updatePreview()
+		}
+		imgui.PopItemWidth()
+		render.FrameImage("Preview", state.frameCache, state.previewKey, imgui.Vec2{X: lineHeight * 20, Y: lineHeight * 15})
+		imgui.Separator()
+		if imgui.Button("OK") {
+			state.close()
+			state.callback(state.preview)
+		}
+		imgui.SameLine()
+		if imgui.Button("Cancel") {
+			state.close()
+		}
+		imgui.EndPopup()
+	} else {
+		state.close()
+	}
+}
+
+func (state *imageMappingState) updatePreview() {
+	bitmapper := bitmap.NewBitmapperWithOptions(&state.palette, state.controls.Options())
+	state.preview = bitmapper.Map(state.img)
+	state.frameCache.SetTexture(state.previewKey,
+		uint16(state.preview.Header.Width), uint16(state.preview.Header.Height), state.preview.Pixels, &state.palette)
+}
+
+func (state *imageMappingState) close() {
+	state.frameCache.DropTextureForKey(state.previewKey)
+	state.machine.SetState(nil)
+	imgui.CloseCurrentPopup()
+}
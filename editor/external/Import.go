@@ -1,17 +1,27 @@
 package external
 
 import (
+	"bytes"
 	"image"
 	"image/color"
+	"io/ioutil"
 	"math"
 	"os"
 
+	"github.com/inkyblackness/hacked/editor/graphics"
+	"github.com/inkyblackness/hacked/ss1"
 	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/audio/dsp"
+	"github.com/inkyblackness/hacked/ss1/content/audio/flac"
+	"github.com/inkyblackness/hacked/ss1/content/audio/voc"
+	"github.com/inkyblackness/hacked/ss1/content/audio/vorbis"
 	"github.com/inkyblackness/hacked/ss1/content/audio/wav"
 	"github.com/inkyblackness/hacked/ss1/content/bitmap"
 	"github.com/inkyblackness/hacked/ui/gui"
 )
 
+const errUnsupportedAudioFormat ss1.StringError = "unsupported audio format"
+
 // Import starts an import dialog series, calling the given callback with a file name.
 func Import(machine gui.ModalStateMachine, info string, types []TypeInfo, callback func(string), lastFailed bool) {
 	machine.SetState(&importStartState{
@@ -24,32 +34,61 @@ func Import(machine gui.ModalStateMachine, info string, types []TypeInfo, callba
 }
 
 // ImportAudio is a helper to handle audio file import. The callback is called with the loaded audio.
+// VOC files are in the format of the engine and are taken as they are. For any other format,
+// the audio can be resampled, normalized, trimmed, and faded, with a preview of the result.
 func ImportAudio(machine gui.ModalStateMachine, callback func(l8 audio.L8)) {
-	info := "File must be a WAV file, 22050 Hz, 8-bit or 16-bit, uncompressed."
-	types := []TypeInfo{{Title: "Audio files (*.wav)", Extensions: []string{"wav"}}}
+	info := "File must be a WAV, FLAC, Ogg Vorbis, or VOC file.\n" +
+		"VOC files are taken as they are, others are converted to the engine format in the next step."
+	types := []TypeInfo{{Title: "Audio files (*.wav, *.flac, *.ogg, *.voc)", Extensions: []string{"wav", "flac", "ogg", "oga", "voc"}}}
 	var fileHandler func(string)
 
 	fileHandler = func(filename string) {
-		reader, err := os.Open(filename)
+		data, err := ioutil.ReadFile(filename)
 		if err != nil {
-			Import(machine, info, types, fileHandler, true)
+			Import(machine, "Could not open file.\n"+info, types, fileHandler, true)
 			return
 		}
-		defer func() { _ = reader.Close() }()
-		sound, err := wav.Load(reader)
+		if bytes.HasPrefix(data, []byte(voc.FileHeader)) {
+			sound, err := voc.Load(bytes.NewReader(data))
+			if err != nil {
+				Import(machine, "File not recognized as VOC.\n"+info, types, fileHandler, true)
+				return
+			}
+			callback(sound)
+			return
+		}
+		signal, err := loadAudioSignal(data)
 		if err != nil {
-			Import(machine, info, types, fileHandler, true)
+			Import(machine, "File not recognized as supported audio.\n"+info, types, fileHandler, true)
 			return
 		}
-		callback(sound)
+		machine.SetState(newAudioProcessingState(machine, signal, callback))
 	}
 
 	Import(machine, info, types, fileHandler, false)
 }
 
+// loadAudioSignal decodes the given file data, based on the signature of the format.
+// Files with an ID3 tag are only supported if the tag precedes a FLAC stream.
+func loadAudioSignal(data []byte) (dsp.Signal, error) {
+	reader := bytes.NewReader(data)
+	switch {
+	case flac.IsStream(data):
+		return flac.LoadSignal(reader)
+	case bytes.HasPrefix(data, []byte(vorbis.FileHeader)):
+		return vorbis.LoadSignal(reader)
+	case bytes.HasPrefix(data, []byte("ID3")):
+		return dsp.Signal{}, errUnsupportedAudioFormat
+	default:
+		return wav.LoadSignal(reader)
+	}
+}
+
 // ImportImage is a helper to handle image file import. The callback is called with the loaded image.
-func ImportImage(machine gui.ModalStateMachine, paletteRetriever func() (bitmap.Palette, error), callback func(bitmap.Bitmap)) {
-	info := "File should be either a BMP, GIF, or a PNG file.\nPaletted images matching game palette are taken 1:1,\nothers are mapped closest fitting."
+// Images that do not match the palette are mapped with user-selected options, previewed via given frame cache.
+func ImportImage(machine gui.ModalStateMachine, frameCache *graphics.FrameCache,
+	paletteRetriever func() (bitmap.Palette, error), callback func(bitmap.Bitmap)) {
+	info := "File should be either a BMP, GIF, or a PNG file.\nPaletted images matching game palette are taken 1:1,\nothers are mapped with selectable dithering."
 	types := []TypeInfo{{Title: "Image files (*.bmp, *.gif, *.png)", Extensions: []string{"bmp", "gif", "png"}}}
 	var fileHandler func(string)
 
@@ -66,8 +105,6 @@ func ImportImage(machine gui.ModalStateMachine, paletteRetriever func() (bitmap.
 			return
 		}
 
-		var bmp bitmap.Bitmap
-		importMapped := true
 		rawPalette, err := paletteRetriever()
 		if err != nil {
 			Import(machine, "Can not import image without having a palette loaded.\n"+info, types, fileHandler, true)
@@ -75,7 +112,8 @@ func ImportImage(machine gui.ModalStateMachine, paletteRetriever func() (bitmap.
 		}
 		if palettedImg, isPaletted := img.(image.PalettedImage); isPaletted {
 			imgPalette, hasPalette := palettedImg.ColorModel().(color.Palette)
-			if hasPalette && paletteMatches(imgPalette, rawPalette.ColorPalette(false)) {
+			if hasPalette && PaletteMatches(imgPalette, rawPalette.ColorPalette(false)) {
+				var bmp bitmap.Bitmap
 				bounds := img.Bounds()
 
 				bmp.Header.Width = int16(math.Max(0, math.Min(float64(bounds.Dx()), math.MaxInt16)))
@@ -86,20 +124,43 @@ func ImportImage(machine gui.ModalStateMachine, paletteRetriever func() (bitmap.
 						bmp.Pixels[row*int(bmp.Header.Width)+column] = palettedImg.ColorIndexAt(column, row)
 					}
 				}
-				importMapped = false
+				callback(bmp)
+				return
 			}
 		}
-		if importMapped {
-			bitmapper := bitmap.NewBitmapper(&rawPalette)
-			bmp = bitmapper.Map(img)
+		machine.SetState(newImageMappingState(machine, frameCache, img, rawPalette, callback))
+	}
+
+	Import(machine, info, types, fileHandler, false)
+}
+
+// ImportRawImage is a helper to handle image file import without palette mapping.
+// The callback is called with the decoded image.
+func ImportRawImage(machine gui.ModalStateMachine, info string, callback func(image.Image)) {
+	info = "File should be either a BMP, GIF, or a PNG file.\n" + info
+	types := []TypeInfo{{Title: "Image files (*.bmp, *.gif, *.png)", Extensions: []string{"bmp", "gif", "png"}}}
+	var fileHandler func(string)
+
+	fileHandler = func(filename string) {
+		reader, err := os.Open(filename)
+		if err != nil {
+			Import(machine, "Could not open file.\n"+info, types, fileHandler, true)
+			return
+		}
+		defer func() { _ = reader.Close() }()
+		img, _, err := image.Decode(reader)
+		if err != nil {
+			Import(machine, "File not recognized as image.\n"+info, types, fileHandler, true)
+			return
 		}
-		callback(bmp)
+		callback(img)
 	}
 
 	Import(machine, info, types, fileHandler, false)
 }
 
-func paletteMatches(imgPalette color.Palette, rawPalette color.Palette) bool {
+// PaletteMatches returns true if the colors of the image palette are identical to the first entries of the raw palette.
+func PaletteMatches(imgPalette color.Palette, rawPalette color.Palette) bool {
 	if len(imgPalette) > len(rawPalette) {
 		return false
 	}
@@ -6,6 +6,9 @@ import (
 	"github.com/inkyblackness/hacked/ss1/content/archive/level"
 )
 
+// MusicZoneLabeler returns a description of the music that is played in zones of given music index.
+type MusicZoneLabeler func(musicIndex int) string
+
 func tileHeightFormatterFor(levelHeight level.HeightShift) func(int) string {
 	return func(value int) string {
 		tileHeight, err := levelHeight.ValueFromTileHeight(level.TileHeightUnit(value))
@@ -24,6 +24,7 @@ type MapDisplay struct {
 	gameObjects    *edit.GameObjectsService
 	levelSelection *edit.LevelSelectionService
 	editor         *edit.LevelEditorService
+	musicZoneLabel MusicZoneLabeler
 
 	context  render.Context
 	camera   *LimitedCamera
@@ -52,7 +53,7 @@ type MapDisplay struct {
 // NewMapDisplay returns a new instance.
 func NewMapDisplay(gameObjects *edit.GameObjectsService, levelSelection *edit.LevelSelectionService, editor *edit.LevelEditorService,
 	gl opengl.OpenGL, guiScale float32,
-	textureQuery TextureQuery) *MapDisplay {
+	textureQuery TextureQuery, musicZoneLabel MusicZoneLabeler) *MapDisplay {
 	tilesPerMapSide := float32(64)
 
 	tileBaseLength := float32(level.FineCoordinatesPerTileSide)
@@ -66,6 +67,7 @@ func NewMapDisplay(gameObjects *edit.GameObjectsService, levelSelection *edit.Le
 		gameObjects:    gameObjects,
 		levelSelection: levelSelection,
 		editor:         editor,
+		musicZoneLabel: musicZoneLabel,
 		context: render.Context{
 			OpenGL:           gl,
 			ProjectionMatrix: mgl.Ident4(),
@@ -277,6 +279,8 @@ func (display *MapDisplay) renderPositionOverlay(lvl *level.Level) {
 		hasCeiling := false
 		var ceilingRaw int
 		ceilingString := hintUnknown
+		hasMusic := false
+		var musicIndex int
 
 		if display.hoverItems.activeItem != nil {
 			pos = display.hoverItems.activeItem.Pos()
@@ -303,6 +307,9 @@ func (display *MapDisplay) renderPositionOverlay(lvl *level.Level) {
 					}
 					ceilingRaw = int(ceilingHeight)
 					hasCeiling = true
+
+					musicIndex = tile.Flags.MusicIndex()
+					hasMusic = true
 				}
 			} else if objectItem, isObjectItem := display.hoverItems.activeItem.(objectHoverItem); isObjectItem {
 				_, _, heightShift := lvl.Size()
@@ -336,6 +343,11 @@ func (display *MapDisplay) renderPositionOverlay(lvl *level.Level) {
 		} else {
 			imgui.Text("C: -- = --.---")
 		}
+		if hasMusic {
+			imgui.Text(fmt.Sprintf("M: %2d = %s", musicIndex, display.musicZoneLabel(musicIndex)))
+		} else {
+			imgui.Text("M: -- = --")
+		}
 		imgui.End()
 	}
 }
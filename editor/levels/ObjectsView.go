@@ -473,6 +473,7 @@ func (view *ObjectsView) renderPropertyControl(lvl *level.Level, readOnly bool,
 	}
 
 	simplifier.SetSpecialHandler("VariableKey", addVariableKey)
+	simplifier.SetSpecialHandler("VariableSource", addVariableKey)
 	simplifier.SetSpecialHandler("VariableCondition", func() {
 		addVariableKey()
 
@@ -2,6 +2,7 @@ package levels
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/inkyblackness/imgui-go/v3"
 
@@ -24,6 +25,8 @@ type TilesView struct {
 	textCache    *text.Cache
 	textureCache *graphics.TextureCache
 
+	musicZoneLabel MusicZoneLabeler
+
 	guiScale float32
 	registry cmd.Registry
 	model    tilesViewModel
@@ -31,12 +34,15 @@ type TilesView struct {
 
 // NewTilesView returns a new instance.
 func NewTilesView(editor *edit.LevelEditorService,
-	guiScale float32, textCache *text.Cache, textureCache *graphics.TextureCache, registry cmd.Registry) *TilesView {
+	guiScale float32, textCache *text.Cache, textureCache *graphics.TextureCache, registry cmd.Registry,
+	musicZoneLabel MusicZoneLabeler) *TilesView {
 	view := &TilesView{
 		editor:       editor,
 		textCache:    textCache,
 		textureCache: textureCache,
 
+		musicZoneLabel: musicZoneLabel,
+
 		guiScale: guiScale,
 		model:    freshTilesViewModel(),
 		registry: registry,
@@ -182,7 +188,10 @@ func (view *TilesView) renderContent(lvl *level.Level, tiles []*level.TileMapEnt
 		func(newValue int) { view.changeTiles(setSlopeControlTo(slopeControls[newValue])) })
 	values.RenderUnifiedSliderInt(readOnly, "Music Index", musicIndexUnifier,
 		func(u values.Unifier) int { return u.Unified().(int) },
-		func(value int) string { return "%d" },
+		func(value int) string {
+			// The result is a format string for the raw value, so the label must not contain verbs of its own.
+			return fmt.Sprintf("%s - raw: %%d", strings.ReplaceAll(view.musicZoneLabel(value), "%", "%%"))
+		},
 		0, 15,
 		func(newValue int) { view.changeTiles(setMusicIndexTo(newValue)) })
 
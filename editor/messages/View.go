@@ -9,6 +9,7 @@ import (
 	"github.com/inkyblackness/hacked/editor/graphics"
 	"github.com/inkyblackness/hacked/editor/render"
 	"github.com/inkyblackness/hacked/editor/values"
+	"github.com/inkyblackness/hacked/editor/waveform"
 	"github.com/inkyblackness/hacked/ss1/content/audio"
 	"github.com/inkyblackness/hacked/ss1/content/movie"
 	"github.com/inkyblackness/hacked/ss1/content/text"
@@ -54,6 +55,7 @@ type View struct {
 	clipboard         external.Clipboard
 	guiScale          float32
 	commander         cmd.Commander
+	waveform          *waveform.Editor
 
 	model viewModel
 }
@@ -74,6 +76,7 @@ func NewMessagesView(mod *world.Mod, messageCache *text.ElectronicMessageCache,
 		clipboard:         clipboard,
 		guiScale:          guiScale,
 		commander:         commander,
+		waveform:          waveform.NewEditor(guiScale),
 
 		model: freshViewModel(),
 	}
@@ -151,6 +154,10 @@ func (view *View) renderContent() {
 				if imgui.Button("Export") {
 					view.requestExportAudio(sound)
 				}
+				imgui.SameLine()
+				if imgui.Button("Export AVI") {
+					view.requestExportMovie(sound)
+				}
 				if !soundReadOnly {
 					imgui.SameLine()
 					if imgui.Button("Remove") {
@@ -168,6 +175,9 @@ func (view *View) renderContent() {
 			if imgui.Button("Clear") {
 				view.requestClearAudio()
 			}
+			if !sound.Empty() {
+				view.waveform.Render(view.model.currentKey, sound, false, view.requestSetAudio)
+			}
 			imgui.PopID()
 		}
 		imgui.Separator()
@@ -391,15 +401,27 @@ func (view *View) requestExportAudio(sound audio.L8) {
 	external.ExportAudio(view.modalStateMachine, filename, sound)
 }
 
+func (view *View) requestExportMovie(sound audio.L8) {
+	filename := fmt.Sprintf("%05d_%s.avi",
+		view.model.currentKey.ID.Plus(view.model.currentKey.Index).Plus(300).Value(),
+		view.model.currentKey.Lang.String())
+	message, _ := view.currentMessage()
+	var subtitles movie.Subtitles
+	subtitles.PerLanguage[view.model.currentKey.Lang].Entries = []movie.Subtitle{{Text: message.VerboseText}}
+
+	external.ExportMovie(view.modalStateMachine, filename, movie.AVIMovie(0, 0, nil, sound, subtitles))
+}
+
 func (view *View) requestRemoveAudio() {
 	view.requestAudioChange(nil)
 }
 
 func (view *View) requestImportAudio() {
-	external.ImportAudio(view.modalStateMachine, func(sound audio.L8) {
-		movieData := movie.ContainSoundData(sound)
-		view.requestAudioChange(movieData)
-	})
+	external.ImportAudio(view.modalStateMachine, view.requestSetAudio)
+}
+
+func (view *View) requestSetAudio(sound audio.L8) {
+	view.requestAudioChange(movie.ContainSoundData(sound))
 }
 
 func (view *View) requestClearAudio() {
@@ -3,7 +3,6 @@ package movies
 import (
 	"github.com/inkyblackness/imgui-go/v3"
 
-	"github.com/inkyblackness/hacked/ss1/content/movie"
 	"github.com/inkyblackness/hacked/ui/gui"
 )
 
@@ -13,19 +12,19 @@ type compressingStartState struct {
 	machine gui.ModalStateMachine
 	view    *View
 
+	lowRes   bool
 	width    int
 	height   int
-	input    movie.Scene
+	source   sceneSource
 	listener compressionListenerFunc
 }
 
 func (state compressingStartState) Render() {
 	imgui.OpenPopup("Compressing...")
-	task := newCompressionTask(state.input, state.width, state.height)
+	task := newCompressionTask(state.source, state.lowRes, state.width, state.height)
 	state.machine.SetState(&compressingWaitingState{
 		machine:  state.machine,
 		view:     state.view,
-		input:    state.input,
 		listener: state.listener,
 		task:     task,
 	})
@@ -1,9 +1,10 @@
 package movies
 
 import (
+	"fmt"
+
 	"github.com/inkyblackness/imgui-go/v3"
 
-	"github.com/inkyblackness/hacked/ss1/content/movie"
 	"github.com/inkyblackness/hacked/ui/gui"
 )
 
@@ -11,7 +12,6 @@ type compressingWaitingState struct {
 	machine gui.ModalStateMachine
 	view    *View
 
-	input    movie.Scene
 	listener compressionListenerFunc
 	task     *compressionTask
 }
@@ -23,6 +23,15 @@ func (state *compressingWaitingState) Render() {
 			"Yes, your PC is probably capable of recoding HD movies way quicker;\n" +
 			"Sadly, this codec of '94 is quite tricky.")
 
+		progress := state.task.currentProgress()
+		if progress.sceneCount > 0 {
+			imgui.Text(fmt.Sprintf("Scene %d of %d: %s", progress.scene+1, progress.sceneCount, progress.Stage))
+			imgui.ProgressBar(float32(progress.Fraction))
+		} else {
+			imgui.Text("Preparing scenes...")
+			imgui.ProgressBar(0)
+		}
+
 		if imgui.Button("Cancel") {
 			state.task.cancel()
 		}
@@ -2,17 +2,32 @@ package movies
 
 import (
 	"context"
+	"sync"
 
 	"github.com/inkyblackness/hacked/ss1/content/movie"
 )
 
+// sceneSource provides the scenes that shall be compressed.
+type sceneSource func(ctx context.Context) ([]movie.Scene, error)
+
 type compressionTask struct {
+	lowRes     bool
 	width      int
 	height     int
-	input      movie.Scene
+	source     sceneSource
 	ctx        context.Context
 	ctxCancel  context.CancelFunc
 	resultChan chan compressionResult
+
+	progressMutex sync.Mutex
+	progress      compressionProgress
+}
+
+// compressionProgress describes how far a compression task has advanced.
+type compressionProgress struct {
+	scene      int
+	sceneCount int
+	movie.EncodingProgress
 }
 
 type compressionResult interface{}
@@ -21,13 +36,14 @@ type compressionAborted struct{}
 
 type compressionFailed struct{ err error }
 
-type compressionFinished struct{ scene movie.HighResScene }
+type compressionFinished struct{ scenes []movie.CompressedScene }
 
-func newCompressionTask(scene movie.Scene, width, height int) *compressionTask {
+func newCompressionTask(source sceneSource, lowRes bool, width, height int) *compressionTask {
 	task := &compressionTask{
+		lowRes:     lowRes,
 		width:      width,
 		height:     height,
-		input:      scene,
+		source:     source,
 		resultChan: make(chan compressionResult),
 	}
 	task.ctx, task.ctxCancel = context.WithCancel(context.Background())
@@ -37,17 +53,57 @@ func newCompressionTask(scene movie.Scene, width, height int) *compressionTask {
 
 func (task *compressionTask) run() {
 	defer close(task.resultChan)
-	highResScene, err := movie.HighResSceneFrom(task.ctx, task.input, task.width, task.height)
+	compressedScenes, err := task.compress()
 	switch {
 	case task.ctx.Err() != nil:
 		task.resultChan <- compressionAborted{}
 	case err != nil:
 		task.resultChan <- compressionFailed{err: err}
 	default:
-		task.resultChan <- compressionFinished{scene: highResScene}
+		task.resultChan <- compressionFinished{scenes: compressedScenes}
 	}
 }
 
+func (task *compressionTask) compress() ([]movie.CompressedScene, error) {
+	scenes, err := task.source(task.ctx)
+	if err != nil {
+		return nil, err
+	}
+	compressedScenes := make([]movie.CompressedScene, 0, len(scenes))
+	for sceneIndex, scene := range scenes {
+		reportProgress := func(progress movie.EncodingProgress) {
+			task.setProgress(compressionProgress{
+				scene:            sceneIndex,
+				sceneCount:       len(scenes),
+				EncodingProgress: progress,
+			})
+		}
+		var compressedScene movie.CompressedScene
+		if task.lowRes {
+			compressedScene, err = movie.LowResSceneFromWithProgress(task.ctx, scene, task.width, task.height, reportProgress)
+		} else {
+			compressedScene, err = movie.HighResSceneFromWithProgress(task.ctx, scene, task.width, task.height, reportProgress)
+		}
+		if err != nil {
+			return nil, err
+		}
+		compressedScenes = append(compressedScenes, compressedScene)
+	}
+	return compressedScenes, nil
+}
+
+func (task *compressionTask) setProgress(progress compressionProgress) {
+	task.progressMutex.Lock()
+	defer task.progressMutex.Unlock()
+	task.progress = progress
+}
+
+func (task *compressionTask) currentProgress() compressionProgress {
+	task.progressMutex.Lock()
+	defer task.progressMutex.Unlock()
+	return task.progress
+}
+
 func (task *compressionTask) update() compressionResult {
 	select {
 	case result, ok := <-task.resultChan:
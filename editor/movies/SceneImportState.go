@@ -0,0 +1,91 @@
+package movies
+
+import (
+	"path/filepath"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+type sceneImportState struct {
+	machine  gui.ModalStateMachine
+	filename string
+	callback func(options sceneImportOptions)
+
+	options sceneImportOptions
+	opened  bool
+}
+
+type sceneImportOptions struct {
+	movie.SceneImportOptions
+	frameTimeMs int
+}
+
+func newSceneImportState(machine gui.ModalStateMachine, filename string,
+	callback func(options sceneImportOptions)) *sceneImportState {
+	return &sceneImportState{
+		machine:  machine,
+		filename: filename,
+		callback: callback,
+		options: sceneImportOptions{
+			SceneImportOptions: movie.DefaultSceneImportOptions(),
+			frameTimeMs:        100,
+		},
+	}
+}
+
+func (state *sceneImportState) Render() {
+	if !state.opened {
+		state.opened = true
+		imgui.OpenPopup("Import frames")
+	}
+
+	if imgui.BeginPopupModalV("Import frames", nil,
+		imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+		lineHeight := imgui.TextLineHeightWithSpacing()
+		imgui.Text("Importing from " + filepath.Base(state.filename) + ".\n" +
+			"Frames are split into scenes where the picture changes strongly.\n" +
+			"Each scene receives its own palette.")
+		imgui.Separator()
+		imgui.PushItemWidth(lineHeight * 12)
+		threshold := int(state.options.SceneCutThreshold * 100)
+		if gui.StepSliderIntV("Scene Cut Threshold", &threshold, 1, 101, "%d%%") {
+			state.options.SceneCutThreshold = float64(threshold) / 100
+		}
+		if imgui.BeginCombo("Dithering", state.options.Dithering.String()) {
+			for _, mode := range bitmap.DitherModes() {
+				if imgui.SelectableV(mode.String(), mode == state.options.Dithering, 0, imgui.Vec2{}) {
+					state.options.Dithering = mode
+				}
+			}
+			imgui.EndCombo()
+		}
+		gui.StepSliderIntV("Sequence Frame Time", &state.options.frameTimeMs, 10, 1000, "%d ms")
+		imgui.PopItemWidth()
+		imgui.Text("A threshold above 100% keeps all frames in one scene.\n" +
+			"The frame time applies to image sequences; animations keep their delays.")
+		imgui.Separator()
+		if imgui.Button("OK") {
+			state.close()
+			state.callback(state.options)
+		}
+		imgui.SameLine()
+		if imgui.Button("Cancel") {
+			state.close()
+		}
+		imgui.EndPopup()
+	} else {
+		state.close()
+	}
+}
+
+func (state *sceneImportState) HandleFiles(names []string) {
+}
+
+func (state *sceneImportState) close() {
+	state.machine.SetState(nil)
+	imgui.CloseCurrentPopup()
+}
@@ -0,0 +1,121 @@
+package movies
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/bitmap/apng"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+)
+
+var sequencePattern = regexp.MustCompile(`^(.*?)(\d+)(\.[^.]+)$`)
+
+// loadSourceFrames loads the frames that shall be imported, based on the given file.
+// Animated GIF and APNG files provide their frames and delays. For any other picture, all files in the same
+// directory that share the name pattern with a running number are loaded as an image sequence.
+func loadSourceFrames(filename string, sequenceFrameTime time.Duration) ([]movie.SourceFrame, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, []byte("GIF8")) {
+		anim, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if len(anim.Image) > 1 {
+			frames := make([]movie.SourceFrame, 0, len(anim.Image))
+			for index, img := range bitmap.ComposeGIFFrames(anim) {
+				delay := time.Duration(anim.Delay[index]) * 10 * time.Millisecond // nolint: durationcheck
+				frames = append(frames, movie.SourceFrame{Image: img, DisplayTime: delay})
+			}
+			return frames, nil
+		}
+	}
+	if bytes.HasPrefix(data, []byte("\x89PNG")) {
+		anim, err := apng.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if len(anim.Image) > 1 {
+			frames := make([]movie.SourceFrame, 0, len(anim.Image))
+			for index, img := range anim.Image {
+				frames = append(frames, movie.SourceFrame{Image: img, DisplayTime: anim.Delay[index]})
+			}
+			return frames, nil
+		}
+	}
+	return loadImageSequence(filename, sequenceFrameTime)
+}
+
+func loadImageSequence(filename string, frameTime time.Duration) ([]movie.SourceFrame, error) {
+	filenames := sequenceFilenames(filename)
+	frames := make([]movie.SourceFrame, 0, len(filenames))
+	for _, sequenceFilename := range filenames {
+		img, err := loadImage(sequenceFilename)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, movie.SourceFrame{Image: img, DisplayTime: frameTime})
+	}
+	return frames, nil
+}
+
+func loadImage(filename string) (image.Image, error) {
+	reader, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	if strings.EqualFold(filepath.Ext(filename), ".png") {
+		return png.Decode(reader)
+	}
+	img, _, err := image.Decode(reader)
+	return img, err
+}
+
+// sequenceFilenames returns the sorted list of files that belong to the same sequence as the given file.
+// If the given file has no running number, it is the only entry.
+func sequenceFilenames(filename string) []string {
+	dirname, basename := filepath.Split(filename)
+	match := sequencePattern.FindStringSubmatch(basename)
+	if match == nil {
+		return []string{filename}
+	}
+	prefix, extension := match[1], match[3]
+	dirEntries, err := ioutil.ReadDir(dirname)
+	if err != nil {
+		return []string{filename}
+	}
+	type numberedFile struct {
+		name   string
+		number int
+	}
+	var files []numberedFile
+	for _, entry := range dirEntries {
+		entryMatch := sequencePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || (entryMatch == nil) ||
+			(entryMatch[1] != prefix) || !strings.EqualFold(entryMatch[3], extension) {
+			continue
+		}
+		number, _ := strconv.Atoi(entryMatch[2])
+		files = append(files, numberedFile{name: filepath.Join(dirname, entry.Name()), number: number})
+	}
+	sort.Slice(files, func(a, b int) bool { return files[a].number < files[b].number })
+	result := make([]string, len(files))
+	for index, file := range files {
+		result[index] = file.name
+	}
+	return result
+}
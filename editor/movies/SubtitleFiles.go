@@ -0,0 +1,102 @@
+package movies
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/asticode/go-astisub"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+)
+
+// subtitleFormat describes a file format for subtitles that can be imported and exported.
+type subtitleFormat struct {
+	title      string
+	extensions []string
+	read       func(io.Reader) (*astisub.Subtitles, error)
+	write      func(astisub.Subtitles, io.Writer) error
+}
+
+var subtitleFormats = []subtitleFormat{
+	{title: "SubRip", extensions: []string{"srt"}, read: astisub.ReadFromSRT, write: astisub.Subtitles.WriteToSRT},
+	{title: "WebVTT", extensions: []string{"vtt"}, read: astisub.ReadFromWebVTT, write: astisub.Subtitles.WriteToWebVTT},
+	{title: "SubStation Alpha", extensions: []string{"ass", "ssa"}, read: astisub.ReadFromSSA, write: astisub.Subtitles.WriteToSSA},
+	{title: "TTML", extensions: []string{"ttml", "xml"}, read: astisub.ReadFromTTML, write: astisub.Subtitles.WriteToTTML},
+}
+
+func subtitleFormatFor(filename string) (subtitleFormat, bool) {
+	extension := strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	for _, format := range subtitleFormats {
+		for _, formatExtension := range format.extensions {
+			if formatExtension == extension {
+				return format, true
+			}
+		}
+	}
+	return subtitleFormat{}, false
+}
+
+func subtitleTypeInfo() []external.TypeInfo {
+	var all []string
+	types := make([]external.TypeInfo, 0, len(subtitleFormats)+1)
+	for _, format := range subtitleFormats {
+		all = append(all, format.extensions...)
+	}
+	types = append(types, external.TypeInfo{Title: "Subtitle files (*." + strings.Join(all, ", *.") + ")", Extensions: all})
+	for _, format := range subtitleFormats {
+		types = append(types, external.TypeInfo{
+			Title:      format.title + " files (*." + strings.Join(format.extensions, ", *.") + ")",
+			Extensions: format.extensions,
+		})
+	}
+	return types
+}
+
+// subtitlesFromFile converts the items of a subtitle file into a list.
+// As the movie only knows the start of each text, gaps between items are filled with empty entries.
+func subtitlesFromFile(file *astisub.Subtitles) movie.SubtitleList {
+	var list movie.SubtitleList
+	var lastEnd time.Duration
+	for index, item := range file.Items {
+		if (index > 0) && (lastEnd < item.StartAt) {
+			list.Entries = append(list.Entries, movie.Subtitle{Timestamp: lastEnd})
+		}
+		var lines []string
+		for _, line := range item.Lines {
+			var parts []string
+			for _, lineItem := range line.Items {
+				parts = append(parts, lineItem.Text)
+			}
+			lines = append(lines, strings.Join(parts, " "))
+		}
+		list.Entries = append(list.Entries, movie.Subtitle{Timestamp: item.StartAt, Text: strings.Join(lines, "\n")})
+		lastEnd = item.EndAt
+	}
+	if (len(file.Items) > 0) && (lastEnd > file.Items[len(file.Items)-1].StartAt) {
+		list.Entries = append(list.Entries, movie.Subtitle{Timestamp: lastEnd})
+	}
+	return list
+}
+
+// subtitlesToFile converts a list into the items of a subtitle file.
+// Each text lasts until the next entry starts; Empty entries only end the previous text.
+func subtitlesToFile(list movie.SubtitleList, movieEnd time.Duration) *astisub.Subtitles {
+	file := astisub.NewSubtitles()
+	for index, entry := range list.Entries {
+		if len(entry.Text) == 0 {
+			continue
+		}
+		item := &astisub.Item{
+			StartAt: entry.Timestamp,
+			EndAt:   list.EndOf(index, movieEnd),
+		}
+		for _, text := range strings.Split(entry.Text, "\n") {
+			item.Lines = append(item.Lines, astisub.Line{Items: []astisub.LineItem{{Text: text}}})
+		}
+		file.Items = append(file.Items, item)
+	}
+	return file
+}
@@ -0,0 +1,382 @@
+package movies
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+const (
+	timelineRulerHeight    = 18
+	timelineSceneHeight    = 14
+	timelineSubtitleHeight = 40
+	timelineMaxZoom        = 64
+	timelineWheelZoom      = 2.0
+	timelineMargin         = 2 * time.Second
+	timelineMinTickSpacing = 60
+)
+
+var (
+	timelineBackgroundColor = imgui.Packed(color.RGBA{R: 0x10, G: 0x10, B: 0x10, A: 0xFF})
+	timelineAudioColor      = imgui.Packed(color.RGBA{R: 0x18, G: 0x30, B: 0x18, A: 0xFF})
+	timelineTickColor       = imgui.Packed(color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xFF})
+	timelineSceneColor      = imgui.Packed(color.RGBA{R: 0xE0, G: 0x90, B: 0x20, A: 0xFF})
+	timelineEntryColor      = imgui.Packed(color.RGBA{R: 0x30, G: 0x50, B: 0x90, A: 0xFF})
+	timelineSelectedColor   = imgui.Packed(color.RGBA{R: 0x50, G: 0x80, B: 0xE0, A: 0xFF})
+	timelineBorderColor     = imgui.Packed(color.RGBA{R: 0xC0, G: 0xC0, B: 0xC0, A: 0xFF})
+	timelineTextColor       = imgui.Packed(color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF})
+	timelineCursorColor     = imgui.Packed(color.RGBA{R: 0xFF, G: 0x40, B: 0x40, A: 0xFF})
+)
+
+// subtitleTimeline displays the subtitles of one language along the time of the movie.
+// Entries can be selected, retimed by dragging, and edited. Modifications are reported as a new list.
+type subtitleTimeline struct {
+	guiScale float32
+	cp       text.Codepage
+
+	key  resource.Key
+	lang resource.Language
+
+	zoom   float64
+	scroll time.Duration
+	cursor time.Duration
+
+	selected   int
+	dragging   bool
+	dragOffset time.Duration
+	preview    movie.SubtitleList
+
+	editIndex  int
+	editText   string
+	textActive bool
+}
+
+func newSubtitleTimeline(guiScale float32, cp text.Codepage) *subtitleTimeline {
+	return &subtitleTimeline{
+		guiScale:  guiScale,
+		cp:        cp,
+		zoom:      1,
+		selected:  -1,
+		editIndex: -1,
+	}
+}
+
+// timelineInfo describes the movie the subtitles belong to.
+type timelineInfo struct {
+	audioEnd    time.Duration
+	sceneStarts []time.Duration
+	movieEnd    time.Duration
+}
+
+func (timeline *subtitleTimeline) render(key resource.Key, lang resource.Language, list movie.SubtitleList,
+	info timelineInfo, apply func(movie.SubtitleList)) {
+	if (key != timeline.key) || (lang != timeline.lang) {
+		timeline.key = key
+		timeline.lang = lang
+		timeline.zoom = 1
+		timeline.scroll = 0
+		timeline.cursor = 0
+		timeline.selected = -1
+		timeline.dragging = false
+	}
+	if timeline.selected >= len(list.Entries) {
+		timeline.selected = len(list.Entries) - 1
+	}
+	if timeline.dragging && (len(timeline.preview.Entries) != len(list.Entries)) {
+		timeline.dragging = false
+	}
+	imgui.PushID("timeline")
+	timeline.renderDisplay(list, info, apply)
+	timeline.renderControls(list, info, apply)
+	imgui.PopID()
+}
+
+func (timeline *subtitleTimeline) length(list movie.SubtitleList, info timelineInfo) time.Duration {
+	length := info.movieEnd
+	if count := len(list.Entries); count > 0 && list.Entries[count-1].Timestamp+timelineMargin > length {
+		length = list.Entries[count-1].Timestamp + timelineMargin
+	}
+	if length <= 0 {
+		length = timelineMargin
+	}
+	return length
+}
+
+func (timeline *subtitleTimeline) renderDisplay(list movie.SubtitleList, info timelineInfo,
+	apply func(movie.SubtitleList)) {
+	length := timeline.length(list, info)
+	size := imgui.Vec2{
+		X: imgui.ContentRegionAvail().X,
+		Y: (timelineRulerHeight + timelineSceneHeight + timelineSubtitleHeight) * timeline.guiScale,
+	}
+	if size.X < 1 {
+		size.X = 1
+	}
+	topLeft := imgui.CursorScreenPos()
+	imgui.InvisibleButtonV("display", size, imgui.ButtonFlagsMouseButtonLeft)
+	visible := time.Duration(float64(length) / timeline.zoom)
+	timeline.limitScroll(length, visible)
+	perPixel := float64(visible) / float64(size.X)
+	timeAt := func(x float32) time.Duration {
+		at := timeline.scroll + time.Duration(float64(x-topLeft.X)*perPixel)
+		if at < 0 {
+			return 0
+		}
+		return at
+	}
+	xOf := func(at time.Duration) float32 {
+		return topLeft.X + float32(float64(at-timeline.scroll)/perPixel)
+	}
+	subtitleTop := topLeft.Y + (timelineRulerHeight+timelineSceneHeight)*timeline.guiScale
+
+	if imgui.IsItemHovered() {
+		mouse := imgui.MousePos()
+		_, wheel := imgui.CurrentIO().MouseWheel()
+		if wheel != 0 {
+			anchor := timeAt(mouse.X)
+			timeline.zoom = math.Max(1, math.Min(timelineMaxZoom, timeline.zoom*math.Pow(timelineWheelZoom, float64(wheel))))
+			visible = time.Duration(float64(length) / timeline.zoom)
+			timeline.scroll = anchor - time.Duration(float64(mouse.X-topLeft.X)*float64(visible)/float64(size.X))
+			timeline.limitScroll(length, visible)
+		}
+		if imgui.IsMouseClicked(0) {
+			at := timeAt(mouse.X)
+			timeline.cursor = at
+			timeline.selected = -1
+			if mouse.Y >= subtitleTop {
+				timeline.selected = entryAt(list, at, info.movieEnd)
+			}
+			if timeline.selected >= 0 {
+				timeline.dragging = true
+				timeline.dragOffset = at - list.Entries[timeline.selected].Timestamp
+				timeline.preview = list
+			}
+		}
+	}
+	if timeline.dragging {
+		if imgui.IsMouseDown(0) {
+			timeline.preview = list.WithTimestamp(timeline.selected, timeAt(imgui.MousePos().X)-timeline.dragOffset)
+		} else {
+			timeline.dragging = false
+			if timeline.preview.Entries[timeline.selected].Timestamp != list.Entries[timeline.selected].Timestamp {
+				apply(timeline.preview)
+			}
+		}
+	}
+
+	shown := list
+	if timeline.dragging {
+		shown = timeline.preview
+	}
+	bottomRight := topLeft.Plus(size)
+	drawList := imgui.WindowDrawList()
+	drawList.AddRectFilled(topLeft, bottomRight, timelineBackgroundColor)
+	if info.audioEnd > timeline.scroll {
+		drawList.AddRectFilled(imgui.Vec2{X: topLeft.X, Y: subtitleTop},
+			imgui.Vec2{X: float32(math.Min(float64(xOf(info.audioEnd)), float64(bottomRight.X))), Y: bottomRight.Y},
+			timelineAudioColor)
+	}
+	timeline.renderRuler(drawList, topLeft, bottomRight, perPixel, xOf)
+
+	sceneTop := topLeft.Y + timelineRulerHeight*timeline.guiScale
+	for index, start := range info.sceneStarts {
+		x := xOf(start)
+		if (x < topLeft.X) || (x > bottomRight.X) {
+			continue
+		}
+		drawList.AddLine(imgui.Vec2{X: x, Y: sceneTop}, imgui.Vec2{X: x, Y: bottomRight.Y}, timelineSceneColor)
+		drawList.AddText(imgui.Vec2{X: x + 2, Y: sceneTop}, timelineSceneColor, fmt.Sprintf("%02d", index))
+	}
+
+	for index, entry := range shown.Entries {
+		left := xOf(entry.Timestamp)
+		right := xOf(shown.EndOf(index, length))
+		if (right < topLeft.X) || (left > bottomRight.X) {
+			continue
+		}
+		left = float32(math.Max(float64(left), float64(topLeft.X)))
+		right = float32(math.Min(float64(right), float64(bottomRight.X)))
+		blockTop := imgui.Vec2{X: left, Y: subtitleTop + 2}
+		blockBottom := imgui.Vec2{X: right, Y: bottomRight.Y - 2}
+		if len(entry.Text) > 0 {
+			fill := timelineEntryColor
+			if index == timeline.selected {
+				fill = timelineSelectedColor
+			}
+			drawList.AddRectFilled(blockTop, blockBottom, fill)
+			drawList.AddText(imgui.Vec2{X: left + 2, Y: subtitleTop + 4}, timelineTextColor,
+				fittedText(firstLine(entry.Text), right-left-4))
+		}
+		if index == timeline.selected {
+			drawList.AddRect(blockTop, blockBottom, timelineBorderColor)
+		}
+		drawList.AddLine(imgui.Vec2{X: left, Y: subtitleTop}, imgui.Vec2{X: left, Y: bottomRight.Y}, timelineBorderColor)
+	}
+
+	if cursorX := xOf(timeline.cursor); (cursorX >= topLeft.X) && (cursorX <= bottomRight.X) {
+		drawList.AddLine(imgui.Vec2{X: cursorX, Y: topLeft.Y}, imgui.Vec2{X: cursorX, Y: bottomRight.Y}, timelineCursorColor)
+	}
+
+	if visible < length {
+		scrollMs := int(timeline.scroll / time.Millisecond)
+		imgui.PushItemWidth(-1)
+		if gui.StepSliderIntV("##scroll", &scrollMs, 0, int((length-visible)/time.Millisecond), "") {
+			timeline.scroll = time.Duration(scrollMs) * time.Millisecond
+		}
+		imgui.PopItemWidth()
+	}
+}
+
+func (timeline *subtitleTimeline) renderRuler(drawList imgui.DrawList, topLeft, bottomRight imgui.Vec2,
+	perPixel float64, xOf func(time.Duration) float32) {
+	tick := 100 * time.Millisecond
+	for _, candidate := range []time.Duration{
+		250 * time.Millisecond, 500 * time.Millisecond,
+		time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second, time.Minute,
+	} {
+		if float64(tick)/perPixel >= timelineMinTickSpacing*float64(timeline.guiScale) {
+			break
+		}
+		tick = candidate
+	}
+	rulerBottom := topLeft.Y + timelineRulerHeight*timeline.guiScale
+	for at := (timeline.scroll / tick) * tick; ; at += tick {
+		x := xOf(at)
+		if x > bottomRight.X {
+			break
+		}
+		if x < topLeft.X {
+			continue
+		}
+		drawList.AddLine(imgui.Vec2{X: x, Y: rulerBottom - 4}, imgui.Vec2{X: x, Y: rulerBottom}, timelineTickColor)
+		drawList.AddText(imgui.Vec2{X: x + 2, Y: topLeft.Y}, timelineTickColor, formatTimestamp(at))
+	}
+	drawList.AddLine(imgui.Vec2{X: topLeft.X, Y: rulerBottom}, imgui.Vec2{X: bottomRight.X, Y: rulerBottom}, timelineTickColor)
+}
+
+func (timeline *subtitleTimeline) limitScroll(length, visible time.Duration) {
+	if timeline.scroll > length-visible {
+		timeline.scroll = length - visible
+	}
+	if timeline.scroll < 0 {
+		timeline.scroll = 0
+	}
+}
+
+func (timeline *subtitleTimeline) renderControls(list movie.SubtitleList, info timelineInfo,
+	apply func(movie.SubtitleList)) {
+	if timeline.selected < 0 {
+		imgui.Text(fmt.Sprintf("Cursor: %s - %d entries", formatTimestamp(timeline.cursor), len(list.Entries)))
+	} else {
+		imgui.Text(fmt.Sprintf("Entry %d of %d", timeline.selected+1, len(list.Entries)))
+	}
+
+	if imgui.Button("Zoom In") {
+		timeline.zoom = math.Min(timelineMaxZoom, timeline.zoom*2)
+	}
+	imgui.SameLine()
+	if imgui.Button("Zoom Out") {
+		timeline.zoom = math.Max(1, timeline.zoom/2)
+	}
+	imgui.SameLine()
+	if imgui.Button("Fit") {
+		timeline.zoom = 1
+	}
+	imgui.SameLine()
+	if imgui.Button("Add at Cursor") {
+		newList, index := list.Insert(timeline.cursor, "")
+		timeline.selected = index
+		apply(newList)
+	}
+	if timeline.selected < 0 {
+		return
+	}
+	imgui.SameLine()
+	if imgui.Button("Remove") {
+		apply(list.Remove(timeline.selected))
+		timeline.selected = -1
+		return
+	}
+	imgui.SameLine()
+	if imgui.Button("Split") {
+		apply(list.Split(timeline.selected, info.movieEnd))
+	}
+	if timeline.selected+1 < len(list.Entries) {
+		imgui.SameLine()
+		if imgui.Button("Merge with Next") {
+			apply(list.Merge(timeline.selected))
+		}
+	}
+
+	entry := list.Entries[timeline.selected]
+	imgui.PushItemWidth(-150 * timeline.guiScale)
+	timestampMs := int32(entry.Timestamp / time.Millisecond)
+	if imgui.InputIntV("Start (ms)", &timestampMs, 10, 100, imgui.InputTextFlagsEnterReturnsTrue) {
+		apply(list.WithTimestamp(timeline.selected, time.Duration(timestampMs)*time.Millisecond))
+	}
+	imgui.PopItemWidth()
+
+	if (timeline.editIndex != timeline.selected) || !timeline.textActive {
+		timeline.editIndex = timeline.selected
+		timeline.editText = entry.Text
+	}
+	imgui.InputTextMultilineV("Text", &timeline.editText,
+		imgui.Vec2{X: -150 * timeline.guiScale, Y: 60 * timeline.guiScale}, imgui.InputTextFlagsNoUndoRedo, nil)
+	timeline.textActive = imgui.IsItemActive()
+	if imgui.IsItemDeactivatedAfterEdit() && (timeline.editText != entry.Text) {
+		apply(list.WithText(timeline.selected, timeline.editText))
+	}
+	renderCodepageWarning(timeline.cp, timeline.editText)
+}
+
+func entryAt(list movie.SubtitleList, at time.Duration, movieEnd time.Duration) int {
+	for index := len(list.Entries) - 1; index >= 0; index-- {
+		if (list.Entries[index].Timestamp <= at) && (at < list.EndOf(index, movieEnd) || (index == len(list.Entries)-1)) {
+			return index
+		}
+	}
+	return -1
+}
+
+func firstLine(value string) string {
+	for index, r := range value {
+		if r == '\n' {
+			return value[:index] + " ..."
+		}
+	}
+	return value
+}
+
+func fittedText(value string, width float32) string {
+	runes := []rune(value)
+	for (len(runes) > 0) && (imgui.CalcTextSize(string(runes), false, 0).X > width) {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes)
+}
+
+func formatTimestamp(at time.Duration) string {
+	return fmt.Sprintf("%d:%02d.%03d", int(at/time.Minute), int((at%time.Minute)/time.Second), int((at%time.Second)/time.Millisecond))
+}
+
+// renderCodepageWarning shows the characters of the text that can not be stored in the game.
+func renderCodepageWarning(cp text.Codepage, value string) {
+	unencodable := text.UnencodableRunes(cp, value)
+	if len(unencodable) == 0 {
+		return
+	}
+	imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1, Y: 0, Z: 0, W: 1})
+	imgui.Text(fmt.Sprintf("Unsupported characters: %q", string(unencodable)))
+	imgui.PopStyleColor()
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("The game can not display these characters.\nThey will be saved as '?'.")
+	}
+}
@@ -1,23 +1,26 @@
 package movies
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"image/color"
 	"image/gif"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/asticode/go-astisub"
 	"github.com/inkyblackness/imgui-go/v3"
 
 	"github.com/inkyblackness/hacked/editor/external"
 	"github.com/inkyblackness/hacked/editor/graphics"
 	"github.com/inkyblackness/hacked/editor/render"
+	"github.com/inkyblackness/hacked/editor/waveform"
 	"github.com/inkyblackness/hacked/ss1/content/audio"
 	"github.com/inkyblackness/hacked/ss1/content/bitmap"
 	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/text"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/resource"
@@ -29,15 +32,36 @@ import (
 type movieInfo struct {
 	title     string
 	multilang bool
+	lowRes    bool
+}
+
+func (info movieInfo) width() int {
+	if info.lowRes {
+		return movie.LowResDefaultWidth
+	}
+	return movie.HighResDefaultWidth
+}
+
+func (info movieInfo) height() int {
+	if info.lowRes {
+		return movie.LowResDefaultHeight
+	}
+	return movie.HighResDefaultHeight
 }
 
 var knownMovies = map[resource.ID]movieInfo{
-	ids.MovieIntro: {title: "Intro", multilang: true},
-	ids.MovieDeath: {title: "Death", multilang: false},
-	ids.MovieEnd:   {title: "End", multilang: false},
+	ids.MovieIntro:       {title: "Intro", multilang: true},
+	ids.MovieDeath:       {title: "Death", multilang: false},
+	ids.MovieEnd:         {title: "End", multilang: false},
+	ids.LowResMovieIntro: {title: "Intro (low-res)", multilang: true, lowRes: true},
+	ids.LowResMovieDeath: {title: "Death (low-res)", multilang: false, lowRes: true},
+	ids.LowResMovieEnd:   {title: "End (low-res)", multilang: false, lowRes: true},
 }
 
-var knownMoviesOrder = []resource.ID{ids.MovieIntro, ids.MovieDeath, ids.MovieEnd}
+var knownMoviesOrder = []resource.ID{
+	ids.MovieIntro, ids.MovieDeath, ids.MovieEnd,
+	ids.LowResMovieIntro, ids.LowResMovieDeath, ids.LowResMovieEnd,
+}
 
 // View provides edit controls for animations.
 type View struct {
@@ -47,17 +71,20 @@ type View struct {
 	frameCacheKey graphics.FrameCacheKey
 
 	movieService undoable.MovieService
+	cp           text.Codepage
 
 	modalStateMachine gui.ModalStateMachine
 	guiScale          float32
 	commander         cmd.Commander
+	waveform          *waveform.Editor
+	timeline          *subtitleTimeline
 
 	model viewModel
 }
 
 // NewMoviesView returns a new instance.
 func NewMoviesView(mod *world.Mod, frameCache *graphics.FrameCache,
-	movieService undoable.MovieService,
+	movieService undoable.MovieService, cp text.Codepage,
 	modalStateMachine gui.ModalStateMachine, guiScale float32, commander cmd.Commander) *View {
 	view := &View{
 		mod: mod,
@@ -66,10 +93,13 @@ func NewMoviesView(mod *world.Mod, frameCache *graphics.FrameCache,
 		frameCacheKey: frameCache.AllocateKey(),
 
 		movieService: movieService,
+		cp:           cp,
 
 		modalStateMachine: modalStateMachine,
 		guiScale:          guiScale,
 		commander:         commander,
+		waveform:          waveform.NewEditor(guiScale),
+		timeline:          newSubtitleTimeline(guiScale, cp),
 
 		model: freshViewModel(),
 	}
@@ -95,6 +125,16 @@ func (view *View) Render() {
 		}
 		imgui.End()
 	}
+	if view.model.windowOpen && view.model.timelineOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 800 * view.guiScale, Y: 260 * view.guiScale}, imgui.ConditionFirstUseEver)
+		title := fmt.Sprintf("Movie Subtitles: %s (%s)###MovieSubtitles",
+			knownMovies[view.model.currentKey.ID].title, view.model.currentSubtitleLang.String())
+		if imgui.BeginV(title, &view.model.timelineOpen, imgui.WindowFlagsNoCollapse) {
+			view.timeline.render(view.model.currentKey, view.model.currentSubtitleLang, view.currentSubtitles(),
+				view.currentTimelineInfo(), view.requestSetSubtitles)
+		}
+		imgui.End()
+	}
 }
 
 func (view *View) renderContent() {
@@ -127,6 +167,10 @@ func (view *View) renderContent() {
 			imgui.LabelText("Language", "(not localized)")
 		}
 
+		if imgui.Button("Export AVI") {
+			view.requestExportMovie()
+		}
+
 		imgui.Separator()
 
 		view.renderProperties()
@@ -164,6 +208,10 @@ func (view *View) renderContent() {
 		view.requestImportScene("")
 	}
 	imgui.SameLine()
+	if imgui.Button("Import Frames") {
+		view.requestImportFrames("")
+	}
+	imgui.SameLine()
 	if imgui.Button("Export") {
 		view.requestExportScene()
 	}
@@ -180,13 +228,14 @@ func (view *View) renderContent() {
 				frame = &scene.Frames[view.model.currentFrame]
 			}
 			if frame != nil {
+				info := knownMovies[view.model.currentKey.ID]
 				// This code updates the texture every render cycle. In case of performance loss, this is a point to optimize.
-				view.frameCache.SetTexture(view.frameCacheKey, 600, 300, frame.Pixels, &scene.Palette)
+				view.frameCache.SetTexture(view.frameCacheKey, uint16(info.width()), uint16(info.height()), frame.Pixels, &scene.Palette)
 
 				render.FrameImage("Frame", view.frameCache, view.frameCacheKey,
 					imgui.Vec2{
-						X: float32(movie.HighResDefaultWidth) * view.guiScale,
-						Y: float32(movie.HighResDefaultHeight) * view.guiScale,
+						X: float32(info.width()) * view.guiScale,
+						Y: float32(info.height()) * view.guiScale,
 					})
 			}
 
@@ -231,12 +280,17 @@ func (view *View) renderAudioProperties() {
 	if !sound.Empty() {
 		imgui.SameLine()
 		if imgui.Button("Export") {
-			view.requestExportAudio(sound)
+			view.requestExportAudio(sound, "wav")
+		}
+		imgui.SameLine()
+		if imgui.Button("Export VOC") {
+			view.requestExportAudio(sound, "voc")
 		}
 		imgui.SameLine()
 		if imgui.Button("Clear") {
 			view.requestClearAudio()
 		}
+		view.waveform.Render(view.model.currentKey, sound, false, view.requestSetAudio)
 	}
 	imgui.PopID()
 }
@@ -253,8 +307,20 @@ func (view *View) renderSubtitlesProperties() {
 		}
 		imgui.EndCombo()
 	}
+	if imgui.BeginCombo("Sub Format", subtitleFormats[view.model.subtitleFormat].title) {
+		for index, format := range subtitleFormats {
+			if imgui.SelectableV(format.title, index == view.model.subtitleFormat, 0, imgui.Vec2{}) {
+				view.model.subtitleFormat = index
+			}
+		}
+		imgui.EndCombo()
+	}
 	sub := view.currentSubtitles()
 	imgui.Text(fmt.Sprintf("%d lines", len(sub.Entries)))
+	if imgui.Button("Timeline") {
+		view.model.timelineOpen = true
+	}
+	imgui.SameLine()
 	if imgui.Button("Import") {
 		view.requestImportSubtitles()
 	}
@@ -268,6 +334,11 @@ func (view *View) renderSubtitlesProperties() {
 			view.requestClearSubtitles()
 		}
 	}
+	var allText strings.Builder
+	for _, entry := range sub.Entries {
+		allText.WriteString(entry.Text)
+	}
+	renderCodepageWarning(view.cp, allText.String())
 	imgui.PopID()
 }
 
@@ -307,31 +378,66 @@ func (view *View) currentSound() audio.L8 {
 	return view.movieService.Audio(view.model.currentKey)
 }
 
-func (view *View) requestExportAudio(sound audio.L8) {
-	filename := fmt.Sprintf("%s_%s.wav", knownMovies[view.model.currentKey.ID].title, view.model.currentKey.Lang.String())
+func (view *View) requestExportAudio(sound audio.L8, extension string) {
+	filename := fmt.Sprintf("%s_%s.%s", knownMovies[view.model.currentKey.ID].title, view.model.currentKey.Lang.String(), extension)
 
 	external.ExportAudio(view.modalStateMachine, filename, sound)
 }
 
 func (view *View) requestImportAudio() {
-	external.ImportAudio(view.modalStateMachine, func(sound audio.L8) {
-		view.movieService.RequestSetAudio(view.model.currentKey, sound, view.restoreFunc())
-	})
+	external.ImportAudio(view.modalStateMachine, view.requestSetAudio)
+}
+
+func (view *View) requestSetAudio(sound audio.L8) {
+	view.movieService.RequestSetAudio(view.model.currentKey, sound, view.restoreFunc())
 }
 
 func (view *View) requestClearAudio() {
 	view.movieService.RequestSetAudio(view.model.currentKey, audio.L8{}, view.restoreFunc())
 }
 
+func (view *View) requestExportMovie() {
+	info := knownMovies[view.model.currentKey.ID]
+	filename := fmt.Sprintf("%s_%s.avi", info.title, view.model.currentKey.Lang.String())
+	var subtitles movie.Subtitles
+	for _, lang := range resource.Languages() {
+		subtitles.PerLanguage[lang] = view.movieService.Subtitles(view.model.currentKey, lang)
+	}
+	aviMovie := movie.AVIMovie(info.width(), info.height(),
+		view.movieService.Video(view.model.currentKey), view.currentSound(), subtitles)
+
+	external.ExportMovie(view.modalStateMachine, filename, aviMovie)
+}
+
 func (view *View) currentSubtitles() movie.SubtitleList {
 	return view.movieService.Subtitles(view.model.currentKey, view.model.currentSubtitleLang)
 }
 
+// currentTimelineInfo returns the scene starts and the time at which both video and audio have finished.
+func (view *View) currentTimelineInfo() timelineInfo {
+	var info timelineInfo
+	info.audioEnd = time.Duration(float64(view.currentSound().Duration()) * float64(time.Second))
+	var videoEnd time.Duration
+	for _, scene := range view.movieService.Video(view.model.currentKey) {
+		info.sceneStarts = append(info.sceneStarts, videoEnd)
+		for _, frame := range scene.Frames {
+			videoEnd += frame.DisplayTime
+		}
+	}
+	info.movieEnd = videoEnd
+	if info.audioEnd > info.movieEnd {
+		info.movieEnd = info.audioEnd
+	}
+	return info
+}
+
 func (view View) requestExportSubtitles() {
-	filename := fmt.Sprintf("%s_%s.srt", knownMovies[view.model.currentKey.ID].title, view.model.currentSubtitleLang.String())
+	format := subtitleFormats[view.model.subtitleFormat]
+	filename := fmt.Sprintf("%s_%s.%s", knownMovies[view.model.currentKey.ID].title,
+		view.model.currentSubtitleLang.String(), format.extensions[0])
 	info := "File to be written: " + filename
 	var exportTo func(string)
-	currentSubtitles := view.currentSubtitles()
+	file := subtitlesToFile(view.currentSubtitles(), view.currentTimelineInfo().movieEnd)
 
 	exportTo = func(dirname string) {
 		writer, err := os.Create(filepath.Join(dirname, filename))
@@ -341,24 +447,7 @@ func (view View) requestExportSubtitles() {
 		}
 		defer func() { _ = writer.Close() }()
 
-		sub := astisub.NewSubtitles()
-
-		var lastItem *astisub.Item
-		for _, entry := range currentSubtitles.Entries {
-			var item astisub.Item
-			var line astisub.Line
-			line.Items = append(line.Items, astisub.LineItem{Text: entry.Text})
-			item.Lines = []astisub.Line{line}
-			item.StartAt = entry.Timestamp
-			item.EndAt = item.StartAt
-			if lastItem != nil {
-				lastItem.EndAt = item.StartAt
-			}
-			lastItem = &item
-			sub.Items = append(sub.Items, lastItem)
-		}
-
-		err = sub.WriteToSRT(writer)
+		err = format.write(*file, writer)
 		if err != nil {
 			external.Export(view.modalStateMachine, "Could not export subtitles.\n"+info, exportTo, true)
 			return
@@ -369,11 +458,16 @@ func (view View) requestExportSubtitles() {
 }
 
 func (view *View) requestImportSubtitles() {
-	info := "File must be an .SRT file."
-	types := []external.TypeInfo{{Title: "Subtitle files (*.srt)", Extensions: []string{"srt"}}}
+	info := "File must be an SRT, WebVTT, SSA/ASS or TTML file."
+	types := subtitleTypeInfo()
 	var fileHandler func(string)
 
 	fileHandler = func(filename string) {
+		format, known := subtitleFormatFor(filename)
+		if !known {
+			external.Import(view.modalStateMachine, "File type not supported.\n"+info, types, fileHandler, true)
+			return
+		}
 		reader, err := os.Open(filename)
 		if err != nil {
 			external.Import(view.modalStateMachine, "Could not open file.\n"+info, types, fileHandler, true)
@@ -381,41 +475,31 @@ func (view *View) requestImportSubtitles() {
 		}
 		defer func() { _ = reader.Close() }()
 
-		subtitles, err := astisub.ReadFromSRT(reader)
+		file, err := format.read(reader)
 		if err != nil {
-			external.Import(view.modalStateMachine, "File not recognized as SRT.\n"+info, types, fileHandler, true)
+			external.Import(view.modalStateMachine, "File not recognized as "+format.title+".\n"+info,
+				types, fileHandler, true)
 			return
 		}
-		var newSubtitles movie.SubtitleList
-		for _, item := range subtitles.Items {
-			var newEntry movie.Subtitle
-			newEntry.Timestamp = item.StartAt
-			for _, line := range item.Lines {
-				for _, lineItem := range line.Items {
-					if len(newEntry.Text) > 0 {
-						newEntry.Text += "\n"
-					}
-					newEntry.Text += lineItem.Text
-				}
-			}
-			newSubtitles.Entries = append(newSubtitles.Entries, newEntry)
-		}
-
-		view.movieService.RequestSetSubtitles(view.model.currentKey, view.model.currentSubtitleLang,
-			newSubtitles, view.restoreFunc())
+		view.requestSetSubtitles(subtitlesFromFile(file))
 	}
 
 	external.Import(view.modalStateMachine, info, types, fileHandler, false)
 }
 
-func (view *View) requestClearSubtitles() {
+func (view *View) requestSetSubtitles(list movie.SubtitleList) {
 	view.movieService.RequestSetSubtitles(view.model.currentKey, view.model.currentSubtitleLang,
-		movie.SubtitleList{}, view.restoreFunc())
+		list, view.restoreFunc())
+}
+
+func (view *View) requestClearSubtitles() {
+	view.requestSetSubtitles(movie.SubtitleList{})
 }
 
 func (view *View) requestImportScene(returningInfo string) {
+	movieInfo := knownMovies[view.model.currentKey.ID]
 	info := fmt.Sprintf("File must be an animated GIF file in size %dx%d.",
-		movie.HighResDefaultWidth, movie.HighResDefaultHeight)
+		movieInfo.width(), movieInfo.height())
 	types := []external.TypeInfo{{Title: "Animation files (*.gif)", Extensions: []string{"gif"}}}
 	var fileHandler func(string)
 
@@ -432,7 +516,7 @@ func (view *View) requestImportScene(returningInfo string) {
 			return
 		}
 
-		if (data.Config.Width != movie.HighResDefaultWidth) || (data.Config.Height != movie.HighResDefaultHeight) {
+		if (data.Config.Width != movieInfo.width()) || (data.Config.Height != movieInfo.height()) {
 			external.Import(view.modalStateMachine, info, types, fileHandler, true)
 			return
 		}
@@ -500,36 +584,71 @@ func (view *View) requestImportScene(returningInfo string) {
 			scene.Frames[index].Pixels = framebufferSnapshot()
 		}
 
-		view.compressAndAddScene(scene, data.Config.Width, data.Config.Height)
+		view.compressAndAddScene(scene, movieInfo.lowRes, data.Config.Width, data.Config.Height)
+	}
+
+	external.Import(view.modalStateMachine, returningInfo+info, types, fileHandler, false)
+}
+
+func (view *View) requestImportFrames(returningInfo string) {
+	movieInfo := knownMovies[view.model.currentKey.ID]
+	info := fmt.Sprintf("File must be an animated GIF or PNG file, or part of a numbered image sequence,\n"+
+		"in size %dx%d. Select any image of a sequence to import all of it.",
+		movieInfo.width(), movieInfo.height())
+	types := []external.TypeInfo{{Title: "Image files (*.png, *.gif)", Extensions: []string{"png", "gif"}}}
+
+	fileHandler := func(filename string) {
+		view.modalStateMachine.SetState(newSceneImportState(view.modalStateMachine, filename,
+			func(options sceneImportOptions) {
+				source := func(ctx context.Context) ([]movie.Scene, error) {
+					frames, err := loadSourceFrames(filename, time.Duration(options.frameTimeMs)*time.Millisecond)
+					if err != nil {
+						return nil, err
+					}
+					return movie.ScenesFromTrueColor(ctx, frames, movieInfo.width(), movieInfo.height(),
+						options.SceneImportOptions)
+				}
+				view.compressAndAddScenes(source, movieInfo.lowRes, movieInfo.width(), movieInfo.height(),
+					view.requestImportFrames)
+			}))
 	}
 
 	external.Import(view.modalStateMachine, returningInfo+info, types, fileHandler, false)
 }
 
-func (view *View) compressAndAddScene(scene movie.Scene, width, height int) {
+func (view *View) compressAndAddScene(scene movie.Scene, lowRes bool, width, height int) {
+	view.compressAndAddScenes(func(context.Context) ([]movie.Scene, error) {
+		return []movie.Scene{scene}, nil
+	}, lowRes, width, height, view.requestImportScene)
+}
+
+func (view *View) compressAndAddScenes(source sceneSource, lowRes bool, width, height int, retry func(string)) {
 	view.modalStateMachine.SetState(&compressingStartState{
-		machine:  view.modalStateMachine,
-		view:     view,
-		width:    width,
-		height:   height,
-		input:    scene,
-		listener: view.onCompressionResult,
+		machine: view.modalStateMachine,
+		view:    view,
+		lowRes:  lowRes,
+		width:   width,
+		height:  height,
+		source:  source,
+		listener: func(result compressionResult) {
+			view.onCompressionResult(result, retry)
+		},
 	})
 }
 
-func (view *View) onCompressionResult(result compressionResult) {
+func (view *View) onCompressionResult(result compressionResult, retry func(string)) {
 	switch typedResult := result.(type) {
 	case compressionAborted:
 	case compressionFinished:
-		view.requestAddScene(typedResult.scene)
+		view.requestAddScenes(typedResult.scenes)
 	case compressionFailed:
-		view.requestImportScene("Could not compress. Follow recommendations and retry.\n" +
+		retry("Could not compress. Follow recommendations and retry.\n" +
 			"Technical details:\n" + typedResult.err.Error() + "\n\n")
 	}
 }
 
-func (view *View) requestAddScene(scene movie.HighResScene) {
-	view.movieService.RequestAddScene(view.model.currentKey, scene, view.restoreFunc())
+func (view *View) requestAddScenes(scenes []movie.CompressedScene) {
+	view.movieService.RequestAddScenes(view.model.currentKey, scenes, view.restoreFunc())
 }
 
 func (view *View) requestExportScene() {
@@ -540,6 +659,7 @@ func (view *View) requestExportScene() {
 	info := "File to be written: " + filename
 	var exportTo func(string)
 
+	movieInfo := knownMovies[view.model.currentKey.ID]
 	scenes := view.movieService.Video(view.model.currentKey)
 	var scene *movie.Scene
 	if view.model.currentScene >= 0 && view.model.currentScene < len(scenes) {
@@ -561,8 +681,8 @@ func (view *View) requestExportScene() {
 		colorPalette := scene.Palette.ColorPalette(false)
 		data := gif.GIF{
 			Config: image.Config{
-				Width:      600,
-				Height:     300,
+				Width:      movieInfo.width(),
+				Height:     movieInfo.height(),
 				ColorModel: colorPalette,
 			},
 			LoopCount: -1,
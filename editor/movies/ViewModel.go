@@ -11,6 +11,8 @@ type viewModel struct {
 
 	currentKey          resource.Key
 	currentSubtitleLang resource.Language
+	subtitleFormat      int
+	timelineOpen        bool
 	currentScene        int
 	currentFrame        int
 
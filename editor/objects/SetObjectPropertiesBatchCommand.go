@@ -0,0 +1,30 @@
+package objects
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+type setObjectPropertiesBatchCommand struct {
+	model *viewModel
+
+	oldProperties map[object.Triple]object.Properties
+	newProperties map[object.Triple]object.Properties
+}
+
+func (command setObjectPropertiesBatchCommand) Do(modder world.Modder) error {
+	return command.perform(modder, command.newProperties)
+}
+
+func (command setObjectPropertiesBatchCommand) Undo(modder world.Modder) error {
+	return command.perform(modder, command.oldProperties)
+}
+
+func (command setObjectPropertiesBatchCommand) perform(modder world.Modder, properties map[object.Triple]object.Properties) error {
+	for triple, prop := range properties {
+		modder.SetObjectProperties(triple, prop)
+	}
+
+	command.model.restoreFocus = true
+	return nil
+}
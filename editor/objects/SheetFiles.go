@@ -0,0 +1,91 @@
+package objects
+
+import (
+	"encoding/csv"
+	"os"
+	"strings"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/object/objprop"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+const sheetColumnName = "Name"
+
+var sheetTypes = []external.TypeInfo{{Title: "Spreadsheet files (*.csv)", Extensions: []string{"csv"}}}
+
+func (view *View) requestExportClassSheet(class object.Class) {
+	external.SaveFile(view.modalStateMachine, sheetTypes, func(filename string) error {
+		if !strings.HasSuffix(strings.ToLower(filename), ".csv") {
+			filename += ".csv"
+		}
+		table := view.mod.ObjectProperties()
+		triples := table.TriplesInClass(class)
+		sheet := objprop.ClassSheet(table, class)
+		for index, row := range sheet {
+			name := sheetColumnName
+			if index > 0 {
+				name = view.objectName(triples[index-1], resource.LangDefault, true)
+			}
+			sheet[index] = append(row[:3:3], append([]string{name}, row[3:]...)...)
+		}
+		return writeSheet(filename, sheet)
+	})
+}
+
+func writeSheet(filename string, sheet [][]string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(file)
+	err = writer.WriteAll(sheet)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+func (view *View) requestImportClassSheet(class object.Class) {
+	external.LoadFile(view.modalStateMachine, sheetTypes, func(filename string) error {
+		sheet, err := readSheet(filename)
+		if err != nil {
+			return err
+		}
+		table := view.mod.ObjectProperties()
+		changes, err := objprop.ApplyClassSheet(table, class, sheet)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		command := setObjectPropertiesBatchCommand{
+			model:         &view.model,
+			oldProperties: make(map[object.Triple]object.Properties),
+			newProperties: changes,
+		}
+		for triple := range changes {
+			prop, err := table.ForObject(triple)
+			if err != nil {
+				return err
+			}
+			command.oldProperties[triple] = prop.Clone()
+		}
+		view.commander.Queue(command)
+		return nil
+	})
+}
+
+func readSheet(filename string) ([][]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
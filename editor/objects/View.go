@@ -29,6 +29,7 @@ type View struct {
 	cp           text.Codepage
 	imageCache   *graphics.TextureCache
 	paletteCache *graphics.PaletteCache
+	frameCache   *graphics.FrameCache
 
 	modalStateMachine gui.ModalStateMachine
 	clipboard         external.Clipboard
@@ -41,7 +42,7 @@ type View struct {
 // NewView returns a new instance.
 func NewView(mod *world.Mod, textCache *text.Cache, cp text.Codepage,
 	imageCache *graphics.TextureCache, paletteCache *graphics.PaletteCache,
-	modalStateMachine gui.ModalStateMachine,
+	frameCache *graphics.FrameCache, modalStateMachine gui.ModalStateMachine,
 	clipboard external.Clipboard, guiScale float32, commander cmd.Commander) *View {
 	view := &View{
 		mod:          mod,
@@ -49,6 +50,7 @@ func NewView(mod *world.Mod, textCache *text.Cache, cp text.Codepage,
 		cp:           cp,
 		imageCache:   imageCache,
 		paletteCache: paletteCache,
+		frameCache:   frameCache,
 
 		modalStateMachine: modalStateMachine,
 		clipboard:         clipboard,
@@ -108,6 +110,22 @@ func (view *View) renderContent() {
 		}
 
 		readOnly := !view.mod.HasModifiableObjectProperties()
+		if imgui.Button("Export Class...") {
+			view.requestExportClassSheet(view.model.currentObject.Class)
+		}
+		if imgui.IsItemHovered() {
+			imgui.SetTooltip("Writes the properties of all objects of this class as a spreadsheet (CSV).")
+		}
+		if !readOnly {
+			imgui.SameLine()
+			if imgui.Button("Import Class...") {
+				view.requestImportClassSheet(view.model.currentObject.Class)
+			}
+			if imgui.IsItemHovered() {
+				imgui.SetTooltip("Reads a spreadsheet (CSV) as written by the export, and applies all changed values.\n" +
+					"Empty cells and unknown columns are ignored. Values are checked against their valid ranges.")
+			}
+		}
 		properties, propErr := view.mod.ObjectProperties().ForObject(view.model.currentObject)
 
 		imgui.Separator()
@@ -617,7 +635,7 @@ func (view *View) requestImportBitmap() {
 		}
 		return palette.Palette(), nil
 	}
-	external.ImportImage(view.modalStateMachine, paletteRetriever, func(bmp bitmap.Bitmap) {
+	external.ImportImage(view.modalStateMachine, view.frameCache, paletteRetriever, func(bmp bitmap.Bitmap) {
 		view.requestSetBitmap(bmp)
 	})
 }
@@ -0,0 +1,112 @@
+package project
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+func (view *View) renderConflicts() {
+	if !view.model.conflictsWindowOpen {
+		return
+	}
+	imgui.SetNextWindowSizeV(imgui.Vec2{X: 700 * view.guiScale, Y: 400 * view.guiScale}, imgui.ConditionFirstUseEver)
+	if imgui.BeginV("Manifest Override Conflicts", &view.model.conflictsWindowOpen, imgui.WindowFlagsNoCollapse) {
+		view.renderConflictsContent(view.currentConflicts())
+	}
+	imgui.End()
+}
+
+// currentConflicts returns the conflict report, which is determined again if the manifest entries changed.
+func (view *View) currentConflicts() world.ConflictReport {
+	manifest := view.service.Mod().World()
+	if (view.conflicts != nil) && (len(view.conflicts.EntryIDs) == manifest.EntryCount()) {
+		for index, id := range view.conflicts.EntryIDs {
+			entry, _ := manifest.Entry(index)
+			if entry.ID != id {
+				view.conflicts = nil
+				break
+			}
+		}
+	} else {
+		view.conflicts = nil
+	}
+	if view.conflicts == nil {
+		report := manifest.Conflicts()
+		view.conflicts = &report
+	}
+	return *view.conflicts
+}
+
+func (view *View) renderConflictsContent(report world.ConflictReport) {
+	imgui.Text(fmt.Sprintf("%d resource(s) provided by more than one entry, %d list(s) partially overridden, %d property table(s) replaced.",
+		len(report.Resources), report.PartialCount(), len(report.Properties)))
+	if imgui.Button("Refresh") {
+		view.conflicts = nil
+	}
+	imgui.SameLine()
+	if imgui.Button("Export...") {
+		view.startExportingConflicts(report)
+	}
+	imgui.SameLine()
+	imgui.Checkbox("Only partial overrides", &view.model.conflictsOnlyPartial)
+
+	if len(report.Properties) > 0 {
+		imgui.Separator()
+		for _, conflict := range report.Properties {
+			imgui.Text(fmt.Sprintf("%s: used from %s, replacing %d other table(s)",
+				conflict.Table, report.EntryName(conflict.Winner), len(conflict.Entries)-1))
+		}
+	}
+
+	imgui.Separator()
+	headers := []string{"Resource", "Provided by", "Used from"}
+	imgui.ColumnsV(len(headers), "conflicts", true)
+	for _, header := range headers {
+		imgui.Text(header)
+		imgui.NextColumn()
+	}
+	imgui.Separator()
+	for _, conflict := range report.Resources {
+		partial := conflict.IsPartial()
+		if view.model.conflictsOnlyPartial && !partial {
+			continue
+		}
+		label := fmt.Sprintf("%s (%s)", conflict.ID, conflict.Language)
+		if conflict.List {
+			label += " list"
+		}
+		imgui.Text(label)
+		imgui.NextColumn()
+		for _, entry := range conflict.Entries {
+			imgui.Text(report.EntryName(entry))
+		}
+		imgui.NextColumn()
+		if partial {
+			imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 1.0, Z: 0.0, W: 1.0})
+			imgui.Text("partial: " + report.BlockDistribution(conflict))
+			imgui.PopStyleColor()
+		} else if conflict.List {
+			imgui.Text("blocks: " + report.BlockDistribution(conflict))
+		} else {
+			imgui.Text(report.EntryName(conflict.Winner))
+		}
+		imgui.NextColumn()
+	}
+	imgui.Columns()
+}
+
+func (view *View) startExportingConflicts(report world.ConflictReport) {
+	external.SaveFile(view.modalStateMachine, []external.TypeInfo{{Title: "Text files (*.txt)", Extensions: []string{"txt"}}},
+		func(filename string) error {
+			if filepath.Ext(filename) == "" {
+				filename += ".txt"
+			}
+			return ioutil.WriteFile(filename, []byte(report.Text()), 0640)
+		})
+}
@@ -0,0 +1,74 @@
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/ss1/resource/lgres"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+// ShowDamagedFiles opens a window with the reports of all damaged files of the project, if there are any.
+func (view *View) ShowDamagedFiles() {
+	reports := make(map[world.FileLocation]lgres.SalvageReport)
+	manifest := view.service.Mod().World()
+	for index := 0; index < manifest.EntryCount(); index++ {
+		entry, _ := manifest.Entry(index)
+		for location, report := range entry.Salvaged {
+			reports[location] = report
+		}
+	}
+	for location, report := range view.service.SalvagedModFiles() {
+		reports[location] = report
+	}
+	view.showSalvageReports(reports)
+}
+
+func (view *View) showSalvageReports(reports map[world.FileLocation]lgres.SalvageReport) {
+	if len(reports) == 0 {
+		return
+	}
+	filenames := make([]string, 0, len(reports))
+	reportsByFilename := make(map[string]lgres.SalvageReport)
+	for location, report := range reports {
+		filename := filepath.Join(location.DirPath, location.Name)
+		filenames = append(filenames, filename)
+		reportsByFilename[filename] = report
+	}
+	sort.Strings(filenames)
+	var summary strings.Builder
+	var details strings.Builder
+	for _, filename := range filenames {
+		report := reportsByFilename[filename]
+		line := fmt.Sprintf("%s: %d resource(s) recovered, %d lost, %d issue(s)",
+			filename, len(report.Salvaged), report.Lost(), len(report.Issues))
+		summary.WriteString(line + "\n")
+		details.WriteString(line + "\n" + report.String() + "\n")
+	}
+	view.model.salvageSummary = summary.String()
+	view.model.salvageDetails = details.String()
+	view.model.salvageWindowOpen = true
+}
+
+func (view *View) renderSalvageReports() {
+	if !view.model.salvageWindowOpen {
+		return
+	}
+	imgui.SetNextWindowSizeV(imgui.Vec2{X: 600 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionFirstUseEver)
+	if imgui.BeginV("Damaged Files", &view.model.salvageWindowOpen, imgui.WindowFlagsNoCollapse) {
+		imgui.Text("The following files are damaged. Only the recovered resources were loaded.")
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.0, Z: 0.0, W: 1.0})
+		imgui.Text(view.model.salvageSummary)
+		imgui.PopStyleColor()
+		imgui.Separator()
+		if imgui.BeginChildV("details", imgui.Vec2{X: -1, Y: 0}, true, imgui.WindowFlagsHorizontalScrollbar) {
+			imgui.Text(view.model.salvageDetails)
+		}
+		imgui.EndChild()
+	}
+	imgui.End()
+}
@@ -2,9 +2,11 @@ package project
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/inkyblackness/imgui-go/v3"
 
+	"github.com/inkyblackness/hacked/editor/external"
 	"github.com/inkyblackness/hacked/ss1/edit"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
 	"github.com/inkyblackness/hacked/ss1/world"
@@ -20,6 +22,8 @@ type View struct {
 	commander         cmd.Registry
 
 	model viewModel
+
+	conflicts *world.ConflictReport
 }
 
 // NewView creates a new instance for the project display.
@@ -62,6 +66,8 @@ func (view *View) Render() {
 		}
 		imgui.End()
 	}
+	view.renderConflicts()
+	view.renderSalvageReports()
 }
 
 func (view *View) renderContent() {
@@ -90,6 +96,8 @@ func (view *View) renderContent() {
 	}
 	imgui.EndGroup()
 
+	view.renderDescriptionFiles()
+
 	imgui.Text("Static World Data")
 	imgui.BeginChildV("ManifestEntries", imgui.Vec2{X: -100 * view.guiScale, Y: 0}, true, 0)
 	manifest := view.service.Mod().World()
@@ -115,9 +123,93 @@ func (view *View) renderContent() {
 	if imgui.ButtonV("Remove", imgui.Vec2{X: -1, Y: 0}) {
 		view.requestRemoveManifestEntry()
 	}
+	if imgui.ButtonV("Conflicts...", imgui.Vec2{X: -1, Y: 0}) {
+		view.model.conflictsWindowOpen = true
+		view.conflicts = nil
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Shows which resources and property tables are provided by more than one entry.")
+	}
 	imgui.EndGroup()
 }
 
+func (view *View) renderDescriptionFiles() {
+	imgui.Text("Data Interpreter Overrides")
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Schema files that change how object and game state data is interpreted.\n" +
+			"They are applied in order on top of the built-in descriptions.\n" +
+			"Use \"Export...\" for a reference of all built-in descriptions.")
+	}
+	filenames := view.service.DescriptionFiles()
+	imgui.BeginChildV("DescriptionFiles", imgui.Vec2{X: -100 * view.guiScale, Y: imgui.TextLineHeightWithSpacing() * 4.5}, true, 0)
+	for index, filename := range filenames {
+		if imgui.SelectableV(filename, view.model.selectedDescriptionFile == index, 0, imgui.Vec2{}) {
+			view.model.selectedDescriptionFile = index
+		}
+	}
+	imgui.EndChild()
+	imgui.SameLine()
+	imgui.BeginGroup()
+	if imgui.ButtonV("Add...##description", imgui.Vec2{X: -1, Y: 0}) {
+		view.startAddingDescriptionFile()
+	}
+	if imgui.ButtonV("Remove##description", imgui.Vec2{X: -1, Y: 0}) {
+		view.removeDescriptionFile()
+	}
+	if imgui.ButtonV("Reload", imgui.Vec2{X: -1, Y: 0}) {
+		view.applyDescriptionFiles(filenames)
+	}
+	if imgui.ButtonV("Export...", imgui.Vec2{X: -1, Y: 0}) {
+		view.startExportingDescriptions()
+	}
+	imgui.EndGroup()
+	if err := view.service.DescriptionFilesError(); err != nil {
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.0, Z: 0.0, W: 1.0})
+		imgui.Text("Using built-in descriptions: " + err.Error())
+		imgui.PopStyleColor()
+	}
+}
+
+func (view *View) startAddingDescriptionFile() {
+	external.LoadFile(view.modalStateMachine, descriptionFileTypes(), func(filename string) error {
+		_, err := edit.LoadDescriptionSchema(filename)
+		if err != nil {
+			return err
+		}
+		view.applyDescriptionFiles(append(view.service.DescriptionFiles(), filename))
+		view.model.selectedDescriptionFile = len(view.service.DescriptionFiles()) - 1
+		return nil
+	})
+}
+
+func (view *View) removeDescriptionFile() {
+	filenames := view.service.DescriptionFiles()
+	at := view.model.selectedDescriptionFile
+	if (at < 0) || (at >= len(filenames)) {
+		return
+	}
+	view.applyDescriptionFiles(append(filenames[:at], filenames[at+1:]...))
+	view.model.selectedDescriptionFile = -1
+}
+
+func (view *View) applyDescriptionFiles(filenames []string) {
+	// A problem is kept by the service and shown with the list of files.
+	_ = view.service.SetDescriptionFiles(filenames)
+}
+
+func (view *View) startExportingDescriptions() {
+	external.SaveFile(view.modalStateMachine, descriptionFileTypes(), func(filename string) error {
+		if filepath.Ext(filename) == "" {
+			filename += ".json"
+		}
+		return edit.SaveBuiltInDescriptions(filename)
+	})
+}
+
+func descriptionFileTypes() []external.TypeInfo {
+	return []external.TypeInfo{{Title: "Description schema files (*.json)", Extensions: []string{"json"}}}
+}
+
 func (view *View) startLoadingMod() {
 	view.modalStateMachine.SetState(&loadModStartState{
 		machine: view.modalStateMachine,
@@ -180,6 +272,7 @@ func (view *View) tryAddManifestEntryFrom(names []string) error {
 	}
 
 	view.requestAddManifestEntry(entry)
+	view.showSalvageReports(entry.Salvaged)
 	return nil
 }
 
@@ -213,7 +306,12 @@ func (view *View) requestRemoveManifestEntry() {
 }
 
 func (view *View) tryLoadModFrom(names []string) error {
-	return view.service.TryLoadModFrom(names)
+	err := view.service.TryLoadModFrom(names)
+	if err != nil {
+		return err
+	}
+	view.showSalvageReports(view.service.SalvagedModFiles())
+	return nil
 }
 
 func (view *View) requestSaveMod(modPath string) {
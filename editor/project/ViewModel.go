@@ -4,11 +4,22 @@ type viewModel struct {
 	restoreFocus          bool
 	windowOpen            bool
 	selectedManifestEntry int
+
+	selectedDescriptionFile int
+
+	conflictsWindowOpen  bool
+	conflictsOnlyPartial bool
+
+	salvageWindowOpen bool
+	salvageSummary    string
+	salvageDetails    string
 }
 
 func freshViewModel() viewModel {
 	return viewModel{
 		windowOpen:            false,
 		selectedManifestEntry: -1,
+
+		selectedDescriptionFile: -1,
 	}
 }
@@ -0,0 +1,385 @@
+package rawdata
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/raw"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+const bytesPerRow = 16
+
+// unknownLevelResources are the level-specific resources of which the content is not understood.
+var unknownLevelResources = []struct {
+	title string
+	id    int
+}{
+	{title: "Unused41", id: lvlids.Unused41},
+	{title: "Unknown48", id: lvlids.Unknown48},
+	{title: "Unknown49", id: lvlids.Unknown49},
+	{title: "Unknown50", id: lvlids.Unknown50},
+	{title: "Unknown52", id: lvlids.Unknown52},
+}
+
+var (
+	colorUndefined = imgui.Vec4{X: 1.0, Y: 0.4, Z: 0.4, W: 1.0}
+	colorPartial   = imgui.Vec4{X: 1.0, Y: 0.7, Z: 0.4, W: 1.0}
+	colorFieldEven = imgui.Vec4{X: 0.6, Y: 0.9, Z: 1.0, W: 1.0}
+	colorFieldOdd  = imgui.Vec4{X: 0.7, Y: 1.0, Z: 0.7, W: 1.0}
+	colorVarying   = imgui.Vec4{X: 1.0, Y: 1.0, Z: 0.0, W: 1.0}
+	colorMissing   = imgui.Vec4{X: 1.0, Y: 1.0, Z: 1.0, W: 0.5}
+)
+
+// View is the raw data inspector. It shows the bytes of any resource block, together with what is known about them.
+type View struct {
+	service        *edit.RawDataService
+	levelSelection *edit.LevelSelectionService
+
+	guiScale float32
+
+	model viewModel
+
+	cached cachedBlock
+}
+
+// cachedBlock keeps the overlay of the last shown block, as it is costly to determine.
+type cachedBlock struct {
+	lang    resource.Language
+	id      resource.ID
+	index   int
+	data    []byte
+	overlay raw.Overlay
+}
+
+// NewView returns a new instance.
+func NewView(service *edit.RawDataService, levelSelection *edit.LevelSelectionService, guiScale float32) *View {
+	view := &View{
+		service:        service,
+		levelSelection: levelSelection,
+
+		guiScale: guiScale,
+
+		model: freshViewModel(),
+	}
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *View) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Render renders the view.
+func (view *View) Render() {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 1000 * view.guiScale, Y: 600 * view.guiScale}, imgui.ConditionFirstUseEver)
+		if imgui.BeginV("Raw Data Inspector", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent()
+		}
+		imgui.End()
+	}
+}
+
+func (view *View) renderContent() {
+	view.renderSelection()
+	imgui.Separator()
+
+	data, err := view.service.Block(view.model.lang, view.model.resourceID, view.model.blockIndex)
+	if err != nil {
+		imgui.Text(fmt.Sprintf("Block not available: %v", err))
+		return
+	}
+	overlay := view.overlayFor(data)
+	if view.model.cursor >= len(data) {
+		view.model.cursor = 0
+	}
+	view.renderSummary(data, overlay)
+
+	if imgui.BeginChildV("hex", imgui.Vec2{X: 620 * view.guiScale, Y: 0}, true, imgui.WindowFlagsHorizontalScrollbar) {
+		view.renderHex(data, overlay)
+	}
+	imgui.EndChild()
+	imgui.SameLine()
+	if imgui.BeginChildV("details", imgui.Vec2{X: -1, Y: 0}, true, 0) {
+		view.renderDetails(data, overlay)
+	}
+	imgui.EndChild()
+}
+
+func (view *View) renderSelection() {
+	imgui.PushItemWidth(-200 * view.guiScale)
+	if imgui.InputTextV("Resource ID (hex)", &view.model.resourceIDText,
+		imgui.InputTextFlagsCharsHexadecimal|imgui.InputTextFlagsEnterReturnsTrue, nil) {
+		value, err := strconv.ParseUint(view.model.resourceIDText, 16, 16)
+		if err == nil {
+			view.model.selectResource(resource.ID(value))
+		} else {
+			view.model.resourceIDText = view.model.resourceID.String()
+		}
+	}
+	if imgui.BeginCombo("Language", view.model.lang.String()) {
+		languages := append([]resource.Language{resource.LangAny}, resource.Languages()...)
+		for _, lang := range languages {
+			if imgui.SelectableV(lang.String(), lang == view.model.lang, 0, imgui.Vec2{}) {
+				view.model.lang = lang
+			}
+		}
+		imgui.EndCombo()
+	}
+	blockCount := view.service.BlockCount(view.model.lang, view.model.resourceID)
+	if blockCount > 1 {
+		gui.StepSliderInt("Block", &view.model.blockIndex, 0, blockCount-1)
+	} else {
+		imgui.LabelText("Block", fmt.Sprintf("%d of %d", view.model.blockIndex, blockCount))
+	}
+	imgui.PopItemWidth()
+
+	levelIndex := view.levelSelection.CurrentLevelID()
+	imgui.Text(fmt.Sprintf("Level %d:", levelIndex))
+	for _, entry := range unknownLevelResources {
+		imgui.SameLine()
+		if imgui.Button(entry.title) {
+			view.model.selectResource(edit.LevelResourceID(levelIndex, entry.id))
+		}
+	}
+	imgui.SameLine()
+	if imgui.Button("Game State") {
+		view.model.selectResource(ids.GameState)
+	}
+}
+
+func (view *View) overlayFor(data []byte) raw.Overlay {
+	if (view.cached.lang != view.model.lang) || (view.cached.id != view.model.resourceID) ||
+		(view.cached.index != view.model.blockIndex) || (view.cached.data == nil) || !bytes.Equal(view.cached.data, data) {
+		view.cached = cachedBlock{
+			lang:    view.model.lang,
+			id:      view.model.resourceID,
+			index:   view.model.blockIndex,
+			data:    data,
+			overlay: view.service.Overlay(view.model.resourceID, view.model.blockIndex, data),
+		}
+	}
+	return view.cached.overlay
+}
+
+func (view *View) renderSummary(data []byte, overlay raw.Overlay) {
+	summary := fmt.Sprintf("Size: %d bytes", len(data))
+	if overlay.Undefined == nil {
+		summary += " - structure not known"
+	} else {
+		undefinedBytes := 0
+		for _, mask := range overlay.Undefined {
+			if mask != 0 {
+				undefinedBytes++
+			}
+		}
+		summary += fmt.Sprintf(" - %d known fields, %d bytes with undefined bits", len(overlay.Fields), undefinedBytes)
+	}
+	imgui.Text(summary)
+}
+
+func (view *View) renderHex(data []byte, overlay raw.Overlay) {
+	cellSize := imgui.CalcTextSize("FF", false, 0)
+	rowCount := (len(data) + bytesPerRow - 1) / bytesPerRow
+	var clipper imgui.ListClipper
+	clipper.Begin(rowCount)
+	for clipper.Step() {
+		for row := clipper.DisplayStart; row < clipper.DisplayEnd; row++ {
+			rowStart := row * bytesPerRow
+			imgui.Text(fmt.Sprintf("%06X", rowStart))
+			var ascii strings.Builder
+			for column := 0; column < bytesPerRow; column++ {
+				offset := rowStart + column
+				imgui.SameLine()
+				if offset >= len(data) {
+					imgui.Dummy(cellSize)
+					continue
+				}
+				view.renderHexCell(data, overlay, offset, cellSize)
+				ascii.WriteByte(printable(data[offset]))
+			}
+			imgui.SameLine()
+			imgui.Text(ascii.String())
+		}
+	}
+}
+
+func (view *View) renderHexCell(data []byte, overlay raw.Overlay, offset int, cellSize imgui.Vec2) {
+	color, hasColor := cellColor(overlay, offset)
+	if hasColor {
+		imgui.PushStyleColor(imgui.StyleColorText, color)
+	}
+	if imgui.SelectableV(fmt.Sprintf("%02X##%d", data[offset], offset), offset == view.model.cursor, 0, cellSize) {
+		view.model.cursor = offset
+		view.model.editProblem = ""
+	}
+	if hasColor {
+		imgui.PopStyleColor()
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip(fmt.Sprintf("Offset 0x%X (%d)\n%s", offset, offset, fieldDescription(overlay, offset)))
+	}
+}
+
+func cellColor(overlay raw.Overlay, offset int) (imgui.Vec4, bool) {
+	if overlay.Undefined == nil {
+		return imgui.Vec4{}, false
+	}
+	undefined := overlay.UndefinedBits(offset)
+	fieldIndex := overlay.FieldAt(offset)
+	switch {
+	case undefined == 0xFF:
+		return colorUndefined, true
+	case undefined != 0:
+		return colorPartial, true
+	case fieldIndex%2 == 0:
+		return colorFieldEven, true
+	default:
+		return colorFieldOdd, true
+	}
+}
+
+func fieldDescription(overlay raw.Overlay, offset int) string {
+	if overlay.Undefined == nil {
+		return "(structure not known)"
+	}
+	fieldIndex := overlay.FieldAt(offset)
+	text := "(undefined)"
+	if fieldIndex >= 0 {
+		field := overlay.Fields[fieldIndex]
+		text = fmt.Sprintf("%s [%d bytes at 0x%X]", field.Name, field.Count, field.Start)
+	}
+	if undefined := overlay.UndefinedBits(offset); undefined != 0 {
+		text += fmt.Sprintf("\nUndefined bits: %08b", undefined)
+	}
+	return text
+}
+
+func printable(value byte) byte {
+	if (value < 0x20) || (value >= 0x7F) {
+		return '.'
+	}
+	return value
+}
+
+func (view *View) renderDetails(data []byte, overlay raw.Overlay) {
+	cursor := view.model.cursor
+	imgui.Text(fmt.Sprintf("Offset 0x%X (%d)", cursor, cursor))
+	imgui.Text(fieldDescription(overlay, cursor))
+	imgui.Separator()
+
+	imgui.PushItemWidth(-100 * view.guiScale)
+	for index, interpretation := range raw.Interpretations {
+		value, available := interpretation.Read(data, cursor)
+		if !available {
+			imgui.LabelText(interpretation.String(), "-")
+			continue
+		}
+		text := fmt.Sprintf("%d", value)
+		imgui.PushIDInt(index)
+		if imgui.InputTextV(interpretation.String(), &text, imgui.InputTextFlagsEnterReturnsTrue, nil) {
+			view.requestSetValue(data, interpretation, text)
+		}
+		imgui.PopID()
+	}
+	imgui.PopItemWidth()
+	if len(view.model.editProblem) > 0 {
+		imgui.PushStyleColor(imgui.StyleColorText, colorUndefined)
+		imgui.Text(view.model.editProblem)
+		imgui.PopStyleColor()
+	}
+
+	if (overlay.Undefined != nil) && imgui.TreeNodeV("Undefined Ranges", imgui.TreeNodeFlagsFramed) {
+		for index, field := range overlay.UndefinedRanges() {
+			label := fmt.Sprintf("0x%X: %d bytes##%d", field.Start, field.Count, index)
+			if imgui.SelectableV(label, (cursor >= field.Start) && (cursor < field.Start+field.Count), 0, imgui.Vec2{}) {
+				view.model.cursor = field.Start
+			}
+		}
+		imgui.TreePop()
+	}
+
+	_, levelResource, isLevel := edit.LevelResourceOf(view.model.resourceID)
+	if isLevel && imgui.TreeNodeV("Across Levels", imgui.TreeNodeFlagsFramed|imgui.TreeNodeFlagsDefaultOpen) {
+		view.renderAcrossLevels(levelResource)
+		imgui.TreePop()
+	}
+}
+
+func (view *View) requestSetValue(data []byte, interpretation raw.Interpretation, text string) {
+	value, err := strconv.ParseInt(strings.TrimSpace(text), 0, 64)
+	if err != nil {
+		view.model.editProblem = fmt.Sprintf("Not a number: %q", text)
+		return
+	}
+	newData := make([]byte, len(data))
+	copy(newData, data)
+	err = interpretation.Write(newData, view.model.cursor, value)
+	if err == nil {
+		err = view.service.SetBlock(view.model.lang, view.model.resourceID, view.model.blockIndex, newData)
+	}
+	if err != nil {
+		view.model.editProblem = err.Error()
+		return
+	}
+	view.model.editProblem = ""
+}
+
+func (view *View) renderAcrossLevels(levelResource int) {
+	blocks := view.service.BlockAcrossLevels(levelResource, view.model.blockIndex)
+	variation := raw.Variation(blocks)
+	varyingCount := 0
+	for _, varies := range variation {
+		if varies {
+			varyingCount++
+		}
+	}
+	imgui.Text(fmt.Sprintf("%d of %d offsets differ between levels", varyingCount, len(variation)))
+
+	rowStart := (view.model.cursor / bytesPerRow) * bytesPerRow
+	for levelIndex, data := range blocks {
+		if imgui.SelectableV(fmt.Sprintf("Level %2d", levelIndex), view.isCurrentLevel(levelIndex), 0,
+			imgui.CalcTextSize("Level 00", false, 0)) {
+			cursor := view.model.cursor
+			blockIndex := view.model.blockIndex
+			view.model.selectResource(edit.LevelResourceID(levelIndex, levelResource))
+			view.model.blockIndex = blockIndex
+			view.model.cursor = cursor
+		}
+		for column := 0; column < bytesPerRow; column++ {
+			offset := rowStart + column
+			imgui.SameLine()
+			switch {
+			case offset >= len(data):
+				imgui.PushStyleColor(imgui.StyleColorText, colorMissing)
+				imgui.Text("--")
+				imgui.PopStyleColor()
+			case (offset < len(variation)) && variation[offset]:
+				imgui.PushStyleColor(imgui.StyleColorText, colorVarying)
+				imgui.Text(fmt.Sprintf("%02X", data[offset]))
+				imgui.PopStyleColor()
+			default:
+				imgui.Text(fmt.Sprintf("%02X", data[offset]))
+			}
+		}
+	}
+}
+
+func (view *View) isCurrentLevel(levelIndex int) bool {
+	current, _, _ := edit.LevelResourceOf(view.model.resourceID)
+	return current == levelIndex
+}
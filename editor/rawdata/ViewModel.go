@@ -0,0 +1,36 @@
+package rawdata
+
+import (
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+type viewModel struct {
+	windowOpen   bool
+	restoreFocus bool
+
+	resourceIDText string
+	resourceID     resource.ID
+	lang           resource.Language
+	blockIndex     int
+
+	cursor int
+
+	editProblem string
+}
+
+func freshViewModel() viewModel {
+	model := viewModel{
+		lang: resource.LangAny,
+	}
+	model.selectResource(ids.GameState)
+	return model
+}
+
+func (model *viewModel) selectResource(id resource.ID) {
+	model.resourceID = id
+	model.resourceIDText = id.String()
+	model.blockIndex = 0
+	model.cursor = 0
+	model.editProblem = ""
+}
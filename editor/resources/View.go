@@ -0,0 +1,362 @@
+package resources
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+var rawBlockTypes = []external.TypeInfo{{Title: "Raw block data (*.bin)", Extensions: []string{"bin"}}}
+
+// View is the resource explorer. It lists all resources of all layers, and which layer provides the data.
+type View struct {
+	mod               *world.Mod
+	service           *edit.RawDataService
+	modalStateMachine gui.ModalStateMachine
+
+	guiScale float32
+
+	model viewModel
+
+	files      []fileNode
+	filesStamp filesStamp
+}
+
+// fileNode collects the resources of all layers that have a file of the same name.
+type fileNode struct {
+	name      string
+	languages []resource.Language
+	ids       []resource.ID
+	layers    map[resource.ID]int
+}
+
+// filesStamp is used to detect whether the list of files needs to be refreshed.
+type filesStamp struct {
+	entryCount int
+	modFiles   int
+	changeTime time.Time
+}
+
+// NewView returns a new instance.
+func NewView(mod *world.Mod, service *edit.RawDataService, modalStateMachine gui.ModalStateMachine, guiScale float32) *View {
+	view := &View{
+		mod:               mod,
+		service:           service,
+		modalStateMachine: modalStateMachine,
+
+		guiScale: guiScale,
+
+		model: freshViewModel(),
+	}
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *View) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// Render renders the view.
+func (view *View) Render() {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 900 * view.guiScale, Y: 600 * view.guiScale}, imgui.ConditionFirstUseEver)
+		if imgui.BeginV("Resource Explorer", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
+			view.renderContent()
+		}
+		imgui.End()
+	}
+}
+
+func (view *View) renderContent() {
+	view.updateFiles(false)
+
+	if imgui.BeginChildV("files", imgui.Vec2{X: 300 * view.guiScale, Y: 0}, true, 0) {
+		view.renderFiles()
+	}
+	imgui.EndChild()
+	imgui.SameLine()
+	if imgui.BeginChildV("details", imgui.Vec2{X: -1, Y: 0}, true, 0) {
+		if view.model.selected {
+			view.renderDetails()
+		} else {
+			imgui.Text("Select a resource.")
+		}
+	}
+	imgui.EndChild()
+}
+
+func (view *View) updateFiles(forced bool) {
+	stamp := filesStamp{
+		entryCount: view.mod.World().EntryCount(),
+		modFiles:   len(view.mod.ModifiedResources()),
+		changeTime: view.mod.LastChangeTime(),
+	}
+	if !forced && (view.files != nil) && (stamp == view.filesStamp) {
+		return
+	}
+	view.filesStamp = stamp
+	view.files = fileNodesOf(view.mod.ResourceFiles())
+}
+
+func fileNodesOf(files []world.ResourceFile) []fileNode {
+	nodesByName := make(map[string]*fileNode)
+	var names []string
+	for _, file := range files {
+		name := strings.ToLower(file.Filename)
+		node, existing := nodesByName[name]
+		if !existing {
+			node = &fileNode{name: name, layers: make(map[resource.ID]int)}
+			nodesByName[name] = node
+			names = append(names, name)
+		}
+		if !languageListed(node.languages, file.Language) {
+			node.languages = append(node.languages, file.Language)
+		}
+		for _, id := range file.IDs {
+			if node.layers[id] == 0 {
+				node.ids = append(node.ids, id)
+			}
+			node.layers[id]++
+		}
+	}
+	sort.Strings(names)
+	nodes := make([]fileNode, len(names))
+	for index, name := range names {
+		node := nodesByName[name]
+		sort.Slice(node.ids, func(a, b int) bool { return node.ids[a] < node.ids[b] })
+		nodes[index] = *node
+	}
+	return nodes
+}
+
+func languageListed(list []resource.Language, lang resource.Language) bool {
+	for _, entry := range list {
+		if entry == lang {
+			return true
+		}
+	}
+	return false
+}
+
+func (view *View) renderFiles() {
+	imgui.PushItemWidth(-1)
+	imgui.InputTextV("##idFilter", &view.model.idFilter, imgui.InputTextFlagsCharsHexadecimal, nil)
+	imgui.PopItemWidth()
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Filter resources by the start of their hexadecimal ID.")
+	}
+	if imgui.Button("Refresh") {
+		view.updateFiles(true)
+	}
+	filter := strings.ToUpper(view.model.idFilter)
+	for _, node := range view.files {
+		var matching []resource.ID
+		for _, id := range node.ids {
+			if strings.HasPrefix(id.String(), filter) {
+				matching = append(matching, id)
+			}
+		}
+		if len(matching) == 0 {
+			continue
+		}
+		if imgui.TreeNode(fmt.Sprintf("%s (%d)###%s", node.name, len(matching), node.name)) {
+			for _, id := range matching {
+				view.renderFileEntry(node, id)
+			}
+			imgui.TreePop()
+		}
+	}
+}
+
+func (view *View) renderFileEntry(node fileNode, id resource.ID) {
+	lang := node.languages[0]
+	label := id.String()
+	if layerCount := node.layers[id]; layerCount > 1 {
+		label += fmt.Sprintf(" (%d layers)", layerCount)
+	}
+	isSelected := view.model.selected && (view.model.selectedID == id) && (view.model.selectedLang == lang)
+	if imgui.SelectableV(label+"##"+node.name, isSelected, 0, imgui.Vec2{}) {
+		view.model.selected = true
+		view.model.selectedID = id
+		view.model.selectedLang = lang
+		view.model.selectedBlock = 0
+		view.model.deleteProblem = ""
+	}
+}
+
+func (view *View) renderDetails() {
+	id := view.model.selectedID
+	lang := view.model.selectedLang
+	imgui.Text(fmt.Sprintf("Resource %s", id))
+	if info, known := ids.Info(id); known {
+		imgui.Text(fmt.Sprintf("Known as %s resource, stored in %s", info.ContentType, info.ResFile.For(lang)))
+	} else {
+		imgui.Text("Not a known resource")
+	}
+	if imgui.BeginCombo("Language", lang.String()) {
+		languages := append([]resource.Language{resource.LangAny}, resource.Languages()...)
+		for _, option := range languages {
+			if imgui.SelectableV(option.String(), option == lang, 0, imgui.Vec2{}) {
+				view.model.selectedLang = option
+			}
+		}
+		imgui.EndCombo()
+	}
+
+	provenance := view.mod.Provenance(lang, id)
+	if len(provenance.Sources) == 0 {
+		imgui.Text("The resource is not available in this language.")
+		return
+	}
+	view.renderLayers(provenance)
+	imgui.Separator()
+	view.renderBlocks(provenance)
+}
+
+func (view *View) renderLayers(provenance world.ResourceProvenance) {
+	imgui.Text("Layers, from bottom to top:")
+	headers := []string{"Layer", "Language", "Type", "Compound", "Compressed", "Blocks"}
+	imgui.ColumnsV(len(headers), "layers", true)
+	for _, header := range headers {
+		imgui.Text(header)
+		imgui.NextColumn()
+	}
+	imgui.Separator()
+	for _, source := range provenance.Sources {
+		imgui.Text(source.ResourceLayer.String())
+		imgui.NextColumn()
+		imgui.Text(source.Language.String())
+		imgui.NextColumn()
+		imgui.Text(source.View.ContentType().String())
+		imgui.NextColumn()
+		imgui.Text(yesNo(source.View.Compound()))
+		imgui.NextColumn()
+		imgui.Text(yesNo(source.View.Compressed()))
+		imgui.NextColumn()
+		imgui.Text(fmt.Sprintf("%d", source.View.BlockCount()))
+		imgui.NextColumn()
+	}
+	imgui.Columns()
+
+	inMod := view.mod.ModifiedResource(view.model.selectedLang, view.model.selectedID) != nil
+	if inMod && imgui.Button("Remove from Mod") {
+		view.model.deleteProblem = ""
+		err := view.service.DeleteResource(view.model.selectedLang, view.model.selectedID)
+		if err != nil {
+			view.model.deleteProblem = "Could not remove resource: " + err.Error()
+		}
+	}
+	if inMod && imgui.IsItemHovered() {
+		imgui.SetTooltip("Removes the resource of the selected language from the mod.\nThe data of the lower layers becomes visible again.")
+	}
+	if len(view.model.deleteProblem) > 0 {
+		imgui.PushStyleColor(imgui.StyleColorText, imgui.Vec4{X: 1.0, Y: 0.0, Z: 0.0, W: 1.0})
+		imgui.Text(view.model.deleteProblem)
+		imgui.PopStyleColor()
+	}
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func (view *View) renderBlocks(provenance world.ResourceProvenance) {
+	blockCount := len(provenance.BlockSources)
+	if view.model.selectedBlock >= blockCount {
+		view.model.selectedBlock = 0
+	}
+	if blockCount > 0 {
+		if imgui.Button("Export Block...") {
+			view.requestExportBlock(view.model.selectedBlock)
+		}
+		imgui.SameLine()
+		if imgui.Button("Import Block...") {
+			view.requestImportBlock(view.model.selectedBlock)
+		}
+	}
+
+	headers := []string{"Block", "Size", "Provided by"}
+	imgui.ColumnsV(len(headers), "blocks", true)
+	for _, header := range headers {
+		imgui.Text(header)
+		imgui.NextColumn()
+	}
+	imgui.Separator()
+	var clipper imgui.ListClipper
+	clipper.Begin(blockCount)
+	for clipper.Step() {
+		for index := clipper.DisplayStart; index < clipper.DisplayEnd; index++ {
+			view.renderBlock(provenance, index)
+		}
+	}
+	imgui.Columns()
+}
+
+func (view *View) renderBlock(provenance world.ResourceProvenance, index int) {
+	if imgui.SelectableV(fmt.Sprintf("%d", index), index == view.model.selectedBlock, imgui.SelectableFlagsSpanAllColumns, imgui.Vec2{}) {
+		view.model.selectedBlock = index
+	}
+	imgui.NextColumn()
+	sourceIndex := provenance.BlockSources[index]
+	if sourceIndex < 0 {
+		imgui.Text("0")
+		imgui.NextColumn()
+		imgui.Text("(empty)")
+		imgui.NextColumn()
+		return
+	}
+	source := provenance.Sources[sourceIndex]
+	size := "?"
+	if reader, err := source.View.Block(index); err == nil {
+		if data, err := ioutil.ReadAll(reader); err == nil {
+			size = fmt.Sprintf("%d", len(data))
+		}
+	}
+	imgui.Text(size)
+	imgui.NextColumn()
+	imgui.Text(source.ResourceLayer.String())
+	imgui.NextColumn()
+}
+
+func (view *View) requestExportBlock(index int) {
+	lang := view.model.selectedLang
+	id := view.model.selectedID
+	external.SaveFile(view.modalStateMachine, rawBlockTypes, func(filename string) error {
+		data, err := view.service.Block(lang, id, index)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filename, data, 0640)
+	})
+}
+
+func (view *View) requestImportBlock(index int) {
+	lang := view.model.selectedLang
+	id := view.model.selectedID
+	external.LoadFile(view.modalStateMachine, rawBlockTypes, func(filename string) error {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		return view.service.SetBlock(lang, id, index, data)
+	})
+}
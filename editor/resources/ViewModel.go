@@ -0,0 +1,25 @@
+package resources
+
+import (
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+type viewModel struct {
+	windowOpen   bool
+	restoreFocus bool
+
+	idFilter string
+
+	selected      bool
+	selectedID    resource.ID
+	selectedLang  resource.Language
+	selectedBlock int
+
+	deleteProblem string
+}
+
+func freshViewModel() viewModel {
+	return viewModel{
+		selectedLang: resource.LangAny,
+	}
+}
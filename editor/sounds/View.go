@@ -6,6 +6,7 @@ import (
 	"github.com/inkyblackness/imgui-go/v3"
 
 	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/editor/waveform"
 	"github.com/inkyblackness/hacked/ss1/content/audio"
 	"github.com/inkyblackness/hacked/ss1/edit/undoable"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
@@ -18,6 +19,7 @@ type View struct {
 
 	modalStateMachine gui.ModalStateMachine
 	guiScale          float32
+	waveform          *waveform.Editor
 
 	model viewModel
 }
@@ -31,6 +33,7 @@ func NewSoundEffectsView(soundEffectService undoable.SoundEffectService,
 
 		modalStateMachine: modalStateMachine,
 		guiScale:          guiScale,
+		waveform:          waveform.NewEditor(guiScale),
 
 		model: freshViewModel(),
 	}
@@ -50,7 +53,7 @@ func (view *View) Render() {
 		view.model.windowOpen = true
 	}
 	if view.model.windowOpen {
-		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 300 * view.guiScale}, imgui.ConditionFirstUseEver)
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 400 * view.guiScale, Y: 500 * view.guiScale}, imgui.ConditionFirstUseEver)
 		if imgui.BeginV("Sound Effects", view.WindowOpen(), imgui.WindowFlagsNoCollapse) {
 			view.renderContent()
 		}
@@ -61,7 +64,7 @@ func (view *View) Render() {
 func (view *View) renderContent() {
 	info, _ := ids.Info(view.model.currentKey.ID)
 
-	imgui.BeginChildV("SoundEffects", imgui.Vec2{X: -1, Y: -60 * view.guiScale}, true, 0)
+	imgui.BeginChildV("SoundEffects", imgui.Vec2{X: -1, Y: -260 * view.guiScale}, true, 0)
 	for i := 0; i < info.MaxCount; i++ {
 		effects := ids.SoundEffectsForAudio(i)
 		text := fmt.Sprintf("%3d", i)
@@ -87,7 +90,11 @@ func (view *View) renderContent() {
 	if !sound.Empty() {
 		imgui.LabelText("Audio", fmt.Sprintf("%.2f sec", sound.Duration()))
 		if imgui.Button("Export") {
-			view.requestExportAudio(sound)
+			view.requestExportAudio(sound, "wav")
+		}
+		imgui.SameLine()
+		if imgui.Button("Export VOC") {
+			view.requestExportAudio(sound, "voc")
 		}
 		imgui.SameLine()
 	} else {
@@ -107,6 +114,9 @@ func (view *View) renderContent() {
 			view.removeAudio()
 		}
 	}
+	if !sound.Empty() {
+		view.waveform.Render(view.model.currentKey, sound, false, view.requestSetAudio)
+	}
 }
 
 func (view *View) clearAudio() {
@@ -121,16 +131,18 @@ func (view *View) currentSound() (sound audio.L8) {
 	return view.soundEffectService.Audio(view.model.currentKey)
 }
 
-func (view *View) requestExportAudio(sound audio.L8) {
-	filename := fmt.Sprintf("sfx_%03d.wav", view.model.currentKey.Index)
+func (view *View) requestExportAudio(sound audio.L8, extension string) {
+	filename := fmt.Sprintf("sfx_%03d.%s", view.model.currentKey.Index, extension)
 
 	external.ExportAudio(view.modalStateMachine, filename, sound)
 }
 
 func (view *View) requestImportAudio() {
-	external.ImportAudio(view.modalStateMachine, func(sound audio.L8) {
-		view.soundEffectService.RequestSetAudio(view.model.currentKey, sound, view.restoreFunc())
-	})
+	external.ImportAudio(view.modalStateMachine, view.requestSetAudio)
+}
+
+func (view *View) requestSetAudio(sound audio.L8) {
+	view.soundEffectService.RequestSetAudio(view.model.currentKey, sound, view.restoreFunc())
 }
 
 func (view *View) restoreFunc() func() {
@@ -0,0 +1,133 @@
+package textures
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/editor/graphics"
+	"github.com/inkyblackness/hacked/editor/render"
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+type importTextureSetDialog struct {
+	view  *View
+	index int
+
+	palette bitmap.Palette
+	scaled  []image.Image
+	bitmaps []bitmap.Bitmap
+	keys    []graphics.FrameCacheKey
+
+	controls external.ImageMappingControls
+
+	setTexts bool
+	name     string
+	use      string
+
+	opened bool
+}
+
+func newImportTextureSetDialog(view *View, index int, img image.Image, palette bitmap.Palette) *importTextureSetDialog {
+	dialog := &importTextureSetDialog{
+		view:     view,
+		index:    index,
+		palette:  palette,
+		controls: external.NewImageMappingControls(),
+	}
+	for _, size := range texture.Sizes() {
+		dialog.scaled = append(dialog.scaled, texture.ScaledImage(img, size))
+		dialog.keys = append(dialog.keys, view.frameCache.AllocateKey())
+	}
+	dialog.bitmaps = make([]bitmap.Bitmap, len(dialog.scaled))
+	dialog.name, _ = view.textCache.Text(resource.KeyOf(ids.TextureNames, view.model.currentLang, index))
+	dialog.use, _ = view.textCache.Text(resource.KeyOf(ids.TextureUsages, view.model.currentLang, index))
+	return dialog
+}
+
+func (dialog *importTextureSetDialog) Render() {
+	if !dialog.opened {
+		dialog.opened = true
+		dialog.updateBitmaps()
+		imgui.OpenPopup("Import texture set")
+	}
+
+	if imgui.BeginPopupModalV("Import texture set", nil,
+		imgui.WindowFlagsNoSavedSettings|imgui.WindowFlagsAlwaysAutoResize) {
+		guiScale := dialog.view.guiScale
+		imgui.Text(fmt.Sprintf("Texture %d", dialog.index))
+		imgui.PushItemWidth(300 * guiScale)
+		if dialog.controls.Render() {
+			dialog.updateBitmaps()
+		}
+		imgui.Separator()
+		readOnly := !dialog.view.mod.HasModifiableTextureProperties()
+		if readOnly {
+			dialog.setTexts = false
+		} else {
+			imgui.Checkbox("Set texts for "+dialog.view.model.currentLang.String(), &dialog.setTexts)
+		}
+		textFlags := imgui.InputTextFlagsNoUndoRedo
+		if !dialog.setTexts {
+			textFlags |= imgui.InputTextFlagsReadOnly
+		}
+		imgui.InputTextV("Name", &dialog.name, textFlags, nil)
+		imgui.InputTextV("Use", &dialog.use, textFlags, nil)
+		imgui.PopItemWidth()
+		imgui.Separator()
+		for sizeIndex, size := range texture.Sizes() {
+			if sizeIndex > 0 {
+				imgui.SameLine()
+			}
+			imgui.BeginGroup()
+			render.FrameImage(fmt.Sprintf("Preview%d", size), dialog.view.frameCache, dialog.keys[sizeIndex],
+				imgui.Vec2{X: 128 * guiScale, Y: 128 * guiScale})
+			imgui.Text(fmt.Sprintf("%d x %d px", size, size))
+			imgui.EndGroup()
+		}
+		imgui.Separator()
+		if imgui.Button("OK") {
+			dialog.close()
+			dialog.apply()
+		}
+		imgui.SameLine()
+		if imgui.Button("Cancel") {
+			dialog.close()
+		}
+		imgui.EndPopup()
+	} else {
+		dialog.close()
+	}
+}
+
+func (dialog *importTextureSetDialog) updateBitmaps() {
+	bitmapper := bitmap.NewBitmapperWithOptions(&dialog.palette, dialog.controls.Options())
+	for sizeIndex, img := range dialog.scaled {
+		bmp := bitmapper.Map(img)
+		dialog.bitmaps[sizeIndex] = bmp
+		dialog.view.frameCache.SetTexture(dialog.keys[sizeIndex],
+			uint16(bmp.Header.Width), uint16(bmp.Header.Height), bmp.Pixels, &dialog.palette)
+	}
+}
+
+func (dialog *importTextureSetDialog) apply() {
+	var name, use *string
+	if dialog.setTexts {
+		name = &dialog.name
+		use = &dialog.use
+	}
+	dialog.view.requestSetTextureSet(dialog.index, dialog.bitmaps, name, use)
+}
+
+func (dialog *importTextureSetDialog) close() {
+	for _, key := range dialog.keys {
+		dialog.view.frameCache.DropTextureForKey(key)
+	}
+	dialog.view.modalStateMachine.SetState(nil)
+	imgui.CloseCurrentPopup()
+}
@@ -2,6 +2,7 @@ package textures
 
 import (
 	"fmt"
+	"image"
 	"math"
 
 	"github.com/inkyblackness/imgui-go/v3"
@@ -20,6 +21,13 @@ import (
 	"github.com/inkyblackness/hacked/ui/gui"
 )
 
+var textureIDs = map[texture.Size]resource.ID{
+	texture.SizeLarge:  ids.LargeTextures,
+	texture.SizeMedium: ids.MediumTextures,
+	texture.SizeSmall:  ids.SmallTextures,
+	texture.SizeIcon:   ids.IconTextures,
+}
+
 // View provides edit controls for textures.
 type View struct {
 	mod          *world.Mod
@@ -27,6 +35,7 @@ type View struct {
 	cp           text.Codepage
 	imageCache   *graphics.TextureCache
 	paletteCache *graphics.PaletteCache
+	frameCache   *graphics.FrameCache
 
 	modalStateMachine gui.ModalStateMachine
 	clipboard         external.Clipboard
@@ -39,7 +48,7 @@ type View struct {
 // NewTexturesView returns a new instance.
 func NewTexturesView(mod *world.Mod, textCache *text.Cache, cp text.Codepage,
 	imageCache *graphics.TextureCache, paletteCache *graphics.PaletteCache,
-	modalStateMachine gui.ModalStateMachine,
+	frameCache *graphics.FrameCache, modalStateMachine gui.ModalStateMachine,
 	clipboard external.Clipboard, guiScale float32, commander cmd.Commander) *View {
 	view := &View{
 		mod:          mod,
@@ -47,6 +56,7 @@ func NewTexturesView(mod *world.Mod, textCache *text.Cache, cp text.Codepage,
 		cp:           cp,
 		imageCache:   imageCache,
 		paletteCache: paletteCache,
+		frameCache:   frameCache,
 
 		modalStateMachine: modalStateMachine,
 		clipboard:         clipboard,
@@ -94,6 +104,9 @@ func (view *View) renderContent() {
 			func(newValue int) {
 				view.model.currentIndex = newValue
 			})
+		if imgui.Button("Import Texture Set...") {
+			view.requestImportSet(view.model.currentIndex)
+		}
 
 		readOnly := !view.mod.HasModifiableTextureProperties()
 
@@ -306,18 +319,23 @@ func (view *View) requestSetTextureUsage(value string) {
 }
 
 func (view *View) requestSetTextureText(id resource.ID, newValue string) {
-	key := resource.KeyOf(id, view.model.currentLang, view.model.currentIndex)
+	command, changed := view.textureTextCommand(id, view.model.currentIndex, newValue)
+	if changed {
+		view.commander.Queue(command)
+	}
+}
+
+func (view *View) textureTextCommand(id resource.ID, index int, newValue string) (setTextureTextCommand, bool) {
+	key := resource.KeyOf(id, view.model.currentLang, index)
 	oldValue, _ := view.textCache.Text(key)
 
-	if oldValue != newValue {
-		command := setTextureTextCommand{
-			model:   &view.model,
-			key:     key,
-			oldData: view.cp.Encode(oldValue),
-			newData: view.cp.Encode(text.Blocked(newValue)[0]),
-		}
-		view.commander.Queue(command)
+	command := setTextureTextCommand{
+		model:   &view.model,
+		key:     key,
+		oldData: view.cp.Encode(oldValue),
+		newData: view.cp.Encode(text.Blocked(newValue)[0]),
 	}
+	return command, oldValue != newValue
 }
 
 func (view *View) requestExport(id resource.ID, index int, sizeID string) {
@@ -354,7 +372,7 @@ func (view *View) requestImport(id resource.ID, index int) {
 		return palette.Palette(), nil
 	}
 
-	external.ImportImage(view.modalStateMachine, paletteRetriever, func(bmp bitmap.Bitmap) {
+	external.ImportImage(view.modalStateMachine, view.frameCache, paletteRetriever, func(bmp bitmap.Bitmap) {
 		view.requestSetBitmap(id, index, bmp)
 	})
 }
@@ -371,6 +389,10 @@ func (view *View) requestClear(id resource.ID, index int, sideLength int) {
 }
 
 func (view *View) requestSetBitmap(id resource.ID, index int, bmp bitmap.Bitmap) {
+	view.requestSetBitmapData(id, index, textureBitmapData(bmp))
+}
+
+func textureBitmapData(bmp bitmap.Bitmap) []byte {
 	highestBitShift := func(value int16) (result byte) {
 		if value != 0 {
 			for (value >> result) != 1 {
@@ -385,18 +407,57 @@ func (view *View) requestSetBitmap(id resource.ID, index int, bmp bitmap.Bitmap)
 	bmp.Header.WidthFactor = highestBitShift(bmp.Header.Width)
 	bmp.Header.HeightFactor = highestBitShift(bmp.Header.Height)
 	bmp.Header.Stride = uint16(bmp.Header.Width)
-	data := bitmap.Encode(&bmp, 0)
-	view.requestSetBitmapData(id, index, data)
+	return bitmap.Encode(&bmp, 0)
 }
 
 func (view *View) requestSetBitmapData(id resource.ID, index int, newData []byte) {
+	view.commander.Queue(view.textureBitmapCommand(id, index, newData))
+}
+
+func (view *View) textureBitmapCommand(id resource.ID, index int, newData []byte) setTextureBitmapCommand {
 	resourceKey := view.indexedResourceKey(id, index)
-	command := setTextureBitmapCommand{
+	return setTextureBitmapCommand{
 		model:        &view.model,
 		id:           id,
 		textureIndex: index,
 		oldData:      view.mod.ModifiedBlock(resource.LangAny, resourceKey.ID, resourceKey.Index),
 		newData:      newData,
 	}
-	view.commander.Queue(command)
+}
+
+func (view *View) requestImportSet(index int) {
+	palette, err := view.paletteCache.Palette(0)
+	if err != nil {
+		return
+	}
+	rawPalette := palette.Palette()
+	info := "The image is scaled to all texture sizes.\nIdeally, it is square and at least 128x128 pixels large."
+	external.ImportRawImage(view.modalStateMachine, info, func(img image.Image) {
+		view.modalStateMachine.SetState(newImportTextureSetDialog(view, index, img, rawPalette))
+	})
+}
+
+// requestSetTextureSet sets all bitmaps of a texture, and optionally its texts, as one undoable step.
+// The bitmaps are given in the order of texture.Sizes().
+func (view *View) requestSetTextureSet(index int, bitmaps []bitmap.Bitmap, name, use *string) {
+	var commands cmd.List
+	for sizeIndex, size := range texture.Sizes() {
+		commands = append(commands, view.textureBitmapCommand(textureIDs[size], index, textureBitmapData(bitmaps[sizeIndex])))
+	}
+	texts := []struct {
+		id    resource.ID
+		value *string
+	}{
+		{id: ids.TextureNames, value: name},
+		{id: ids.TextureUsages, value: use},
+	}
+	for _, entry := range texts {
+		if entry.value == nil {
+			continue
+		}
+		if command, changed := view.textureTextCommand(entry.id, index, *entry.value); changed {
+			commands = append(commands, command)
+		}
+	}
+	view.commander.Queue(commands)
 }
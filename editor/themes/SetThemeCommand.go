@@ -0,0 +1,39 @@
+package themes
+
+import (
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+type setThemeCommand struct {
+	model *viewModel
+
+	themeIndex    int
+	sequenceIndex int
+
+	oldData []byte
+	newData []byte
+}
+
+func (cmd setThemeCommand) Do(modder world.Modder) error {
+	return cmd.perform(modder, cmd.newData)
+}
+
+func (cmd setThemeCommand) Undo(modder world.Modder) error {
+	return cmd.perform(modder, cmd.oldData)
+}
+
+func (cmd setThemeCommand) perform(modder world.Modder, data []byte) error {
+	id := ids.MusicThemesStart.Plus(cmd.themeIndex)
+	if len(data) > 0 {
+		modder.SetResourceBlocks(resource.LangAny, id, [][]byte{data})
+	} else {
+		modder.DelResource(resource.LangAny, id)
+	}
+
+	cmd.model.restoreFocus = true
+	cmd.model.themeIndex = cmd.themeIndex
+	cmd.model.sequenceIndex = cmd.sequenceIndex
+	return nil
+}
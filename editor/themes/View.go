@@ -0,0 +1,323 @@
+package themes
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/editor/external"
+	"github.com/inkyblackness/hacked/ss1/content/audio/midi"
+	"github.com/inkyblackness/hacked/ss1/content/audio/xmi"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// musicZoneCount is the amount of distinct music indices a tile can have.
+const musicZoneCount = 16
+
+// ThemeRetriever provides the music theme of given index.
+type ThemeRetriever interface {
+	Theme(index int) (xmi.File, error)
+}
+
+// View provides edit controls for music themes.
+// The editor assumes that the music index of a tile selects the sequence of the same index within the theme.
+type View struct {
+	mod    *world.Mod
+	themes ThemeRetriever
+
+	modalStateMachine gui.ModalStateMachine
+	guiScale          float32
+	commander         cmd.Commander
+
+	model viewModel
+}
+
+// NewMusicView returns a new instance.
+func NewMusicView(mod *world.Mod, themes ThemeRetriever,
+	modalStateMachine gui.ModalStateMachine, guiScale float32, commander cmd.Commander) *View {
+	view := &View{
+		mod:    mod,
+		themes: themes,
+
+		modalStateMachine: modalStateMachine,
+		guiScale:          guiScale,
+		commander:         commander,
+
+		model: freshViewModel(),
+	}
+	return view
+}
+
+// WindowOpen returns the flag address, to be used with the main menu.
+func (view *View) WindowOpen() *bool {
+	return &view.model.windowOpen
+}
+
+// ZoneLabel returns a description of the sequence that is played for the given music index of tiles.
+// The theme used for this is the one selected for labels in the view.
+func (view *View) ZoneLabel(musicIndex int) string {
+	filename := ids.MusicThemeFile(view.model.labelThemeIndex).For(resource.LangAny)
+	theme, err := view.themes.Theme(view.model.labelThemeIndex)
+	if err != nil {
+		return filename + " missing"
+	}
+	if (musicIndex < 0) || (musicIndex >= len(theme.Sequences)) {
+		return fmt.Sprintf("%s #%d missing", filename, musicIndex)
+	}
+	return fmt.Sprintf("%s #%d (%s)", filename, musicIndex, formatDuration(theme.Sequences[musicIndex].Info().Duration))
+}
+
+// Render renders the view.
+func (view *View) Render() {
+	if view.model.restoreFocus {
+		imgui.SetNextWindowFocus()
+		view.model.restoreFocus = false
+		view.model.windowOpen = true
+	}
+	if view.model.windowOpen {
+		imgui.SetNextWindowSizeV(imgui.Vec2{X: 600 * view.guiScale, Y: 400 * view.guiScale}, imgui.ConditionFirstUseEver)
+		if imgui.BeginV("Music", view.WindowOpen(), imgui.WindowFlagsNoCollapse|imgui.WindowFlagsHorizontalScrollbar) {
+			view.renderContent()
+		}
+		imgui.End()
+	}
+}
+
+func (view *View) renderContent() {
+	theme, themeErr := view.themes.Theme(view.model.themeIndex)
+	sequenceCount := len(theme.Sequences)
+	if view.model.sequenceIndex >= sequenceCount {
+		view.model.sequenceIndex = 0
+	}
+
+	if imgui.BeginChildV("Properties", imgui.Vec2{X: 350 * view.guiScale, Y: 0}, false, 0) {
+		imgui.PushItemWidth(-150 * view.guiScale)
+		gui.StepSliderInt("Theme", &view.model.themeIndex, 0, ids.MusicThemeCount-1)
+		imgui.LabelText("File", ids.MusicThemeFile(view.model.themeIndex).For(resource.LangAny))
+		imgui.LabelText("Source", view.themeSource(themeErr))
+
+		if imgui.Button("Import XMI") {
+			view.requestImportTheme()
+		}
+		if themeErr == nil {
+			imgui.SameLine()
+			if imgui.Button("Export XMI") {
+				view.requestExportTheme(theme)
+			}
+		}
+		if view.hasModCurrentTheme() {
+			imgui.SameLine()
+			if imgui.Button("Remove") {
+				view.requestSetThemeData(view.model.sequenceIndex, nil)
+			}
+		}
+
+		imgui.Separator()
+		if sequenceCount > 0 {
+			gui.StepSliderInt("Track", &view.model.sequenceIndex, 0, sequenceCount-1)
+			view.renderSequenceInfo(theme.Sequences[view.model.sequenceIndex])
+			if imgui.Button("Export MIDI") {
+				view.requestExportSequence(theme.Sequences[view.model.sequenceIndex])
+			}
+			imgui.SameLine()
+			if imgui.Button("Replace with MIDI") {
+				view.requestImportSequence(theme, view.model.sequenceIndex)
+			}
+		}
+		if imgui.Button("Add MIDI as Track") {
+			view.requestImportSequence(theme, sequenceCount)
+		}
+		if sequenceCount > 1 {
+			imgui.SameLine()
+			if imgui.Button("Remove Track") {
+				view.requestRemoveSequence(theme, view.model.sequenceIndex)
+			}
+		}
+
+		imgui.Separator()
+		gui.StepSliderInt("Theme for Map Labels", &view.model.labelThemeIndex, 0, ids.MusicThemeCount-1)
+		imgui.PopItemWidth()
+	}
+	imgui.EndChild()
+	imgui.SameLine()
+
+	if imgui.BeginChildV("Tracks", imgui.Vec2{X: -1, Y: 0}, true, 0) {
+		for index, seq := range theme.Sequences {
+			label := fmt.Sprintf("#%2d - %s", index, formatDuration(seq.Info().Duration))
+			if index < musicZoneCount {
+				label += fmt.Sprintf(" - music index %d", index)
+			}
+			if imgui.SelectableV(label, index == view.model.sequenceIndex, 0, imgui.Vec2{}) {
+				view.model.sequenceIndex = index
+			}
+		}
+		for index := sequenceCount; index < musicZoneCount; index++ {
+			imgui.SelectableV(fmt.Sprintf("#%2d - missing - music index %d", index, index), false, imgui.SelectableFlagsDisabled, imgui.Vec2{})
+		}
+	}
+	imgui.EndChild()
+}
+
+func (view *View) renderSequenceInfo(seq xmi.Sequence) {
+	info := seq.Info()
+	imgui.LabelText("Duration", formatDuration(info.Duration))
+	imgui.LabelText("Channels", formatNumbers(info.Channels))
+	imgui.LabelText("Programs", formatNumbers(info.Programs))
+	imgui.LabelText("Notes", fmt.Sprintf("%d", info.Notes))
+	imgui.LabelText("Loops", fmt.Sprintf("%d", info.Loops))
+}
+
+func (view *View) themeSource(err error) string {
+	switch {
+	case view.hasModCurrentTheme():
+		return "Mod"
+	case err == nil:
+		return "Game"
+	default:
+		return "Missing"
+	}
+}
+
+func (view *View) hasModCurrentTheme() bool {
+	return len(view.mod.ModifiedBlock(resource.LangAny, ids.MusicThemesStart.Plus(view.model.themeIndex), 0)) > 0
+}
+
+func (view *View) requestImportTheme() {
+	info := "File must be an XMI file."
+	types := []external.TypeInfo{{Title: "XMI files (*.xmi)", Extensions: []string{"xmi"}}}
+	var fileHandler func(string)
+
+	fileHandler = func(filename string) {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			external.Import(view.modalStateMachine, "Could not open file.\n"+info, types, fileHandler, true)
+			return
+		}
+		theme, err := xmi.Load(data)
+		if err != nil {
+			external.Import(view.modalStateMachine, "File not recognized as XMI.\n"+info, types, fileHandler, true)
+			return
+		}
+		view.requestSetThemeData(0, xmi.Save(theme))
+	}
+
+	external.Import(view.modalStateMachine, info, types, fileHandler, false)
+}
+
+func (view *View) requestImportSequence(theme xmi.File, sequenceIndex int) {
+	info := "File must be a standard MIDI file.\nAll tracks are merged into one sequence."
+	types := []external.TypeInfo{{Title: "MIDI files (*.mid, *.midi)", Extensions: []string{"mid", "midi"}}}
+	var fileHandler func(string)
+
+	fileHandler = func(filename string) {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			external.Import(view.modalStateMachine, "Could not open file.\n"+info, types, fileHandler, true)
+			return
+		}
+		file, err := midi.Load(bytes.NewReader(data))
+		if err != nil {
+			external.Import(view.modalStateMachine, "File not recognized as MIDI.\n"+info, types, fileHandler, true)
+			return
+		}
+		seq, err := xmi.SequenceFromMIDI(file)
+		if err != nil {
+			external.Import(view.modalStateMachine, "MIDI file could not be converted.\n"+info, types, fileHandler, true)
+			return
+		}
+		sequences := append([]xmi.Sequence{}, theme.Sequences...)
+		if sequenceIndex < len(sequences) {
+			sequences[sequenceIndex] = seq
+		} else {
+			sequences = append(sequences, seq)
+		}
+		view.requestSetThemeData(sequenceIndex, xmi.Save(xmi.File{Sequences: sequences}))
+	}
+
+	external.Import(view.modalStateMachine, info, types, fileHandler, false)
+}
+
+func (view *View) requestRemoveSequence(theme xmi.File, sequenceIndex int) {
+	sequences := append([]xmi.Sequence{}, theme.Sequences[:sequenceIndex]...)
+	sequences = append(sequences, theme.Sequences[sequenceIndex+1:]...)
+	newIndex := sequenceIndex
+	if newIndex >= len(sequences) {
+		newIndex = len(sequences) - 1
+	}
+	view.requestSetThemeData(newIndex, xmi.Save(xmi.File{Sequences: sequences}))
+}
+
+func (view *View) requestExportTheme(theme xmi.File) {
+	filename := ids.MusicThemeFile(view.model.themeIndex).For(resource.LangAny)
+	view.exportTo(filename, func(writer *os.File) error {
+		_, err := writer.Write(xmi.Save(theme))
+		return err
+	})
+}
+
+func (view *View) requestExportSequence(seq xmi.Sequence) {
+	themeFilename := ids.MusicThemeFile(view.model.themeIndex).For(resource.LangAny)
+	filename := fmt.Sprintf("%s_%02d.mid", strings.TrimSuffix(themeFilename, filepath.Ext(themeFilename)), view.model.sequenceIndex)
+	view.exportTo(filename, func(writer *os.File) error {
+		return midi.Save(writer, seq.ToMIDI())
+	})
+}
+
+func (view *View) exportTo(filename string, write func(*os.File) error) {
+	info := "File to be written: " + filename
+	var exportTo func(string)
+
+	exportTo = func(dirname string) {
+		writer, err := os.Create(filepath.Join(dirname, filename))
+		if err != nil {
+			external.Export(view.modalStateMachine, "Could not create file.\n"+info, exportTo, true)
+			return
+		}
+		defer func() { _ = writer.Close() }()
+		err = write(writer)
+		if err != nil {
+			external.Export(view.modalStateMachine, "Could not write file.\n"+info, exportTo, true)
+		}
+	}
+
+	external.Export(view.modalStateMachine, info, exportTo, false)
+}
+
+func (view *View) requestSetThemeData(sequenceIndex int, newData []byte) {
+	command := setThemeCommand{
+		model: &view.model,
+
+		themeIndex:    view.model.themeIndex,
+		sequenceIndex: sequenceIndex,
+
+		oldData: view.mod.ModifiedBlock(resource.LangAny, ids.MusicThemesStart.Plus(view.model.themeIndex), 0),
+		newData: newData,
+	}
+	view.commander.Queue(command)
+}
+
+func formatDuration(duration time.Duration) string {
+	seconds := int(duration.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func formatNumbers(values []int) string {
+	texts := make([]string, 0, len(values))
+	for _, value := range values {
+		texts = append(texts, fmt.Sprintf("%d", value))
+	}
+	if len(texts) == 0 {
+		return "-"
+	}
+	return strings.Join(texts, ", ")
+}
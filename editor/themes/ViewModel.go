@@ -0,0 +1,15 @@
+package themes
+
+type viewModel struct {
+	windowOpen   bool
+	restoreFocus bool
+
+	themeIndex    int
+	sequenceIndex int
+
+	labelThemeIndex int
+}
+
+func freshViewModel() viewModel {
+	return viewModel{}
+}
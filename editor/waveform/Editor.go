@@ -0,0 +1,313 @@
+package waveform
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/inkyblackness/imgui-go/v3"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/audio/dsp"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ui/gui"
+)
+
+// clipboard holds the samples that were cut or copied last.
+// It is shared among all editors, so that samples can be moved between sounds.
+var clipboard audio.L8
+
+const (
+	maxZoom          = 1 << 12
+	displayHeight    = 100
+	maxSilenceMs     = 5000
+	minGainDB        = -24
+	maxGainDB        = 24
+	wheelZoomFactor  = 2.0
+	defaultSilenceMs = 100
+)
+
+var (
+	backgroundColor = imgui.Packed(color.RGBA{R: 0x10, G: 0x10, B: 0x10, A: 0xFF})
+	centerColor     = imgui.Packed(color.RGBA{R: 0x40, G: 0x40, B: 0x40, A: 0xFF})
+	waveColor       = imgui.Packed(color.RGBA{R: 0x21, G: 0xFF, B: 0x43, A: 0xFF})
+	selectionColor  = imgui.Packed(color.RGBA{R: 0x30, G: 0x60, B: 0xC0, A: 0x80})
+	cursorColor     = imgui.Packed(color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF})
+)
+
+// Editor displays the waveform of a sound and provides operations on ranges of samples.
+// Modifications are not applied directly; They are reported as a new sound to the caller.
+type Editor struct {
+	guiScale float32
+
+	key resource.Key
+
+	// zoom is the factor of how many more samples are shown compared to the width, if the whole sound was fitted.
+	zoom      float64
+	scroll    int
+	selection [2]int
+	dragStart int
+	dragging  bool
+
+	gainDB    int
+	silenceMs int
+}
+
+// NewEditor returns a new instance.
+func NewEditor(guiScale float32) *Editor {
+	return &Editor{
+		guiScale:  guiScale,
+		zoom:      1,
+		silenceMs: defaultSilenceMs,
+	}
+}
+
+// Render renders the waveform of the sound with the given key, followed by controls to modify it.
+// Changing the key resets zoom and selection. If the sound is not read-only, modifications are
+// reported via the apply function.
+func (editor *Editor) Render(key resource.Key, sound audio.L8, readOnly bool, apply func(audio.L8)) {
+	if key != editor.key {
+		editor.key = key
+		editor.zoom = 1
+		editor.scroll = 0
+		editor.selection = [2]int{}
+	}
+	editor.limitSelection(len(sound.Samples))
+	imgui.PushID("waveform")
+	editor.renderDisplay(sound)
+	editor.renderSelectionInfo(sound)
+	editor.renderViewControls(sound)
+	if !readOnly {
+		editor.renderEditControls(sound, apply)
+	}
+	imgui.PopID()
+}
+
+func (editor *Editor) limitSelection(count int) {
+	for index, value := range editor.selection {
+		if value > count {
+			editor.selection[index] = count
+		}
+	}
+	if editor.scroll > count {
+		editor.scroll = 0
+	}
+}
+
+func (editor *Editor) selectionRange() (from, to int) {
+	from, to = editor.selection[0], editor.selection[1]
+	if from > to {
+		from, to = to, from
+	}
+	return
+}
+
+func (editor *Editor) hasSelection() bool {
+	from, to := editor.selectionRange()
+	return to > from
+}
+
+func (editor *Editor) samplesPerPixel(count int, width float32) float64 {
+	if (count == 0) || (width <= 0) {
+		return 1
+	}
+	return math.Max(float64(count)/float64(width)/editor.zoom, 1.0/16)
+}
+
+func (editor *Editor) renderDisplay(sound audio.L8) {
+	count := len(sound.Samples)
+	size := imgui.Vec2{X: imgui.ContentRegionAvail().X, Y: displayHeight * editor.guiScale}
+	if size.X < 1 {
+		size.X = 1
+	}
+	topLeft := imgui.CursorScreenPos()
+	imgui.InvisibleButtonV("display", size, imgui.ButtonFlagsMouseButtonLeft)
+	samplesPerPixel := editor.samplesPerPixel(count, size.X)
+	visibleCount := int(math.Ceil(samplesPerPixel * float64(size.X)))
+	editor.limitScroll(count, visibleCount)
+	sampleAt := func(x float32) int {
+		sample := editor.scroll + int(math.Round(float64(x-topLeft.X)*samplesPerPixel))
+		return int(math.Max(0, math.Min(float64(count), float64(sample))))
+	}
+	xOf := func(sample int) float32 {
+		return topLeft.X + float32(float64(sample-editor.scroll)/samplesPerPixel)
+	}
+
+	if imgui.IsItemHovered() {
+		mouse := imgui.MousePos()
+		_, wheel := imgui.CurrentIO().MouseWheel()
+		if wheel != 0 {
+			anchor := sampleAt(mouse.X)
+			editor.setZoom(editor.zoom*math.Pow(wheelZoomFactor, float64(wheel)), count, size.X)
+			editor.scroll = anchor - int(float64(mouse.X-topLeft.X)*editor.samplesPerPixel(count, size.X))
+		}
+		if imgui.IsMouseClicked(0) {
+			editor.dragStart = sampleAt(mouse.X)
+			editor.dragging = true
+			editor.selection = [2]int{editor.dragStart, editor.dragStart}
+		}
+	}
+	if editor.dragging {
+		if imgui.IsMouseDown(0) {
+			editor.selection = [2]int{editor.dragStart, sampleAt(imgui.MousePos().X)}
+		} else {
+			editor.dragging = false
+		}
+	}
+
+	bottomRight := topLeft.Plus(size)
+	centerY := topLeft.Y + size.Y/2
+	drawList := imgui.WindowDrawList()
+	drawList.AddRectFilled(topLeft, bottomRight, backgroundColor)
+	from, to := editor.selectionRange()
+	if to > from {
+		drawList.AddRectFilled(imgui.Vec2{X: float32(math.Max(float64(xOf(from)), float64(topLeft.X))), Y: topLeft.Y},
+			imgui.Vec2{X: float32(math.Min(float64(xOf(to)), float64(bottomRight.X))), Y: bottomRight.Y}, selectionColor)
+	}
+	drawList.AddLine(imgui.Vec2{X: topLeft.X, Y: centerY}, imgui.Vec2{X: bottomRight.X, Y: centerY}, centerColor)
+	amplitude := size.Y / 2 / 128
+	for column := 0; column < int(size.X); column++ {
+		first := editor.scroll + int(float64(column)*samplesPerPixel)
+		last := editor.scroll + int(float64(column+1)*samplesPerPixel)
+		if first >= count {
+			break
+		}
+		if last <= first {
+			last = first + 1
+		}
+		if last > count {
+			last = count
+		}
+		low, high := sound.Samples[first], sound.Samples[first]
+		for _, sample := range sound.Samples[first:last] {
+			if sample < low {
+				low = sample
+			}
+			if sample > high {
+				high = sample
+			}
+		}
+		x := topLeft.X + float32(column)
+		drawList.AddLine(imgui.Vec2{X: x, Y: centerY - float32(int(high)-audio.SilenceValue)*amplitude},
+			imgui.Vec2{X: x, Y: centerY - float32(int(low)-audio.SilenceValue)*amplitude + 1}, waveColor)
+	}
+	if (from == to) && (from >= editor.scroll) && (from <= editor.scroll+visibleCount) {
+		x := xOf(from)
+		drawList.AddLine(imgui.Vec2{X: x, Y: topLeft.Y}, imgui.Vec2{X: x, Y: bottomRight.Y}, cursorColor)
+	}
+
+	if visibleCount < count {
+		imgui.PushItemWidth(-1)
+		gui.StepSliderIntV("##scroll", &editor.scroll, 0, count-visibleCount, "")
+		imgui.PopItemWidth()
+	}
+}
+
+func (editor *Editor) limitScroll(count, visibleCount int) {
+	if editor.scroll > count-visibleCount {
+		editor.scroll = count - visibleCount
+	}
+	if editor.scroll < 0 {
+		editor.scroll = 0
+	}
+}
+
+func (editor *Editor) setZoom(zoom float64, count int, width float32) {
+	editor.zoom = math.Max(1, math.Min(maxZoom, zoom))
+	editor.limitScroll(count, int(editor.samplesPerPixel(count, width)*float64(width)))
+}
+
+func (editor *Editor) renderSelectionInfo(sound audio.L8) {
+	seconds := func(sample int) float64 {
+		if sound.SampleRate <= 0 {
+			return 0
+		}
+		return float64(sample) / float64(sound.SampleRate)
+	}
+	from, to := editor.selectionRange()
+	if to > from {
+		imgui.Text(fmt.Sprintf("Selection: %.3f s - %.3f s (%.3f s)", seconds(from), seconds(to), seconds(to-from)))
+	} else {
+		imgui.Text(fmt.Sprintf("Cursor: %.3f s / %.3f s", seconds(from), seconds(len(sound.Samples))))
+	}
+}
+
+func (editor *Editor) renderViewControls(sound audio.L8) {
+	count := len(sound.Samples)
+	width := imgui.ContentRegionAvail().X
+	if imgui.Button("Zoom In") {
+		editor.setZoom(editor.zoom*2, count, width)
+	}
+	imgui.SameLine()
+	if imgui.Button("Zoom Out") {
+		editor.setZoom(editor.zoom/2, count, width)
+	}
+	imgui.SameLine()
+	if imgui.Button("Fit") {
+		editor.setZoom(1, count, width)
+	}
+	imgui.SameLine()
+	if imgui.Button("Select All") {
+		editor.selection = [2]int{0, count}
+	}
+	imgui.SameLine()
+	if imgui.Button("Select None") {
+		from, _ := editor.selectionRange()
+		editor.selection = [2]int{from, from}
+	}
+}
+
+func (editor *Editor) renderEditControls(sound audio.L8, apply func(audio.L8)) {
+	from, to := editor.selectionRange()
+	modify := func(modified audio.L8, selectionFrom, selectionTo int) {
+		editor.selection = [2]int{selectionFrom, selectionTo}
+		apply(modified)
+	}
+	first := true
+	button := func(label string) bool {
+		if !first {
+			imgui.SameLine()
+		}
+		first = false
+		return imgui.Button(label)
+	}
+	if editor.hasSelection() {
+		if button("Cut") {
+			clipboard = sound.Range(from, to)
+			modify(sound.Cut(from, to), from, from)
+		}
+		if button("Copy") {
+			clipboard = sound.Range(from, to)
+		}
+	}
+	if !clipboard.Empty() && button("Paste") {
+		modify(sound.Cut(from, to).Insert(from, clipboard), from, from+len(clipboard.Samples))
+	}
+	if editor.hasSelection() {
+		if button("Delete") {
+			modify(sound.Cut(from, to), from, from)
+		}
+		if button("Fade In") {
+			modify(sound.FadedIn(from, to), from, to)
+		}
+		if button("Fade Out") {
+			modify(sound.FadedOut(from, to), from, to)
+		}
+	}
+
+	imgui.PushItemWidth(imgui.TextLineHeightWithSpacing() * 10)
+	if editor.hasSelection() {
+		gui.StepSliderIntV("##gain", &editor.gainDB, minGainDB, maxGainDB, "%+d dB")
+		imgui.SameLine()
+		if imgui.Button("Apply Gain") {
+			modify(sound.WithGain(from, to, dsp.AmplitudeFromDecibels(float64(editor.gainDB))), from, to)
+		}
+	}
+	gui.StepSliderIntV("##silence", &editor.silenceMs, 1, maxSilenceMs, "%d ms")
+	imgui.SameLine()
+	if imgui.Button("Insert Silence") && (sound.SampleRate > 0) {
+		silenceCount := int(float64(editor.silenceMs) * float64(sound.SampleRate) / 1000)
+		modify(sound.InsertSilence(from, silenceCount), from, from+silenceCount)
+	}
+	imgui.PopItemWidth()
+}
@@ -0,0 +1,26 @@
+package archive
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+)
+
+// gameStateDescriptionName is the name of the game state description in the catalog.
+const gameStateDescriptionName = "GameState"
+
+var builtInGameStateDesc = gameStateDesc
+
+// BuiltInDescriptions returns the descriptions of archive data as they are known by this package.
+func BuiltInDescriptions() interpreters.Catalog {
+	return interpreters.Catalog{gameStateDescriptionName: builtInGameStateDesc}
+}
+
+// UseDescriptions sets the descriptions to use for archive data, named as in BuiltInDescriptions().
+// Descriptions that are not part of the catalog are reset to the built-in ones.
+// This function must not be called while data is interpreted.
+func UseDescriptions(catalog interpreters.Catalog) {
+	desc, existing := catalog[gameStateDescriptionName]
+	if !existing {
+		desc = builtInGameStateDesc
+	}
+	gameStateDesc = desc
+}
@@ -617,6 +617,12 @@ func (state GameState) HackerMapPosition() (level.Coordinate, level.Coordinate)
 	return level.CoordinateAt(byte(x>>16), byte(x>>8)), level.CoordinateAt(byte(y>>16), byte(y>>8))
 }
 
+// SetHackerMapPosition sets the X/Y location on the map. The fraction below the fine coordinate is reset.
+func (state *GameState) SetHackerMapPosition(x, y level.Coordinate) {
+	state.Set("Hacker Position X", uint32(x)<<8)
+	state.Set("Hacker Position Y", uint32(y)<<8)
+}
+
 // BooleanVar returns the state of the boolean variable at given index. Unsupported indices return 0.
 func (state GameState) BooleanVar(index int) bool {
 	if (index < 0) || (index >= BooleanVarCount) {
@@ -0,0 +1,131 @@
+package archive
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/text"
+)
+
+// GameStateAspect identifies which part of a game state a difference is about.
+type GameStateAspect string
+
+// GameStateAspect constants are listed below.
+const (
+	AspectGeneral         GameStateAspect = "General"
+	AspectBooleanVariable GameStateAspect = "Boolean Variable"
+	AspectIntegerVariable GameStateAspect = "Integer Variable"
+	AspectWeapon          GameStateAspect = "Weapon"
+	AspectAmmo            GameStateAspect = "Ammo"
+	AspectGrenade         GameStateAspect = "Grenade"
+	AspectPatch           GameStateAspect = "Patch"
+	AspectInventory       GameStateAspect = "Inventory"
+	AspectHardware        GameStateAspect = "Hardware"
+	AspectOffenseSoftware GameStateAspect = "Offense Software"
+	AspectDefenseSoftware GameStateAspect = "Defense Software"
+	AspectOneshotSoftware GameStateAspect = "One-shot Software"
+	AspectMessageEMail    GameStateAspect = "EMail"
+	AspectMessageLog      GameStateAspect = "Log"
+	AspectMessageFragment GameStateAspect = "Fragment"
+)
+
+var messageAspects = [MessageKindCount]GameStateAspect{AspectMessageEMail, AspectMessageLog, AspectMessageFragment}
+
+// GameStateDifference describes one value that differs between two game states.
+type GameStateDifference struct {
+	Aspect GameStateAspect
+	// Key identifies the field of general differences.
+	Key string
+	// Index identifies the entry within the aspect, such as the variable index. It is -1 for general differences.
+	Index int
+
+	Old string
+	New string
+}
+
+// CompareGameStates returns the list of differences between the two states.
+// The differences are ordered by aspect, then by index.
+func CompareGameStates(oldState, newState *GameState, cp text.Codepage) []GameStateDifference {
+	var diffs []GameStateDifference
+	add := func(aspect GameStateAspect, key string, index int, oldValue, newValue string) {
+		if oldValue != newValue {
+			diffs = append(diffs, GameStateDifference{Aspect: aspect, Key: key, Index: index, Old: oldValue, New: newValue})
+		}
+	}
+	number := func(value int) string { return fmt.Sprintf("%d", value) }
+	flag := func(value bool) string { return fmt.Sprintf("%v", value) }
+
+	add(AspectGeneral, "Hacker Name", -1, oldState.HackerName(cp), newState.HackerName(cp))
+	for _, key := range oldState.Keys() {
+		add(AspectGeneral, key, -1, number(int(oldState.Get(key))), number(int(newState.Get(key))))
+	}
+
+	for index := 0; index < BooleanVarCount; index++ {
+		add(AspectBooleanVariable, "", index, flag(oldState.BooleanVar(index)), flag(newState.BooleanVar(index)))
+	}
+	for index := 0; index < IntegerVarCount; index++ {
+		add(AspectIntegerVariable, "", index, number(int(oldState.IntegerVar(index))), number(int(newState.IntegerVar(index))))
+	}
+
+	weaponText := func(slot InventoryWeaponSlot) string {
+		if !slot.IsInUse() {
+			return "-"
+		}
+		a, b := slot.WeaponState()
+		return fmt.Sprintf("%v (%d, %d)", slot.Triple(), a, b)
+	}
+	for index := 0; index < InventoryWeaponSlots; index++ {
+		add(AspectWeapon, "", index, weaponText(oldState.InventoryWeaponSlot(index)), weaponText(newState.InventoryWeaponSlot(index)))
+	}
+	ammoText := func(ammo InventoryAmmo) string {
+		return fmt.Sprintf("%d clip(s) + %d", ammo.FullClipCount(), ammo.ExtraRoundsCount())
+	}
+	for index := 0; index < AmmoTypeCount; index++ {
+		add(AspectAmmo, "", index, ammoText(oldState.InventoryAmmo(index)), ammoText(newState.InventoryAmmo(index)))
+	}
+	for index := 0; index < GrenadeTypeCount; index++ {
+		add(AspectGrenade, "", index, number(oldState.InventoryGrenade(index).Count()), number(newState.InventoryGrenade(index).Count()))
+	}
+	for index := 0; index < PatchTypeCount; index++ {
+		add(AspectPatch, "", index, number(oldState.PatchState(index).Count()), number(newState.PatchState(index).Count()))
+	}
+	for index := 0; index < GeneralInventorySlotCount; index++ {
+		add(AspectInventory, "", index,
+			number(int(oldState.GeneralInventorySlot(index).ObjectID())), number(int(newState.GeneralInventorySlot(index).ObjectID())))
+	}
+	hardwareText := func(hardware HardwareState) string {
+		return fmt.Sprintf("v%d active: %v", hardware.Version(), hardware.IsActive())
+	}
+	for index := 0; index < HardwareTypeCount; index++ {
+		add(AspectHardware, "", index, hardwareText(oldState.HardwareState(index)), hardwareText(newState.HardwareState(index)))
+	}
+	for index := 0; index < SoftwareOffenseTypeCount; index++ {
+		add(AspectOffenseSoftware, "", index,
+			number(oldState.VersionedOffenseSoftwareState(index).Version()), number(newState.VersionedOffenseSoftwareState(index).Version()))
+	}
+	for index := 0; index < SoftwareDefenseTypeCount; index++ {
+		add(AspectDefenseSoftware, "", index,
+			number(oldState.VersionedDefenseSoftwareState(index).Version()), number(newState.VersionedDefenseSoftwareState(index).Version()))
+	}
+	for index := 0; index < SoftwareOneshotTypeCount; index++ {
+		add(AspectOneshotSoftware, "", index,
+			number(oldState.OneshotSoftwareState(index).Count()), number(newState.OneshotSoftwareState(index).Count()))
+	}
+
+	messageText := func(msg MessageState) string {
+		switch {
+		case msg.Read():
+			return "read"
+		case msg.Received():
+			return "received"
+		default:
+			return "-"
+		}
+	}
+	for kindIndex, kind := range MessageKinds() {
+		for index := 0; index < kind.Count(); index++ {
+			add(messageAspects[kindIndex], "", index, messageText(kind.StateIn(oldState, index)), messageText(kind.StateIn(newState, index)))
+		}
+	}
+
+	return diffs
+}
@@ -0,0 +1,45 @@
+package archive_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+)
+
+func TestCompareGameStatesReturnsNothingForEqualStates(t *testing.T) {
+	diffs := archive.CompareGameStates(archive.DefaultGameState(), archive.DefaultGameState(), text.DefaultCodepage())
+	assert.Empty(t, diffs)
+}
+
+func TestCompareGameStatesListsChangedValues(t *testing.T) {
+	oldState := archive.DefaultGameState()
+	newState := archive.DefaultGameState()
+	newState.SetBooleanVar(20, true)
+	newState.SetIntegerVar(3, 7)
+	newState.HardwareState(2).SetVersion(1)
+	newState.LogState(4).SetReceived(true)
+
+	diffs := archive.CompareGameStates(oldState, newState, text.DefaultCodepage())
+
+	assert.Equal(t, []archive.GameStateDifference{
+		{Aspect: archive.AspectBooleanVariable, Index: 20, Old: "false", New: "true"},
+		{Aspect: archive.AspectIntegerVariable, Index: 3, Old: "0", New: "7"},
+		{Aspect: archive.AspectHardware, Index: 2, Old: "v0 active: false", New: "v1 active: false"},
+		{Aspect: archive.AspectMessageLog, Index: 4, Old: "-", New: "received"},
+	}, diffs)
+}
+
+func TestCompareGameStatesListsGeneralFields(t *testing.T) {
+	oldState := archive.DefaultGameState()
+	newState := archive.DefaultGameState()
+	newState.Set("Current Level", 3)
+
+	diffs := archive.CompareGameStates(oldState, newState, text.DefaultCodepage())
+
+	assert.Equal(t, []archive.GameStateDifference{
+		{Aspect: archive.AspectGeneral, Key: "Current Level", Index: -1, Old: "0", New: "3"},
+	}, diffs)
+}
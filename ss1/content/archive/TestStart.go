@@ -0,0 +1,228 @@
+package archive
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+)
+
+// DifficultyCount is the number of difficulty settings.
+const DifficultyCount = 4
+
+// MessageKind identifies a set of message states.
+type MessageKind int
+
+// MessageKind constants are listed below.
+const (
+	MessageKindEMail    MessageKind = 0
+	MessageKindLog      MessageKind = 1
+	MessageKindFragment MessageKind = 2
+
+	// MessageKindCount is the number of known message kinds.
+	MessageKindCount = 3
+)
+
+// MessageKinds returns all known kinds of messages.
+func MessageKinds() []MessageKind {
+	return []MessageKind{MessageKindEMail, MessageKindLog, MessageKindFragment}
+}
+
+// String returns the textual representation.
+func (kind MessageKind) String() string {
+	switch kind {
+	case MessageKindEMail:
+		return "EMail"
+	case MessageKindLog:
+		return "Log"
+	case MessageKindFragment:
+		return "Fragment"
+	default:
+		return "Unknown"
+	}
+}
+
+// Count returns the number of messages of this kind.
+func (kind MessageKind) Count() int {
+	switch kind {
+	case MessageKindEMail:
+		return EMailCount
+	case MessageKindLog:
+		return LogCount
+	case MessageKindFragment:
+		return FragmentCount
+	default:
+		return 0
+	}
+}
+
+// StateIn returns the accessor for the message of given index in the given state.
+func (kind MessageKind) StateIn(state *GameState, index int) MessageState {
+	switch kind {
+	case MessageKindEMail:
+		return state.EMailState(index)
+	case MessageKindLog:
+		return state.LogState(index)
+	case MessageKindFragment:
+		return state.FragmentState(index)
+	default:
+		return MessageState{State: state, Index: -1}
+	}
+}
+
+// TestStartWeapon describes a weapon in the inventory of a test start.
+type TestStartWeapon struct {
+	Subclass object.Subclass
+	Type     object.Type
+}
+
+// TestStartMessage describes a received message of a test start.
+type TestStartMessage struct {
+	Kind  MessageKind
+	Index int
+	Read  bool
+}
+
+// TestStartBooleanVariable describes the value of a boolean variable of a test start.
+type TestStartBooleanVariable struct {
+	Index int
+	Value bool
+}
+
+// TestStartIntegerVariable describes the value of an integer variable of a test start.
+type TestStartIntegerVariable struct {
+	Index int
+	Value int16
+}
+
+// TestStart describes the conditions for a game that starts at a specific point, meant for testing.
+// Counts and versions of zero mean the respective item is not carried.
+type TestStart struct {
+	Name       string
+	HackerName string
+
+	Level int
+	X     level.Coordinate
+	Y     level.Coordinate
+	// Yaw is the direction the hacker looks at, in degrees.
+	Yaw float32
+
+	Difficulty [DifficultyCount]int
+
+	Weapons         []TestStartWeapon `json:",omitempty"`
+	AmmoClips       [AmmoTypeCount]int
+	Grenades        [GrenadeTypeCount]int
+	Patches         [PatchTypeCount]int
+	Hardware        [HardwareTypeCount]int
+	OffenseSoftware [SoftwareOffenseTypeCount]int
+	DefenseSoftware [SoftwareDefenseTypeCount]int
+	OneshotSoftware [SoftwareOneshotTypeCount]int
+
+	Messages         []TestStartMessage         `json:",omitempty"`
+	BooleanVariables []TestStartBooleanVariable `json:",omitempty"`
+	IntegerVariables []TestStartIntegerVariable `json:",omitempty"`
+}
+
+// NewTestStart returns a test start with given name at the center of the first level, on medium difficulty.
+func NewTestStart(name string) TestStart {
+	center := level.CoordinateAt(32, 0x80)
+	return TestStart{
+		Name:       name,
+		HackerName: "Tester",
+		X:          center,
+		Y:          center,
+		Difficulty: [DifficultyCount]int{2, 2, 2, 2},
+	}
+}
+
+// Message returns the state of the identified message, and whether it is set at all.
+func (start TestStart) Message(kind MessageKind, index int) (received bool, read bool) {
+	for _, msg := range start.Messages {
+		if (msg.Kind == kind) && (msg.Index == index) {
+			return true, msg.Read
+		}
+	}
+	return false, false
+}
+
+// SetMessage updates the state of the identified message. Messages that are not received are removed.
+func (start *TestStart) SetMessage(kind MessageKind, index int, received bool, read bool) {
+	var messages []TestStartMessage
+	for _, msg := range start.Messages {
+		if (msg.Kind != kind) || (msg.Index != index) {
+			messages = append(messages, msg)
+		}
+	}
+	if received {
+		messages = append(messages, TestStartMessage{Kind: kind, Index: index, Read: read})
+	}
+	start.Messages = messages
+}
+
+// ApplyTo modifies the given state to match the test start, and marks it as a savegame.
+// The height of the hacker is not modified, as it depends on the level.
+func (start TestStart) ApplyTo(state *GameState, cp text.Codepage) {
+	if len(start.HackerName) > 0 {
+		state.SetHackerName(start.HackerName, cp)
+	}
+	if state.Get("Game time") == 0 {
+		state.Set("Game time", 1)
+	}
+	state.Set("Current Level", uint32(start.Level))
+	state.SetHackerMapPosition(start.X, start.Y)
+	state.Set("Hacker Yaw", uint32(float64(start.Yaw)*float64(edmsFullCircle())/360.0))
+	for index, key := range []string{"Difficulty: Combat", "Difficulty: Mission", "Difficulty: Puzzle", "Difficulty: Cyber"} {
+		state.Set(key, uint32(start.Difficulty[index]))
+	}
+
+	for index := 0; index < InventoryWeaponSlots; index++ {
+		slot := state.InventoryWeaponSlot(index)
+		if index < len(start.Weapons) {
+			slot.SetInUse(start.Weapons[index].Subclass, start.Weapons[index].Type)
+		} else {
+			slot.SetFree()
+		}
+	}
+	for index, count := range start.AmmoClips {
+		state.InventoryAmmo(index).SetFullClipCount(count)
+		state.InventoryAmmo(index).SetExtraRoundsCount(0)
+	}
+	for index, count := range start.Grenades {
+		state.InventoryGrenade(index).SetCount(count)
+	}
+	for index, count := range start.Patches {
+		state.PatchState(index).SetCount(count)
+	}
+	for index, version := range start.Hardware {
+		state.HardwareState(index).SetVersion(version)
+		state.HardwareState(index).SetActive(false)
+	}
+	for index, version := range start.OffenseSoftware {
+		state.VersionedOffenseSoftwareState(index).SetVersion(version)
+	}
+	for index, version := range start.DefenseSoftware {
+		state.VersionedDefenseSoftwareState(index).SetVersion(version)
+	}
+	for index, count := range start.OneshotSoftware {
+		state.OneshotSoftwareState(index).SetCount(count)
+	}
+
+	for _, kind := range MessageKinds() {
+		for index := 0; index < kind.Count(); index++ {
+			msg := kind.StateIn(state, index)
+			msg.SetReceived(false)
+			msg.SetRead(false)
+		}
+	}
+	for _, msg := range start.Messages {
+		msgState := msg.Kind.StateIn(state, msg.Index)
+		msgState.SetReceived(true)
+		msgState.SetRead(msg.Read)
+	}
+
+	for _, variable := range start.BooleanVariables {
+		state.SetBooleanVar(variable.Index, variable.Value)
+	}
+	for _, variable := range start.IntegerVariables {
+		state.SetIntegerVar(variable.Index, variable.Value)
+	}
+}
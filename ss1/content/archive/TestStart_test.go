@@ -0,0 +1,86 @@
+package archive_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+func TestTestStartApplyToCreatesSavegame(t *testing.T) {
+	state := archive.DefaultGameState()
+	start := archive.NewTestStart("deck 7")
+	start.Level = 7
+	start.X = level.CoordinateAt(10, 0x80)
+	start.Y = level.CoordinateAt(20, 0x40)
+
+	start.ApplyTo(state, text.DefaultCodepage())
+
+	res := resource.Resource{
+		Properties: resource.Properties{ContentType: resource.Archive},
+		Blocks:     resource.BlocksFrom([][]byte{state.Raw()}),
+	}
+	assert.True(t, archive.IsSavegame(res), "should be a savegame")
+	assert.Equal(t, 7, state.CurrentLevel())
+	x, y := state.HackerMapPosition()
+	assert.Equal(t, start.X, x, "X mismatch")
+	assert.Equal(t, start.Y, y, "Y mismatch")
+	assert.Equal(t, "Tester", state.HackerName(text.DefaultCodepage()))
+}
+
+func TestTestStartApplyToSetsInventory(t *testing.T) {
+	state := archive.DefaultGameState()
+	state.InventoryWeaponSlot(3).SetInUse(1, 2)
+	start := archive.NewTestStart("gear")
+	start.Weapons = []archive.TestStartWeapon{{Subclass: 0, Type: 1}}
+	start.AmmoClips[2] = 5
+	start.Hardware[4] = 3
+	start.OffenseSoftware[1] = 2
+
+	start.ApplyTo(state, text.DefaultCodepage())
+
+	assert.Equal(t, object.TripleFrom(int(object.ClassGun), 0, 1), state.InventoryWeaponSlot(0).Triple())
+	assert.False(t, state.InventoryWeaponSlot(3).IsInUse(), "other slots should be free")
+	assert.Equal(t, 5, state.InventoryAmmo(2).FullClipCount())
+	assert.Equal(t, 3, state.HardwareState(4).Version())
+	assert.Equal(t, 2, state.VersionedOffenseSoftwareState(1).Version())
+}
+
+func TestTestStartApplyToSetsMessagesAndVariables(t *testing.T) {
+	state := archive.DefaultGameState()
+	state.LogState(5).SetReceived(true)
+	start := archive.NewTestStart("progress")
+	start.SetMessage(archive.MessageKindEMail, 3, true, true)
+	start.SetMessage(archive.MessageKindFragment, 1, true, false)
+	start.BooleanVariables = []archive.TestStartBooleanVariable{{Index: 20, Value: true}}
+	start.IntegerVariables = []archive.TestStartIntegerVariable{{Index: 10, Value: -3}}
+
+	start.ApplyTo(state, text.DefaultCodepage())
+
+	assert.True(t, state.EMailState(3).Received(), "email should be received")
+	assert.True(t, state.EMailState(3).Read(), "email should be read")
+	assert.True(t, state.FragmentState(1).Received(), "fragment should be received")
+	assert.False(t, state.FragmentState(1).Read(), "fragment should not be read")
+	assert.False(t, state.LogState(5).Received(), "previous log should be reset")
+	assert.True(t, state.BooleanVar(20), "boolean variable should be set")
+	assert.Equal(t, int16(-3), state.IntegerVar(10))
+}
+
+func TestTestStartSetMessageReplacesEntries(t *testing.T) {
+	start := archive.NewTestStart("messages")
+	start.SetMessage(archive.MessageKindLog, 2, true, false)
+	start.SetMessage(archive.MessageKindLog, 2, true, true)
+	received, read := start.Message(archive.MessageKindLog, 2)
+	assert.True(t, received, "should be received")
+	assert.True(t, read, "should be read")
+	assert.Equal(t, 1, len(start.Messages))
+
+	start.SetMessage(archive.MessageKindLog, 2, false, false)
+	received, _ = start.Message(archive.MessageKindLog, 2)
+	assert.False(t, received, "should be removed")
+}
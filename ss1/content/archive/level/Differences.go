@@ -0,0 +1,200 @@
+package level
+
+import (
+	"bytes"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+)
+
+// ObjectChange is a set of flags describing how an object differs between two levels.
+type ObjectChange byte
+
+// ObjectChange constants are listed below.
+const (
+	// ObjectAdded marks an object that only exists in the new level.
+	ObjectAdded ObjectChange = 0x01
+	// ObjectRemoved marks an object that only exists in the old level, such as destroyed or picked up objects.
+	ObjectRemoved ObjectChange = 0x02
+	// ObjectReplaced marks an entry that is used by an object of a different type.
+	ObjectReplaced ObjectChange = 0x04
+	// ObjectMoved marks an object with a different position or orientation.
+	ObjectMoved ObjectChange = 0x08
+	// ObjectStateChanged marks an object with different hitpoints or properties.
+	ObjectStateChanged ObjectChange = 0x10
+)
+
+// Has returns true if all the given flags are set.
+func (change ObjectChange) Has(flags ObjectChange) bool {
+	return (change & flags) == flags
+}
+
+// String returns a textual representation of the set flags.
+func (change ObjectChange) String() string {
+	names := []struct {
+		flag ObjectChange
+		name string
+	}{
+		{flag: ObjectAdded, name: "added"},
+		{flag: ObjectRemoved, name: "removed"},
+		{flag: ObjectReplaced, name: "replaced"},
+		{flag: ObjectMoved, name: "moved"},
+		{flag: ObjectStateChanged, name: "changed"},
+	}
+	result := ""
+	for _, entry := range names {
+		if change.Has(entry.flag) {
+			if len(result) > 0 {
+				result += ", "
+			}
+			result += entry.name
+		}
+	}
+	return result
+}
+
+// ObjectDifference describes the difference of one object entry between two levels.
+type ObjectDifference struct {
+	ID     ObjectID
+	Change ObjectChange
+
+	Old ObjectMainEntry
+	New ObjectMainEntry
+
+	// ChangedProperties lists the keys of class and extra data properties that differ.
+	// Properties of refinements are listed as "refinement.key".
+	ChangedProperties []string
+}
+
+// Differences describes how two levels differ.
+type Differences struct {
+	Objects []ObjectDifference
+	Tiles   []TilePosition
+	// VisitedTiles is the number of tiles with a different automap visited state.
+	// This state is not considered for the list of changed tiles.
+	VisitedTiles int
+}
+
+// IsEmpty returns true if there are no differences.
+func (diff Differences) IsEmpty() bool {
+	return (len(diff.Objects) == 0) && (len(diff.Tiles) == 0) && (diff.VisitedTiles == 0)
+}
+
+// CompareLevels returns the differences between the two levels.
+// Objects are compared by their identifier, tiles by their position.
+func CompareLevels(oldLevel, newLevel *Level) Differences {
+	var diff Differences
+	diff.Objects = compareObjects(oldLevel, newLevel)
+	diff.Tiles, diff.VisitedTiles = compareTiles(oldLevel, newLevel)
+	return diff
+}
+
+func compareObjects(oldLevel, newLevel *Level) []ObjectDifference {
+	var result []ObjectDifference
+	capacity := oldLevel.ObjectCapacity()
+	if newCapacity := newLevel.ObjectCapacity(); newCapacity > capacity {
+		capacity = newCapacity
+	}
+	for id := ObjectID(1); int(id) < capacity; id++ {
+		oldEntry := oldLevel.Object(id)
+		newEntry := newLevel.Object(id)
+		oldInUse := (oldEntry != nil) && (oldEntry.InUse != 0)
+		newInUse := (newEntry != nil) && (newEntry.InUse != 0)
+		if !oldInUse && !newInUse {
+			continue
+		}
+		objDiff := ObjectDifference{ID: id}
+		if oldInUse {
+			objDiff.Old = *oldEntry
+		}
+		if newInUse {
+			objDiff.New = *newEntry
+		}
+		switch {
+		case !oldInUse:
+			objDiff.Change = ObjectAdded
+		case !newInUse:
+			objDiff.Change = ObjectRemoved
+		case oldEntry.Triple() != newEntry.Triple():
+			objDiff.Change = ObjectReplaced
+		default:
+			if (oldEntry.X != newEntry.X) || (oldEntry.Y != newEntry.Y) || (oldEntry.Z != newEntry.Z) ||
+				(oldEntry.XRotation != newEntry.XRotation) || (oldEntry.YRotation != newEntry.YRotation) ||
+				(oldEntry.ZRotation != newEntry.ZRotation) {
+				objDiff.Change |= ObjectMoved
+			}
+			objDiff.ChangedProperties = append(objDiff.ChangedProperties,
+				changedProperties(oldLevel.ObjectClassData(oldEntry), newLevel.ObjectClassData(newEntry))...)
+			objDiff.ChangedProperties = append(objDiff.ChangedProperties,
+				changedProperties(oldLevel.ObjectExtraData(oldEntry), newLevel.ObjectExtraData(newEntry))...)
+			if (oldEntry.Hitpoints != newEntry.Hitpoints) || (len(objDiff.ChangedProperties) > 0) {
+				objDiff.Change |= ObjectStateChanged
+			}
+		}
+		if objDiff.Change != 0 {
+			result = append(result, objDiff)
+		}
+	}
+	return result
+}
+
+func changedProperties(oldData, newData *interpreters.Instance) []string {
+	if bytes.Equal(oldData.Raw(), newData.Raw()) {
+		return nil
+	}
+	var keys []string
+	for _, key := range oldData.Keys() {
+		if oldData.Get(key) != newData.Get(key) {
+			keys = append(keys, key)
+		}
+	}
+	for _, refinementKey := range oldData.ActiveRefinements() {
+		for _, key := range changedProperties(oldData.Refined(refinementKey), newData.Refined(refinementKey)) {
+			keys = append(keys, refinementKey+"."+key)
+		}
+	}
+	if len(keys) == 0 {
+		keys = append(keys, "(raw)")
+	}
+	return keys
+}
+
+func compareTiles(oldLevel, newLevel *Level) (changed []TilePosition, visited int) {
+	oldWidth, oldHeight, _ := oldLevel.Size()
+	newWidth, newHeight, _ := newLevel.Size()
+	width, height := oldWidth, oldHeight
+	if newWidth > width {
+		width = newWidth
+	}
+	if newHeight > height {
+		height = newHeight
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			pos := TilePosition{X: byte(x), Y: byte(y)}
+			oldTile := oldLevel.Tile(pos)
+			newTile := newLevel.Tile(pos)
+			if (oldTile == nil) && (newTile == nil) {
+				continue
+			}
+			if (oldTile == nil) || (newTile == nil) {
+				changed = append(changed, pos)
+				continue
+			}
+			if oldTile.Flags.ForRealWorld().TileVisited() != newTile.Flags.ForRealWorld().TileVisited() {
+				visited++
+			}
+			if comparableTile(*oldTile) != comparableTile(*newTile) {
+				changed = append(changed, pos)
+			}
+		}
+	}
+	return changed, visited
+}
+
+// comparableTile returns the tile without the properties that are not compared.
+// The visited flag is automap state, and the first object index only follows changes of objects.
+func comparableTile(tile TileMapEntry) TileMapEntry {
+	tile.Flags = tile.Flags.ForRealWorld().WithTileVisited(false).AsTileFlag()
+	tile.FirstObjectIndex = 0
+	return tile
+}
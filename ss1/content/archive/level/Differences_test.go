@@ -0,0 +1,115 @@
+package level_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+func TestCompareLevelsReturnsNothingForEqualLevels(t *testing.T) {
+	oldLevel := levelFrom(t, level.EmptyLevelData(level.EmptyLevelParameters{}))
+	newLevel := levelFrom(t, level.EmptyLevelData(level.EmptyLevelParameters{}))
+
+	diff := level.CompareLevels(oldLevel, newLevel)
+
+	assert.True(t, diff.IsEmpty(), "no differences expected")
+}
+
+func TestCompareLevelsListsChangedTiles(t *testing.T) {
+	oldLevel := levelFrom(t, level.EmptyLevelData(level.EmptyLevelParameters{}))
+	newLevel := levelFrom(t, level.EmptyLevelData(level.EmptyLevelParameters{}))
+	newLevel.Tile(level.TilePosition{X: 3, Y: 4}).Type = level.TileTypeOpen
+
+	diff := level.CompareLevels(oldLevel, newLevel)
+
+	assert.Equal(t, []level.TilePosition{{X: 3, Y: 4}}, diff.Tiles)
+}
+
+func TestCompareLevelsCountsVisitedTilesSeparately(t *testing.T) {
+	oldLevel := levelFrom(t, level.EmptyLevelData(level.EmptyLevelParameters{}))
+	newLevel := levelFrom(t, level.EmptyLevelData(level.EmptyLevelParameters{}))
+	tile := newLevel.Tile(level.TilePosition{X: 3, Y: 4})
+	tile.Flags = tile.Flags.ForRealWorld().WithTileVisited(true).AsTileFlag()
+
+	diff := level.CompareLevels(oldLevel, newLevel)
+
+	assert.Equal(t, 0, len(diff.Tiles), "no tile difference expected")
+	assert.Equal(t, 1, diff.VisitedTiles, "one visited tile expected")
+}
+
+func TestCompareLevelsIgnoresFirstObjectIndexOfTiles(t *testing.T) {
+	oldLevel := levelFrom(t, level.EmptyLevelData(level.EmptyLevelParameters{}))
+	newLevel := levelFrom(t, level.EmptyLevelData(level.EmptyLevelParameters{}))
+	newLevel.Tile(level.TilePosition{X: 3, Y: 4}).FirstObjectIndex = 2
+
+	diff := level.CompareLevels(oldLevel, newLevel)
+
+	assert.True(t, diff.IsEmpty(), "no differences expected")
+}
+
+func TestCompareLevelsListsObjectChanges(t *testing.T) {
+	baseData := levelDataWithObjects(t, 3)
+	oldLevel := levelFrom(t, baseData)
+	newLevel := levelFrom(t, baseData)
+	addedID, err := newLevel.NewObject(object.ClassGun)
+	require.Nil(t, err, "no error expected adding object")
+	newLevel.DelObject(1)
+	newLevel.Object(2).X = level.CoordinateAt(10, 0x80)
+	newLevel.Object(3).Hitpoints = 5
+
+	diff := level.CompareLevels(oldLevel, newLevel)
+
+	require.Equal(t, 4, len(diff.Objects), "four differences expected")
+	changes := make(map[level.ObjectID]level.ObjectChange)
+	for _, objDiff := range diff.Objects {
+		changes[objDiff.ID] = objDiff.Change
+	}
+	assert.Equal(t, level.ObjectRemoved, changes[1], "object 1 should be removed")
+	assert.Equal(t, level.ObjectMoved, changes[2], "object 2 should be moved")
+	assert.Equal(t, level.ObjectStateChanged, changes[3], "object 3 should be changed")
+	assert.Equal(t, level.ObjectAdded, changes[addedID], "new object should be added")
+}
+
+func TestObjectChangeString(t *testing.T) {
+	assert.Equal(t, "moved, changed", (level.ObjectMoved | level.ObjectStateChanged).String())
+	assert.Equal(t, "", level.ObjectChange(0).String())
+}
+
+func levelDataWithObjects(t *testing.T, count int) [lvlids.PerLevel][]byte {
+	t.Helper()
+	levelData := level.EmptyLevelData(level.EmptyLevelParameters{})
+	lvl := levelFrom(t, levelData)
+	for i := 0; i < count; i++ {
+		_, err := lvl.NewObject(object.ClassGun)
+		require.Nil(t, err, "no error expected creating object")
+	}
+	for index, data := range lvl.EncodeState() {
+		if len(data) > 0 {
+			levelData[index] = data
+		}
+	}
+	return levelData
+}
+
+func levelFrom(t *testing.T, levelData [lvlids.PerLevel][]byte) *level.Level {
+	t.Helper()
+	var store resource.Store
+	for index, data := range levelData {
+		if data == nil {
+			continue
+		}
+		err := store.Put(resource.ID(0x0FA0).Plus(index), resource.Resource{
+			Properties: resource.Properties{ContentType: resource.Archive},
+			Blocks:     resource.BlocksFrom([][]byte{data}),
+		})
+		require.Nil(t, err, "no error expected storing data")
+	}
+	localizer := resource.LocalizedResourcesList{{ID: "test", Language: resource.LangAny, Viewer: store}}
+	return level.NewLevel(resource.ID(0x0FA0), 0, localizer)
+}
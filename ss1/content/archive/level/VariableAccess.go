@@ -0,0 +1,115 @@
+package level
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+)
+
+// VariableAccessKind describes how a game variable is accessed.
+type VariableAccessKind int
+
+// VariableAccessKind constants are listed below.
+const (
+	VariableRead  VariableAccessKind = 0
+	VariableWrite VariableAccessKind = 1
+)
+
+// String returns the textual representation of the kind.
+func (kind VariableAccessKind) String() string {
+	switch kind {
+	case VariableRead:
+		return "Read"
+	case VariableWrite:
+		return "Write"
+	default:
+		return fmt.Sprintf("Unknown%d", int(kind))
+	}
+}
+
+// VariableReference identifies one boolean or integer game variable.
+type VariableReference struct {
+	Integer bool
+	Index   int
+}
+
+// String returns the textual representation of the reference.
+func (ref VariableReference) String() string {
+	if ref.Integer {
+		return fmt.Sprintf("Int%02d", ref.Index)
+	}
+	return fmt.Sprintf("Bool%03d", ref.Index)
+}
+
+const (
+	variableKeyIntegerFlag = 0x1000
+	variableKeyIndexMask   = 0x01FF
+)
+
+// VariableReferenceFromKey returns the reference encoded in a variable key of object properties.
+func VariableReferenceFromKey(key uint32) VariableReference {
+	return VariableReference{
+		Integer: (key & variableKeyIntegerFlag) != 0,
+		Index:   int(key & variableKeyIndexMask),
+	}
+}
+
+// VariableAccess describes one access to a game variable by object properties.
+type VariableAccess struct {
+	Variable VariableReference
+	Kind     VariableAccessKind
+	// Key is the full property key that holds the variable reference, such as "Action.SetGameVariable.VariableKey".
+	Key string
+}
+
+// VariableAccessesOf returns all game variable accesses described by the given object properties.
+// Only active refinements are considered. Conditions and locks that refer to the zero key are
+// considered to be unused and are not reported.
+func VariableAccessesOf(data *interpreters.Instance) []VariableAccess {
+	var accesses []VariableAccess
+	var current string
+	var currentValue uint32
+	report := func(kind VariableAccessKind, ref VariableReference) {
+		accesses = append(accesses, VariableAccess{Variable: ref, Kind: kind, Key: current})
+	}
+	simplifier := interpreters.NewSimplifier(func(minValue, maxValue int64, formatter interpreters.RawValueFormatter) {})
+	simplifier.SetSpecialHandler("VariableKey", func() {
+		report(VariableWrite, VariableReferenceFromKey(currentValue))
+	})
+	simplifier.SetSpecialHandler("VariableSource", func() {
+		report(VariableRead, VariableReferenceFromKey(currentValue))
+	})
+	simplifier.SetSpecialHandler("VariableCondition", func() {
+		if currentValue != 0 {
+			report(VariableRead, VariableReferenceFromKey(currentValue))
+		}
+	})
+	simplifier.SetSpecialHandler("LockVariable", func() {
+		if currentValue != 0 {
+			report(VariableRead, VariableReference{Index: int(currentValue & variableKeyIndexMask)})
+		}
+	})
+
+	var process func(string, *interpreters.Instance)
+	process = func(path string, inst *interpreters.Instance) {
+		for _, key := range inst.Keys() {
+			current = path + key
+			currentValue = inst.Get(key)
+			inst.Describe(key, simplifier)
+		}
+		for _, key := range inst.ActiveRefinements() {
+			process(path+key+".", inst.Refined(key))
+		}
+	}
+	process("", data)
+	return accesses
+}
+
+// ForEachVariableAccess iterates over all active objects and calls the handler for each of their game variable accesses.
+func (lvl *Level) ForEachVariableAccess(handler func(ObjectID, VariableAccess)) {
+	lvl.ForEachObject(func(id ObjectID, entry ObjectMainEntry) {
+		for _, access := range VariableAccessesOf(lvl.ObjectClassData(&entry)) {
+			handler(id, access)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package level_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlobj"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariableReferenceFromKey(t *testing.T) {
+	assert.Equal(t, level.VariableReference{Integer: false, Index: 0x17}, level.VariableReferenceFromKey(0x0017))
+	assert.Equal(t, level.VariableReference{Integer: true, Index: 5}, level.VariableReferenceFromKey(0x5005))
+}
+
+func TestVariableAccessesOfTrapWithConditionAndSetAction(t *testing.T) {
+	data := make([]byte, 28)
+	data[0] = 4 // Set Game Variable
+	data[2] = 0x05
+	data[3] = 0x10
+	data[6] = 0x17
+	inst := lvlobj.ForRealWorld(object.TripleFrom(int(object.ClassTrap), 0, 0), data)
+
+	accesses := level.VariableAccessesOf(inst)
+
+	assert.Equal(t, []level.VariableAccess{
+		{Variable: level.VariableReference{Integer: false, Index: 0x17}, Kind: level.VariableWrite, Key: "Action.SetGameVariable.VariableKey"},
+		{Variable: level.VariableReference{Integer: true, Index: 5}, Kind: level.VariableRead, Key: "Condition.VariableKey"},
+	}, accesses)
+}
+
+func TestVariableAccessesOfIgnoresUnusedCondition(t *testing.T) {
+	data := make([]byte, 28)
+	inst := lvlobj.ForRealWorld(object.TripleFrom(int(object.ClassTrap), 0, 0), data)
+
+	accesses := level.VariableAccessesOf(inst)
+
+	assert.Empty(t, accesses)
+}
+
+func TestVariableAccessesOfDoorLock(t *testing.T) {
+	data := make([]byte, 16)
+	data[0] = 0x30
+	inst := lvlobj.ForRealWorld(object.TripleFrom(int(object.ClassDoor), 0, 0), data)
+
+	accesses := level.VariableAccessesOf(inst)
+
+	assert.Equal(t, []level.VariableAccess{
+		{Variable: level.VariableReference{Integer: false, Index: 0x30}, Kind: level.VariableRead, Key: "LockVariableIndex"},
+	}, accesses)
+}
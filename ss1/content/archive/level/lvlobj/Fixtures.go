@@ -11,17 +11,17 @@ import (
 var baseFixture = interpreters.New()
 
 var gameVariablePanel = baseFixture.
-	Refining("Condition", 2, 4, conditions.GameVariable(), interpreters.Always)
+	RefiningWhen("Condition", 2, 4, conditions.GameVariable(), interpreters.Unconditional)
 
 var buttonPanel = gameVariablePanel.
-	Refining("Action", 0, 22, actions.Unconditional(), interpreters.Always).
+	RefiningWhen("Action", 0, 22, actions.Unconditional(), interpreters.Unconditional).
 	With("AccessMask", 22, 2)
 
 var recepticlePanel = baseFixture
 
 var standardRecepticle = recepticlePanel.
-	Refining("Action", 0, 22, actions.Unconditional(), interpreters.Always).
-	Refining("TypeCondition", 2, 3, conditions.ObjectType(), interpreters.Always).
+	RefiningWhen("Action", 0, 22, actions.Unconditional(), interpreters.Unconditional).
+	RefiningWhen("TypeCondition", 2, 3, conditions.ObjectType(), interpreters.Unconditional).
 	With("FailMessage", 5, 1).As(interpreters.RangedValue(0, 255))
 
 var antennaRelayPanel = recepticlePanel.
@@ -30,7 +30,7 @@ var antennaRelayPanel = recepticlePanel.
 	With("DestroyObjectID", 14, 2).As(interpreters.ObjectID())
 
 var retinalIDScanner = recepticlePanel.
-	Refining("Action", 0, 22, actions.Unconditional(), interpreters.Always).
+	RefiningWhen("Action", 0, 22, actions.Unconditional(), interpreters.Unconditional).
 	With("Head", 2, 3).As(interpreters.FormattedRangedValue(0, 21,
 	func(value int) (result string) {
 		if value < 11 {
@@ -41,7 +41,7 @@ var retinalIDScanner = recepticlePanel.
 	}))
 
 var cyberspaceTerminal = gameVariablePanel.
-	Refining("Action", 0, 22, actions.Unconditional(), interpreters.Always)
+	RefiningWhen("Action", 0, 22, actions.Unconditional(), interpreters.Unconditional)
 
 var energyChargeStation = gameVariablePanel.
 	With("EnergyDelta", 6, 4).As(interpreters.RangedValue(0, 255)).
@@ -53,7 +53,7 @@ var energyChargeStation = gameVariablePanel.
 	With("RechargedTimestamp", 18, 4)
 
 var vendingMachine = baseFixture.
-	Refining("TypeCondition", 2, 3, conditions.ObjectType(), interpreters.Always).
+	RefiningWhen("TypeCondition", 2, 3, conditions.ObjectType(), interpreters.Unconditional).
 	With("FailMessage", 5, 1).As(interpreters.RangedValue(0, 255))
 
 var inputPanel = gameVariablePanel
@@ -95,15 +95,11 @@ var blockPuzzleData = interpreters.New().
 
 var puzzleSpecificData = interpreters.New().
 	With("Type", 7, 1).As(interpreters.EnumValue(map[uint32]string{0: "WirePuzzle", 0x10: "BlockPuzzle"})).
-	Refining("Wire", 0, 18, wirePuzzleData, func(inst *interpreters.Instance) bool {
-		return inst.Get("Type") == 0
-	}).
-	Refining("Block", 0, 18, blockPuzzleData, func(inst *interpreters.Instance) bool {
-		return inst.Get("Type") == 0x10
-	})
+	RefiningWhen("Wire", 0, 18, wirePuzzleData, interpreters.FieldIn("Type", 0)).
+	RefiningWhen("Block", 0, 18, blockPuzzleData, interpreters.FieldIn("Type", 0x10))
 
 var puzzlePanel = inputPanel.
-	Refining("Puzzle", 6, 18, puzzleSpecificData, interpreters.Always)
+	RefiningWhen("Puzzle", 6, 18, puzzleSpecificData, interpreters.Unconditional)
 
 var elevatorPanel = inputPanel.
 	With("DestinationObjectIndex2", 6, 2).As(interpreters.RangedValue(0, 871)).
@@ -157,7 +153,7 @@ var numberPad = inputPanel.
 	With("FailObjectID", 18, 2).As(interpreters.ObjectID())
 
 var inactiveCyberspaceSwitch = gameVariablePanel.
-	Refining("Action", 0, 22, actions.Unconditional(), interpreters.Always)
+	RefiningWhen("Action", 0, 22, actions.Unconditional(), interpreters.Unconditional)
 
 func initFixtures() interpreterRetriever {
 	standardRecepticles := newInterpreterLeaf(standardRecepticle)
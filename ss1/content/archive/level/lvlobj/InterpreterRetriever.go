@@ -1,12 +1,43 @@
 package lvlobj
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/inkyblackness/hacked/ss1/content/interpreters"
 )
 
 type interpreterRetriever interface {
 	specialize(key int) interpreterRetriever
 	instance(data []byte) *interpreters.Instance
+	// describe registers the descriptions of the retriever, and all nested ones, under given name.
+	describe(name string, catalog interpreters.Catalog)
+	// overridden returns a copy of the retriever that uses the descriptions of the catalog.
+	// Descriptions that are not part of the catalog are kept.
+	overridden(name string, catalog interpreters.Catalog) interpreterRetriever
+}
+
+func nestedName(name string, key int) string {
+	return name + "/" + strconv.Itoa(key)
+}
+
+// nestedKeys returns the keys of all descriptions of the catalog that are nested under given name.
+func nestedKeys(name string, catalog interpreters.Catalog) []int {
+	prefix := name + "/"
+	found := make(map[int]bool)
+	var keys []int
+	for catalogName := range catalog {
+		if !strings.HasPrefix(catalogName, prefix) {
+			continue
+		}
+		keyText := strings.SplitN(strings.TrimPrefix(catalogName, prefix), "/", 2)[0]
+		key, err := strconv.Atoi(keyText)
+		if (err == nil) && !found[key] {
+			found[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
 }
 
 type interpreterLeaf struct {
@@ -25,6 +56,27 @@ func (node *interpreterLeaf) instance(data []byte) *interpreters.Instance {
 	return node.desc.For(data)
 }
 
+func (node *interpreterLeaf) describe(name string, catalog interpreters.Catalog) {
+	catalog[name] = node.desc
+}
+
+func (node *interpreterLeaf) overridden(name string, catalog interpreters.Catalog) interpreterRetriever {
+	if len(nestedKeys(name, catalog)) > 0 {
+		// The leaf applies to all nested keys. Nested descriptions turn it into an entry.
+		entry := &interpreterEntry{defaultLeaf: node, subEntries: make(map[int]interpreterRetriever)}
+		return entry.overriddenEntry(name, catalog)
+	}
+	return node.overriddenLeaf(name, catalog)
+}
+
+func (node *interpreterLeaf) overriddenLeaf(name string, catalog interpreters.Catalog) *interpreterLeaf {
+	desc, existing := catalog[name]
+	if !existing {
+		return node
+	}
+	return newInterpreterLeaf(desc)
+}
+
 type interpreterEntry struct {
 	defaultLeaf *interpreterLeaf
 	subEntries  map[int]interpreterRetriever
@@ -51,3 +103,31 @@ func (node *interpreterEntry) specialize(key int) (retriever interpreterRetrieve
 func (node *interpreterEntry) instance(data []byte) *interpreters.Instance {
 	return node.defaultLeaf.instance(data)
 }
+
+func (node *interpreterEntry) describe(name string, catalog interpreters.Catalog) {
+	node.defaultLeaf.describe(name, catalog)
+	for key, sub := range node.subEntries {
+		sub.describe(nestedName(name, key), catalog)
+	}
+}
+
+func (node *interpreterEntry) overridden(name string, catalog interpreters.Catalog) interpreterRetriever {
+	return node.overriddenEntry(name, catalog)
+}
+
+func (node *interpreterEntry) overriddenEntry(name string, catalog interpreters.Catalog) *interpreterEntry {
+	result := &interpreterEntry{
+		defaultLeaf: node.defaultLeaf.overriddenLeaf(name, catalog),
+		subEntries:  make(map[int]interpreterRetriever),
+	}
+	for key, sub := range node.subEntries {
+		result.subEntries[key] = sub.overridden(nestedName(name, key), catalog)
+	}
+	for _, key := range nestedKeys(name, catalog) {
+		if _, existing := result.subEntries[key]; !existing {
+			added := &interpreterEntry{defaultLeaf: result.defaultLeaf, subEntries: make(map[int]interpreterRetriever)}
+			result.subEntries[key] = added.overriddenEntry(nestedName(name, key), catalog)
+		}
+	}
+	return result
+}
@@ -23,30 +23,26 @@ var aiHint = baseTraps.
 	With("TriggerObjectID", 20, 2).As(interpreters.ObjectID())
 
 var baseTrigger = baseTraps.
-	Refining("Action", 0, 22, actions.Unconditional(), interpreters.Always)
+	RefiningWhen("Action", 0, 22, actions.Unconditional(), interpreters.Unconditional)
 
 var gameVariableTrigger = baseTrigger.
-	Refining("Condition", 2, 4, conditions.GameVariable(), interpreters.Always)
+	RefiningWhen("Condition", 2, 4, conditions.GameVariable(), interpreters.Unconditional)
 
 var puzzleData = interpreters.New()
 
 var nullTrigger = baseTraps.
-	Refining("Action", 0, 22, actions.Unconditional().
-		Refining("PuzzleData", 6, 16, puzzleData, func(inst *interpreters.Instance) bool { return inst.Get("Type") == 0 }),
-		interpreters.Always).
-	Refining("Condition", 2, 4, conditions.GameVariable(), interpreters.Always)
+	RefiningWhen("Action", 0, 22, actions.Unconditional().
+		RefiningWhen("PuzzleData", 6, 16, puzzleData, interpreters.FieldIn("Type", 0)),
+		interpreters.Unconditional).
+	RefiningWhen("Condition", 2, 4, conditions.GameVariable(), interpreters.Unconditional)
 
 var deathWatchTrigger = baseTrigger.
 	With("ConditionType", 5, 1).As(interpreters.EnumValue(map[uint32]string{0: "Object Type", 1: "Object ID"})).
-	Refining("TypeCondition", 2, 4, conditions.ObjectType(), func(inst *interpreters.Instance) bool {
-		return inst.Get("ConditionType") == 0
-	}).
-	Refining("IndexCondition", 2, 4, conditions.ObjectID(), func(inst *interpreters.Instance) bool {
-		return inst.Get("ConditionType") == 1
-	})
+	RefiningWhen("TypeCondition", 2, 4, conditions.ObjectType(), interpreters.FieldIn("ConditionType", 0)).
+	RefiningWhen("IndexCondition", 2, 4, conditions.ObjectID(), interpreters.FieldIn("ConditionType", 1))
 
 var ecologyTrigger = baseTrigger.
-	Refining("TypeCondition", 2, 4, conditions.ObjectType(), interpreters.Always).
+	RefiningWhen("TypeCondition", 2, 4, conditions.ObjectType(), interpreters.Unconditional).
 	With("ConditionLimit", 5, 1)
 
 var mapNote = baseTraps.
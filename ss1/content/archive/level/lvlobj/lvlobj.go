@@ -1,6 +1,9 @@
 package lvlobj
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/inkyblackness/hacked/ss1/content/interpreters"
 	"github.com/inkyblackness/hacked/ss1/content/object"
 )
@@ -11,6 +14,16 @@ var cyberspaceEntries *interpreterEntry
 var realWorldExtras *interpreterEntry
 var cyberspaceExtras *interpreterEntry
 
+// builtIn is the set of all the entries as they were initialized.
+var builtIn map[string]*interpreterEntry
+
+const (
+	realWorldName       = "RealWorld"
+	cyberspaceName      = "Cyberspace"
+	realWorldExtraName  = "RealWorldExtra"
+	cyberspaceExtraName = "CyberspaceExtra"
+)
+
 var extraDefault = interpreters.New().
 	With("CurrentFrame", 1, 1).
 	With("TimeRemainder", 2, 1)
@@ -74,4 +87,50 @@ func init() {
 	cyberspaceExtras.set(int(object.ClassBigStuff), newInterpreterLeaf(extraIced))
 	cyberspaceExtras.set(int(object.ClassSmallStuff), newInterpreterLeaf(extraIced))
 	cyberspaceExtras.set(int(object.ClassFixture), newInterpreterLeaf(extraIcedFixtures))
+
+	builtIn = map[string]*interpreterEntry{
+		realWorldName:       realWorldEntries,
+		cyberspaceName:      cyberspaceEntries,
+		realWorldExtraName:  realWorldExtras,
+		cyberspaceExtraName: cyberspaceExtras,
+	}
+}
+
+// BuiltInDescriptions returns the descriptions of level object data as they are known by this package.
+// The descriptions are named by the kind of data, followed by the class, subclass, and type.
+// For example, "RealWorld/7/2/0" is for the class data of a real world object of triple 7/2/0.
+// Descriptions without subclass or type apply to all objects that have no more specific description.
+func BuiltInDescriptions() interpreters.Catalog {
+	catalog := make(interpreters.Catalog)
+	for name, entry := range builtIn {
+		entry.describe(name, catalog)
+	}
+	return catalog
+}
+
+// IsDescriptionName returns whether the given name identifies level object data, as used by BuiltInDescriptions().
+// This includes names of descriptions that are not built in, such as for objects without specific data.
+func IsDescriptionName(name string) bool {
+	parts := strings.Split(name, "/")
+	if _, known := builtIn[parts[0]]; !known || (len(parts) > 4) {
+		return false
+	}
+	for _, part := range parts[1:] {
+		value, err := strconv.Atoi(part)
+		if (err != nil) || (value < 0) || (value > 0xFF) || (strconv.Itoa(value) != part) {
+			return false
+		}
+	}
+	return true
+}
+
+// UseDescriptions sets the descriptions to use for level object data, named as in BuiltInDescriptions().
+// Descriptions that are not part of the catalog are reset to the built-in ones.
+// Descriptions of the catalog that are not built in are added, if their name is valid.
+// This function must not be called while data is interpreted.
+func UseDescriptions(catalog interpreters.Catalog) {
+	realWorldEntries = builtIn[realWorldName].overriddenEntry(realWorldName, catalog)
+	cyberspaceEntries = builtIn[cyberspaceName].overriddenEntry(cyberspaceName, catalog)
+	realWorldExtras = builtIn[realWorldExtraName].overriddenEntry(realWorldExtraName, catalog)
+	cyberspaceExtras = builtIn[cyberspaceExtraName].overriddenEntry(cyberspaceExtraName, catalog)
 }
@@ -0,0 +1,54 @@
+package lvlobj_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlobj"
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+func TestIsDescriptionName(t *testing.T) {
+	for _, name := range []string{"RealWorld", "RealWorld/4", "Cyberspace/7/2/1", "RealWorldExtra/8/2"} {
+		assert.True(t, lvlobj.IsDescriptionName(name), "%s should be valid", name)
+	}
+	for _, name := range []string{"Other/4", "RealWorld/a", "RealWorld/04", "RealWorld/256", "RealWorld/1/2/3/4"} {
+		assert.False(t, lvlobj.IsDescriptionName(name), "%s should be invalid", name)
+	}
+}
+
+func TestUseDescriptionsAddsDescriptionsThatAreNotBuiltIn(t *testing.T) {
+	defer lvlobj.UseDescriptions(nil)
+	drug := object.TripleFrom(int(object.ClassDrug), 0, 2)
+	builtIn := lvlobj.BuiltInDescriptions()
+	require.NotContains(t, builtIn, "RealWorld/4/0", "test requires a subclass without built-in description")
+	catalog, err := builtIn.Merged(interpreters.CatalogSchema{
+		"RealWorld/4/0/2": {Fields: []interpreters.FieldSchema{{Key: "NewField", Start: 0, Count: 1}}},
+	}, lvlobj.IsDescriptionName)
+	require.Nil(t, err, "no error expected")
+
+	lvlobj.UseDescriptions(catalog)
+	assert.Equal(t, []string{"NewField"}, lvlobj.ForRealWorld(drug, make([]byte, 1)).Keys())
+	assert.Empty(t, lvlobj.ForRealWorld(object.TripleFrom(int(object.ClassDrug), 0, 1), make([]byte, 1)).Keys(),
+		"other types must keep their description")
+
+	lvlobj.UseDescriptions(nil)
+	assert.Empty(t, lvlobj.ForRealWorld(drug, make([]byte, 1)).Keys())
+}
+
+func TestUseDescriptionsRefinesDescriptionsThatApplyToAllNestedKeys(t *testing.T) {
+	defer lvlobj.UseDescriptions(nil)
+	fixture := object.TripleFrom(int(object.ClassFixture), 0, 0)
+	before := lvlobj.RealWorldExtra(fixture, make([]byte, 4)).Keys()
+	catalog, err := lvlobj.BuiltInDescriptions().Merged(interpreters.CatalogSchema{
+		"RealWorldExtra/9/1": {Fields: []interpreters.FieldSchema{{Key: "NewField", Start: 0, Count: 1}}},
+	}, lvlobj.IsDescriptionName)
+	require.Nil(t, err, "no error expected")
+
+	lvlobj.UseDescriptions(catalog)
+	assert.Equal(t, []string{"NewField"}, lvlobj.RealWorldExtra(object.TripleFrom(int(object.ClassFixture), 1, 0), make([]byte, 4)).Keys())
+	assert.Equal(t, before, lvlobj.RealWorldExtra(fixture, make([]byte, 4)).Keys(), "other subclasses must keep their description")
+}
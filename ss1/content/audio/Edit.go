@@ -0,0 +1,94 @@
+package audio
+
+import "math"
+
+// SilenceValue is the sample value of no amplitude.
+const SilenceValue = 0x80
+
+// clampRange limits the given range to the available samples.
+func (sound L8) clampRange(from, to int) (int, int) {
+	count := len(sound.Samples)
+	if from < 0 {
+		from = 0
+	}
+	if to < 0 {
+		to = 0
+	}
+	if to > count {
+		to = count
+	}
+	if from > to {
+		from = to
+	}
+	return from, to
+}
+
+func (sound L8) withSamples(samples []byte) L8 {
+	return L8{SampleRate: sound.SampleRate, Samples: samples}
+}
+
+// Range returns a copy of the samples in the range [from, to).
+func (sound L8) Range(from, to int) L8 {
+	from, to = sound.clampRange(from, to)
+	return sound.withSamples(append([]byte{}, sound.Samples[from:to]...))
+}
+
+// Cut returns a sound without the samples in the range [from, to).
+func (sound L8) Cut(from, to int) L8 {
+	from, to = sound.clampRange(from, to)
+	samples := make([]byte, 0, len(sound.Samples)-(to-from))
+	samples = append(samples, sound.Samples[:from]...)
+	samples = append(samples, sound.Samples[to:]...)
+	return sound.withSamples(samples)
+}
+
+// Insert returns a sound with the samples of the other sound inserted at the given position.
+// The samples are taken as they are, regardless of the sample rate of the other sound.
+func (sound L8) Insert(at int, other L8) L8 {
+	at, _ = sound.clampRange(at, at)
+	samples := make([]byte, 0, len(sound.Samples)+len(other.Samples))
+	samples = append(samples, sound.Samples[:at]...)
+	samples = append(samples, other.Samples...)
+	samples = append(samples, sound.Samples[at:]...)
+	return sound.withSamples(samples)
+}
+
+// InsertSilence returns a sound with the given amount of silent samples inserted at the given position.
+func (sound L8) InsertSilence(at, count int) L8 {
+	silence := make([]byte, count)
+	for index := range silence {
+		silence[index] = SilenceValue
+	}
+	return sound.Insert(at, L8{Samples: silence})
+}
+
+// WithGain returns a sound with the amplitude of the samples in the range [from, to) multiplied by given factor.
+// Resulting values are clipped.
+func (sound L8) WithGain(from, to int, factor float64) L8 {
+	return sound.mapRange(from, to, func(int, int) float64 { return factor })
+}
+
+// FadedIn returns a sound with the amplitude of the samples in the range [from, to) rising linearly from zero.
+func (sound L8) FadedIn(from, to int) L8 {
+	return sound.mapRange(from, to, func(index, count int) float64 {
+		return float64(index) / float64(count)
+	})
+}
+
+// FadedOut returns a sound with the amplitude of the samples in the range [from, to) falling linearly to zero.
+func (sound L8) FadedOut(from, to int) L8 {
+	return sound.mapRange(from, to, func(index, count int) float64 {
+		return float64(count-1-index) / float64(count)
+	})
+}
+
+func (sound L8) mapRange(from, to int, factor func(index, count int) float64) L8 {
+	from, to = sound.clampRange(from, to)
+	samples := append([]byte{}, sound.Samples...)
+	count := to - from
+	for index := 0; index < count; index++ {
+		value := float64(int(samples[from+index])-SilenceValue) * factor(index, count)
+		samples[from+index] = byte(math.Max(0, math.Min(255, math.Round(value+SilenceValue))))
+	}
+	return sound.withSamples(samples)
+}
@@ -0,0 +1,68 @@
+package audio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+)
+
+func testSound(samples ...byte) audio.L8 {
+	return audio.L8{SampleRate: 22050, Samples: samples}
+}
+
+func TestRangeCopiesSamples(t *testing.T) {
+	sound := testSound(1, 2, 3, 4)
+
+	part := sound.Range(1, 3)
+	part.Samples[0] = 0xFF
+
+	assert.Equal(t, []byte{0xFF, 3}, part.Samples)
+	assert.Equal(t, []byte{1, 2, 3, 4}, sound.Samples)
+	assert.Equal(t, sound.SampleRate, part.SampleRate)
+}
+
+func TestRangeIsClamped(t *testing.T) {
+	assert.Equal(t, []byte{3, 4}, testSound(1, 2, 3, 4).Range(2, 10).Samples)
+	assert.Equal(t, []byte{}, testSound(1, 2).Range(-5, -1).Samples)
+}
+
+func TestCutRemovesSamples(t *testing.T) {
+	sound := testSound(1, 2, 3, 4)
+
+	result := sound.Cut(1, 3)
+
+	assert.Equal(t, []byte{1, 4}, result.Samples)
+	assert.Equal(t, []byte{1, 2, 3, 4}, sound.Samples)
+}
+
+func TestInsertAddsSamples(t *testing.T) {
+	result := testSound(1, 2).Insert(1, testSound(8, 9))
+
+	assert.Equal(t, []byte{1, 8, 9, 2}, result.Samples)
+}
+
+func TestInsertSilenceAddsSilentSamples(t *testing.T) {
+	result := testSound(1, 2).InsertSilence(2, 2)
+
+	assert.Equal(t, []byte{1, 2, audio.SilenceValue, audio.SilenceValue}, result.Samples)
+}
+
+func TestWithGainScalesAndClips(t *testing.T) {
+	result := testSound(0x80, 0x90, 0xC0, 0x40).WithGain(1, 4, 2)
+
+	assert.Equal(t, []byte{0x80, 0xA0, 0xFF, 0x00}, result.Samples)
+}
+
+func TestFadedInRisesFromSilence(t *testing.T) {
+	result := testSound(0xC0, 0xC0, 0xC0, 0xC0).FadedIn(0, 4)
+
+	assert.Equal(t, []byte{0x80, 0x90, 0xA0, 0xB0}, result.Samples)
+}
+
+func TestFadedOutFallsToSilence(t *testing.T) {
+	result := testSound(0xC0, 0xC0, 0xC0, 0xC0).FadedOut(0, 4)
+
+	assert.Equal(t, []byte{0xB0, 0xA0, 0x90, 0x80}, result.Samples)
+}
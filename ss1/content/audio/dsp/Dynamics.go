@@ -0,0 +1,82 @@
+package dsp
+
+import "math"
+
+// Gain returns the signal with all samples multiplied by the given factor.
+// The result is clipped to the valid range.
+func Gain(signal Signal, factor float64) Signal {
+	output := make([]float64, len(signal.Samples))
+	for index, sample := range signal.Samples {
+		output[index] = math.Max(-1, math.Min(1, sample*factor))
+	}
+	return signal.withSamples(output)
+}
+
+// NormalizePeak scales the signal so that its highest peak reaches the given level, in decibels of full scale.
+// A silent signal is returned unchanged.
+func NormalizePeak(signal Signal, levelDB float64) Signal {
+	peak := signal.Peak()
+	if peak == 0 {
+		return signal
+	}
+	return Gain(signal, AmplitudeFromDecibels(levelDB)/peak)
+}
+
+// NormalizeLoudness scales the signal so that its RMS reaches the given level, in decibels of full scale.
+// The gain is limited such that the peak does not exceed full scale; the signal is never clipped.
+// A silent signal is returned unchanged.
+func NormalizeLoudness(signal Signal, levelDB float64) Signal {
+	rms := signal.RMS()
+	if rms == 0 {
+		return signal
+	}
+	factor := AmplitudeFromDecibels(levelDB) / rms
+	factor = math.Min(factor, 1/signal.Peak())
+	return Gain(signal, factor)
+}
+
+// TrimSilence removes the samples at the start and the end of the signal that are below the given level,
+// in decibels of full scale. If the whole signal is below the level, an empty signal is returned.
+func TrimSilence(signal Signal, thresholdDB float64) Signal {
+	threshold := AmplitudeFromDecibels(thresholdDB)
+	start := 0
+	for (start < len(signal.Samples)) && (math.Abs(signal.Samples[start]) < threshold) {
+		start++
+	}
+	end := len(signal.Samples)
+	for (end > start) && (math.Abs(signal.Samples[end-1]) < threshold) {
+		end--
+	}
+	return signal.withSamples(append([]float64{}, signal.Samples[start:end]...))
+}
+
+// FadeIn returns the signal with its level raised linearly from silence over the given duration, in seconds.
+func FadeIn(signal Signal, duration float64) Signal {
+	output := append([]float64{}, signal.Samples...)
+	length := fadeLength(signal, duration)
+	for index := 0; index < length; index++ {
+		output[index] *= float64(index) / float64(length)
+	}
+	return signal.withSamples(output)
+}
+
+// FadeOut returns the signal with its level lowered linearly to silence over the given duration, in seconds.
+func FadeOut(signal Signal, duration float64) Signal {
+	output := append([]float64{}, signal.Samples...)
+	length := fadeLength(signal, duration)
+	for index := 0; index < length; index++ {
+		output[len(output)-1-index] *= float64(index) / float64(length)
+	}
+	return signal.withSamples(output)
+}
+
+func fadeLength(signal Signal, duration float64) int {
+	length := int(duration * signal.SampleRate)
+	if length > len(signal.Samples) {
+		length = len(signal.Samples)
+	}
+	if length < 0 {
+		length = 0
+	}
+	return length
+}
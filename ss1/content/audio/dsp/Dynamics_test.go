@@ -0,0 +1,66 @@
+package dsp_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio/dsp"
+)
+
+func TestNormalizePeak(t *testing.T) {
+	signal := dsp.Signal{SampleRate: 1, Samples: []float64{0.25, -0.125}}
+
+	result := dsp.NormalizePeak(signal, 0)
+
+	assert.InDeltaSlice(t, []float64{1.0, -0.5}, result.Samples, 1e-9)
+}
+
+func TestNormalizeLoudnessDoesNotClip(t *testing.T) {
+	signal := dsp.Signal{SampleRate: 1, Samples: []float64{0.5, 0.01, 0.01, 0.01}}
+
+	result := dsp.NormalizeLoudness(signal, 0)
+
+	assert.InDelta(t, 1.0, result.Peak(), 1e-9)
+}
+
+func TestNormalizeKeepsSilence(t *testing.T) {
+	signal := dsp.Signal{SampleRate: 1, Samples: []float64{0, 0}}
+
+	assert.Equal(t, signal, dsp.NormalizePeak(signal, 0))
+	assert.Equal(t, signal, dsp.NormalizeLoudness(signal, 0))
+}
+
+func TestTrimSilence(t *testing.T) {
+	signal := dsp.Signal{SampleRate: 1, Samples: []float64{0.0001, 0, 0.5, 0, -0.25, 0.0001}}
+
+	result := dsp.TrimSilence(signal, -40)
+
+	assert.Equal(t, []float64{0.5, 0, -0.25}, result.Samples)
+}
+
+func TestTrimSilenceOfSilentSignal(t *testing.T) {
+	signal := dsp.Signal{SampleRate: 1, Samples: []float64{0, 0, 0}}
+
+	result := dsp.TrimSilence(signal, -40)
+
+	assert.Equal(t, 0, len(result.Samples))
+}
+
+func TestFades(t *testing.T) {
+	signal := dsp.Signal{SampleRate: 4, Samples: []float64{1, 1, 1, 1, 1, 1}}
+
+	assert.Equal(t, []float64{0, 0.5, 1, 1, 1, 1}, dsp.FadeIn(signal, 0.5).Samples)
+	assert.Equal(t, []float64{1, 1, 1, 1, 0.5, 0}, dsp.FadeOut(signal, 0.5).Samples)
+	assert.Equal(t, []float64{1, 1, 1, 1, 1, 1}, signal.Samples, "original must not be modified")
+}
+
+func TestOptionsProcess(t *testing.T) {
+	signal := sine(44100, 440, 0.25, 44100)
+	options := dsp.DefaultOptions()
+
+	result := options.Process(signal)
+
+	assert.Equal(t, 22050.0, result.SampleRate)
+	assert.InDelta(t, dsp.AmplitudeFromDecibels(-1), result.Peak(), 1e-3)
+}
@@ -0,0 +1,35 @@
+package dsp
+
+import "fmt"
+
+// Normalization describes how the level of a signal is adjusted.
+type Normalization byte
+
+// String returns the textual representation of the value.
+func (mode Normalization) String() string {
+	if int(mode) >= len(normalizationNames) {
+		return fmt.Sprintf("Unknown%02X", int(mode))
+	}
+	return normalizationNames[mode]
+}
+
+// Normalization constants.
+const (
+	// NormalizationNone keeps the level as is.
+	NormalizationNone Normalization = 0
+	// NormalizationPeak scales the signal based on its highest peak.
+	NormalizationPeak Normalization = 1
+	// NormalizationLoudness scales the signal based on its RMS level.
+	NormalizationLoudness Normalization = 2
+)
+
+var normalizationNames = []string{
+	"None",
+	"Peak",
+	"Loudness (RMS)",
+}
+
+// Normalizations returns all known constants.
+func Normalizations() []Normalization {
+	return []Normalization{NormalizationNone, NormalizationPeak, NormalizationLoudness}
+}
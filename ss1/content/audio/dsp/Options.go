@@ -0,0 +1,56 @@
+package dsp
+
+// Options describe the processing of a sound for import into the engine.
+type Options struct {
+	// SampleRate is the rate to resample to. A value of 0 keeps the original rate.
+	SampleRate float64
+	// TrimSilence removes quiet parts at the start and the end.
+	TrimSilence bool
+	// TrimThresholdDB is the level below which samples are considered silent.
+	TrimThresholdDB float64
+	// Normalization specifies how the level is adjusted.
+	Normalization Normalization
+	// NormalizationLevelDB is the target level of the normalization.
+	NormalizationLevelDB float64
+	// FadeIn is the duration, in seconds, of a fade from silence at the start.
+	FadeIn float64
+	// FadeOut is the duration, in seconds, of a fade to silence at the end.
+	FadeOut float64
+	// Dither specifies whether noise is added when reducing to 8 bits.
+	Dither bool
+}
+
+// DefaultOptions returns the recommended options for importing speech.
+func DefaultOptions() Options {
+	return Options{
+		SampleRate:           22050,
+		TrimSilence:          false,
+		TrimThresholdDB:      -48,
+		Normalization:        NormalizationPeak,
+		NormalizationLevelDB: -1,
+		Dither:               true,
+	}
+}
+
+// Process applies all steps, in order of resampling, trimming, normalization, and fading.
+func (options Options) Process(signal Signal) Signal {
+	if options.SampleRate > 0 {
+		signal = Resample(signal, options.SampleRate)
+	}
+	if options.TrimSilence {
+		signal = TrimSilence(signal, options.TrimThresholdDB)
+	}
+	switch options.Normalization {
+	case NormalizationPeak:
+		signal = NormalizePeak(signal, options.NormalizationLevelDB)
+	case NormalizationLoudness:
+		signal = NormalizeLoudness(signal, options.NormalizationLevelDB)
+	}
+	if options.FadeIn > 0 {
+		signal = FadeIn(signal, options.FadeIn)
+	}
+	if options.FadeOut > 0 {
+		signal = FadeOut(signal, options.FadeOut)
+	}
+	return signal
+}
@@ -0,0 +1,62 @@
+package dsp
+
+import "math"
+
+// EngineSampleRates returns the sample rates that the engine plays back natively.
+func EngineSampleRates() []float64 {
+	return []float64{11025, 22050}
+}
+
+// resampleHalfTaps is the number of input samples considered on each side of an output sample.
+const resampleHalfTaps = 32
+
+// Resample converts the signal to the given sample rate.
+// It uses band-limited interpolation with a Blackman windowed sinc filter,
+// which also removes frequencies that the target rate can not represent.
+func Resample(signal Signal, rate float64) Signal {
+	if (rate <= 0) || (signal.SampleRate <= 0) || (rate == signal.SampleRate) {
+		return signal.withSamples(append([]float64{}, signal.Samples...))
+	}
+	ratio := signal.SampleRate / rate
+	// When reducing the rate, the cutoff frequency is lowered, and the filter widened, accordingly.
+	cutoff := math.Min(1.0, 1.0/ratio)
+	halfWidth := float64(resampleHalfTaps) / cutoff
+	outputLength := int(math.Floor(float64(len(signal.Samples)) / ratio))
+	output := make([]float64, outputLength)
+	for index := range output {
+		center := float64(index) * ratio
+		first := int(math.Ceil(center - halfWidth))
+		last := int(math.Floor(center + halfWidth))
+		sum := 0.0
+		weights := 0.0
+		for input := first; input <= last; input++ {
+			if (input < 0) || (input >= len(signal.Samples)) {
+				continue
+			}
+			distance := float64(input) - center
+			weight := cutoff * sinc(cutoff*distance) * blackman(distance/halfWidth)
+			sum += signal.Samples[input] * weight
+			weights += weight
+		}
+		if weights != 0 {
+			output[index] = sum / weights
+		}
+	}
+	return Signal{SampleRate: rate, Samples: output}
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
+
+// blackman returns the window value for the given position in range [-1.0, 1.0].
+func blackman(position float64) float64 {
+	if math.Abs(position) > 1 {
+		return 0
+	}
+	phase := math.Pi * (position + 1)
+	return 0.42 - 0.5*math.Cos(phase) + 0.08*math.Cos(2*phase)
+}
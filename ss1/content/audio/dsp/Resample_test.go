@@ -0,0 +1,49 @@
+package dsp_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio/dsp"
+)
+
+func TestResampleChangesLengthAndRate(t *testing.T) {
+	signal := sine(44100, 440, 0.5, 44100)
+
+	result := dsp.Resample(signal, 22050)
+
+	assert.Equal(t, 22050.0, result.SampleRate)
+	assert.Equal(t, 22050, len(result.Samples))
+}
+
+func TestResampleKeepsAudibleFrequencies(t *testing.T) {
+	signal := sine(44100, 440, 0.5, 44100)
+
+	result := dsp.Resample(signal, 22050)
+	expected := sine(22050, 440, 0.5, 22050)
+
+	for index := 1000; index < 21000; index++ {
+		assert.InDelta(t, expected.Samples[index], result.Samples[index], 0.01, "sample %d", index)
+	}
+}
+
+func TestResampleRemovesFrequenciesAboveNewLimit(t *testing.T) {
+	signal := sine(44100, 15000, 0.5, 44100)
+
+	result := dsp.Resample(signal, 22050)
+
+	assert.Less(t, dsp.Signal{Samples: result.Samples[1000:21000]}.Peak(), 0.02)
+}
+
+func TestResampleUpwards(t *testing.T) {
+	signal := sine(11025, 440, 0.5, 11025)
+
+	result := dsp.Resample(signal, 22050)
+	expected := sine(22050, 440, 0.5, 22050)
+
+	assert.Equal(t, 22050, len(result.Samples))
+	for index := 1000; index < 21000; index++ {
+		assert.InDelta(t, expected.Samples[index], result.Samples[index], 0.01, "sample %d", index)
+	}
+}
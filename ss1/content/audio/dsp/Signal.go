@@ -0,0 +1,92 @@
+package dsp
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+)
+
+// Signal is a mono sound with samples in the range of [-1.0, 1.0].
+type Signal struct {
+	SampleRate float64
+	Samples    []float64
+}
+
+// SignalFromL8 returns a signal based on the given 8-bit sound.
+func SignalFromL8(sound audio.L8) Signal {
+	signal := Signal{
+		SampleRate: float64(sound.SampleRate),
+		Samples:    make([]float64, len(sound.Samples)),
+	}
+	for index, sample := range sound.Samples {
+		signal.Samples[index] = (float64(sample) - 128.0) / 128.0
+	}
+	return signal
+}
+
+// Duration returns the length of the signal in seconds.
+func (signal Signal) Duration() float64 {
+	if signal.SampleRate <= 0 {
+		return 0
+	}
+	return float64(len(signal.Samples)) / signal.SampleRate
+}
+
+// Peak returns the highest absolute sample value.
+func (signal Signal) Peak() float64 {
+	peak := 0.0
+	for _, sample := range signal.Samples {
+		peak = math.Max(peak, math.Abs(sample))
+	}
+	return peak
+}
+
+// RMS returns the root mean square of all samples, a simple measure of loudness.
+func (signal Signal) RMS() float64 {
+	if len(signal.Samples) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, sample := range signal.Samples {
+		sum += sample * sample
+	}
+	return math.Sqrt(sum / float64(len(signal.Samples)))
+}
+
+// ToL8 reduces the signal to 8-bit samples.
+// If dither is set, triangular noise of one quantization step is added before rounding,
+// which trades the harmonic distortion of plain rounding for a low, constant noise floor.
+// The noise is created with a fixed seed, so the result is reproducible.
+func (signal Signal) ToL8(dither bool) audio.L8 {
+	sound := audio.L8{
+		SampleRate: float32(signal.SampleRate),
+		Samples:    make([]byte, len(signal.Samples)),
+	}
+	random := rand.New(rand.NewSource(0)) // nolint: gosec
+	for index, sample := range signal.Samples {
+		value := sample*128.0 + 128.0
+		if dither {
+			value += random.Float64() - random.Float64()
+		}
+		sound.Samples[index] = byte(math.Max(0, math.Min(255, math.Round(value))))
+	}
+	return sound
+}
+
+// DecibelsFromAmplitude returns the level of the given amplitude, relative to full scale.
+func DecibelsFromAmplitude(amplitude float64) float64 {
+	if amplitude <= 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(amplitude)
+}
+
+// AmplitudeFromDecibels returns the amplitude of the given level, relative to full scale.
+func AmplitudeFromDecibels(decibels float64) float64 {
+	return math.Pow(10, decibels/20)
+}
+
+func (signal Signal) withSamples(samples []float64) Signal {
+	return Signal{SampleRate: signal.SampleRate, Samples: samples}
+}
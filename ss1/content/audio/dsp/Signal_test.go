@@ -0,0 +1,56 @@
+package dsp_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/audio/dsp"
+)
+
+func sine(rate, frequency, amplitude float64, count int) dsp.Signal {
+	signal := dsp.Signal{SampleRate: rate, Samples: make([]float64, count)}
+	for index := range signal.Samples {
+		signal.Samples[index] = amplitude * math.Sin(2*math.Pi*frequency*float64(index)/rate)
+	}
+	return signal
+}
+
+func TestSignalFromL8AndBackIsLossless(t *testing.T) {
+	sound := audio.L8{SampleRate: 11025, Samples: []byte{0x00, 0x40, 0x80, 0xC0, 0xFF}}
+
+	result := dsp.SignalFromL8(sound).ToL8(false)
+
+	assert.Equal(t, sound, result)
+}
+
+func TestToL8DitherKeepsAverageLevel(t *testing.T) {
+	signal := dsp.Signal{SampleRate: 22050, Samples: make([]float64, 10000)}
+	for index := range signal.Samples {
+		signal.Samples[index] = 0.3 / 128.0
+	}
+
+	plain := signal.ToL8(false)
+	dithered := signal.ToL8(true)
+
+	assert.Equal(t, byte(0x80), plain.Samples[0], "plain rounding loses the level")
+	sum := 0.0
+	for _, sample := range dithered.Samples {
+		sum += float64(sample) - 128.0
+	}
+	assert.InDelta(t, 0.3, sum/float64(len(dithered.Samples)), 0.05, "dithering should keep the average")
+}
+
+func TestPeakAndRMS(t *testing.T) {
+	signal := dsp.Signal{SampleRate: 1, Samples: []float64{0.5, -0.5, 0.5, -0.5}}
+
+	assert.InDelta(t, 0.5, signal.Peak(), 1e-9)
+	assert.InDelta(t, 0.5, signal.RMS(), 1e-9)
+}
+
+func TestDecibelConversion(t *testing.T) {
+	assert.InDelta(t, -6.0206, dsp.DecibelsFromAmplitude(0.5), 1e-3)
+	assert.InDelta(t, 0.5, dsp.AmplitudeFromDecibels(-6.0206), 1e-4)
+}
@@ -0,0 +1,5 @@
+// Package dsp provides signal processing for preparing audio for the engine.
+//
+// Sounds are processed as Signal, which holds mono samples in floating point.
+// After processing, a signal is reduced to the 8-bit format of the engine.
+package dsp
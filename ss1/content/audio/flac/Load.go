@@ -0,0 +1,130 @@
+package flac
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"github.com/inkyblackness/hacked/ss1"
+	"github.com/inkyblackness/hacked/ss1/content/audio/dsp"
+)
+
+const (
+	errSourceIsNil       ss1.StringError = "source is nil"
+	errNotAFlacStream    ss1.StringError = "not a FLAC stream"
+	errMissingStreamInfo ss1.StringError = "missing stream info"
+	errInvalidFrame      ss1.StringError = "invalid frame"
+)
+
+// FileHeader identifies FLAC streams.
+const FileHeader = "fLaC"
+
+const (
+	metadataTypeStreamInfo = 0
+	streamInfoSize         = 34
+	id3Header              = "ID3"
+	id3HeaderSize          = 10
+)
+
+type streamInfo struct {
+	sampleRate    int
+	channels      int
+	bitsPerSample int
+	totalSamples  uint64
+}
+
+// LoadSignal reads a FLAC stream from the given source and returns the audio mixed down to mono.
+func LoadSignal(source io.Reader) (dsp.Signal, error) {
+	if source == nil {
+		return dsp.Signal{}, errSourceIsNil
+	}
+	data, err := ioutil.ReadAll(source)
+	if err != nil {
+		return dsp.Signal{}, err
+	}
+	data = skipID3(data)
+	if !bytes.HasPrefix(data, []byte(FileHeader)) {
+		return dsp.Signal{}, errNotAFlacStream
+	}
+	reader := bitReader{data: data, bitPos: len(FileHeader) * 8}
+	info, err := readMetadata(&reader)
+	if err != nil {
+		return dsp.Signal{}, err
+	}
+
+	decoder := frameDecoder{info: info}
+	var samples []float64
+	for findFrameSync(&reader) {
+		if (info.totalSamples > 0) && (uint64(len(samples)) >= info.totalSamples) {
+			break
+		}
+		samples, err = decoder.decodeFrame(&reader, samples)
+		if err != nil {
+			return dsp.Signal{}, err
+		}
+	}
+	if (info.totalSamples > 0) && (uint64(len(samples)) > info.totalSamples) {
+		samples = samples[:info.totalSamples]
+	}
+	return dsp.Signal{SampleRate: float64(info.sampleRate), Samples: samples}, nil
+}
+
+// IsStream returns true if the given data starts with a FLAC stream, possibly after an ID3v2 tag.
+func IsStream(data []byte) bool {
+	return bytes.HasPrefix(skipID3(data), []byte(FileHeader))
+}
+
+// skipID3 removes an ID3v2 tag some tools put in front of the stream.
+func skipID3(data []byte) []byte {
+	if !bytes.HasPrefix(data, []byte(id3Header)) || (len(data) < id3HeaderSize) {
+		return data
+	}
+	size := id3HeaderSize + (int(data[6]&0x7F) << 21) + (int(data[7]&0x7F) << 14) +
+		(int(data[8]&0x7F) << 7) + int(data[9]&0x7F)
+	if size > len(data) {
+		return data
+	}
+	return data[size:]
+}
+
+func readMetadata(reader *bitReader) (streamInfo, error) {
+	var info streamInfo
+	infoFound := false
+	for isLast := false; !isLast; {
+		isLast = reader.readBits(1) != 0
+		blockType := reader.readBits(7)
+		blockSize := int(reader.readBits(24))
+		blockEnd := reader.bitPos + blockSize*8
+		if (blockType == metadataTypeStreamInfo) && (blockSize >= streamInfoSize) {
+			reader.readBits(16 + 16 + 24 + 24) // block sizes and frame sizes
+			info.sampleRate = int(reader.readBits(20))
+			info.channels = int(reader.readBits(3)) + 1
+			info.bitsPerSample = int(reader.readBits(5)) + 1
+			info.totalSamples = reader.readBits(36)
+			infoFound = true
+		}
+		reader.bitPos = blockEnd
+		if reader.bytePos() > len(reader.data) {
+			return info, errUnexpectedEnd
+		}
+	}
+	if reader.err != nil {
+		return info, reader.err
+	}
+	if !infoFound || (info.sampleRate == 0) {
+		return info, errMissingStreamInfo
+	}
+	return info, nil
+}
+
+// findFrameSync advances the reader to the next frame sync code. It returns false if there is none.
+func findFrameSync(reader *bitReader) bool {
+	reader.alignToByte()
+	for pos := reader.bytePos(); pos+1 < len(reader.data); pos++ {
+		if (reader.data[pos] == 0xFF) && ((reader.data[pos+1] & 0xFE) == 0xF8) {
+			reader.bitPos = pos * 8
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package flac_test
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio/flac"
+)
+
+type bitWriter struct {
+	data  []byte
+	count uint
+}
+
+func (writer *bitWriter) write(value uint64, bits uint) {
+	for bit := bits; bit > 0; bit-- {
+		if (writer.count % 8) == 0 {
+			writer.data = append(writer.data, 0)
+		}
+		if (value>>(bit-1))&1 != 0 {
+			writer.data[len(writer.data)-1] |= 0x80 >> (writer.count % 8)
+		}
+		writer.count++
+	}
+}
+
+func (writer *bitWriter) writeSigned(value int64, bits uint) {
+	writer.write(uint64(value)&(uint64(1)<<bits-1), bits)
+}
+
+func (writer *bitWriter) writeRice(value int64, parameter uint) {
+	folded := uint64(value<<1) ^ uint64(value>>63)
+	for quotient := folded >> parameter; quotient > 0; quotient-- {
+		writer.write(0, 1)
+	}
+	writer.write(1, 1)
+	writer.write(folded, parameter)
+}
+
+func (writer *bitWriter) align() {
+	writer.count = uint(len(writer.data)) * 8
+}
+
+func streamHeader(channels int, totalSamples uint64) *bitWriter {
+	writer := &bitWriter{}
+	writer.data = append(writer.data, []byte(flac.FileHeader)...)
+	writer.count = uint(len(writer.data)) * 8
+	writer.write(1, 1) // last metadata block
+	writer.write(0, 7) // stream info
+	writer.write(34, 24)
+	writer.write(4, 16) // min block size
+	writer.write(4, 16) // max block size
+	writer.write(0, 24) // min frame size
+	writer.write(0, 24) // max frame size
+	writer.write(22050, 20)
+	writer.write(uint64(channels-1), 3)
+	writer.write(15, 5) // 16 bits per sample
+	writer.write(totalSamples, 36)
+	writer.write(0, 64) // MD5
+	writer.write(0, 64)
+	return writer
+}
+
+func frameHeader(writer *bitWriter, channelAssignment uint64) {
+	writer.write(0x3FFE, 14)
+	writer.write(0, 2)
+	writer.write(6, 4) // block size in 8 bits
+	writer.write(0, 4) // sample rate from stream info
+	writer.write(channelAssignment, 4)
+	writer.write(4, 3) // 16 bits per sample
+	writer.write(0, 1)
+	writer.write(0, 8) // frame number
+	writer.write(3, 8) // block size - 1
+	writer.write(0, 8) // CRC-8
+}
+
+func frameFooter(writer *bitWriter) {
+	writer.align()
+	writer.write(0, 16) // CRC-16
+}
+
+func TestLoadSignalReturnsErrorOnNil(t *testing.T) {
+	_, err := flac.LoadSignal(nil)
+
+	assert.NotNil(t, err)
+}
+
+func TestLoadSignalRejectsOtherData(t *testing.T) {
+	_, err := flac.LoadSignal(bytes.NewReader([]byte("RIFF1234WAVE")))
+
+	assert.NotNil(t, err)
+}
+
+func TestIsStream(t *testing.T) {
+	id3 := []byte{'I', 'D', '3', 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00}
+
+	assert.True(t, flac.IsStream([]byte(flac.FileHeader)), "plain stream")
+	assert.True(t, flac.IsStream(append(id3, []byte(flac.FileHeader)...)), "stream after ID3 tag")
+	assert.False(t, flac.IsStream(append(id3, 0xFF, 0xFB, 0x90, 0x00)), "other data after ID3 tag")
+	assert.False(t, flac.IsStream([]byte("RIFF1234WAVE")), "other data")
+}
+
+func TestLoadSignalDecodesConstantSubframe(t *testing.T) {
+	writer := streamHeader(1, 4)
+	frameHeader(writer, 0)
+	writer.write(0, 1)
+	writer.write(0, 6) // constant
+	writer.write(0, 1)
+	writer.writeSigned(16384, 16)
+	frameFooter(writer)
+
+	signal, err := flac.LoadSignal(bytes.NewReader(writer.data))
+
+	require.Nil(t, err)
+	assert.Equal(t, 22050.0, signal.SampleRate)
+	assert.InDeltaSlice(t, []float64{0.5, 0.5, 0.5, 0.5}, signal.Samples, 1e-9)
+}
+
+func TestLoadSignalDecodesLPCSubframe(t *testing.T) {
+	writer := streamHeader(1, 4)
+	frameHeader(writer, 0)
+	writer.write(0, 1)
+	writer.write(32, 6) // LPC, order 1
+	writer.write(0, 1)
+	writer.writeSigned(100, 16) // warm-up
+	writer.write(3, 4)          // precision 4
+	writer.writeSigned(0, 5)    // shift
+	writer.writeSigned(1, 4)    // coefficient
+	writer.write(0, 2)          // Rice, 4-bit parameter
+	writer.write(0, 4)          // partition order
+	writer.write(1, 4)          // parameter
+	for i := 0; i < 3; i++ {
+		writer.writeRice(1, 1)
+	}
+	frameFooter(writer)
+
+	signal, err := flac.LoadSignal(bytes.NewReader(writer.data))
+
+	require.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{100.0 / 32768, 101.0 / 32768, 102.0 / 32768, 103.0 / 32768},
+		signal.Samples, 1e-9)
+}
+
+func TestLoadSignalMixesDownMidSideStereo(t *testing.T) {
+	writer := streamHeader(2, 4)
+	frameHeader(writer, 10)
+	writer.write(0, 1)
+	writer.write(9, 6) // fixed, order 1
+	writer.write(0, 1)
+	writer.writeSigned(500, 16)
+	writer.write(0, 2)
+	writer.write(0, 4)
+	writer.write(10, 4)
+	for _, residual := range []int64{1000, 500, 1000} {
+		writer.writeRice(residual, 10)
+	}
+	writer.write(0, 1)
+	writer.write(1, 6) // verbatim
+	writer.write(0, 1)
+	for _, side := range []int64{1000, 1000, 2000, 2000} {
+		writer.writeSigned(side, 17)
+	}
+	frameFooter(writer)
+
+	signal, err := flac.LoadSignal(bytes.NewReader(writer.data))
+
+	require.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{500.0 / 32768, 1500.0 / 32768, 2000.0 / 32768, 3000.0 / 32768},
+		signal.Samples, 1e-9)
+}
+
+func TestLoadSignalDecodesEncoderOutput(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("_testdata", "sample.flac"))
+	require.Nil(t, err)
+
+	signal, err := flac.LoadSignal(bytes.NewReader(data))
+
+	require.Nil(t, err)
+	assert.Equal(t, 8000.0, signal.SampleRate)
+	require.Equal(t, 21312, len(signal.Samples))
+	pcm := make([]byte, len(signal.Samples)*2)
+	for index, sample := range signal.Samples {
+		binary.LittleEndian.PutUint16(pcm[index*2:], uint16(int16(math.Round(sample*32768))))
+	}
+	assert.Equal(t, "3133c736f37f1bd0c8b32e5044d2d3ac", fmt.Sprintf("%x", md5.Sum(pcm)), "lossless decoding expected")
+}
@@ -0,0 +1,92 @@
+package flac
+
+import (
+	"github.com/inkyblackness/hacked/ss1"
+)
+
+const errUnexpectedEnd ss1.StringError = "unexpected end of data"
+
+// bitReader reads big-endian values of arbitrary bit size.
+// Reading beyond the end of the data yields zeroes and records an error.
+type bitReader struct {
+	data   []byte
+	bitPos int
+	err    error
+}
+
+func (reader *bitReader) readBit() uint32 {
+	bytePos := reader.bitPos >> 3
+	if bytePos >= len(reader.data) {
+		reader.err = errUnexpectedEnd
+		return 0
+	}
+	bit := (reader.data[bytePos] >> (7 - uint(reader.bitPos&7))) & 1
+	reader.bitPos++
+	return uint32(bit)
+}
+
+func (reader *bitReader) readBits(count int) uint64 {
+	var value uint64
+	for (count > 0) && ((reader.bitPos & 7) != 0) {
+		value = (value << 1) | uint64(reader.readBit())
+		count--
+	}
+	for (count >= 8) && (reader.err == nil) {
+		bytePos := reader.bitPos >> 3
+		if bytePos >= len(reader.data) {
+			reader.err = errUnexpectedEnd
+			return 0
+		}
+		value = (value << 8) | uint64(reader.data[bytePos])
+		reader.bitPos += 8
+		count -= 8
+	}
+	for count > 0 {
+		value = (value << 1) | uint64(reader.readBit())
+		count--
+	}
+	return value
+}
+
+func (reader *bitReader) readSigned(count int) int64 {
+	if count == 0 {
+		return 0
+	}
+	value := reader.readBits(count)
+	shift := uint(64 - count)
+	return int64(value<<shift) >> shift
+}
+
+// readUnary returns the number of zero bits before the next one bit.
+func (reader *bitReader) readUnary() uint32 {
+	var count uint32
+	for (reader.readBit() == 0) && (reader.err == nil) {
+		count++
+	}
+	return count
+}
+
+// readUTF8 reads a number that is encoded like an extended UTF-8 character.
+func (reader *bitReader) readUTF8() uint64 {
+	first := reader.readBits(8)
+	extraBytes := 0
+	for mask := uint64(0x80); (first & mask) != 0; mask >>= 1 {
+		extraBytes++
+	}
+	if extraBytes == 0 {
+		return first
+	}
+	value := first & (0xFF >> uint(extraBytes+1))
+	for index := 1; index < extraBytes; index++ {
+		value = (value << 6) | (reader.readBits(8) & 0x3F)
+	}
+	return value
+}
+
+func (reader *bitReader) alignToByte() {
+	reader.bitPos = (reader.bitPos + 7) &^ 7
+}
+
+func (reader *bitReader) bytePos() int {
+	return reader.bitPos >> 3
+}
@@ -0,0 +1,5 @@
+// Package flac decodes audio of the Free Lossless Audio Codec.
+//
+// The decoder handles native FLAC streams with all subframe types and channel assignments.
+// Checksums are not verified, and the decoded audio is mixed down to a mono signal.
+package flac
@@ -0,0 +1,295 @@
+package flac
+
+const (
+	frameSyncCode = 0x3FFE
+
+	channelAssignmentLeftSide  = 8
+	channelAssignmentSideRight = 9
+	channelAssignmentMidSide   = 10
+
+	subframeTypeConstant = 0
+	subframeTypeVerbatim = 1
+	subframeTypeFixedMin = 8
+	subframeTypeFixedMax = 12
+	subframeTypeLPCMin   = 32
+)
+
+var fixedSampleRates = [...]int{0, 88200, 176400, 192000, 8000, 16000, 22050, 24000, 32000, 44100, 48000, 96000}
+var fixedSampleSizes = [...]int{0, 8, 12, 0, 16, 20, 24, 32}
+
+type frameDecoder struct {
+	info     streamInfo
+	channels [][]int64
+}
+
+// decodeFrame decodes the frame at the current position and appends its mono mix to the given samples.
+func (decoder *frameDecoder) decodeFrame(reader *bitReader, samples []float64) ([]float64, error) {
+	if reader.readBits(14) != frameSyncCode {
+		return samples, errInvalidFrame
+	}
+	reader.readBits(2) // reserved and blocking strategy
+	blockSizeCode := int(reader.readBits(4))
+	sampleRateCode := int(reader.readBits(4))
+	channelAssignment := int(reader.readBits(4))
+	sampleSizeCode := int(reader.readBits(3))
+	reader.readBits(1) // reserved
+	reader.readUTF8()  // frame or sample number
+
+	blockSize := 0
+	switch {
+	case blockSizeCode == 1:
+		blockSize = 192
+	case (blockSizeCode >= 2) && (blockSizeCode <= 5):
+		blockSize = 576 << uint(blockSizeCode-2)
+	case blockSizeCode == 6:
+		blockSize = int(reader.readBits(8)) + 1
+	case blockSizeCode == 7:
+		blockSize = int(reader.readBits(16)) + 1
+	case blockSizeCode >= 8:
+		blockSize = 256 << uint(blockSizeCode-8)
+	default:
+		return samples, errInvalidFrame
+	}
+	switch sampleRateCode {
+	case 12:
+		reader.readBits(8)
+	case 13, 14:
+		reader.readBits(16)
+	case 15:
+		return samples, errInvalidFrame
+	}
+	bitsPerSample := fixedSampleSizes[sampleSizeCode]
+	if sampleSizeCode == 0 {
+		bitsPerSample = decoder.info.bitsPerSample
+	}
+	if bitsPerSample == 0 {
+		return samples, errInvalidFrame
+	}
+	channelCount := channelAssignment + 1
+	if channelAssignment >= channelAssignmentLeftSide {
+		channelCount = 2
+	}
+	if channelAssignment > channelAssignmentMidSide {
+		return samples, errInvalidFrame
+	}
+	reader.readBits(8) // CRC-8
+	if reader.err != nil {
+		return samples, reader.err
+	}
+
+	decoder.prepareChannels(channelCount, blockSize)
+	for channel := 0; channel < channelCount; channel++ {
+		channelBits := bitsPerSample
+		if ((channelAssignment == channelAssignmentLeftSide) && (channel == 1)) ||
+			((channelAssignment == channelAssignmentSideRight) && (channel == 0)) ||
+			((channelAssignment == channelAssignmentMidSide) && (channel == 1)) {
+			channelBits++
+		}
+		err := decodeSubframe(reader, decoder.channels[channel], channelBits)
+		if err != nil {
+			return samples, err
+		}
+	}
+	reader.alignToByte()
+	reader.readBits(16) // CRC-16
+	if reader.err != nil {
+		return samples, reader.err
+	}
+
+	decorrelate(channelAssignment, decoder.channels)
+	scale := 1.0 / float64(int64(1)<<uint(bitsPerSample-1)) / float64(channelCount)
+	for index := 0; index < blockSize; index++ {
+		var sum int64
+		for _, channel := range decoder.channels {
+			sum += channel[index]
+		}
+		samples = append(samples, float64(sum)*scale)
+	}
+	return samples, nil
+}
+
+func (decoder *frameDecoder) prepareChannels(count int, blockSize int) {
+	if len(decoder.channels) != count {
+		decoder.channels = make([][]int64, count)
+	}
+	for index := range decoder.channels {
+		if cap(decoder.channels[index]) < blockSize {
+			decoder.channels[index] = make([]int64, blockSize)
+		}
+		decoder.channels[index] = decoder.channels[index][:blockSize]
+	}
+}
+
+func decorrelate(channelAssignment int, channels [][]int64) {
+	switch channelAssignment {
+	case channelAssignmentLeftSide:
+		left, side := channels[0], channels[1]
+		for index := range left {
+			channels[1][index] = left[index] - side[index]
+		}
+	case channelAssignmentSideRight:
+		side, right := channels[0], channels[1]
+		for index := range side {
+			channels[0][index] = side[index] + right[index]
+		}
+	case channelAssignmentMidSide:
+		mid, side := channels[0], channels[1]
+		for index := range mid {
+			sum := (mid[index] << 1) | (side[index] & 1)
+			diff := side[index]
+			channels[0][index] = (sum + diff) >> 1
+			channels[1][index] = (sum - diff) >> 1
+		}
+	}
+}
+
+func decodeSubframe(reader *bitReader, output []int64, bitsPerSample int) error {
+	if reader.readBits(1) != 0 {
+		return errInvalidFrame
+	}
+	subframeType := int(reader.readBits(6))
+	wastedBits := 0
+	if reader.readBits(1) != 0 {
+		wastedBits = int(reader.readUnary()) + 1
+	}
+	bitsPerSample -= wastedBits
+	if bitsPerSample < 1 {
+		return errInvalidFrame
+	}
+
+	switch {
+	case subframeType == subframeTypeConstant:
+		value := reader.readSigned(bitsPerSample)
+		for index := range output {
+			output[index] = value
+		}
+	case subframeType == subframeTypeVerbatim:
+		for index := range output {
+			output[index] = reader.readSigned(bitsPerSample)
+		}
+	case (subframeType >= subframeTypeFixedMin) && (subframeType <= subframeTypeFixedMax):
+		order := subframeType - subframeTypeFixedMin
+		err := decodeFixed(reader, output, order, bitsPerSample)
+		if err != nil {
+			return err
+		}
+	case subframeType >= subframeTypeLPCMin:
+		order := subframeType - subframeTypeLPCMin + 1
+		err := decodeLPC(reader, output, order, bitsPerSample)
+		if err != nil {
+			return err
+		}
+	default:
+		return errInvalidFrame
+	}
+	if wastedBits > 0 {
+		for index := range output {
+			output[index] <<= uint(wastedBits)
+		}
+	}
+	return reader.err
+}
+
+func readWarmUp(reader *bitReader, output []int64, order int, bitsPerSample int) error {
+	if order > len(output) {
+		return errInvalidFrame
+	}
+	for index := 0; index < order; index++ {
+		output[index] = reader.readSigned(bitsPerSample)
+	}
+	return nil
+}
+
+func decodeFixed(reader *bitReader, output []int64, order int, bitsPerSample int) error {
+	err := readWarmUp(reader, output, order, bitsPerSample)
+	if err != nil {
+		return err
+	}
+	err = decodeResidual(reader, output, order)
+	if err != nil {
+		return err
+	}
+	for i := order; i < len(output); i++ {
+		switch order {
+		case 1:
+			output[i] += output[i-1]
+		case 2:
+			output[i] += 2*output[i-1] - output[i-2]
+		case 3:
+			output[i] += 3*output[i-1] - 3*output[i-2] + output[i-3]
+		case 4:
+			output[i] += 4*output[i-1] - 6*output[i-2] + 4*output[i-3] - output[i-4]
+		}
+	}
+	return nil
+}
+
+func decodeLPC(reader *bitReader, output []int64, order int, bitsPerSample int) error {
+	err := readWarmUp(reader, output, order, bitsPerSample)
+	if err != nil {
+		return err
+	}
+	precision := int(reader.readBits(4)) + 1
+	if precision == 16 {
+		return errInvalidFrame
+	}
+	shift := reader.readSigned(5)
+	if shift < 0 {
+		return errInvalidFrame
+	}
+	coefficients := make([]int64, order)
+	for index := range coefficients {
+		coefficients[index] = reader.readSigned(precision)
+	}
+	err = decodeResidual(reader, output, order)
+	if err != nil {
+		return err
+	}
+	for i := order; i < len(output); i++ {
+		var prediction int64
+		for j, coefficient := range coefficients {
+			prediction += coefficient * output[i-1-j]
+		}
+		output[i] += prediction >> uint(shift)
+	}
+	return nil
+}
+
+// decodeResidual reads the Rice coded residual into output, starting after the warm-up samples.
+func decodeResidual(reader *bitReader, output []int64, order int) error {
+	method := reader.readBits(2)
+	parameterBits := 4
+	if method == 1 {
+		parameterBits = 5
+	} else if method != 0 {
+		return errInvalidFrame
+	}
+	escapeCode := uint64(1)<<uint(parameterBits) - 1
+	partitionOrder := uint(reader.readBits(4))
+	partitionCount := 1 << partitionOrder
+	partitionSize := len(output) >> partitionOrder
+	if (partitionSize<<partitionOrder) != len(output) || (partitionSize < order) {
+		return errInvalidFrame
+	}
+
+	index := order
+	for partition := 0; partition < partitionCount; partition++ {
+		end := (partition + 1) * partitionSize
+		parameter := reader.readBits(parameterBits)
+		if parameter == escapeCode {
+			rawBits := int(reader.readBits(5))
+			for ; index < end; index++ {
+				output[index] = reader.readSigned(rawBits)
+			}
+		} else {
+			for ; index < end; index++ {
+				value := uint64(reader.readUnary())<<uint(parameter) | reader.readBits(int(parameter))
+				output[index] = int64(value>>1) ^ -int64(value&1)
+			}
+		}
+		if reader.err != nil {
+			return reader.err
+		}
+	}
+	return nil
+}
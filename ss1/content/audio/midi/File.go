@@ -0,0 +1,98 @@
+package midi
+
+// Status bytes of relevant events are listed below.
+const (
+	StatusNoteOff       byte = 0x80
+	StatusNoteOn        byte = 0x90
+	StatusControlChange byte = 0xB0
+	StatusProgramChange byte = 0xC0
+	StatusSysEx         byte = 0xF0
+	StatusSysExEscape   byte = 0xF7
+	StatusMeta          byte = 0xFF
+)
+
+// Meta event types of relevance are listed below.
+const (
+	MetaEndOfTrack byte = 0x2F
+	MetaTempo      byte = 0x51
+)
+
+// DefaultTempo is the tempo, in microseconds per quarter note, if a file does not specify one.
+const DefaultTempo = 500000
+
+// Event is a single MIDI event at an absolute point in time.
+type Event struct {
+	// Time is the absolute time of the event, in ticks.
+	Time uint32
+	// Status is the status byte of the event, including the channel for channel messages.
+	Status byte
+	// MetaType is the type of meta events. It is only used if Status is StatusMeta.
+	MetaType byte
+	// Data contains the parameters of channel messages, or the payload of meta and system exclusive events.
+	Data []byte
+}
+
+// IsChannelEvent returns true for events that address a specific channel.
+func (event Event) IsChannelEvent() bool {
+	return event.Status < StatusSysEx
+}
+
+// Channel returns the channel of a channel event.
+func (event Event) Channel() int {
+	return int(event.Status & 0x0F)
+}
+
+// Kind returns the status without channel information for channel events, or the plain status otherwise.
+func (event Event) Kind() byte {
+	if event.IsChannelEvent() {
+		return event.Status & 0xF0
+	}
+	return event.Status
+}
+
+// IsNoteOn returns true if the event starts a note. Note-on events with zero velocity are not considered.
+func (event Event) IsNoteOn() bool {
+	return (event.Kind() == StatusNoteOn) && (len(event.Data) > 1) && (event.Data[1] != 0)
+}
+
+// IsNoteOff returns true if the event stops a note. This includes note-on events with zero velocity.
+func (event Event) IsNoteOff() bool {
+	kind := event.Kind()
+	return (kind == StatusNoteOff) || ((kind == StatusNoteOn) && (len(event.Data) > 1) && (event.Data[1] == 0))
+}
+
+// IsMeta returns true if the event is a meta event of given type.
+func (event Event) IsMeta(metaType byte) bool {
+	return (event.Status == StatusMeta) && (event.MetaType == metaType)
+}
+
+// Tempo returns the tempo of a tempo meta event, in microseconds per quarter note.
+func (event Event) Tempo() (uint32, bool) {
+	if !event.IsMeta(MetaTempo) || (len(event.Data) != 3) {
+		return 0, false
+	}
+	return uint32(event.Data[0])<<16 | uint32(event.Data[1])<<8 | uint32(event.Data[2]), true
+}
+
+// TempoEvent returns a tempo meta event for given time and tempo.
+func TempoEvent(time uint32, microsecondsPerQuarter uint32) Event {
+	return Event{
+		Time:     time,
+		Status:   StatusMeta,
+		MetaType: MetaTempo,
+		Data:     []byte{byte(microsecondsPerQuarter >> 16), byte(microsecondsPerQuarter >> 8), byte(microsecondsPerQuarter)},
+	}
+}
+
+// Track is a list of events, ordered by time.
+type Track []Event
+
+// File describes a standard MIDI file.
+type File struct {
+	// Format is 0 for a single track, 1 for simultaneous tracks, and 2 for independent tracks.
+	Format uint16
+	// Division is the amount of ticks per quarter note. SMPTE based divisions are not supported.
+	Division uint16
+	// Tracks contains the events per track. End-of-track events are not included.
+	Tracks []Track
+}
@@ -0,0 +1,189 @@
+package midi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+
+	"github.com/inkyblackness/hacked/ss1"
+)
+
+const (
+	errSourceIsNil        ss1.StringError = "source is nil"
+	errNotAMidiFile       ss1.StringError = "not a MIDI file"
+	errUnsupportedTiming  ss1.StringError = "SMPTE based timing is not supported"
+	errUnexpectedEnd      ss1.StringError = "unexpected end of data"
+	errMissingStatus      ss1.StringError = "data byte without running status"
+	errUnsupportedMessage ss1.StringError = "unsupported message"
+)
+
+// Load reads a standard MIDI file from given source.
+func Load(source io.Reader) (File, error) {
+	var file File
+	if source == nil {
+		return file, errSourceIsNil
+	}
+	data, err := ioutil.ReadAll(source)
+	if err != nil {
+		return file, err
+	}
+	reader := bytes.NewReader(data)
+	var header struct {
+		Tag      [4]byte
+		Length   uint32
+		Format   uint16
+		Tracks   uint16
+		Division uint16
+	}
+	if binary.Read(reader, binary.BigEndian, &header) != nil || string(header.Tag[:]) != "MThd" || header.Length < 6 {
+		return file, errNotAMidiFile
+	}
+	if (header.Division & 0x8000) != 0 {
+		return file, errUnsupportedTiming
+	}
+	_, _ = reader.Seek(int64(8+header.Length), io.SeekStart)
+	file.Format = header.Format
+	file.Division = header.Division
+
+	for len(file.Tracks) < int(header.Tracks) {
+		var chunk struct {
+			Tag    [4]byte
+			Length uint32
+		}
+		if binary.Read(reader, binary.BigEndian, &chunk) != nil {
+			return file, errUnexpectedEnd
+		}
+		if int64(chunk.Length) > int64(reader.Len()) {
+			return file, errUnexpectedEnd
+		}
+		chunkData := make([]byte, chunk.Length)
+		_, _ = reader.Read(chunkData)
+		if string(chunk.Tag[:]) != "MTrk" {
+			continue
+		}
+		track, err := decodeTrack(chunkData)
+		if err != nil {
+			return file, err
+		}
+		file.Tracks = append(file.Tracks, track)
+	}
+	return file, nil
+}
+
+func decodeTrack(data []byte) (Track, error) {
+	var track Track
+	source := ByteSource(data)
+	var time uint32
+	var runningStatus byte
+	for !source.Empty() {
+		delta, err := source.VariableLength()
+		if err != nil {
+			return nil, err
+		}
+		time += delta
+		status, err := source.Byte()
+		if err != nil {
+			return nil, err
+		}
+		var firstData []byte
+		if status < 0x80 {
+			if runningStatus == 0 {
+				return nil, errMissingStatus
+			}
+			firstData = []byte{status}
+			status = runningStatus
+		}
+		event := Event{Time: time, Status: status}
+		switch {
+		case status < StatusSysEx:
+			runningStatus = status
+			var remaining []byte
+			remaining, err = source.Bytes(ChannelDataLength(status) - len(firstData))
+			event.Data = append(firstData, remaining...)
+		case (status == StatusSysEx) || (status == StatusSysExEscape):
+			runningStatus = 0
+			event.Data, err = source.LengthPrefixed()
+		case status == StatusMeta:
+			runningStatus = 0
+			event.MetaType, err = source.Byte()
+			if err == nil {
+				event.Data, err = source.LengthPrefixed()
+			}
+		default:
+			err = errUnsupportedMessage
+		}
+		if err != nil {
+			return nil, err
+		}
+		if event.IsMeta(MetaEndOfTrack) {
+			break
+		}
+		track = append(track, event)
+	}
+	return track, nil
+}
+
+// ChannelDataLength returns the number of data bytes that follow the given channel status.
+func ChannelDataLength(status byte) int {
+	switch status & 0xF0 {
+	case StatusProgramChange, 0xD0:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ByteSource is a helper for decoding MIDI related data.
+type ByteSource []byte
+
+// Empty returns true if no more bytes are available.
+func (source ByteSource) Empty() bool {
+	return len(source) == 0
+}
+
+// Byte returns the next byte.
+func (source *ByteSource) Byte() (byte, error) {
+	if len(*source) == 0 {
+		return 0, errUnexpectedEnd
+	}
+	value := (*source)[0]
+	*source = (*source)[1:]
+	return value, nil
+}
+
+// Bytes returns the next given amount of bytes.
+func (source *ByteSource) Bytes(count int) ([]byte, error) {
+	if len(*source) < count {
+		return nil, errUnexpectedEnd
+	}
+	value := make([]byte, count)
+	copy(value, *source)
+	*source = (*source)[count:]
+	return value, nil
+}
+
+// VariableLength returns the next variable-length encoded value.
+func (source *ByteSource) VariableLength() (uint32, error) {
+	var value uint32
+	for i := 0; i < 4; i++ {
+		b, err := source.Byte()
+		if err != nil {
+			return 0, err
+		}
+		value = (value << 7) | uint32(b&0x7F)
+		if (b & 0x80) == 0 {
+			return value, nil
+		}
+	}
+	return value, nil
+}
+
+// LengthPrefixed returns the next bytes that are prefixed with a variable length value.
+func (source *ByteSource) LengthPrefixed() ([]byte, error) {
+	length, err := source.VariableLength()
+	if err != nil {
+		return nil, err
+	}
+	return source.Bytes(int(length))
+}
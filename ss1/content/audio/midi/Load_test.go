@@ -0,0 +1,70 @@
+package midi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio/midi"
+)
+
+func TestLoadFailsForNilSource(t *testing.T) {
+	_, err := midi.Load(nil)
+	assert.Error(t, err)
+}
+
+func TestLoadFailsForOtherData(t *testing.T) {
+	_, err := midi.Load(bytes.NewReader([]byte("RIFF0000WAVE")))
+	assert.Error(t, err)
+}
+
+func TestLoadHandlesRunningStatus(t *testing.T) {
+	data := []byte{
+		'M', 'T', 'h', 'd', 0x00, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x01, 0x00, 0x60,
+		'M', 'T', 'r', 'k', 0x00, 0x00, 0x00, 0x0F,
+		0x00, 0x90, 0x3C, 0x40,
+		0x81, 0x00, 0x3C, 0x00,
+		0x00, 0xC1, 0x05,
+		0x00, 0xFF, 0x2F, 0x00,
+	}
+	file, err := midi.Load(bytes.NewReader(data))
+	require.Nil(t, err)
+	assert.Equal(t, uint16(0x60), file.Division)
+	require.Equal(t, 1, len(file.Tracks))
+	assert.Equal(t, midi.Track{
+		{Time: 0, Status: 0x90, Data: []byte{0x3C, 0x40}},
+		{Time: 0x80, Status: 0x90, Data: []byte{0x3C, 0x00}},
+		{Time: 0x80, Status: 0xC1, Data: []byte{0x05}},
+	}, file.Tracks[0])
+	assert.True(t, file.Tracks[0][1].IsNoteOff(), "zero velocity should be note-off")
+}
+
+func TestSaveAndLoadRoundTrip(t *testing.T) {
+	original := midi.File{
+		Format:   1,
+		Division: 120,
+		Tracks: []midi.Track{
+			{
+				midi.TempoEvent(0, 400000),
+				{Time: 0, Status: 0xB2, Data: []byte{0x07, 0x64}},
+				{Time: 10, Status: 0x92, Data: []byte{0x40, 0x7F}},
+				{Time: 300, Status: 0x82, Data: []byte{0x40, 0x40}},
+			},
+			{
+				{Time: 20000, Status: midi.StatusSysEx, Data: []byte{0x41, 0x10, 0xF7}},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	err := midi.Save(&buf, original)
+	require.Nil(t, err)
+
+	loaded, err := midi.Load(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+	assert.Equal(t, original, loaded)
+	tempo, isTempo := loaded.Tracks[0][0].Tempo()
+	assert.True(t, isTempo)
+	assert.Equal(t, uint32(400000), tempo)
+}
@@ -0,0 +1,75 @@
+package midi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// Save writes the given file as a standard MIDI file. Running status is not used.
+// Each track is terminated with an end-of-track event at the time of its last event.
+func Save(writer io.Writer, file File) error {
+	var buf bytes.Buffer
+	header := struct {
+		Tag      [4]byte
+		Length   uint32
+		Format   uint16
+		Tracks   uint16
+		Division uint16
+	}{
+		Length:   6,
+		Format:   file.Format,
+		Tracks:   uint16(len(file.Tracks)),
+		Division: file.Division,
+	}
+	copy(header.Tag[:], "MThd")
+	_ = binary.Write(&buf, binary.BigEndian, &header)
+	for _, track := range file.Tracks {
+		trackData := encodeTrack(track)
+		buf.WriteString("MTrk")
+		_ = binary.Write(&buf, binary.BigEndian, uint32(len(trackData)))
+		buf.Write(trackData)
+	}
+	_, err := writer.Write(buf.Bytes())
+	return err
+}
+
+func encodeTrack(track Track) []byte {
+	var buf bytes.Buffer
+	var time uint32
+	for _, event := range track {
+		if event.Time > time {
+			WriteVariableLength(&buf, event.Time-time)
+			time = event.Time
+		} else {
+			WriteVariableLength(&buf, 0)
+		}
+		buf.WriteByte(event.Status)
+		switch {
+		case event.Status < StatusSysEx:
+			buf.Write(event.Data)
+		case event.Status == StatusMeta:
+			buf.WriteByte(event.MetaType)
+			WriteVariableLength(&buf, uint32(len(event.Data)))
+			buf.Write(event.Data)
+		default:
+			WriteVariableLength(&buf, uint32(len(event.Data)))
+			buf.Write(event.Data)
+		}
+	}
+	WriteVariableLength(&buf, 0)
+	buf.Write([]byte{StatusMeta, MetaEndOfTrack, 0x00})
+	return buf.Bytes()
+}
+
+// WriteVariableLength writes the given value in variable-length encoding.
+func WriteVariableLength(buf *bytes.Buffer, value uint32) {
+	var encoded [5]byte
+	pos := len(encoded) - 1
+	encoded[pos] = byte(value & 0x7F)
+	for value >>= 7; value > 0; value >>= 7 {
+		pos--
+		encoded[pos] = byte(value&0x7F) | 0x80
+	}
+	buf.Write(encoded[pos:])
+}
@@ -0,0 +1,56 @@
+package vorbis
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/inkyblackness/hacked/ss1"
+	"github.com/inkyblackness/hacked/ss1/content/audio/dsp"
+)
+
+const (
+	errSourceIsNil    ss1.StringError = "source is nil"
+	errMissingHeaders ss1.StringError = "missing Vorbis headers"
+)
+
+const headerPacketCount = 3
+
+// LoadSignal reads an Ogg Vorbis stream from the given source and returns the audio mixed down to mono.
+func LoadSignal(source io.Reader) (dsp.Signal, error) {
+	if source == nil {
+		return dsp.Signal{}, errSourceIsNil
+	}
+	data, err := ioutil.ReadAll(source)
+	if err != nil {
+		return dsp.Signal{}, err
+	}
+	stream, err := readOggStream(data)
+	if err != nil {
+		return dsp.Signal{}, err
+	}
+	if len(stream.packets) < headerPacketCount {
+		return dsp.Signal{}, errMissingHeaders
+	}
+	decoder := newDecoder()
+	err = decoder.readIdentification(stream.packets[0])
+	if err != nil {
+		return dsp.Signal{}, err
+	}
+	err = readHeaderStart(newBitReader(stream.packets[1]), headerTypeComment)
+	if err != nil {
+		return dsp.Signal{}, err
+	}
+	err = decoder.readSetup(stream.packets[2])
+	if err != nil {
+		return dsp.Signal{}, err
+	}
+
+	var samples []float64
+	for _, packet := range stream.packets[headerPacketCount:] {
+		samples = decoder.decodePacket(packet, samples)
+	}
+	if (stream.lastGranule >= 0) && (int64(len(samples)) > stream.lastGranule) {
+		samples = samples[:stream.lastGranule]
+	}
+	return dsp.Signal{SampleRate: float64(decoder.sampleRate), Samples: samples}, nil
+}
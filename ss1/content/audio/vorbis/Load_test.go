@@ -0,0 +1,209 @@
+package vorbis_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio/vorbis"
+)
+
+type bitWriter struct {
+	data  []byte
+	count uint
+}
+
+func (writer *bitWriter) write(value uint64, bits uint) {
+	for bit := uint(0); bit < bits; bit++ {
+		if (writer.count % 8) == 0 {
+			writer.data = append(writer.data, 0)
+		}
+		if (value>>bit)&1 != 0 {
+			writer.data[len(writer.data)-1] |= 1 << (writer.count % 8)
+		}
+		writer.count++
+	}
+}
+
+func (writer *bitWriter) writeHeaderStart(headerType uint64) {
+	writer.write(headerType, 8)
+	for _, b := range []byte("vorbis") {
+		writer.write(uint64(b), 8)
+	}
+}
+
+func identificationHeader() []byte {
+	writer := &bitWriter{}
+	writer.writeHeaderStart(1)
+	writer.write(0, 32)     // version
+	writer.write(1, 8)      // channels
+	writer.write(22050, 32) // sample rate
+	writer.write(0, 32*3)   // bitrates
+	writer.write(8, 4)      // block size 0: 256
+	writer.write(8, 4)      // block size 1: 256
+	writer.write(1, 1)      // framing
+	return writer.data
+}
+
+func commentHeader() []byte {
+	writer := &bitWriter{}
+	writer.writeHeaderStart(3)
+	writer.write(0, 32) // vendor length
+	writer.write(0, 32) // comment count
+	writer.write(1, 1)  // framing
+	return writer.data
+}
+
+func setupHeader() []byte {
+	writer := &bitWriter{}
+	writer.writeHeaderStart(5)
+	writer.write(0, 8) // one codebook
+	writer.write(0x564342, 24)
+	writer.write(1, 16) // dimensions
+	writer.write(2, 24) // entries
+	writer.write(0, 1)  // not ordered
+	writer.write(0, 1)  // not sparse
+	writer.write(0, 5)  // length 1
+	writer.write(0, 5)  // length 1
+	writer.write(1, 4)  // lookup type 1
+	writer.write(0, 32) // minimum value 0.0
+	writer.write(0x00100000|(788-20)<<21, 32)
+	writer.write(0, 4) // 1 bit per value
+	writer.write(0, 1) // no sequence
+	writer.write(0, 1) // multiplicand 0
+	writer.write(1, 1) // multiplicand 1
+
+	writer.write(0, 6)  // one time domain transform
+	writer.write(0, 16) // placeholder
+	writer.write(0, 6)  // one floor
+	writer.write(1, 16) // floor type 1
+	writer.write(0, 5)  // no partitions
+	writer.write(0, 2)  // multiplier 1
+	writer.write(7, 4)  // range bits
+	writer.write(0, 6)  // one residue
+	writer.write(1, 16) // residue type 1
+	writer.write(0, 24) // begin
+	writer.write(128, 24)
+	writer.write(127, 24) // partition size 128
+	writer.write(0, 6)    // one classification
+	writer.write(0, 8)    // classbook
+	writer.write(1, 3)    // cascade: pass 0
+	writer.write(0, 1)
+	writer.write(0, 8) // book for pass 0
+	writer.write(0, 6) // one mapping
+	writer.write(0, 16)
+	writer.write(0, 1) // one submap
+	writer.write(0, 1) // no coupling
+	writer.write(0, 2) // reserved
+	writer.write(0, 8) // time
+	writer.write(0, 8) // floor
+	writer.write(0, 8) // residue
+	writer.write(0, 6) // one mode
+	writer.write(0, 1) // short block
+	writer.write(0, 16)
+	writer.write(0, 16)
+	writer.write(0, 8) // mapping
+	writer.write(1, 1) // framing
+	return writer.data
+}
+
+func audioPacket(withSignal bool) []byte {
+	writer := &bitWriter{}
+	writer.write(0, 1) // audio packet
+	if !withSignal {
+		writer.write(0, 1) // floor unused
+		return writer.data
+	}
+	writer.write(1, 1)   // floor used
+	writer.write(255, 8) // Y0
+	writer.write(255, 8) // Y1
+	writer.write(0, 1)   // classification 0
+	writer.write(1, 1)   // first coefficient 1.0
+	writer.write(0, 127) // remaining coefficients 0.0
+	return writer.data
+}
+
+func oggPage(flags byte, granule int64, sequence uint32, packets ...[]byte) []byte {
+	var segments []byte
+	var body []byte
+	for _, packet := range packets {
+		size := len(packet)
+		for ; size >= 255; size -= 255 {
+			segments = append(segments, 255)
+		}
+		segments = append(segments, byte(size))
+		body = append(body, packet...)
+	}
+	page := []byte{'O', 'g', 'g', 'S', 0, flags}
+	page = append(page, make([]byte, 8+4+4+4)...)
+	binary.LittleEndian.PutUint64(page[6:14], uint64(granule))
+	binary.LittleEndian.PutUint32(page[14:18], 0x1234)
+	binary.LittleEndian.PutUint32(page[18:22], sequence)
+	page = append(page, byte(len(segments)))
+	page = append(page, segments...)
+	return append(page, body...)
+}
+
+func stream(granule int64, packets ...[]byte) []byte {
+	data := oggPage(0x02, 0, 0, identificationHeader())
+	data = append(data, oggPage(0x00, 0, 1, commentHeader(), setupHeader())...)
+	data = append(data, oggPage(0x04, granule, 2, packets...)...)
+	return data
+}
+
+func TestLoadSignalReturnsErrorOnNil(t *testing.T) {
+	_, err := vorbis.LoadSignal(nil)
+
+	assert.NotNil(t, err)
+}
+
+func TestLoadSignalRejectsOtherData(t *testing.T) {
+	_, err := vorbis.LoadSignal(bytes.NewReader([]byte("fLaC")))
+
+	assert.NotNil(t, err)
+}
+
+func TestLoadSignalDecodesSilence(t *testing.T) {
+	data := stream(256, audioPacket(false), audioPacket(false), audioPacket(false))
+
+	signal, err := vorbis.LoadSignal(bytes.NewReader(data))
+
+	require.Nil(t, err)
+	assert.Equal(t, 22050.0, signal.SampleRate)
+	require.Equal(t, 256, len(signal.Samples))
+	assert.Equal(t, 0.0, signal.Peak())
+}
+
+func TestLoadSignalDecodesAudioAndTrimsToGranulePosition(t *testing.T) {
+	data := stream(200, audioPacket(true), audioPacket(true), audioPacket(true))
+
+	signal, err := vorbis.LoadSignal(bytes.NewReader(data))
+
+	require.Nil(t, err)
+	assert.Equal(t, 200, len(signal.Samples))
+	assert.True(t, signal.Peak() > 0.1, "signal expected")
+}
+
+func TestLoadSignalDecodesEncoderOutput(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("_testdata", "sample.ogg"))
+	require.Nil(t, err)
+	raw, err := ioutil.ReadFile(filepath.Join("_testdata", "sample.raw"))
+	require.Nil(t, err)
+	reference := make([]float64, len(raw)/4)
+	for index := range reference {
+		reference[index] = float64(math.Float32frombits(binary.LittleEndian.Uint32(raw[index*4:])))
+	}
+
+	signal, err := vorbis.LoadSignal(bytes.NewReader(data))
+
+	require.Nil(t, err)
+	assert.Equal(t, 44100.0, signal.SampleRate)
+	require.Equal(t, 44100, len(signal.Samples))
+	assert.InDeltaSlice(t, reference, signal.Samples[:len(reference)], 0.00002)
+}
@@ -0,0 +1,53 @@
+package vorbis
+
+// bitReader reads little-endian values of arbitrary bit size from a packet.
+// Reading beyond the end of the packet yields zeroes and sets the end-of-packet condition.
+type bitReader struct {
+	data        []byte
+	bitPos      int
+	endOfPacket bool
+}
+
+func newBitReader(data []byte) *bitReader {
+	return &bitReader{data: data}
+}
+
+func (reader *bitReader) read(count int) uint32 {
+	var value uint32
+	shift := uint(0)
+	for count > 0 {
+		bytePos := reader.bitPos >> 3
+		if bytePos >= len(reader.data) {
+			reader.endOfPacket = true
+			return 0
+		}
+		bitOffset := uint(reader.bitPos & 7)
+		take := 8 - int(bitOffset)
+		if take > count {
+			take = count
+		}
+		bits := (uint32(reader.data[bytePos]) >> bitOffset) & (1<<uint(take) - 1)
+		value |= bits << shift
+		shift += uint(take)
+		reader.bitPos += take
+		count -= take
+	}
+	return value
+}
+
+func (reader *bitReader) readInt(count int) int {
+	return int(reader.read(count))
+}
+
+func (reader *bitReader) readFlag() bool {
+	return reader.read(1) != 0
+}
+
+func ilog(value int) int {
+	count := 0
+	for value > 0 {
+		count++
+		value >>= 1
+	}
+	return count
+}
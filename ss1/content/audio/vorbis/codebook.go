@@ -0,0 +1,231 @@
+package vorbis
+
+import (
+	"math"
+
+	"github.com/inkyblackness/hacked/ss1"
+)
+
+const (
+	errInvalidCodebook ss1.StringError = "invalid codebook"
+
+	codebookSyncPattern = 0x564342
+	maxCodebookValues   = 1 << 24
+)
+
+type codebook struct {
+	dimensions int
+	entries    int
+
+	// tree holds the nodes of the Huffman tree. Positive values refer to further nodes,
+	// negative values are complemented entry numbers, zero marks an unused branch.
+	tree        [][2]int32
+	singleEntry int
+	singleBits  int
+
+	// vectors holds the values of all entries, with dimensions values each. It is nil without lookup.
+	vectors []float64
+}
+
+func readCodebook(reader *bitReader) (*codebook, error) {
+	if reader.read(24) != codebookSyncPattern {
+		return nil, errInvalidCodebook
+	}
+	book := &codebook{
+		dimensions:  reader.readInt(16),
+		entries:     reader.readInt(24),
+		singleEntry: -1,
+	}
+	lengths := make([]int, book.entries)
+	if reader.readFlag() {
+		currentEntry := 0
+		for currentLength := reader.readInt(5) + 1; currentEntry < book.entries; currentLength++ {
+			number := reader.readInt(ilog(book.entries - currentEntry))
+			if (currentEntry+number > book.entries) || (currentLength > 32) {
+				return nil, errInvalidCodebook
+			}
+			for ; number > 0; number-- {
+				lengths[currentEntry] = currentLength
+				currentEntry++
+			}
+		}
+	} else {
+		sparse := reader.readFlag()
+		for entry := range lengths {
+			if !sparse || reader.readFlag() {
+				lengths[entry] = reader.readInt(5) + 1
+			}
+		}
+	}
+	if reader.endOfPacket {
+		return nil, errInvalidCodebook
+	}
+	err := book.buildTree(lengths)
+	if err != nil {
+		return nil, err
+	}
+	err = book.readLookup(reader)
+	if err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// buildTree assigns the codewords to the entries, in order of the entries and always taking
+// the lowest available codeword of the requested length.
+func (book *codebook) buildTree(lengths []int) error {
+	var marker [33]uint32
+	usedEntries := 0
+	book.tree = [][2]int32{{}}
+	for entry, length := range lengths {
+		if length == 0 {
+			continue
+		}
+		usedEntries++
+		code := marker[length]
+		if (length < 32) && ((code >> uint(length)) != 0) {
+			return errInvalidCodebook
+		}
+		book.insertCode(entry, code, length)
+		book.singleEntry = entry
+		book.singleBits = length
+
+		for bit := length; bit > 0; bit-- {
+			if (marker[bit] & 1) != 0 {
+				if bit == 1 {
+					marker[1]++
+				} else {
+					marker[bit] = marker[bit-1] << 1
+				}
+				break
+			}
+			marker[bit]++
+		}
+		for bit := length + 1; bit < len(marker); bit++ {
+			if (marker[bit] >> 1) != code {
+				break
+			}
+			code = marker[bit]
+			marker[bit] = marker[bit-1] << 1
+		}
+	}
+	if usedEntries != 1 {
+		book.singleEntry = -1
+	}
+	return nil
+}
+
+func (book *codebook) insertCode(entry int, code uint32, length int) {
+	node := 0
+	for bit := length - 1; bit > 0; bit-- {
+		branch := (code >> uint(bit)) & 1
+		next := book.tree[node][branch]
+		if next <= 0 {
+			next = int32(len(book.tree))
+			book.tree = append(book.tree, [2]int32{})
+			book.tree[node][branch] = next
+		}
+		node = int(next)
+	}
+	book.tree[node][code&1] = ^int32(entry)
+}
+
+func (book *codebook) readLookup(reader *bitReader) error {
+	lookupType := reader.readInt(4)
+	if lookupType == 0 {
+		return nil
+	}
+	if (lookupType > 2) || (book.dimensions == 0) || (book.entries*book.dimensions > maxCodebookValues) {
+		return errInvalidCodebook
+	}
+	minimum := float32Unpack(reader.read(32))
+	delta := float32Unpack(reader.read(32))
+	valueBits := reader.readInt(4) + 1
+	sequenceP := reader.readFlag()
+	lookupValues := book.entries * book.dimensions
+	if lookupType == 1 {
+		lookupValues = lookup1Values(book.entries, book.dimensions)
+	}
+	multiplicands := make([]float64, lookupValues)
+	for index := range multiplicands {
+		multiplicands[index] = float64(reader.read(valueBits))
+	}
+	if reader.endOfPacket {
+		return errInvalidCodebook
+	}
+
+	book.vectors = make([]float64, book.entries*book.dimensions)
+	for entry := 0; entry < book.entries; entry++ {
+		last := 0.0
+		indexDivisor := 1
+		for dimension := 0; dimension < book.dimensions; dimension++ {
+			offset := entry*book.dimensions + dimension
+			if lookupType == 1 {
+				offset = (entry / indexDivisor) % lookupValues
+				indexDivisor *= lookupValues
+			}
+			value := multiplicands[offset]*delta + minimum + last
+			if sequenceP {
+				last = value
+			}
+			book.vectors[entry*book.dimensions+dimension] = value
+		}
+	}
+	return nil
+}
+
+// decodeScalar reads the next entry number. It returns -1 if the data is not decodable.
+func (book *codebook) decodeScalar(reader *bitReader) int {
+	if book.singleEntry >= 0 {
+		reader.read(book.singleBits)
+		return book.singleEntry
+	}
+	node := int32(0)
+	for !reader.endOfPacket {
+		node = book.tree[node][reader.read(1)]
+		if node < 0 {
+			return int(^node)
+		}
+		if node == 0 {
+			return -1
+		}
+	}
+	return -1
+}
+
+// decodeVector reads the next entry and returns its values. It returns nil if the data is not decodable.
+func (book *codebook) decodeVector(reader *bitReader) []float64 {
+	entry := book.decodeScalar(reader)
+	if (entry < 0) || (book.vectors == nil) || reader.endOfPacket {
+		return nil
+	}
+	return book.vectors[entry*book.dimensions : (entry+1)*book.dimensions]
+}
+
+func float32Unpack(value uint32) float64 {
+	mantissa := float64(value & 0x1FFFFF)
+	exponent := int((value & 0x7FE00000) >> 21)
+	if (value & 0x80000000) != 0 {
+		mantissa = -mantissa
+	}
+	return math.Ldexp(mantissa, exponent-788)
+}
+
+// lookup1Values returns the greatest value for which the power of dimensions is less than or equal to entries.
+func lookup1Values(entries, dimensions int) int {
+	result := int(math.Floor(math.Pow(float64(entries), 1/float64(dimensions))))
+	power := func(base int) int {
+		value := 1
+		for i := 0; (i < dimensions) && (value <= entries); i++ {
+			value *= base
+		}
+		return value
+	}
+	for power(result+1) <= entries {
+		result++
+	}
+	for (result > 0) && (power(result) > entries) {
+		result--
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package vorbis // nolint: testpackage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodebookAssignsCodewordsInEntryOrder(t *testing.T) {
+	book := &codebook{singleEntry: -1}
+	err := book.buildTree([]int{2, 4, 4, 4, 4, 2, 3, 3})
+	require.Nil(t, err)
+
+	// codewords: 00, 0100, 0101, 0110, 0111, 10, 110, 111 - read first bit first
+	codes := []struct {
+		bits  []uint32
+		entry int
+	}{
+		{bits: []uint32{0, 0}, entry: 0},
+		{bits: []uint32{0, 1, 0, 0}, entry: 1},
+		{bits: []uint32{0, 1, 1, 1}, entry: 4},
+		{bits: []uint32{1, 0}, entry: 5},
+		{bits: []uint32{1, 1, 1}, entry: 7},
+	}
+	for _, code := range codes {
+		var data []byte
+		for index, bit := range code.bits {
+			if index%8 == 0 {
+				data = append(data, 0)
+			}
+			data[index/8] |= byte(bit << uint(index%8))
+		}
+		reader := newBitReader(data)
+		assert.Equal(t, code.entry, book.decodeScalar(reader), "code %v", code.bits)
+	}
+}
+
+func TestCodebookRejectsOverspecifiedLengths(t *testing.T) {
+	book := &codebook{singleEntry: -1}
+	err := book.buildTree([]int{1, 1, 1})
+
+	assert.NotNil(t, err)
+}
+
+func TestFloat32Unpack(t *testing.T) {
+	assert.Equal(t, 1.0, float32Unpack(0x00100000|(788-20)<<21))
+	assert.Equal(t, -2.0, float32Unpack(0x80000000|0x00100000|(788-19)<<21))
+}
+
+func TestLookup1Values(t *testing.T) {
+	assert.Equal(t, 2, lookup1Values(8, 3))
+	assert.Equal(t, 3, lookup1Values(81, 4))
+	assert.Equal(t, 9, lookup1Values(81, 2))
+	assert.Equal(t, 8, lookup1Values(80, 2))
+}
@@ -0,0 +1,194 @@
+package vorbis
+
+import (
+	"math"
+)
+
+type windowKey struct {
+	blockFlag    bool
+	previousLong bool
+	nextLong     bool
+}
+
+type decoder struct {
+	channels   int
+	sampleRate int
+	blockSizes [2]int
+
+	codebooks []*codebook
+	floors    []floor
+	residues  []*residue
+	mappings  []*mapping
+	modes     []mode
+
+	transforms map[int]*imdct
+	windows    map[windowKey][]float64
+
+	// previous holds the windowed output of the last block for each channel, to be overlapped with the next.
+	previous     [][]float64
+	previousSize int
+}
+
+func newDecoder() *decoder {
+	return &decoder{
+		transforms: make(map[int]*imdct),
+		windows:    make(map[windowKey][]float64),
+	}
+}
+
+// decodePacket decodes one audio packet and appends the finished samples, mixed down to mono.
+func (decoder *decoder) decodePacket(packet []byte, samples []float64) []float64 {
+	reader := newBitReader(packet)
+	if reader.readFlag() {
+		return samples
+	}
+	modeNumber := reader.readInt(ilog(len(decoder.modes) - 1))
+	if modeNumber >= len(decoder.modes) {
+		return samples
+	}
+	currentMode := decoder.modes[modeNumber]
+	blockMapping := decoder.mappings[currentMode.mapping]
+	key := windowKey{blockFlag: currentMode.blockFlag}
+	n := decoder.blockSizes[0]
+	if currentMode.blockFlag {
+		n = decoder.blockSizes[1]
+		key.previousLong = reader.readFlag()
+		key.nextLong = reader.readFlag()
+	}
+	if reader.endOfPacket {
+		return samples
+	}
+	half := n / 2
+
+	vectors := make([][]float64, decoder.channels)
+	noResidue := make([]bool, decoder.channels)
+	floors := make([][]float64, decoder.channels)
+	for channel := range vectors {
+		vectors[channel] = make([]float64, half)
+		floors[channel] = make([]float64, half)
+		submap := blockMapping.mux[channel]
+		channelFloor := decoder.floors[blockMapping.submapFloor[submap]]
+		noResidue[channel] = !channelFloor.decode(reader, decoder.codebooks, floors[channel])
+	}
+	for step := range blockMapping.couplingMagnitude {
+		magnitude, angle := blockMapping.couplingMagnitude[step], blockMapping.couplingAngle[step]
+		if !noResidue[magnitude] || !noResidue[angle] {
+			noResidue[magnitude] = false
+			noResidue[angle] = false
+		}
+	}
+	for submap, residueNumber := range blockMapping.submapResidue {
+		var bundle [][]float64
+		var doNotDecode []bool
+		for channel, channelSubmap := range blockMapping.mux {
+			if channelSubmap == submap {
+				bundle = append(bundle, vectors[channel])
+				doNotDecode = append(doNotDecode, noResidue[channel])
+			}
+		}
+		if len(bundle) > 0 {
+			decoder.residues[residueNumber].decode(reader, decoder.codebooks, bundle, doNotDecode)
+		}
+	}
+	for step := len(blockMapping.couplingMagnitude) - 1; step >= 0; step-- {
+		magnitudes := vectors[blockMapping.couplingMagnitude[step]]
+		angles := vectors[blockMapping.couplingAngle[step]]
+		for index, m := range magnitudes {
+			a := angles[index]
+			switch {
+			case (m > 0) && (a > 0):
+				angles[index] = m - a
+			case m > 0:
+				magnitudes[index], angles[index] = m+a, m
+			case a > 0:
+				angles[index] = m + a
+			default:
+				magnitudes[index], angles[index] = m-a, m
+			}
+		}
+	}
+
+	transform := decoder.transform(n)
+	window := decoder.window(key, n)
+	current := make([][]float64, decoder.channels)
+	for channel, vector := range vectors {
+		for index := range vector {
+			vector[index] *= floors[channel][index]
+		}
+		current[channel] = make([]float64, n)
+		transform.transform(vector, current[channel])
+		for index, factor := range window {
+			current[channel][index] *= factor
+		}
+	}
+
+	if decoder.previous != nil {
+		samples = decoder.overlap(current, n, samples)
+	}
+	decoder.previous = current
+	decoder.previousSize = n
+	return samples
+}
+
+// overlap adds the right half of the previous block to the left half of the current block.
+// The returned samples range from the center of the previous block to the center of the current block.
+func (decoder *decoder) overlap(current [][]float64, n int, samples []float64) []float64 {
+	previousSize := decoder.previousSize
+	count := previousSize/4 + n/4
+	scale := 1.0 / float64(decoder.channels)
+	for k := 0; k < count; k++ {
+		previousIndex := previousSize/2 + k
+		currentIndex := k - previousSize/4 + n/4
+		sum := 0.0
+		for channel := range current {
+			if previousIndex < previousSize {
+				sum += decoder.previous[channel][previousIndex]
+			}
+			if currentIndex >= 0 {
+				sum += current[channel][currentIndex]
+			}
+		}
+		samples = append(samples, sum*scale)
+	}
+	return samples
+}
+
+func (decoder *decoder) transform(n int) *imdct {
+	transform, cached := decoder.transforms[n]
+	if !cached {
+		transform = newIMDCT(n)
+		decoder.transforms[n] = transform
+	}
+	return transform
+}
+
+func (decoder *decoder) window(key windowKey, n int) []float64 {
+	if window, cached := decoder.windows[key]; cached {
+		return window
+	}
+	shortSize := decoder.blockSizes[0]
+	leftStart, leftEnd, leftN := 0, n/2, n/2
+	if key.blockFlag && !key.previousLong {
+		leftStart, leftEnd, leftN = n/4-shortSize/4, n/4+shortSize/4, shortSize/2
+	}
+	rightStart, rightEnd, rightN := n/2, n, n/2
+	if key.blockFlag && !key.nextLong {
+		rightStart, rightEnd, rightN = n*3/4-shortSize/4, n*3/4+shortSize/4, shortSize/2
+	}
+	slope := func(position float64) float64 {
+		return math.Sin(math.Pi / 2 * sqr(math.Sin(position*math.Pi/2)))
+	}
+	window := make([]float64, n)
+	for i := range window {
+		switch {
+		case (i >= leftStart) && (i < leftEnd):
+			window[i] = slope((float64(i-leftStart) + 0.5) / float64(leftN))
+		case (i >= leftEnd) && (i < rightStart):
+			window[i] = 1
+		case (i >= rightStart) && (i < rightEnd):
+			window[i] = slope((float64(i-rightStart)+0.5)/float64(rightN) + 1)
+		}
+	}
+	decoder.windows[key] = window
+	return window
+}
@@ -0,0 +1,5 @@
+// Package vorbis decodes Ogg Vorbis audio.
+//
+// The decoder follows the Vorbis I specification. It handles the first logical stream of an
+// Ogg container and mixes the decoded audio down to a mono signal.
+package vorbis
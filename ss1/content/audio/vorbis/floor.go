@@ -0,0 +1,392 @@
+package vorbis
+
+import (
+	"math"
+	"sort"
+
+	"github.com/inkyblackness/hacked/ss1"
+)
+
+const (
+	errInvalidFloor ss1.StringError = "invalid floor"
+
+	floor1MaxValues = 65
+)
+
+// floor describes the spectral envelope of a channel.
+type floor interface {
+	// decode reads the floor of one channel and renders its curve of size n into output.
+	// It returns false if the channel is unused in the current packet.
+	decode(reader *bitReader, codebooks []*codebook, output []float64) bool
+}
+
+func readFloor(reader *bitReader, codebookCount int) (floor, error) {
+	switch reader.readInt(16) {
+	case 0:
+		return readFloor0(reader, codebookCount)
+	case 1:
+		return readFloor1(reader, codebookCount)
+	default:
+		return nil, errInvalidFloor
+	}
+}
+
+type floor0 struct {
+	order           int
+	rate            int
+	barkMapSize     int
+	amplitudeBits   int
+	amplitudeOffset int
+	books           []int
+
+	barkMaps map[int][]int
+}
+
+func readFloor0(reader *bitReader, codebookCount int) (*floor0, error) {
+	f := &floor0{
+		order:           reader.readInt(8),
+		rate:            reader.readInt(16),
+		barkMapSize:     reader.readInt(16),
+		amplitudeBits:   reader.readInt(6),
+		amplitudeOffset: reader.readInt(8),
+		barkMaps:        make(map[int][]int),
+	}
+	f.books = make([]int, reader.readInt(4)+1)
+	for index := range f.books {
+		f.books[index] = reader.readInt(8)
+		if f.books[index] >= codebookCount {
+			return nil, errInvalidFloor
+		}
+	}
+	if (f.order < 1) || (f.rate < 1) || (f.barkMapSize < 1) {
+		return nil, errInvalidFloor
+	}
+	return f, nil
+}
+
+func (f *floor0) decode(reader *bitReader, codebooks []*codebook, output []float64) bool {
+	amplitude := reader.readInt(f.amplitudeBits)
+	if amplitude == 0 {
+		return false
+	}
+	bookNumber := reader.readInt(ilog(len(f.books)))
+	if bookNumber >= len(f.books) {
+		return false
+	}
+	book := codebooks[f.books[bookNumber]]
+	coefficients := make([]float64, 0, f.order+book.dimensions)
+	last := 0.0
+	for len(coefficients) < f.order {
+		vector := book.decodeVector(reader)
+		if vector == nil {
+			return false
+		}
+		for _, value := range vector {
+			coefficients = append(coefficients, value+last)
+		}
+		last = coefficients[len(coefficients)-1]
+	}
+
+	n := len(output)
+	barkMap := f.barkMap(n)
+	maxAmplitude := float64(int(1)<<uint(f.amplitudeBits) - 1)
+	for i := 0; i < n; {
+		omega := math.Pi * float64(barkMap[i]) / float64(f.barkMapSize)
+		cosOmega := math.Cos(omega)
+		p, q := 1.0, 1.0
+		for j := 0; j+1 < f.order; j += 2 {
+			p *= 4 * sqr(math.Cos(coefficients[j+1])-cosOmega)
+			q *= 4 * sqr(math.Cos(coefficients[j])-cosOmega)
+		}
+		if (f.order % 2) != 0 {
+			q *= 4 * sqr(math.Cos(coefficients[f.order-1])-cosOmega)
+			p *= 1 - cosOmega*cosOmega
+			q /= 4
+		} else {
+			p *= (1 - cosOmega) / 2
+			q *= (1 + cosOmega) / 2
+		}
+		value := math.Exp(0.11512925 * (float64(amplitude)*float64(f.amplitudeOffset)/
+			(maxAmplitude*math.Sqrt(p+q)) - float64(f.amplitudeOffset)))
+		condition := barkMap[i]
+		for (i < n) && (barkMap[i] == condition) {
+			output[i] = value
+			i++
+		}
+	}
+	return true
+}
+
+func (f *floor0) barkMap(n int) []int {
+	if barkMap, cached := f.barkMaps[n]; cached {
+		return barkMap
+	}
+	bark := func(x float64) float64 {
+		return 13.1*math.Atan(0.00074*x) + 2.24*math.Atan(0.0000000185*x*x) + 0.0001*x
+	}
+	barkMap := make([]int, n)
+	for i := range barkMap {
+		value := int(math.Floor(bark(float64(f.rate*i)/float64(2*n)) * float64(f.barkMapSize) /
+			bark(0.5*float64(f.rate))))
+		if value > f.barkMapSize-1 {
+			value = f.barkMapSize - 1
+		}
+		barkMap[i] = value
+	}
+	f.barkMaps[n] = barkMap
+	return barkMap
+}
+
+func sqr(value float64) float64 {
+	return value * value
+}
+
+type floor1 struct {
+	partitionClasses []int
+	classDimensions  []int
+	classSubclasses  []int
+	classMasterbooks []int
+	subclassBooks    [][]int
+	multiplier       int
+	xList            []int
+
+	sortedOrder  []int
+	lowNeighbor  []int
+	highNeighbor []int
+}
+
+var floor1Ranges = [...]int{256, 128, 86, 64}
+
+func readFloor1(reader *bitReader, codebookCount int) (*floor1, error) {
+	f := &floor1{}
+	f.partitionClasses = make([]int, reader.readInt(5))
+	maxClass := -1
+	for index := range f.partitionClasses {
+		f.partitionClasses[index] = reader.readInt(4)
+		if f.partitionClasses[index] > maxClass {
+			maxClass = f.partitionClasses[index]
+		}
+	}
+	classCount := maxClass + 1
+	f.classDimensions = make([]int, classCount)
+	f.classSubclasses = make([]int, classCount)
+	f.classMasterbooks = make([]int, classCount)
+	f.subclassBooks = make([][]int, classCount)
+	for class := 0; class < classCount; class++ {
+		f.classDimensions[class] = reader.readInt(3) + 1
+		f.classSubclasses[class] = reader.readInt(2)
+		if f.classSubclasses[class] != 0 {
+			f.classMasterbooks[class] = reader.readInt(8)
+			if f.classMasterbooks[class] >= codebookCount {
+				return nil, errInvalidFloor
+			}
+		}
+		f.subclassBooks[class] = make([]int, 1<<uint(f.classSubclasses[class]))
+		for index := range f.subclassBooks[class] {
+			f.subclassBooks[class][index] = reader.readInt(8) - 1
+			if f.subclassBooks[class][index] >= codebookCount {
+				return nil, errInvalidFloor
+			}
+		}
+	}
+	f.multiplier = reader.readInt(2) + 1
+	rangeBits := reader.readInt(4)
+	f.xList = []int{0, 1 << uint(rangeBits)}
+	for _, class := range f.partitionClasses {
+		for j := 0; j < f.classDimensions[class]; j++ {
+			f.xList = append(f.xList, reader.readInt(rangeBits))
+		}
+	}
+	if (len(f.xList) > floor1MaxValues) || reader.endOfPacket {
+		return nil, errInvalidFloor
+	}
+
+	f.sortedOrder = make([]int, len(f.xList))
+	for index := range f.sortedOrder {
+		f.sortedOrder[index] = index
+	}
+	sort.SliceStable(f.sortedOrder, func(a, b int) bool { return f.xList[f.sortedOrder[a]] < f.xList[f.sortedOrder[b]] })
+	for index := 1; index < len(f.sortedOrder); index++ {
+		if f.xList[f.sortedOrder[index]] == f.xList[f.sortedOrder[index-1]] {
+			return nil, errInvalidFloor
+		}
+	}
+	f.lowNeighbor = make([]int, len(f.xList))
+	f.highNeighbor = make([]int, len(f.xList))
+	for i := 2; i < len(f.xList); i++ {
+		low, high := 0, 1
+		for n := 0; n < i; n++ {
+			if (f.xList[n] < f.xList[i]) && (f.xList[n] > f.xList[low]) {
+				low = n
+			}
+			if (f.xList[n] > f.xList[i]) && (f.xList[n] < f.xList[high]) {
+				high = n
+			}
+		}
+		f.lowNeighbor[i] = low
+		f.highNeighbor[i] = high
+	}
+	return f, nil
+}
+
+func (f *floor1) decode(reader *bitReader, codebooks []*codebook, output []float64) bool {
+	if !reader.readFlag() {
+		return false
+	}
+	valueRange := floor1Ranges[f.multiplier-1]
+	y := make([]int, len(f.xList))
+	y[0] = reader.readInt(ilog(valueRange - 1))
+	y[1] = reader.readInt(ilog(valueRange - 1))
+	offset := 2
+	for _, class := range f.partitionClasses {
+		dimensions := f.classDimensions[class]
+		subclassBits := uint(f.classSubclasses[class])
+		subclassMask := (1 << subclassBits) - 1
+		classValue := 0
+		if subclassBits > 0 {
+			classValue = codebooks[f.classMasterbooks[class]].decodeScalar(reader)
+			if classValue < 0 {
+				return false
+			}
+		}
+		for j := 0; j < dimensions; j++ {
+			book := f.subclassBooks[class][classValue&subclassMask]
+			classValue >>= subclassBits
+			if book >= 0 {
+				y[offset+j] = codebooks[book].decodeScalar(reader)
+				if y[offset+j] < 0 {
+					return false
+				}
+			}
+		}
+		offset += dimensions
+	}
+	if reader.endOfPacket {
+		return false
+	}
+
+	finalY, step2 := f.synthesizeAmplitudes(y, valueRange)
+	f.renderCurve(finalY, step2, output)
+	return true
+}
+
+func (f *floor1) synthesizeAmplitudes(y []int, valueRange int) ([]int, []bool) {
+	finalY := make([]int, len(y))
+	step2 := make([]bool, len(y))
+	finalY[0], finalY[1] = y[0], y[1]
+	step2[0], step2[1] = true, true
+	for i := 2; i < len(y); i++ {
+		low, high := f.lowNeighbor[i], f.highNeighbor[i]
+		predicted := renderPoint(f.xList[low], finalY[low], f.xList[high], finalY[high], f.xList[i])
+		value := y[i]
+		highRoom := valueRange - predicted
+		lowRoom := predicted
+		room := highRoom
+		if lowRoom < room {
+			room = lowRoom
+		}
+		room *= 2
+		switch {
+		case value == 0:
+			finalY[i] = predicted
+		case value >= room:
+			step2[low], step2[high], step2[i] = true, true, true
+			if highRoom > lowRoom {
+				finalY[i] = value - lowRoom + predicted
+			} else {
+				finalY[i] = predicted - value + highRoom - 1
+			}
+		default:
+			step2[low], step2[high], step2[i] = true, true, true
+			if (value % 2) != 0 {
+				finalY[i] = predicted - (value+1)/2
+			} else {
+				finalY[i] = predicted + value/2
+			}
+		}
+		if finalY[i] < 0 {
+			finalY[i] = 0
+		} else if finalY[i] >= valueRange {
+			finalY[i] = valueRange - 1
+		}
+	}
+	return finalY, step2
+}
+
+func (f *floor1) renderCurve(finalY []int, step2 []bool, output []float64) {
+	n := len(output)
+	values := make([]int, n)
+	lx, ly := 0, finalY[f.sortedOrder[0]]*f.multiplier
+	hx, hy := 0, 0
+	for _, index := range f.sortedOrder[1:] {
+		if step2[index] {
+			hy = finalY[index] * f.multiplier
+			hx = f.xList[index]
+			renderLine(lx, ly, hx, hy, values)
+			lx, ly = hx, hy
+		}
+	}
+	if hx < n {
+		renderLine(hx, hy, n, hy, values)
+	}
+	for index, value := range values {
+		output[index] = floor1InverseDBTable[value]
+	}
+}
+
+func renderPoint(x0, y0, x1, y1, x int) int {
+	dy := y1 - y0
+	adx := x1 - x0
+	ady := dy
+	if ady < 0 {
+		ady = -ady
+	}
+	off := ady * (x - x0) / adx
+	if dy < 0 {
+		return y0 - off
+	}
+	return y0 + off
+}
+
+func renderLine(x0, y0, x1, y1 int, values []int) {
+	dy := y1 - y0
+	adx := x1 - x0
+	ady := dy
+	if ady < 0 {
+		ady = -ady
+	}
+	base := dy / adx
+	sy := base + 1
+	if dy < 0 {
+		sy = base - 1
+	}
+	absBase := base
+	if absBase < 0 {
+		absBase = -absBase
+	}
+	ady -= absBase * adx
+	y := y0
+	err := 0
+	if x0 < len(values) {
+		values[x0] = y
+	}
+	for x := x0 + 1; (x < x1) && (x < len(values)); x++ {
+		err += ady
+		if err >= adx {
+			err -= adx
+			y += sy
+		} else {
+			y += base
+		}
+		values[x] = y
+	}
+}
+
+// floor1InverseDBTable maps the floor values to amplitudes, covering a range of 140 dB in equal steps.
+var floor1InverseDBTable = func() [256]float64 {
+	var table [256]float64
+	for index := range table {
+		table[index] = float64(float32(math.Exp(0.11512925 * 0.546875 * float64(index-255))))
+	}
+	return table
+}()
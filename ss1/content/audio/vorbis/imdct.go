@@ -0,0 +1,92 @@
+package vorbis
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// imdct computes the inverse modified discrete cosine transform of one block size.
+// The transform is calculated as a type IV discrete cosine transform of half the size,
+// which in turn is based on a complex FFT of a quarter of the block size.
+type imdct struct {
+	n           int
+	preTwiddle  []complex128
+	postTwiddle []complex128
+	fftTwiddle  []complex128
+	bitReverse  []int
+	work        []complex128
+	dct         []float64
+}
+
+func newIMDCT(n int) *imdct {
+	m := n / 2
+	l := m / 2
+	transform := &imdct{
+		n:           n,
+		preTwiddle:  make([]complex128, l),
+		postTwiddle: make([]complex128, l),
+		fftTwiddle:  make([]complex128, l/2),
+		bitReverse:  make([]int, l),
+		work:        make([]complex128, l),
+		dct:         make([]float64, m),
+	}
+	for k := 0; k < l; k++ {
+		transform.preTwiddle[k] = cmplx.Exp(complex(0, -math.Pi*float64(4*k+1)/float64(4*m)))
+		transform.postTwiddle[k] = cmplx.Exp(complex(0, -math.Pi*float64(k)/float64(m)))
+	}
+	for k := range transform.fftTwiddle {
+		transform.fftTwiddle[k] = cmplx.Exp(complex(0, -2*math.Pi*float64(k)/float64(l)))
+	}
+	bitCount := uint(ilog(l) - 1)
+	for k := range transform.bitReverse {
+		reversed := 0
+		for bit := uint(0); bit < bitCount; bit++ {
+			reversed |= ((k >> bit) & 1) << (bitCount - 1 - bit)
+		}
+		transform.bitReverse[k] = reversed
+	}
+	return transform
+}
+
+// transform calculates n output values from the n/2 input coefficients.
+func (transform *imdct) transform(input []float64, output []float64) {
+	m := transform.n / 2
+	l := m / 2
+	for k := 0; k < l; k++ {
+		value := complex(input[2*k], input[m-1-2*k]) * transform.preTwiddle[k]
+		transform.work[transform.bitReverse[k]] = value
+	}
+	transform.fft()
+	for k := 0; k < l; k++ {
+		value := transform.work[k] * transform.postTwiddle[k]
+		transform.dct[2*k] = real(value)
+		transform.dct[m-1-2*k] = -imag(value)
+	}
+
+	half := m / 2
+	for i := 0; i < half; i++ {
+		output[i] = transform.dct[i+half]
+	}
+	for i := half; i < m+half; i++ {
+		output[i] = -transform.dct[m+half-1-i]
+	}
+	for i := m + half; i < transform.n; i++ {
+		output[i] = -transform.dct[i-m-half]
+	}
+}
+
+// fft performs an in-place radix-2 FFT on the work buffer, which is expected in bit-reversed order.
+func (transform *imdct) fft() {
+	l := len(transform.work)
+	for size := 2; size <= l; size <<= 1 {
+		halfSize := size / 2
+		step := l / size
+		for start := 0; start < l; start += size {
+			for k := 0; k < halfSize; k++ {
+				twiddled := transform.work[start+k+halfSize] * transform.fftTwiddle[k*step]
+				transform.work[start+k+halfSize] = transform.work[start+k] - twiddled
+				transform.work[start+k] += twiddled
+			}
+		}
+	}
+}
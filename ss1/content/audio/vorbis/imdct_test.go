@@ -0,0 +1,30 @@
+package vorbis // nolint: testpackage
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIMDCTMatchesDefinition(t *testing.T) {
+	for _, n := range []int{64, 256, 2048} {
+		random := rand.New(rand.NewSource(int64(n)))
+		input := make([]float64, n/2)
+		for index := range input {
+			input[index] = random.Float64()*2 - 1
+		}
+		expected := make([]float64, n)
+		for i := range expected {
+			for k, value := range input {
+				expected[i] += value * math.Cos(2*math.Pi/float64(n)*(float64(i)+0.5+float64(n)/4)*(float64(k)+0.5))
+			}
+		}
+		output := make([]float64, n)
+
+		newIMDCT(n).transform(input, output)
+
+		assert.InDeltaSlice(t, expected, output, 1e-9, "size %d", n)
+	}
+}
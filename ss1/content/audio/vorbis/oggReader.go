@@ -0,0 +1,83 @@
+package vorbis
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/inkyblackness/hacked/ss1"
+)
+
+const (
+	errNotAnOggStream ss1.StringError = "not an Ogg stream"
+	errInvalidPage    ss1.StringError = "invalid Ogg page"
+)
+
+// FileHeader identifies Ogg streams.
+const FileHeader = "OggS"
+
+const (
+	pageHeaderSize       = 27
+	pageFlagContinuation = 0x01
+)
+
+// oggStream contains the packets of the first logical stream of an Ogg container.
+type oggStream struct {
+	packets [][]byte
+	// lastGranule is the granule position of the last page, which for Vorbis is the total sample count.
+	lastGranule int64
+}
+
+func readOggStream(data []byte) (oggStream, error) {
+	var stream oggStream
+	if !bytes.HasPrefix(data, []byte(FileHeader)) {
+		return stream, errNotAnOggStream
+	}
+	stream.lastGranule = -1
+	var serial uint32
+	var pending []byte
+	for pos, pageIndex := 0, 0; pos+pageHeaderSize <= len(data); pageIndex++ {
+		if !bytes.Equal(data[pos:pos+4], []byte(FileHeader)) {
+			return stream, errInvalidPage
+		}
+		flags := data[pos+5]
+		granule := int64(binary.LittleEndian.Uint64(data[pos+6 : pos+14]))
+		pageSerial := binary.LittleEndian.Uint32(data[pos+14 : pos+18])
+		segmentCount := int(data[pos+26])
+		tableStart := pos + pageHeaderSize
+		bodyStart := tableStart + segmentCount
+		if bodyStart > len(data) {
+			return stream, errInvalidPage
+		}
+		segments := data[tableStart:bodyStart]
+		bodySize := 0
+		for _, size := range segments {
+			bodySize += int(size)
+		}
+		if bodyStart+bodySize > len(data) {
+			// A truncated last page is dropped.
+			break
+		}
+		pos = bodyStart + bodySize
+		if pageIndex == 0 {
+			serial = pageSerial
+		} else if pageSerial != serial {
+			continue
+		}
+		if (flags & pageFlagContinuation) == 0 {
+			pending = nil
+		}
+		body := data[bodyStart:]
+		for _, size := range segments {
+			pending = append(pending, body[:size]...)
+			body = body[size:]
+			if size < 255 {
+				stream.packets = append(stream.packets, pending)
+				pending = nil
+			}
+		}
+		if granule != -1 {
+			stream.lastGranule = granule
+		}
+	}
+	return stream, nil
+}
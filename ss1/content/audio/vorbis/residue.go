@@ -0,0 +1,171 @@
+package vorbis
+
+import (
+	"github.com/inkyblackness/hacked/ss1"
+)
+
+const (
+	errInvalidResidue ss1.StringError = "invalid residue"
+
+	residuePasses = 8
+)
+
+type residue struct {
+	residueType     int
+	begin           int
+	end             int
+	partitionSize   int
+	classifications int
+	classbook       int
+	books           [][residuePasses]int
+}
+
+func readResidue(reader *bitReader, codebooks []*codebook) (*residue, error) {
+	res := &residue{residueType: reader.readInt(16)}
+	if res.residueType > 2 {
+		return nil, errInvalidResidue
+	}
+	res.begin = reader.readInt(24)
+	res.end = reader.readInt(24)
+	res.partitionSize = reader.readInt(24) + 1
+	res.classifications = reader.readInt(6) + 1
+	res.classbook = reader.readInt(8)
+	if res.classbook >= len(codebooks) {
+		return nil, errInvalidResidue
+	}
+	cascades := make([]int, res.classifications)
+	for index := range cascades {
+		cascades[index] = reader.readInt(3)
+		if reader.readFlag() {
+			cascades[index] |= reader.readInt(5) << 3
+		}
+	}
+	res.books = make([][residuePasses]int, res.classifications)
+	for class, cascade := range cascades {
+		for pass := 0; pass < residuePasses; pass++ {
+			res.books[class][pass] = -1
+			if (cascade & (1 << uint(pass))) != 0 {
+				book := reader.readInt(8)
+				if (book >= len(codebooks)) || (codebooks[book].vectors == nil) {
+					return nil, errInvalidResidue
+				}
+				res.books[class][pass] = book
+			}
+		}
+	}
+	if reader.endOfPacket {
+		return nil, errInvalidResidue
+	}
+	return res, nil
+}
+
+// decode reads the residue vectors of the given channels. Vectors marked as not to decode remain zero.
+func (res *residue) decode(reader *bitReader, codebooks []*codebook, vectors [][]float64, doNotDecode []bool) {
+	if res.residueType != 2 {
+		res.decodeVectors(reader, codebooks, vectors, doNotDecode)
+		return
+	}
+
+	anyToDecode := false
+	for _, skip := range doNotDecode {
+		anyToDecode = anyToDecode || !skip
+	}
+	if !anyToDecode {
+		return
+	}
+	channels := len(vectors)
+	n := len(vectors[0])
+	interleaved := make([]float64, channels*n)
+	res.decodeVectors(reader, codebooks, [][]float64{interleaved}, []bool{false})
+	for index, value := range interleaved {
+		vectors[index%channels][index/channels] = value
+	}
+}
+
+func (res *residue) decodeVectors(reader *bitReader, codebooks []*codebook, vectors [][]float64, doNotDecode []bool) {
+	actualSize := len(vectors[0])
+	limitBegin := minInt(res.begin, actualSize)
+	limitEnd := minInt(res.end, actualSize)
+	classbook := codebooks[res.classbook]
+	classWordsPerCodeword := classbook.dimensions
+	partitionsToRead := (limitEnd - limitBegin) / res.partitionSize
+	if (partitionsToRead <= 0) || (classWordsPerCodeword == 0) {
+		return
+	}
+
+	classifications := make([][]int, len(vectors))
+	for channel := range classifications {
+		classifications[channel] = make([]int, partitionsToRead+classWordsPerCodeword)
+	}
+	for pass := 0; pass < residuePasses; pass++ {
+		for partitionCount := 0; partitionCount < partitionsToRead; {
+			if pass == 0 {
+				for channel := range vectors {
+					if doNotDecode[channel] {
+						continue
+					}
+					temp := classbook.decodeScalar(reader)
+					if temp < 0 {
+						return
+					}
+					for i := classWordsPerCodeword - 1; i >= 0; i-- {
+						classifications[channel][i+partitionCount] = temp % res.classifications
+						temp /= res.classifications
+					}
+				}
+			}
+			for i := 0; (i < classWordsPerCodeword) && (partitionCount < partitionsToRead); i++ {
+				for channel, vector := range vectors {
+					if doNotDecode[channel] {
+						continue
+					}
+					book := res.books[classifications[channel][partitionCount]][pass]
+					if book < 0 {
+						continue
+					}
+					offset := limitBegin + partitionCount*res.partitionSize
+					if !res.decodePartition(reader, codebooks[book], vector[offset:offset+res.partitionSize]) {
+						return
+					}
+				}
+				partitionCount++
+			}
+		}
+	}
+}
+
+func (res *residue) decodePartition(reader *bitReader, book *codebook, output []float64) bool {
+	if res.residueType == 0 {
+		step := len(output) / book.dimensions
+		for i := 0; i < step; i++ {
+			vector := book.decodeVector(reader)
+			if vector == nil {
+				return false
+			}
+			for j, value := range vector {
+				output[i+j*step] += value
+			}
+		}
+		return true
+	}
+	for i := 0; i < len(output); {
+		vector := book.decodeVector(reader)
+		if vector == nil {
+			return false
+		}
+		for _, value := range vector {
+			if i < len(output) {
+				output[i] += value
+			}
+			i++
+		}
+	}
+	return true
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,168 @@
+package vorbis
+
+import (
+	"github.com/inkyblackness/hacked/ss1"
+)
+
+const (
+	errInvalidHeader  ss1.StringError = "invalid header"
+	errInvalidMapping ss1.StringError = "invalid mapping"
+
+	headerTypeIdentification = 1
+	headerTypeComment        = 3
+	headerTypeSetup          = 5
+	headerMagic              = "vorbis"
+)
+
+type mapping struct {
+	couplingMagnitude []int
+	couplingAngle     []int
+	mux               []int
+	submapFloor       []int
+	submapResidue     []int
+}
+
+type mode struct {
+	blockFlag bool
+	mapping   int
+}
+
+func readHeaderStart(reader *bitReader, expectedType int) error {
+	if reader.readInt(8) != expectedType {
+		return errInvalidHeader
+	}
+	for _, b := range []byte(headerMagic) {
+		if reader.read(8) != uint32(b) {
+			return errInvalidHeader
+		}
+	}
+	return nil
+}
+
+func (decoder *decoder) readIdentification(packet []byte) error {
+	reader := newBitReader(packet)
+	err := readHeaderStart(reader, headerTypeIdentification)
+	if err != nil {
+		return err
+	}
+	version := reader.read(32)
+	decoder.channels = reader.readInt(8)
+	decoder.sampleRate = int(reader.read(32))
+	reader.read(32) // maximum bitrate
+	reader.read(32) // nominal bitrate
+	reader.read(32) // minimum bitrate
+	decoder.blockSizes[0] = 1 << uint(reader.readInt(4))
+	decoder.blockSizes[1] = 1 << uint(reader.readInt(4))
+	framing := reader.readFlag()
+	if (version != 0) || (decoder.channels == 0) || (decoder.sampleRate == 0) ||
+		(decoder.blockSizes[0] < 64) || (decoder.blockSizes[1] > 8192) ||
+		(decoder.blockSizes[0] > decoder.blockSizes[1]) || !framing {
+		return errInvalidHeader
+	}
+	return nil
+}
+
+func (decoder *decoder) readSetup(packet []byte) error {
+	reader := newBitReader(packet)
+	err := readHeaderStart(reader, headerTypeSetup)
+	if err != nil {
+		return err
+	}
+
+	decoder.codebooks = make([]*codebook, reader.readInt(8)+1)
+	for index := range decoder.codebooks {
+		decoder.codebooks[index], err = readCodebook(reader)
+		if err != nil {
+			return err
+		}
+	}
+	for timeCount := reader.readInt(6) + 1; timeCount > 0; timeCount-- {
+		if reader.readInt(16) != 0 {
+			return errInvalidHeader
+		}
+	}
+	decoder.floors = make([]floor, reader.readInt(6)+1)
+	for index := range decoder.floors {
+		decoder.floors[index], err = readFloor(reader, len(decoder.codebooks))
+		if err != nil {
+			return err
+		}
+	}
+	decoder.residues = make([]*residue, reader.readInt(6)+1)
+	for index := range decoder.residues {
+		decoder.residues[index], err = readResidue(reader, decoder.codebooks)
+		if err != nil {
+			return err
+		}
+	}
+	decoder.mappings = make([]*mapping, reader.readInt(6)+1)
+	for index := range decoder.mappings {
+		decoder.mappings[index], err = decoder.readMapping(reader)
+		if err != nil {
+			return err
+		}
+	}
+	decoder.modes = make([]mode, reader.readInt(6)+1)
+	for index := range decoder.modes {
+		decoder.modes[index].blockFlag = reader.readFlag()
+		reader.read(16 + 16) // window type and transform type
+		decoder.modes[index].mapping = reader.readInt(8)
+		if decoder.modes[index].mapping >= len(decoder.mappings) {
+			return errInvalidHeader
+		}
+	}
+	if !reader.readFlag() || reader.endOfPacket {
+		return errInvalidHeader
+	}
+	return nil
+}
+
+func (decoder *decoder) readMapping(reader *bitReader) (*mapping, error) {
+	if reader.readInt(16) != 0 {
+		return nil, errInvalidMapping
+	}
+	result := &mapping{}
+	submaps := 1
+	if reader.readFlag() {
+		submaps = reader.readInt(4) + 1
+	}
+	if reader.readFlag() {
+		steps := reader.readInt(8) + 1
+		result.couplingMagnitude = make([]int, steps)
+		result.couplingAngle = make([]int, steps)
+		channelBits := ilog(decoder.channels - 1)
+		for step := 0; step < steps; step++ {
+			magnitude := reader.readInt(channelBits)
+			angle := reader.readInt(channelBits)
+			if (magnitude == angle) || (magnitude >= decoder.channels) || (angle >= decoder.channels) {
+				return nil, errInvalidMapping
+			}
+			result.couplingMagnitude[step] = magnitude
+			result.couplingAngle[step] = angle
+		}
+	}
+	if reader.readInt(2) != 0 {
+		return nil, errInvalidMapping
+	}
+	result.mux = make([]int, decoder.channels)
+	if submaps > 1 {
+		for channel := range result.mux {
+			result.mux[channel] = reader.readInt(4)
+			if result.mux[channel] >= submaps {
+				return nil, errInvalidMapping
+			}
+		}
+	}
+	result.submapFloor = make([]int, submaps)
+	result.submapResidue = make([]int, submaps)
+	for submap := 0; submap < submaps; submap++ {
+		reader.read(8) // unused time configuration
+		result.submapFloor[submap] = reader.readInt(8)
+		result.submapResidue[submap] = reader.readInt(8)
+		if (result.submapFloor[submap] >= len(decoder.floors)) ||
+			(result.submapResidue[submap] >= len(decoder.residues)) {
+			return nil, errInvalidMapping
+		}
+	}
+	return result, nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/inkyblackness/hacked/ss1"
 	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/audio/dsp"
 )
 
 const (
@@ -13,6 +14,8 @@ const (
 )
 
 // Load reads from the provided source and returns the data.
+// Mono files with 8 or 16 bits per sample are taken as they are. Any other supported format
+// is mixed down to mono and reduced to 8 bits.
 func Load(source io.Reader) (data audio.L8, err error) {
 	if source == nil {
 		return data, errSourceIsNil
@@ -25,8 +28,49 @@ func Load(source io.Reader) (data audio.L8, err error) {
 		return data, loader.err
 	}
 
+	if !loader.isEngineCompatible() {
+		return loader.signal().ToL8(false), nil
+	}
+
 	data.SampleRate = loader.sampleRate
-	data.Samples = loader.samples
+	data.Samples = loader.dataConverter(loader.samples)
 
 	return
 }
+
+// LoadSignal reads from the provided source and returns the data in full precision.
+// Supported are integer samples with 8, 16, 24, or 32 bits, as well as floating point samples with 32 or 64 bits,
+// also in WAVE_FORMAT_EXTENSIBLE files.
+// Files with several channels are mixed down to mono.
+func LoadSignal(source io.Reader) (dsp.Signal, error) {
+	if source == nil {
+		return dsp.Signal{}, errSourceIsNil
+	}
+
+	var loader waveLoader
+
+	loader.load(source)
+	if loader.err != nil {
+		return dsp.Signal{}, loader.err
+	}
+	return loader.signal(), nil
+}
+
+func (loader *waveLoader) signal() dsp.Signal {
+	bytesPerSample := loader.bitsPerSample / 8
+	frameSize := bytesPerSample * loader.channels
+	frameCount := len(loader.samples) / frameSize
+	signal := dsp.Signal{
+		SampleRate: float64(loader.sampleRate),
+		Samples:    make([]float64, frameCount),
+	}
+	for frame := 0; frame < frameCount; frame++ {
+		sum := 0.0
+		for channel := 0; channel < loader.channels; channel++ {
+			offset := frame*frameSize + channel*bytesPerSample
+			sum += loader.sampleConverter(loader.samples[offset : offset+bytesPerSample])
+		}
+		signal.Samples[frame] = sum / float64(loader.channels)
+	}
+	return signal
+}
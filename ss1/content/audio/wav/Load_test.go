@@ -63,3 +63,150 @@ func TestLoadExtractsDataOfL16(t *testing.T) {
 	assert.Equal(t, float32(22050), data.SampleRate)
 	assert.Equal(t, []byte{0x80, 0xC0, 0xFF, 0x40, 0x7F}, data.Samples)
 }
+
+func TestLoadSignalMixesDownStereoL16(t *testing.T) {
+	input := []byte{
+		0x52, 0x49, 0x46, 0x46, // "RIFF"
+		0x2C, 0x00, 0x00, 0x00, // len(RIFF)
+		0x57, 0x41, 0x56, 0x45, // "WAVE"
+		0x66, 0x6d, 0x74, 0x20, // "fmt "
+		0x10, 0x00, 0x00, 0x00, // len(fmt)
+		0x01, 0x00, // fmt:type
+		0x02, 0x00, // fmt:channels
+		0x44, 0xAC, 0x00, 0x00, // fmt:samples/sec
+		0x10, 0xB1, 0x02, 0x00, // fmt:avgBytes/sec
+		0x04, 0x00, // fmt:blockAlign
+		0x10, 0x00, // fmt:bits/sample
+		0x64, 0x61, 0x74, 0x61, // "data"
+		0x08, 0x00, 0x00, 0x00, // len(data)
+		0x00, 0x40, 0x00, 0x20, 0x00, 0xC0, 0x00, 0xC0} // data
+
+	signal, err := wav.LoadSignal(bytes.NewReader(input))
+
+	require.Nil(t, err)
+	assert.Equal(t, 44100.0, signal.SampleRate)
+	assert.InDeltaSlice(t, []float64{0.375, -0.5}, signal.Samples, 1e-9)
+}
+
+func TestLoadMixesDownStereo(t *testing.T) {
+	input := []byte{
+		0x52, 0x49, 0x46, 0x46, // "RIFF"
+		0x28, 0x00, 0x00, 0x00, // len(RIFF)
+		0x57, 0x41, 0x56, 0x45, // "WAVE"
+		0x66, 0x6d, 0x74, 0x20, // "fmt "
+		0x10, 0x00, 0x00, 0x00, // len(fmt)
+		0x01, 0x00, // fmt:type
+		0x02, 0x00, // fmt:channels
+		0x22, 0x56, 0x00, 0x00, // fmt:samples/sec
+		0x44, 0xAC, 0x00, 0x00, // fmt:avgBytes/sec
+		0x02, 0x00, // fmt:blockAlign
+		0x08, 0x00, // fmt:bits/sample
+		0x64, 0x61, 0x74, 0x61, // "data"
+		0x04, 0x00, 0x00, 0x00, // len(data)
+		0x00, 0x40, 0x80, 0xC0} // data
+
+	data, err := wav.Load(bytes.NewReader(input))
+
+	require.Nil(t, err)
+	assert.Equal(t, float32(22050), data.SampleRate)
+	assert.Equal(t, []byte{0x20, 0xA0}, data.Samples)
+}
+
+func TestLoadSignalExtractsDataOfL24(t *testing.T) {
+	input := []byte{
+		0x52, 0x49, 0x46, 0x46, // "RIFF"
+		0x2A, 0x00, 0x00, 0x00, // len(RIFF)
+		0x57, 0x41, 0x56, 0x45, // "WAVE"
+		0x66, 0x6d, 0x74, 0x20, // "fmt "
+		0x10, 0x00, 0x00, 0x00, // len(fmt)
+		0x01, 0x00, // fmt:type
+		0x01, 0x00, // fmt:channels
+		0x44, 0xAC, 0x00, 0x00, // fmt:samples/sec
+		0xCC, 0x04, 0x02, 0x00, // fmt:avgBytes/sec
+		0x03, 0x00, // fmt:blockAlign
+		0x18, 0x00, // fmt:bits/sample
+		0x64, 0x61, 0x74, 0x61, // "data"
+		0x06, 0x00, 0x00, 0x00, // len(data)
+		0x00, 0x00, 0x40, 0x00, 0x00, 0xE0} // data
+
+	signal, err := wav.LoadSignal(bytes.NewReader(input))
+
+	require.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{0.5, -0.25}, signal.Samples, 1e-9)
+}
+
+func TestLoadSignalExtractsDataOfExtensibleFloat(t *testing.T) {
+	input := []byte{
+		0x52, 0x49, 0x46, 0x46, // "RIFF"
+		0x48, 0x00, 0x00, 0x00, // len(RIFF)
+		0x57, 0x41, 0x56, 0x45, // "WAVE"
+		0x66, 0x6d, 0x74, 0x20, // "fmt "
+		0x28, 0x00, 0x00, 0x00, // len(fmt)
+		0xFE, 0xFF, // fmt:type
+		0x01, 0x00, // fmt:channels
+		0x44, 0xAC, 0x00, 0x00, // fmt:samples/sec
+		0x10, 0xB1, 0x02, 0x00, // fmt:avgBytes/sec
+		0x04, 0x00, // fmt:blockAlign
+		0x20, 0x00, // fmt:bits/sample
+		0x16, 0x00, // fmt:extensionSize
+		0x20, 0x00, // fmt:validBits/sample
+		0x04, 0x00, 0x00, 0x00, // fmt:channelMask
+		0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, // fmt:subFormat
+		0x80, 0x00, 0x00, 0xAA, 0x00, 0x38, 0x9B, 0x71,
+		0x64, 0x61, 0x74, 0x61, // "data"
+		0x08, 0x00, 0x00, 0x00, // len(data)
+		0x00, 0x00, 0x00, 0x3F, 0x00, 0x00, 0x80, 0xBE} // data
+
+	signal, err := wav.LoadSignal(bytes.NewReader(input))
+
+	require.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{0.5, -0.25}, signal.Samples, 1e-9)
+}
+
+func TestLoadSkipsUnknownChunks(t *testing.T) {
+	input := []byte{
+		0x52, 0x49, 0x46, 0x46, // "RIFF"
+		0x34, 0x00, 0x00, 0x00, // len(RIFF)
+		0x57, 0x41, 0x56, 0x45, // "WAVE"
+		0x4C, 0x49, 0x53, 0x54, // "LIST"
+		0x03, 0x00, 0x00, 0x00, // len(LIST)
+		0x01, 0x02, 0x03, 0x00, // LIST data, padded
+		0x66, 0x6d, 0x74, 0x20, // "fmt "
+		0x10, 0x00, 0x00, 0x00, // len(fmt)
+		0x01, 0x00, // fmt:type
+		0x01, 0x00, // fmt:channels
+		0x22, 0x56, 0x00, 0x00, // fmt:samples/sec
+		0x22, 0x56, 0x00, 0x00, // fmt:avgBytes/sec
+		0x01, 0x00, // fmt:blockAlign
+		0x08, 0x00, // fmt:bits/sample
+		0x64, 0x61, 0x74, 0x61, // "data"
+		0x02, 0x00, 0x00, 0x00, // len(data)
+		0x10, 0x20} // data
+
+	data, err := wav.Load(bytes.NewReader(input))
+
+	require.Nil(t, err)
+	assert.Equal(t, []byte{0x10, 0x20}, data.Samples)
+}
+
+func TestLoadRejectsCompressedFormats(t *testing.T) {
+	input := []byte{
+		0x52, 0x49, 0x46, 0x46, // "RIFF"
+		0x28, 0x00, 0x00, 0x00, // len(RIFF)
+		0x57, 0x41, 0x56, 0x45, // "WAVE"
+		0x66, 0x6d, 0x74, 0x20, // "fmt "
+		0x10, 0x00, 0x00, 0x00, // len(fmt)
+		0x02, 0x00, // fmt:type
+		0x01, 0x00, // fmt:channels
+		0x22, 0x56, 0x00, 0x00, // fmt:samples/sec
+		0x22, 0x56, 0x00, 0x00, // fmt:avgBytes/sec
+		0x01, 0x00, // fmt:blockAlign
+		0x04, 0x00, // fmt:bits/sample
+		0x64, 0x61, 0x74, 0x61, // "data"
+		0x04, 0x00, 0x00, 0x00, // len(data)
+		0x00, 0x40, 0x80, 0xC0} // data
+
+	_, err := wav.Load(bytes.NewReader(input))
+
+	assert.NotNil(t, err)
+}
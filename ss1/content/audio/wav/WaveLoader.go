@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/binary"
 	"io"
+	"io/ioutil"
+	"math"
 
 	"github.com/inkyblackness/hacked/ss1"
 )
@@ -27,16 +29,45 @@ func l8FromL16(input []byte) []byte {
 	return output
 }
 
+func sampleFromU8(input []byte) float64 {
+	return (float64(input[0]) - 128.0) / 128.0
+}
+
+func sampleFromS16(input []byte) float64 {
+	return float64(int16(binary.LittleEndian.Uint16(input))) / 32768.0
+}
+
+func sampleFromS24(input []byte) float64 {
+	value := int32(uint32(input[0])<<8|uint32(input[1])<<16|uint32(input[2])<<24) >> 8
+	return float64(value) / 8388608.0
+}
+
+func sampleFromS32(input []byte) float64 {
+	return float64(int32(binary.LittleEndian.Uint32(input))) / 2147483648.0
+}
+
+func sampleFromF32(input []byte) float64 {
+	return float64(math.Float32frombits(binary.LittleEndian.Uint32(input)))
+}
+
+func sampleFromF64(input []byte) float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(input))
+}
+
 type waveLoader struct {
-	dataRead   bool
-	formatRead bool
-	samples    []byte
-	sampleRate float32
+	dataRead      bool
+	formatRead    bool
+	samples       []byte
+	sampleRate    float32
+	channels      int
+	bitsPerSample int
+	formatType    waveFormatType
 
 	reader io.Reader
 	err    error
 
-	dataConverter func([]byte) []byte
+	dataConverter   func([]byte) []byte
+	sampleConverter func([]byte) float64
 }
 
 func (loader *waveLoader) load(reader io.Reader) {
@@ -54,10 +85,14 @@ func (loader *waveLoader) read(data interface{}) bool {
 
 func (loader *waveLoader) readBytes(size uint32) (data []byte) {
 	data = make([]byte, int(size))
-	_, loader.err = loader.reader.Read(data)
+	_, loader.err = io.ReadFull(loader.reader, data)
 	return
 }
 
+func (loader *waveLoader) skipBytes(size uint32) {
+	_, loader.err = io.CopyN(ioutil.Discard, loader.reader, int64(size))
+}
+
 func (loader *waveLoader) loadChunk(handler func(riffChunkType, uint32)) {
 	var tag riffChunkTag
 
@@ -87,7 +122,6 @@ func (loader *waveLoader) loadWave() {
 	for !loader.isDone() {
 		loader.loadFormatOrData()
 	}
-	loader.samples = loader.dataConverter(loader.samples)
 }
 
 func (loader *waveLoader) loadFormatOrData() {
@@ -99,35 +133,87 @@ func (loader *waveLoader) handleFormatOrData(chunkType riffChunkType, size uint3
 		loader.loadFormat(size)
 	} else if chunkType == riffChunkTypeData {
 		loader.loadData(size)
+	} else {
+		loader.skipBytes(size)
+	}
+	if (size%2 != 0) && !loader.isDone() {
+		// chunks are padded to an even size
+		loader.skipBytes(1)
 	}
 }
 
 func (loader *waveLoader) loadFormat(size uint32) {
 	headerData := loader.readBytes(size)
+	if loader.err != nil {
+		return
+	}
 	headerReader := bytes.NewReader(headerData)
 	var header formatHeader
+	var extensible waveFormatExtensible
 
 	loader.formatRead = true
 	loader.err = binary.Read(headerReader, binary.LittleEndian, &header.base)
+	if loader.err != nil {
+		return
+	}
 	loader.err = binary.Read(headerReader, binary.LittleEndian, &header.extension.BitsPerSample)
+	if loader.err != nil {
+		return
+	}
 	loader.sampleRate = float32(header.base.SamplesPerSec)
+	loader.channels = int(header.base.Channels)
+	loader.bitsPerSample = int(header.extension.BitsPerSample)
+	loader.formatType = header.base.FormatType
+
+	if loader.formatType == waveFormatTypeExtensible {
+		_ = binary.Read(headerReader, binary.LittleEndian, &header.extension.ExtensionSize)
+		if binary.Read(headerReader, binary.LittleEndian, &extensible) != nil {
+			loader.err = errUnsupportedFormat
+			return
+		}
+		loader.formatType = waveFormatType(binary.LittleEndian.Uint16(extensible.SubFormat[0:2]))
+	}
 
-	if header.extension.BitsPerSample == 16 {
+	loader.sampleConverter = loader.sampleConverterFor(loader.formatType, loader.bitsPerSample)
+	if (loader.sampleConverter == nil) || (loader.channels < 1) {
+		loader.err = errUnsupportedFormat
+	}
+	if (loader.formatType == waveFormatTypePcm) && (loader.bitsPerSample == 16) {
 		loader.dataConverter = l8FromL16
 	}
+}
 
-	if (header.base.FormatType != waveFormatTypePcm) ||
-		(header.base.Channels != 1) ||
-		((header.extension.BitsPerSample != 8) && (header.extension.BitsPerSample != 16)) {
-		loader.err = errUnsupportedFormat
+func (loader *waveLoader) sampleConverterFor(formatType waveFormatType, bitsPerSample int) func([]byte) float64 {
+	switch {
+	case (formatType == waveFormatTypePcm) && (bitsPerSample == 8):
+		return sampleFromU8
+	case (formatType == waveFormatTypePcm) && (bitsPerSample == 16):
+		return sampleFromS16
+	case (formatType == waveFormatTypePcm) && (bitsPerSample == 24):
+		return sampleFromS24
+	case (formatType == waveFormatTypePcm) && (bitsPerSample == 32):
+		return sampleFromS32
+	case (formatType == waveFormatTypeIeeeFloat) && (bitsPerSample == 32):
+		return sampleFromF32
+	case (formatType == waveFormatTypeIeeeFloat) && (bitsPerSample == 64):
+		return sampleFromF64
+	default:
+		return nil
 	}
 }
 
 func (loader *waveLoader) loadData(size uint32) {
 	loader.dataRead = true
-	loader.samples = loader.readBytes(size)
+	// Some writers don't update the size after recording. Take what is there.
+	loader.samples, loader.err = ioutil.ReadAll(io.LimitReader(loader.reader, int64(size)))
 }
 
 func (loader *waveLoader) isDone() bool {
 	return (loader.err != nil) || (loader.dataRead && loader.formatRead)
 }
+
+// isEngineCompatible returns true if the samples can be taken without mixing or conversion to floating point.
+func (loader *waveLoader) isEngineCompatible() bool {
+	return (loader.channels == 1) && (loader.formatType == waveFormatTypePcm) &&
+		((loader.bitsPerSample == 8) || (loader.bitsPerSample == 16))
+}
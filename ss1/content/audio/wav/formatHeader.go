@@ -7,7 +7,9 @@ import (
 type waveFormatType uint16
 
 const (
-	waveFormatTypePcm = 1
+	waveFormatTypePcm        = 1
+	waveFormatTypeIeeeFloat  = 3
+	waveFormatTypeExtensible = 0xFFFE
 )
 
 type waveFormat struct {
@@ -23,6 +25,14 @@ type waveFormatExtension struct {
 	ExtensionSize uint16
 }
 
+// waveFormatExtensible is the extension of WAVE_FORMAT_EXTENSIBLE headers.
+// The first two bytes of the sub-format GUID hold the actual format type.
+type waveFormatExtensible struct {
+	ValidBitsPerSample uint16
+	ChannelMask        uint32
+	SubFormat          [16]byte
+}
+
 type formatHeader struct {
 	base      waveFormat
 	extension waveFormatExtension
@@ -0,0 +1,72 @@
+package xmi
+
+import (
+	"math"
+
+	"github.com/inkyblackness/hacked/ss1"
+	"github.com/inkyblackness/hacked/ss1/content/audio/midi"
+)
+
+const errInvalidDivision ss1.StringError = "invalid division"
+
+// midiDivision is the amount of ticks per quarter note when converting to standard MIDI.
+// With the default tempo of two quarter notes per second, this matches the XMI timing.
+const midiDivision = TicksPerSecond / 2
+
+// ToMIDI converts the sequence to a single-track standard MIDI file.
+// Tempo events in the sequence are dropped, as XMI timing is fixed and they do not apply.
+func (seq Sequence) ToMIDI() midi.File {
+	track := midi.Track{midi.TempoEvent(0, midi.DefaultTempo)}
+	for _, event := range seq.Events {
+		if event.IsMeta(midi.MetaTempo) {
+			continue
+		}
+		track = append(track, event)
+	}
+	return midi.File{
+		Format:   0,
+		Division: midiDivision,
+		Tracks:   []midi.Track{track},
+	}
+}
+
+// SequenceFromMIDI converts a standard MIDI file to a sequence.
+// All tracks are merged, and their timing is converted to the fixed XMI timing, considering all tempo changes.
+// The timbre list is created from the program changes.
+func SequenceFromMIDI(file midi.File) (Sequence, error) {
+	var seq Sequence
+	if file.Division == 0 {
+		return seq, errInvalidDivision
+	}
+	var merged midi.Track
+	for _, track := range file.Tracks {
+		merged = append(merged, track...)
+	}
+	sortEvents(merged)
+
+	var lastTick uint32
+	var elapsed float64 // in microseconds times division
+	tempo := uint32(midi.DefaultTempo)
+	programs := make(map[byte]bool)
+	for _, event := range merged {
+		elapsed += float64(event.Time-lastTick) * float64(tempo)
+		lastTick = event.Time
+		if newTempo, isTempo := event.Tempo(); isTempo {
+			tempo = newTempo
+			continue
+		}
+		if event.IsMeta(midi.MetaEndOfTrack) || event.IsMeta(midi.MetaTempo) {
+			continue
+		}
+		if (event.Kind() == midi.StatusProgramChange) && (len(event.Data) > 0) && !programs[event.Data[0]] {
+			programs[event.Data[0]] = true
+			seq.Timbres = append(seq.Timbres, event.Data[0], 0x00)
+		}
+		event.Time = uint32(math.Round(elapsed * TicksPerSecond / (float64(file.Division) * 1000000)))
+		seq.Events = append(seq.Events, event)
+	}
+	if seq.Timbres != nil {
+		seq.Timbres = append([]byte{byte(len(programs)), byte(len(programs) >> 8)}, seq.Timbres...)
+	}
+	return seq, nil
+}
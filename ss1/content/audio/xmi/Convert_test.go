@@ -0,0 +1,85 @@
+package xmi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio/midi"
+	"github.com/inkyblackness/hacked/ss1/content/audio/xmi"
+)
+
+func TestToMIDIKeepsTiming(t *testing.T) {
+	seq := xmi.Sequence{
+		Events: midi.Track{
+			{Time: 0, Status: 0xC0, Data: []byte{0x05}},
+			midi.TempoEvent(0, 250000),
+			{Time: 120, Status: 0x90, Data: []byte{0x3C, 0x40}},
+			{Time: 240, Status: 0x80, Data: []byte{0x3C, 0x40}},
+		},
+	}
+	file := seq.ToMIDI()
+	require.Equal(t, 1, len(file.Tracks))
+	assert.Equal(t, midi.Track{
+		midi.TempoEvent(0, midi.DefaultTempo),
+		{Time: 0, Status: 0xC0, Data: []byte{0x05}},
+		{Time: 120, Status: 0x90, Data: []byte{0x3C, 0x40}},
+		{Time: 240, Status: 0x80, Data: []byte{0x3C, 0x40}},
+	}, file.Tracks[0])
+	assert.Equal(t, uint16(60), file.Division)
+}
+
+func TestSequenceFromMIDIConsidersTempoChanges(t *testing.T) {
+	file := midi.File{
+		Format:   1,
+		Division: 100,
+		Tracks: []midi.Track{
+			{
+				midi.TempoEvent(0, 1000000),
+				midi.TempoEvent(200, 500000),
+			},
+			{
+				{Time: 0, Status: 0xC1, Data: []byte{0x10}},
+				{Time: 100, Status: 0x91, Data: []byte{0x3C, 0x40}},
+				{Time: 300, Status: 0x81, Data: []byte{0x3C, 0x40}},
+			},
+		},
+	}
+	seq, err := xmi.SequenceFromMIDI(file)
+	require.Nil(t, err)
+	assert.Equal(t, midi.Track{
+		{Time: 0, Status: 0xC1, Data: []byte{0x10}},
+		{Time: 120, Status: 0x91, Data: []byte{0x3C, 0x40}},
+		{Time: 300, Status: 0x81, Data: []byte{0x3C, 0x40}},
+	}, seq.Events)
+	assert.Equal(t, []byte{0x01, 0x00, 0x10, 0x00}, seq.Timbres)
+}
+
+func TestSequenceFromMIDIFailsForInvalidDivision(t *testing.T) {
+	_, err := xmi.SequenceFromMIDI(midi.File{})
+	assert.Error(t, err)
+}
+
+func TestSequenceInfo(t *testing.T) {
+	seq := xmi.Sequence{
+		Events: midi.Track{
+			{Time: 0, Status: 0xC3, Data: []byte{0x20}},
+			{Time: 0, Status: 0xC1, Data: []byte{0x10}},
+			{Time: 0, Status: 0xB1, Data: []byte{116, 0x00}},
+			{Time: 60, Status: 0x91, Data: []byte{0x3C, 0x40}},
+			{Time: 60, Status: 0x93, Data: []byte{0x3E, 0x40}},
+			{Time: 360, Status: 0x81, Data: []byte{0x3C, 0x40}},
+			{Time: 360, Status: 0x83, Data: []byte{0x3E, 0x40}},
+		},
+	}
+	info := seq.Info()
+	assert.Equal(t, xmi.SequenceInfo{
+		Duration: 3 * time.Second,
+		Channels: []int{1, 3},
+		Programs: []int{0x10, 0x20},
+		Notes:    2,
+		Loops:    1,
+	}, info)
+}
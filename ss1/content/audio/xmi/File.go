@@ -0,0 +1,24 @@
+package xmi
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/audio/midi"
+)
+
+// TicksPerSecond is the fixed timing resolution of all XMI sequences.
+const TicksPerSecond = 120
+
+// Sequence is one musical piece of an XMI file.
+type Sequence struct {
+	// Timbres is the raw content of the optional timbre list. It is nil if not present.
+	Timbres []byte
+	// Branches is the raw content of the optional branch list. It is nil if not present.
+	Branches []byte
+	// Events are the MIDI events of the sequence, timed at TicksPerSecond.
+	// Notes are described with explicit note-off events, even though XMI stores them as note-on with duration.
+	Events midi.Track
+}
+
+// File is a collection of sequences.
+type File struct {
+	Sequences []Sequence
+}
@@ -0,0 +1,63 @@
+package xmi
+
+import (
+	"sort"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio/midi"
+)
+
+// controllerForLoop is the controller that marks the start of a loop in XMI sequences.
+const controllerForLoop = 116
+
+// SequenceInfo summarizes the content of a sequence.
+type SequenceInfo struct {
+	// Duration is the time until the last event.
+	Duration time.Duration
+	// Channels lists the used channels, in ascending order.
+	Channels []int
+	// Programs lists the selected programs (instruments), in ascending order.
+	Programs []int
+	// Notes is the count of played notes.
+	Notes int
+	// Loops is the count of loops started in the sequence.
+	Loops int
+}
+
+// Info returns the summary of the sequence.
+func (seq Sequence) Info() SequenceInfo {
+	var info SequenceInfo
+	channels := make(map[int]bool)
+	programs := make(map[int]bool)
+	var end uint32
+	for _, event := range seq.Events {
+		if event.Time > end {
+			end = event.Time
+		}
+		if !event.IsChannelEvent() {
+			continue
+		}
+		channels[event.Channel()] = true
+		switch {
+		case event.IsNoteOn():
+			info.Notes++
+		case event.Kind() == midi.StatusProgramChange:
+			programs[int(event.Data[0])] = true
+		case (event.Kind() == midi.StatusControlChange) && (event.Data[0] == controllerForLoop):
+			info.Loops++
+		}
+	}
+	info.Duration = time.Duration(end) * time.Second / TicksPerSecond
+	info.Channels = sortedKeys(channels)
+	info.Programs = sortedKeys(programs)
+	return info
+}
+
+func sortedKeys(set map[int]bool) []int {
+	keys := make([]int, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -0,0 +1,181 @@
+package xmi
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/inkyblackness/hacked/ss1"
+	"github.com/inkyblackness/hacked/ss1/content/audio/midi"
+)
+
+const (
+	errNotAnXmiFile       ss1.StringError = "not an XMI file"
+	errUnexpectedEnd      ss1.StringError = "unexpected end of data"
+	errUnsupportedMessage ss1.StringError = "unsupported message"
+)
+
+type chunk struct {
+	tag  string
+	data []byte
+}
+
+func readChunk(data []byte) (chunk, []byte, bool) {
+	if len(data) < 8 {
+		return chunk{}, nil, false
+	}
+	length := int(binary.BigEndian.Uint32(data[4:8]))
+	if length > len(data)-8 {
+		return chunk{}, nil, false
+	}
+	result := chunk{tag: string(data[0:4]), data: data[8 : 8+length]}
+	rest := data[8+length:]
+	if (length%2) != 0 && (len(rest) > 0) {
+		rest = rest[1:]
+	}
+	return result, rest, true
+}
+
+func readChunks(data []byte) ([]chunk, bool) {
+	var chunks []chunk
+	for len(data) > 0 {
+		next, rest, ok := readChunk(data)
+		if !ok {
+			return nil, false
+		}
+		chunks = append(chunks, next)
+		data = rest
+	}
+	return chunks, true
+}
+
+// IsXMI returns true if the given data starts like an XMI file.
+func IsXMI(data []byte) bool {
+	return (len(data) >= 12) && (string(data[0:4]) == "FORM") &&
+		((string(data[8:12]) == "XDIR") || (string(data[8:12]) == "XMID"))
+}
+
+// Load decodes the given XMI data.
+// Both the common form with a directory and a plain single sequence form are supported.
+func Load(data []byte) (File, error) {
+	var file File
+	if !IsXMI(data) {
+		return file, errNotAnXmiFile
+	}
+	topChunks, ok := readChunks(data)
+	if !ok {
+		return file, errUnexpectedEnd
+	}
+	for _, top := range topChunks {
+		if len(top.data) < 4 {
+			continue
+		}
+		formType := string(top.data[0:4])
+		switch {
+		case (top.tag == "FORM") && (formType == "XMID"):
+			seq, err := loadSequence(top.data[4:])
+			if err != nil {
+				return file, err
+			}
+			file.Sequences = append(file.Sequences, seq)
+		case (top.tag == "CAT ") && (formType == "XMID"):
+			forms, ok := readChunks(top.data[4:])
+			if !ok {
+				return file, errUnexpectedEnd
+			}
+			for _, form := range forms {
+				if (form.tag != "FORM") || (len(form.data) < 4) || (string(form.data[0:4]) != "XMID") {
+					continue
+				}
+				seq, err := loadSequence(form.data[4:])
+				if err != nil {
+					return file, err
+				}
+				file.Sequences = append(file.Sequences, seq)
+			}
+		}
+	}
+	return file, nil
+}
+
+func loadSequence(data []byte) (Sequence, error) {
+	var seq Sequence
+	chunks, ok := readChunks(data)
+	if !ok {
+		return seq, errUnexpectedEnd
+	}
+	for _, sub := range chunks {
+		switch sub.tag {
+		case "TIMB":
+			seq.Timbres = append([]byte{}, sub.data...)
+		case "RBRN":
+			seq.Branches = append([]byte{}, sub.data...)
+		case "EVNT":
+			events, err := decodeEvents(sub.data)
+			if err != nil {
+				return seq, err
+			}
+			seq.Events = events
+		}
+	}
+	return seq, nil
+}
+
+func decodeEvents(data []byte) (midi.Track, error) {
+	var events midi.Track
+	source := midi.ByteSource(data)
+	var time uint32
+	for !source.Empty() {
+		status, _ := source.Byte()
+		if status < 0x80 {
+			time += uint32(status)
+			continue
+		}
+		event := midi.Event{Time: time, Status: status}
+		var err error
+		switch {
+		case (status & 0xF0) == midi.StatusNoteOn:
+			event.Data, err = source.Bytes(2)
+			if err != nil {
+				return nil, err
+			}
+			var duration uint32
+			duration, err = source.VariableLength()
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+			event = midi.Event{
+				Time:   time + duration,
+				Status: midi.StatusNoteOff | (status & 0x0F),
+				Data:   []byte{event.Data[0], 0x40},
+			}
+		case status < midi.StatusSysEx:
+			event.Data, err = source.Bytes(midi.ChannelDataLength(status))
+		case (status == midi.StatusSysEx) || (status == midi.StatusSysExEscape):
+			event.Data, err = source.LengthPrefixed()
+		case status == midi.StatusMeta:
+			event.MetaType, err = source.Byte()
+			if err == nil {
+				event.Data, err = source.LengthPrefixed()
+			}
+		default:
+			err = errUnsupportedMessage
+		}
+		if err != nil {
+			return nil, err
+		}
+		if event.IsMeta(midi.MetaEndOfTrack) {
+			break
+		}
+		events = append(events, event)
+	}
+	sortEvents(events)
+	return events, nil
+}
+
+// sortEvents orders the events by time. As the generated note-off events are placed right after their note-on,
+// events of the same time keep their order. This way, notes ending and starting at the same time do not
+// cut each other off.
+func sortEvents(events midi.Track) {
+	sort.SliceStable(events, func(a, b int) bool { return events[a].Time < events[b].Time })
+}
@@ -0,0 +1,75 @@
+package xmi_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio/midi"
+	"github.com/inkyblackness/hacked/ss1/content/audio/xmi"
+)
+
+func singleSequenceData() []byte {
+	return []byte{
+		'F', 'O', 'R', 'M', 0x00, 0x00, 0x00, 0x24, 'X', 'M', 'I', 'D',
+		'T', 'I', 'M', 'B', 0x00, 0x00, 0x00, 0x04, 0x01, 0x00, 0x05, 0x00,
+		'E', 'V', 'N', 'T', 0x00, 0x00, 0x00, 0x0C,
+		0xC0, 0x05,
+		0x7F, 0x01, // delay of 128
+		0x90, 0x3C, 0x40, 0x81, 0x00, // note with duration of 128
+		0xFF, 0x2F, 0x00,
+	}
+}
+
+func TestIsXMI(t *testing.T) {
+	assert.True(t, xmi.IsXMI(singleSequenceData()))
+	assert.False(t, xmi.IsXMI([]byte("MThd")))
+}
+
+func TestLoadSingleSequence(t *testing.T) {
+	file, err := xmi.Load(singleSequenceData())
+	require.Nil(t, err)
+	require.Equal(t, 1, len(file.Sequences))
+	seq := file.Sequences[0]
+	assert.Equal(t, []byte{0x01, 0x00, 0x05, 0x00}, seq.Timbres)
+	assert.Nil(t, seq.Branches)
+	assert.Equal(t, midi.Track{
+		{Time: 0, Status: 0xC0, Data: []byte{0x05}},
+		{Time: 128, Status: 0x90, Data: []byte{0x3C, 0x40}},
+		{Time: 256, Status: 0x80, Data: []byte{0x3C, 0x40}},
+	}, seq.Events)
+}
+
+func TestLoadFailsForOtherData(t *testing.T) {
+	_, err := xmi.Load([]byte("RIFF0000WAVE"))
+	assert.Error(t, err)
+}
+
+func TestSaveAndLoadRoundTrip(t *testing.T) {
+	original := xmi.File{
+		Sequences: []xmi.Sequence{
+			{
+				Timbres: []byte{0x01, 0x00, 0x05, 0x00},
+				Events: midi.Track{
+					{Time: 0, Status: 0xC0, Data: []byte{0x05}},
+					{Time: 10, Status: 0x90, Data: []byte{0x3C, 0x40}},
+					{Time: 10, Status: 0x91, Data: []byte{0x3E, 0x40}},
+					{Time: 300, Status: 0x80, Data: []byte{0x3C, 0x40}},
+					{Time: 300, Status: 0x90, Data: []byte{0x3C, 0x50}},
+					{Time: 400, Status: 0x81, Data: []byte{0x3E, 0x40}},
+					{Time: 400, Status: 0x80, Data: []byte{0x3C, 0x40}},
+				},
+			},
+			{
+				Branches: []byte{0x00, 0x00},
+				Events: midi.Track{
+					{Time: 1000, Status: 0xB2, Data: []byte{0x07, 0x64}},
+				},
+			},
+		},
+	}
+	loaded, err := xmi.Load(xmi.Save(original))
+	require.Nil(t, err)
+	assert.Equal(t, original, loaded)
+}
@@ -0,0 +1,118 @@
+package xmi
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio/midi"
+)
+
+// Save encodes the given file in the common form with a directory.
+func Save(file File) []byte {
+	var catData bytes.Buffer
+	catData.WriteString("XMID")
+	for _, seq := range file.Sequences {
+		var formData bytes.Buffer
+		formData.WriteString("XMID")
+		if seq.Timbres != nil {
+			writeChunk(&formData, "TIMB", seq.Timbres)
+		}
+		if seq.Branches != nil {
+			writeChunk(&formData, "RBRN", seq.Branches)
+		}
+		writeChunk(&formData, "EVNT", encodeEvents(seq.Events))
+		writeChunk(&catData, "FORM", formData.Bytes())
+	}
+
+	var buf bytes.Buffer
+	var info [2]byte
+	binary.LittleEndian.PutUint16(info[:], uint16(len(file.Sequences)))
+	var dirData bytes.Buffer
+	dirData.WriteString("XDIR")
+	writeChunk(&dirData, "INFO", info[:])
+	writeChunk(&buf, "FORM", dirData.Bytes())
+	writeChunk(&buf, "CAT ", catData.Bytes())
+	return buf.Bytes()
+}
+
+func writeChunk(buf *bytes.Buffer, tag string, data []byte) {
+	buf.WriteString(tag)
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(data)))
+	buf.Write(data)
+	if (len(data) % 2) != 0 {
+		buf.WriteByte(0x00)
+	}
+}
+
+type pendingNote struct {
+	channel byte
+	note    byte
+}
+
+func encodeEvents(events midi.Track) []byte {
+	durations := noteDurations(events)
+	var buf bytes.Buffer
+	var time uint32
+	for index, event := range events {
+		if event.IsNoteOff() || event.IsMeta(midi.MetaEndOfTrack) {
+			continue
+		}
+		for time < event.Time {
+			delay := event.Time - time
+			if delay > 0x7F {
+				delay = 0x7F
+			}
+			buf.WriteByte(byte(delay))
+			time += delay
+		}
+		buf.WriteByte(event.Status)
+		switch {
+		case event.IsNoteOn():
+			buf.Write(event.Data)
+			midi.WriteVariableLength(&buf, durations[index])
+		case event.Status < midi.StatusSysEx:
+			buf.Write(event.Data)
+		case event.Status == midi.StatusMeta:
+			buf.WriteByte(event.MetaType)
+			midi.WriteVariableLength(&buf, uint32(len(event.Data)))
+			buf.Write(event.Data)
+		default:
+			midi.WriteVariableLength(&buf, uint32(len(event.Data)))
+			buf.Write(event.Data)
+		}
+	}
+	buf.Write([]byte{midi.StatusMeta, midi.MetaEndOfTrack, 0x00})
+	return buf.Bytes()
+}
+
+// noteDurations pairs note-on events with their note-off events, in order of appearance.
+// Notes that are never stopped last until the end of the sequence.
+func noteDurations(events midi.Track) map[int]uint32 {
+	durations := make(map[int]uint32)
+	pending := make(map[pendingNote][]int)
+	var end uint32
+	for index, event := range events {
+		if event.Time > end {
+			end = event.Time
+		}
+		if !event.IsChannelEvent() || (len(event.Data) < 1) {
+			continue
+		}
+		key := pendingNote{channel: byte(event.Channel()), note: event.Data[0]}
+		switch {
+		case event.IsNoteOn():
+			pending[key] = append(pending[key], index)
+		case event.IsNoteOff():
+			if started := pending[key]; len(started) > 0 {
+				durations[started[0]] = event.Time - events[started[0]].Time
+				pending[key] = started[1:]
+			}
+		}
+	}
+	for _, started := range pending {
+		for _, index := range started {
+			durations[index] = end - events[index].Time
+		}
+	}
+	return durations
+}
@@ -0,0 +1,66 @@
+package bitmap
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/inkyblackness/hacked/ss1/serial/rle"
+)
+
+// FrameTimes returns the frame time, in milliseconds, for each frame of the animation.
+func (anim Animation) FrameTimes() []int16 {
+	var times []int16
+	for _, entry := range anim.Entries {
+		for frame := len(times); frame <= int(entry.LastFrame); frame++ {
+			times = append(times, entry.FrameTime)
+		}
+	}
+	return times
+}
+
+// AnimationEntriesFromFrameTimes returns the entries for an animation with given frame times.
+// Consecutive frames with the same frame time share one entry.
+func AnimationEntriesFromFrameTimes(times []int16) []AnimationEntry {
+	var entries []AnimationEntry
+	for index, frameTime := range times {
+		last := len(entries) - 1
+		if (last >= 0) && (entries[last].FrameTime == frameTime) {
+			entries[last].LastFrame = byte(index)
+		} else {
+			entries = append(entries, AnimationEntry{FirstFrame: byte(index), LastFrame: byte(index), FrameTime: frameTime})
+		}
+	}
+	return entries
+}
+
+// EncodeAnimationFrames serializes the given bitmaps as frames of an animation.
+// Each frame is compressed relative to its predecessor, all frames must have the same size.
+func EncodeAnimationFrames(frames []Bitmap) [][]byte {
+	highestBitShift := func(value int16) (result byte) {
+		if value != 0 {
+			for (value >> result) != 1 {
+				result++
+			}
+		}
+		return
+	}
+
+	var result [][]byte
+	var prevFrame []byte
+	for _, bmp := range frames {
+		header := bmp.Header
+		header.Type = TypeCompressed8Bit
+		header.WidthFactor = highestBitShift(header.Width)
+		header.HeightFactor = highestBitShift(header.Height)
+		header.Area = [4]int16{0, 0, header.Width, header.Height}
+		header.Stride = uint16(header.Width)
+		header.PaletteOffset = 0
+
+		buf := bytes.NewBuffer(nil)
+		_ = binary.Write(buf, binary.LittleEndian, &header)
+		_ = rle.Compress(buf, bmp.Pixels, prevFrame)
+		prevFrame = bmp.Pixels
+		result = append(result, buf.Bytes())
+	}
+	return result
+}
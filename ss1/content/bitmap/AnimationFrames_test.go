@@ -0,0 +1,53 @@
+package bitmap_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+func TestAnimationFrameTimesListsTimePerFrame(t *testing.T) {
+	anim := bitmap.Animation{Entries: []bitmap.AnimationEntry{
+		{FirstFrame: 0, LastFrame: 1, FrameTime: 100},
+		{FirstFrame: 2, LastFrame: 2, FrameTime: 50},
+		{FirstFrame: 3, LastFrame: 5, FrameTime: 200},
+	}}
+	assert.Equal(t, []int16{100, 100, 50, 200, 200, 200}, anim.FrameTimes())
+}
+
+func TestAnimationEntriesFromFrameTimesMergesEqualTimes(t *testing.T) {
+	entries := bitmap.AnimationEntriesFromFrameTimes([]int16{100, 100, 50, 200, 200, 100})
+	assert.Equal(t, []bitmap.AnimationEntry{
+		{FirstFrame: 0, LastFrame: 1, FrameTime: 100},
+		{FirstFrame: 2, LastFrame: 2, FrameTime: 50},
+		{FirstFrame: 3, LastFrame: 4, FrameTime: 200},
+		{FirstFrame: 5, LastFrame: 5, FrameTime: 100},
+	}, entries)
+}
+
+func TestEncodeAnimationFramesCompressesRelativeToPredecessor(t *testing.T) {
+	frame := func(pixels ...byte) bitmap.Bitmap {
+		var bmp bitmap.Bitmap
+		bmp.Header.Width = 2
+		bmp.Header.Height = 2
+		bmp.Pixels = pixels
+		return bmp
+	}
+	encoded := bitmap.EncodeAnimationFrames([]bitmap.Bitmap{frame(1, 2, 3, 4), frame(1, 2, 5, 4)})
+	require.Equal(t, 2, len(encoded))
+
+	first, err := bitmap.Decode(bytes.NewReader(encoded[0]))
+	require.Nil(t, err)
+	assert.Equal(t, bitmap.TypeCompressed8Bit, first.Header.Type)
+	assert.Equal(t, []byte{1, 2, 3, 4}, first.Pixels)
+
+	second, err := bitmap.DecodeReferenced(bytes.NewReader(encoded[1]), func(width, height int16) ([]byte, error) {
+		return append([]byte{}, first.Pixels...), nil
+	})
+	require.Nil(t, err)
+	assert.Equal(t, []byte{1, 2, 5, 4}, second.Pixels)
+}
@@ -4,11 +4,28 @@ import (
 	"image"
 	"image/color"
 	"math"
+	"runtime"
+	"sync"
 )
 
 // d64 is the reference white point.
 var d65 = [3]float64{0.95047, 1.00000, 1.08883}
 
+// bayerMatrix is the 8x8 threshold map for ordered dithering.
+var bayerMatrix = [8][8]float64{
+	{0, 32, 8, 40, 2, 34, 10, 42},
+	{48, 16, 56, 24, 50, 18, 58, 26},
+	{12, 44, 4, 36, 14, 46, 6, 38},
+	{60, 28, 52, 20, 62, 30, 54, 22},
+	{3, 35, 11, 43, 1, 33, 9, 41},
+	{51, 19, 59, 27, 49, 17, 57, 25},
+	{15, 47, 7, 39, 13, 45, 5, 37},
+	{63, 31, 55, 23, 61, 29, 53, 21},
+}
+
+// orderedDitherSpread is the maximum offset, per channel, that ordered dithering applies.
+const orderedDitherSpread = 1.0 / 8.0
+
 func labF(t float64) float64 {
 	if t > 6.0/29.0*6.0/29.0*6.0/29.0 {
 		return math.Cbrt(t)
@@ -20,15 +37,29 @@ func square(value float64) float64 {
 	return value * value
 }
 
+func clampUnit(value float64) float64 {
+	return math.Max(0.0, math.Min(value, 1.0))
+}
+
 type labEntry struct {
 	l float64
 	a float64
 	b float64
 }
 
-func labEntryFromColor(clr color.Color) labEntry {
+type rgbEntry [3]float64
+
+func rgbEntryFromColor(clr color.Color) rgbEntry {
 	rLinear, gLinear, bLinear, _ := clr.RGBA()
-	r, g, b := float64(rLinear)/float64(0xFFFF), float64(gLinear)/float64(0xFFFF), float64(bLinear)/float64(0xFFFF)
+	return rgbEntry{float64(rLinear) / float64(0xFFFF), float64(gLinear) / float64(0xFFFF), float64(bLinear) / float64(0xFFFF)}
+}
+
+func labEntryFromColor(clr color.Color) labEntry {
+	return labEntryFromRGB(rgbEntryFromColor(clr))
+}
+
+func labEntryFromRGB(rgb rgbEntry) labEntry {
+	r, g, b := rgb[0], rgb[1], rgb[2]
 	x := 0.4124564*r + 0.3575761*g + 0.1804375*b
 	y := 0.2126729*r + 0.7151522*g + 0.0721750*b
 	z := 0.0193339*r + 0.1191920*g + 0.9503041*b
@@ -46,23 +77,60 @@ func (entry labEntry) distanceTo(other labEntry) float64 {
 	return math.Sqrt(square(entry.l-other.l) + square(entry.a-other.a) + square(entry.b-other.b))
 }
 
+// MapOptions describe how colors of an image are mapped to palette indices.
+type MapOptions struct {
+	// Dithering specifies how quantization errors are handled.
+	Dithering DitherMode
+	// Indices is the set of palette indices opaque pixels may be mapped to.
+	// Transparent pixels are always mapped to index 0x00.
+	Indices IndexSet
+}
+
+// DefaultMapOptions returns the options of a plain nearest-color mapping,
+// which avoids the transparent and color cycling indices.
+func DefaultMapOptions() MapOptions {
+	return MapOptions{
+		Dithering: DitherNone,
+		Indices:   RegularIndices(),
+	}
+}
+
 // Bitmapper creates bitmap images from generic images.
+// A bitmapper remembers the colors it has mapped, which speeds up repeated mapping of similar images.
+// It is safe to use a bitmapper from several goroutines.
 type Bitmapper struct {
-	pal []labEntry
+	pal     []labEntry
+	rgb     []rgbEntry
+	options MapOptions
+	tree    *labTree
+	cache   *colorCache
 }
 
 // NewBitmapper returns a new bitmapper instance based on the given palette.
+// The bitmapper uses the default map options.
 func NewBitmapper(palette *Palette) *Bitmapper {
-	bitmapper := &Bitmapper{}
+	return NewBitmapperWithOptions(palette, DefaultMapOptions())
+}
+
+// NewBitmapperWithOptions returns a new bitmapper instance based on the given palette and options.
+func NewBitmapperWithOptions(palette *Palette, options MapOptions) *Bitmapper {
+	bitmapper := &Bitmapper{
+		options: options,
+		cache:   newColorCache(),
+	}
 
 	for _, clr := range palette {
-		bitmapper.pal = append(bitmapper.pal, labEntryFromColor(clr.Color(0xFF)))
+		rgb := rgbEntryFromColor(clr.Color(0xFF))
+		bitmapper.rgb = append(bitmapper.rgb, rgb)
+		bitmapper.pal = append(bitmapper.pal, labEntryFromRGB(rgb))
 	}
+	bitmapper.tree = newLabTree(bitmapper.pal, options.Indices)
 
 	return bitmapper
 }
 
 // Map maps the provided image to a bitmap based on the internal palette.
+// Rows are mapped in parallel, unless error diffusion requires them to be processed in sequence.
 func (bitmapper *Bitmapper) Map(img image.Image) Bitmap {
 	var bmp Bitmap
 	bounds := img.Bounds()
@@ -70,36 +138,139 @@ func (bitmapper *Bitmapper) Map(img image.Image) Bitmap {
 	bmp.Header.Width = int16(math.Max(0, math.Min(float64(bounds.Dx()), math.MaxInt16)))
 	bmp.Header.Height = int16(math.Max(0, math.Min(float64(bounds.Dy()), math.MaxInt16)))
 	bmp.Pixels = make([]byte, int(bmp.Header.Width)*int(bmp.Header.Height))
-	for row := 0; row < int(bmp.Header.Height); row++ {
-		for column := 0; column < int(bmp.Header.Width); column++ {
-			bmp.Pixels[row*int(bmp.Header.Width)+column] = bitmapper.MapColor(img.At(column, row))
-		}
+	switch bitmapper.options.Dithering {
+	case DitherFloydSteinberg:
+		bitmapper.mapDiffused(&bmp, img)
+	case DitherOrdered:
+		bitmapper.mapOrdered(&bmp, img)
+	default:
+		bitmapper.mapNearest(&bmp, img)
 	}
 
 	return bmp
 }
 
-// MapColor maps the provided color to the nearest index in the palette.
-func (bitmapper *Bitmapper) MapColor(clr color.Color) (palIndex byte) {
-	_, _, _, a := clr.RGBA() // nolint:dogsled
-	indexWithin := func(index, from, to int) bool {
-		return (index >= from) && (index <= to)
+// mapRows calls the given function for each row of the bitmap, distributed over all available processors.
+func mapRows(bmp *Bitmap, mapRow func(row int)) {
+	height := int(bmp.Header.Height)
+	workers := runtime.NumCPU()
+	if workers > height {
+		workers = height
 	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for worker := 0; worker < workers; worker++ {
+		go func(firstRow int) {
+			defer wg.Done()
+			for row := firstRow; row < height; row += workers {
+				mapRow(row)
+			}
+		}(worker)
+	}
+	wg.Wait()
+}
+
+func (bitmapper *Bitmapper) mapNearest(bmp *Bitmap, img image.Image) {
+	origin := img.Bounds().Min
+	width := int(bmp.Header.Width)
+	mapRows(bmp, func(row int) {
+		for column := 0; column < width; column++ {
+			bmp.Pixels[row*width+column] = bitmapper.MapColor(img.At(origin.X+column, origin.Y+row))
+		}
+	})
+}
 
-	if a > 0 {
-		clrEntry := labEntryFromColor(clr)
-		palDistance := 1000.0
-
-		for colorIndex, palEntry := range bitmapper.pal {
-			isRegularColor := indexWithin(colorIndex, 0x01, 0x02) || indexWithin(colorIndex, 0x08, 0x0A) || indexWithin(colorIndex, 0x20, 0xFF)
-			if isRegularColor {
-				distance := palEntry.distanceTo(clrEntry)
-				if distance < palDistance {
-					palDistance = distance
-					palIndex = byte(colorIndex)
+func (bitmapper *Bitmapper) mapOrdered(bmp *Bitmap, img image.Image) {
+	origin := img.Bounds().Min
+	width := int(bmp.Header.Width)
+	mapRows(bmp, func(row int) {
+		for column := 0; column < width; column++ {
+			clr := img.At(origin.X+column, origin.Y+row)
+			if isTransparent(clr) {
+				continue
+			}
+			threshold := bayerMatrix[row%8][column%8]
+			bmp.Pixels[row*width+column] = bitmapper.cachedIndex(colorKey(clr, 1+uint64(threshold)), func() labEntry {
+				offset := ((threshold+0.5)/64.0 - 0.5) * orderedDitherSpread
+				rgb := rgbEntryFromColor(clr)
+				for channel := range rgb {
+					rgb[channel] = clampUnit(rgb[channel] + offset)
 				}
+				return labEntryFromRGB(rgb)
+			})
+		}
+	})
+}
+
+func (bitmapper *Bitmapper) mapDiffused(bmp *Bitmap, img image.Image) {
+	origin := img.Bounds().Min
+	width := int(bmp.Header.Width)
+	// error rows are padded by one entry on each side to avoid bounds checks.
+	currentErrors := make([]rgbEntry, width+2)
+	nextErrors := make([]rgbEntry, width+2)
+	diffuse := func(target *rgbEntry, err rgbEntry, factor float64) {
+		for channel := range err {
+			target[channel] += err[channel] * factor
+		}
+	}
+
+	for row := 0; row < int(bmp.Header.Height); row++ {
+		for column := 0; column < width; column++ {
+			clr := img.At(origin.X+column, origin.Y+row)
+			if isTransparent(clr) {
+				continue
 			}
+			rgb := rgbEntryFromColor(clr)
+			for channel := range rgb {
+				rgb[channel] = clampUnit(rgb[channel] + currentErrors[column+1][channel])
+			}
+			palIndex := bitmapper.nearestIndex(labEntryFromRGB(rgb))
+			bmp.Pixels[row*width+column] = palIndex
+
+			var err rgbEntry
+			for channel := range err {
+				err[channel] = rgb[channel] - bitmapper.rgb[palIndex][channel]
+			}
+			diffuse(&currentErrors[column+2], err, 7.0/16.0)
+			diffuse(&nextErrors[column], err, 3.0/16.0)
+			diffuse(&nextErrors[column+1], err, 5.0/16.0)
+			diffuse(&nextErrors[column+2], err, 1.0/16.0)
+		}
+		currentErrors, nextErrors = nextErrors, currentErrors
+		for index := range nextErrors {
+			nextErrors[index] = rgbEntry{}
 		}
 	}
-	return
+}
+
+// MapColor maps the provided color to the nearest index in the palette.
+func (bitmapper *Bitmapper) MapColor(clr color.Color) (palIndex byte) {
+	if isTransparent(clr) {
+		return 0x00
+	}
+	return bitmapper.cachedIndex(colorKey(clr, 0), func() labEntry { return labEntryFromColor(clr) })
+}
+
+// colorKey identifies a color, together with a variant of how it is mapped, for the cache.
+func colorKey(clr color.Color, variant uint64) uint64 {
+	r, g, b, _ := clr.RGBA()
+	return (variant << 48) | (uint64(r) << 32) | (uint64(g) << 16) | uint64(b)
+}
+
+func (bitmapper *Bitmapper) cachedIndex(key uint64, entry func() labEntry) byte {
+	if palIndex, isCached := bitmapper.cache.get(key); isCached {
+		return palIndex
+	}
+	palIndex := bitmapper.nearestIndex(entry())
+	bitmapper.cache.put(key, palIndex)
+	return palIndex
+}
+
+func isTransparent(clr color.Color) bool {
+	_, _, _, a := clr.RGBA() // nolint:dogsled
+	return a == 0
+}
+
+func (bitmapper *Bitmapper) nearestIndex(clrEntry labEntry) byte {
+	return bitmapper.tree.nearest(clrEntry)
 }
@@ -0,0 +1,231 @@
+package bitmap_test
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+func grayPalette(dark, bright byte) *bitmap.Palette {
+	var pal bitmap.Palette
+	pal[0x20] = bitmap.RGB{Red: dark, Green: dark, Blue: dark}
+	for index := 0x21; index < bitmap.PaletteSize; index++ {
+		pal[index] = bitmap.RGB{Red: bright, Green: bright, Blue: bright}
+	}
+	return &pal
+}
+
+func blackAndWhitePalette() *bitmap.Palette {
+	return grayPalette(0x00, 0xFF)
+}
+
+func uniformImage(width, height int, clr color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, clr)
+		}
+	}
+	return img
+}
+
+func countIndex(pixels []byte, value byte) int {
+	count := 0
+	for _, pixel := range pixels {
+		if pixel == value {
+			count++
+		}
+	}
+	return count
+}
+
+func TestBitmapperMapColorReturnsZeroForTransparentColor(t *testing.T) {
+	bitmapper := bitmap.NewBitmapper(blackAndWhitePalette())
+	assert.Equal(t, byte(0x00), bitmapper.MapColor(color.RGBA{}))
+}
+
+func TestBitmapperMapColorAvoidsCyclingIndicesByDefault(t *testing.T) {
+	var pal bitmap.Palette
+	pal[0x05] = bitmap.RGB{Red: 0xFF}
+	pal[0x40] = bitmap.RGB{Red: 0xC0}
+	bitmapper := bitmap.NewBitmapper(&pal)
+	assert.Equal(t, byte(0x40), bitmapper.MapColor(color.RGBA{R: 0xFF, A: 0xFF}))
+}
+
+func TestBitmapperMapColorConsidersOnlyGivenIndices(t *testing.T) {
+	var pal bitmap.Palette
+	pal[0x30] = bitmap.RGB{Red: 0xFF}
+	pal[0x40] = bitmap.RGB{Red: 0xC0}
+	options := bitmap.DefaultMapOptions()
+	options.Indices = options.Indices.Without(bitmap.IndexRange(0x30, 0x30))
+	bitmapper := bitmap.NewBitmapperWithOptions(&pal, options)
+	assert.Equal(t, byte(0x40), bitmapper.MapColor(color.RGBA{R: 0xFF, A: 0xFF}))
+}
+
+func TestBitmapperMapWithoutDitheringUsesSingleColorForUniformImage(t *testing.T) {
+	bitmapper := bitmap.NewBitmapper(blackAndWhitePalette())
+	bmp := bitmapper.Map(uniformImage(16, 16, color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xFF}))
+	assert.Equal(t, 16*16, countIndex(bmp.Pixels, bmp.Pixels[0]))
+}
+
+func TestBitmapperMapWithDitheringMixesColorsForUniformImage(t *testing.T) {
+	palettes := map[bitmap.DitherMode]*bitmap.Palette{
+		bitmap.DitherFloydSteinberg: blackAndWhitePalette(),
+		bitmap.DitherOrdered:        grayPalette(0x70, 0x90),
+	}
+	for mode, pal := range palettes {
+		t.Run(mode.String(), func(t *testing.T) {
+			options := bitmap.DefaultMapOptions()
+			options.Dithering = mode
+			options.Indices = bitmap.IndexRange(0x20, 0x21)
+			bitmapper := bitmap.NewBitmapperWithOptions(pal, options)
+			bmp := bitmapper.Map(uniformImage(16, 16, color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xFF}))
+			blackCount := countIndex(bmp.Pixels, 0x20)
+			whiteCount := countIndex(bmp.Pixels, 0x21)
+			assert.Equal(t, 16*16, blackCount+whiteCount, "only restricted indices expected")
+			assert.InDelta(t, 16*16/2, blackCount, 16*16/8, "roughly half should be black")
+		})
+	}
+}
+
+func TestBitmapperMapKeepsTransparentPixelsWithDithering(t *testing.T) {
+	options := bitmap.DefaultMapOptions()
+	options.Dithering = bitmap.DitherFloydSteinberg
+	bitmapper := bitmap.NewBitmapperWithOptions(blackAndWhitePalette(), options)
+	bmp := bitmapper.Map(uniformImage(4, 4, color.RGBA{}))
+	assert.Equal(t, make([]byte, 16), bmp.Pixels)
+}
+
+func TestRegularIndicesExcludeTransparentAndCyclingIndices(t *testing.T) {
+	regular := bitmap.RegularIndices()
+	assert.False(t, regular.Contains(0x00))
+	assert.True(t, regular.Contains(0x01))
+	assert.False(t, regular.Contains(0x05))
+	assert.True(t, regular.Contains(0x09))
+	assert.False(t, regular.Contains(0x1F))
+	assert.True(t, regular.Contains(0x20))
+	assert.Equal(t, 2+3+0xE0, regular.Count())
+}
+
+// referenceMapper is the plain linear search that the bitmapper must match exactly.
+type referenceMapper struct {
+	pal     [][3]float64
+	indices bitmap.IndexSet
+}
+
+func newReferenceMapper(pal *bitmap.Palette, indices bitmap.IndexSet) *referenceMapper {
+	mapper := &referenceMapper{indices: indices}
+	for _, clr := range pal {
+		mapper.pal = append(mapper.pal, referenceLab(clr.Color(0xFF)))
+	}
+	return mapper
+}
+
+func referenceLabF(t float64) float64 {
+	if t > 6.0/29.0*6.0/29.0*6.0/29.0 {
+		return math.Cbrt(t)
+	}
+	return t/3.0*29.0/6.0*29.0/6.0 + 4.0/29.0
+}
+
+func referenceLab(clr color.Color) [3]float64 {
+	rLinear, gLinear, bLinear, _ := clr.RGBA()
+	r, g, b := float64(rLinear)/float64(0xFFFF), float64(gLinear)/float64(0xFFFF), float64(bLinear)/float64(0xFFFF)
+	x := 0.4124564*r + 0.3575761*g + 0.1804375*b
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*b
+	z := 0.0193339*r + 0.1191920*g + 0.9503041*b
+	fy := referenceLabF(y / 1.00000)
+	return [3]float64{1.16*fy - 0.16, 5.0 * (referenceLabF(x/0.95047) - fy), 2.0 * (fy - referenceLabF(z/1.08883))}
+}
+
+func (mapper *referenceMapper) mapColor(clr color.Color) (palIndex byte) {
+	_, _, _, a := clr.RGBA() // nolint:dogsled
+	if a > 0 {
+		lab := referenceLab(clr)
+		palDistance := 1000.0
+		for colorIndex, palEntry := range mapper.pal {
+			if mapper.indices.Contains(byte(colorIndex)) {
+				distance := math.Sqrt((palEntry[0]-lab[0])*(palEntry[0]-lab[0]) +
+					(palEntry[1]-lab[1])*(palEntry[1]-lab[1]) + (palEntry[2]-lab[2])*(palEntry[2]-lab[2]))
+				if distance < palDistance {
+					palDistance = distance
+					palIndex = byte(colorIndex)
+				}
+			}
+		}
+	}
+	return
+}
+
+func (mapper *referenceMapper) mapImage(img image.Image) []byte {
+	bounds := img.Bounds()
+	pixels := make([]byte, 0, bounds.Dx()*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pixels = append(pixels, mapper.mapColor(img.At(x, y)))
+		}
+	}
+	return pixels
+}
+
+func randomPalette(random *rand.Rand) *bitmap.Palette {
+	var pal bitmap.Palette
+	for index := range pal {
+		pal[index] = bitmap.RGB{Red: byte(random.Intn(256)), Green: byte(random.Intn(256)), Blue: byte(random.Intn(256))}
+	}
+	// duplicates verify that the lowest index of equally distant entries is taken.
+	pal[0x80] = pal[0x40]
+	pal[0x41] = pal[0x90]
+	return &pal
+}
+
+func randomImage(random *rand.Rand, width, height int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for index := range img.Pix {
+		img.Pix[index] = byte(random.Intn(256))
+	}
+	for y := 0; y < height; y += 7 {
+		img.Set(0, y, color.NRGBA{})
+	}
+	return img
+}
+
+func TestBitmapperMapIsIdenticalToLinearSearch(t *testing.T) {
+	random := rand.New(rand.NewSource(0x1234)) // nolint:gosec
+	indexSets := map[string]bitmap.IndexSet{
+		"regular": bitmap.RegularIndices(),
+		"all":     bitmap.AllIndices(),
+		"range":   bitmap.IndexRange(0x40, 0x4F),
+		"single":  bitmap.IndexRange(0xC0, 0xC0),
+	}
+	for name, indices := range indexSets {
+		t.Run(name, func(t *testing.T) {
+			pal := randomPalette(random)
+			img := randomImage(random, 64, 48)
+			options := bitmap.DefaultMapOptions()
+			options.Indices = indices
+			bitmapper := bitmap.NewBitmapperWithOptions(pal, options)
+			reference := newReferenceMapper(pal, indices)
+
+			expected := reference.mapImage(img)
+			assert.Equal(t, expected, bitmapper.Map(img).Pixels, "first mapping differs")
+			assert.Equal(t, expected, bitmapper.Map(img).Pixels, "cached mapping differs")
+		})
+	}
+}
+
+func TestBitmapperMapOfPalettedColorsReturnsIdenticalIndices(t *testing.T) {
+	random := rand.New(rand.NewSource(0x5678)) // nolint:gosec
+	pal := randomPalette(random)
+	bitmapper := bitmap.NewBitmapperWithOptions(pal, bitmap.MapOptions{Indices: bitmap.AllIndices()})
+	reference := newReferenceMapper(pal, bitmap.AllIndices())
+	for index, clr := range pal {
+		assert.Equal(t, reference.mapColor(clr.Color(0xFF)), bitmapper.MapColor(clr.Color(0xFF)), "index %d", index)
+	}
+}
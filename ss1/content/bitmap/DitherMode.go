@@ -0,0 +1,35 @@
+package bitmap
+
+import "fmt"
+
+// DitherMode describes how quantization errors are handled when mapping colors to a palette.
+type DitherMode byte
+
+// String returns the textual representation of the value.
+func (mode DitherMode) String() string {
+	if int(mode) >= len(ditherModeNames) {
+		return fmt.Sprintf("Unknown%02X", int(mode))
+	}
+	return ditherModeNames[mode]
+}
+
+// DitherMode constants.
+const (
+	// DitherNone maps each pixel to its closest color without any dithering.
+	DitherNone DitherMode = 0
+	// DitherFloydSteinberg diffuses the quantization error of a pixel to its neighbours.
+	DitherFloydSteinberg DitherMode = 1
+	// DitherOrdered applies an ordered Bayer threshold matrix before mapping.
+	DitherOrdered DitherMode = 2
+)
+
+var ditherModeNames = []string{
+	"None",
+	"Floyd-Steinberg",
+	"Ordered (Bayer)",
+}
+
+// DitherModes returns all known constants.
+func DitherModes() []DitherMode {
+	return []DitherMode{DitherNone, DitherFloydSteinberg, DitherOrdered}
+}
@@ -0,0 +1,95 @@
+package bitmap
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+)
+
+// ComposeGIFFrames returns the frames of given GIF as full images of the size of the GIF.
+// Frames covering only parts of the image are drawn over their predecessor, respecting the disposal methods.
+// If all frames share the same palette, the returned images are of type *image.Paletted with that palette.
+func ComposeGIFFrames(data *gif.GIF) []image.Image {
+	if len(data.Image) == 0 {
+		return nil
+	}
+	rect := image.Rect(0, 0, data.Config.Width, data.Config.Height)
+	palette := data.Image[0].Palette
+	for _, img := range data.Image {
+		if !samePalette(palette, img.Palette) {
+			palette = nil
+		}
+	}
+	var canvas draw.Image
+	if palette != nil {
+		canvas = image.NewPaletted(rect, palette)
+	} else {
+		canvas = image.NewNRGBA(rect)
+	}
+
+	frames := make([]image.Image, 0, len(data.Image))
+	for index, img := range data.Image {
+		var disposal byte
+		if index < len(data.Disposal) {
+			disposal = data.Disposal[index]
+		}
+		var previous draw.Image
+		if disposal == gif.DisposalPrevious {
+			previous = cloneCanvas(canvas)
+		}
+		drawGIFFrame(canvas, img)
+		frames = append(frames, cloneCanvas(canvas))
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, img.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+	return frames
+}
+
+func samePalette(a, b color.Palette) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for index := range a {
+		if a[index] != b[index] {
+			return false
+		}
+	}
+	return true
+}
+
+func drawGIFFrame(canvas draw.Image, img *image.Paletted) {
+	bounds := img.Bounds().Intersect(canvas.Bounds())
+	paletted, isPaletted := canvas.(*image.Paletted)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			palIndex := img.ColorIndexAt(x, y)
+			if int(palIndex) >= len(img.Palette) || isTransparent(img.Palette[palIndex]) {
+				continue
+			}
+			if isPaletted {
+				paletted.SetColorIndex(x, y, palIndex)
+			} else {
+				canvas.Set(x, y, img.Palette[palIndex])
+			}
+		}
+	}
+}
+
+func cloneCanvas(canvas draw.Image) draw.Image {
+	switch img := canvas.(type) {
+	case *image.Paletted:
+		clone := image.NewPaletted(img.Rect, img.Palette)
+		copy(clone.Pix, img.Pix)
+		return clone
+	default:
+		clone := image.NewNRGBA(canvas.Bounds())
+		draw.Draw(clone, clone.Rect, canvas, clone.Rect.Min, draw.Src)
+		return clone
+	}
+}
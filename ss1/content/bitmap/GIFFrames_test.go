@@ -0,0 +1,58 @@
+package bitmap_test
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+func gifFrame(palette color.Palette, rect image.Rectangle, pixels ...byte) *image.Paletted {
+	img := image.NewPaletted(rect, palette)
+	copy(img.Pix, pixels)
+	return img
+}
+
+func TestComposeGIFFramesDrawsPartialFramesOverPredecessor(t *testing.T) {
+	palette := color.Palette{color.RGBA{}, color.RGBA{R: 0xFF, A: 0xFF}, color.RGBA{G: 0xFF, A: 0xFF}, color.RGBA{B: 0xFF, A: 0xFF}}
+	data := &gif.GIF{
+		Image: []*image.Paletted{
+			gifFrame(palette, image.Rect(0, 0, 3, 1), 1, 1, 1),
+			gifFrame(palette, image.Rect(1, 0, 3, 1), 0, 2),
+			gifFrame(palette, image.Rect(0, 0, 1, 1), 3),
+			gifFrame(palette, image.Rect(0, 0, 1, 1), 0),
+		},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalBackground, gif.DisposalPrevious, gif.DisposalNone},
+		Config:   image.Config{Width: 3, Height: 1},
+	}
+	frames := bitmap.ComposeGIFFrames(data)
+	require.Equal(t, 4, len(frames))
+	pixels := func(img image.Image) []byte {
+		paletted, isPaletted := img.(*image.Paletted)
+		require.True(t, isPaletted)
+		return paletted.Pix
+	}
+	assert.Equal(t, []byte{1, 1, 1}, pixels(frames[0]))
+	assert.Equal(t, []byte{1, 1, 2}, pixels(frames[1]))
+	assert.Equal(t, []byte{3, 0, 0}, pixels(frames[2]))
+	assert.Equal(t, []byte{1, 0, 0}, pixels(frames[3]))
+}
+
+func TestComposeGIFFramesReturnsTrueColorFramesForLocalPalettes(t *testing.T) {
+	data := &gif.GIF{
+		Image: []*image.Paletted{
+			gifFrame(color.Palette{color.RGBA{R: 0xFF, A: 0xFF}}, image.Rect(0, 0, 2, 1), 0, 0),
+			gifFrame(color.Palette{color.RGBA{B: 0xFF, A: 0xFF}}, image.Rect(1, 0, 2, 1), 0),
+		},
+		Config: image.Config{Width: 2, Height: 1},
+	}
+	frames := bitmap.ComposeGIFFrames(data)
+	require.Equal(t, 2, len(frames))
+	assert.Equal(t, color.NRGBA{R: 0xFF, A: 0xFF}, frames[1].At(0, 0))
+	assert.Equal(t, color.NRGBA{B: 0xFF, A: 0xFF}, frames[1].At(1, 0))
+}
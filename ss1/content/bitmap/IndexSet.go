@@ -0,0 +1,69 @@
+package bitmap
+
+// IndexSet is a selection of palette indices.
+type IndexSet [PaletteSize]bool
+
+// AllIndices returns a set containing every palette index.
+func AllIndices() IndexSet {
+	return IndexRange(0x00, 0xFF)
+}
+
+// IndexRange returns a set containing the indices from first to last, inclusive.
+func IndexRange(first, last byte) IndexSet {
+	var set IndexSet
+	for index := int(first); index <= int(last); index++ {
+		set[index] = true
+	}
+	return set
+}
+
+// TransparentIndices returns the set of indices that are rendered transparent.
+func TransparentIndices() IndexSet {
+	return IndexRange(0x00, 0x00)
+}
+
+// CyclingIndices returns the set of indices that are subject to color cycling in the game.
+func CyclingIndices() IndexSet {
+	return IndexRange(0x03, 0x07).With(IndexRange(0x0B, 0x1F))
+}
+
+// RegularIndices returns the set of indices that are neither transparent nor cycling.
+func RegularIndices() IndexSet {
+	return AllIndices().Without(TransparentIndices()).Without(CyclingIndices())
+}
+
+// Contains returns true if the given index is part of the set.
+func (set IndexSet) Contains(index byte) bool {
+	return set[index]
+}
+
+// Count returns the number of indices in the set.
+func (set IndexSet) Count() int {
+	count := 0
+	for _, contained := range set {
+		if contained {
+			count++
+		}
+	}
+	return count
+}
+
+// With returns a set that contains the indices of both this and the other set.
+func (set IndexSet) With(other IndexSet) IndexSet {
+	for index, contained := range other {
+		if contained {
+			set[index] = true
+		}
+	}
+	return set
+}
+
+// Without returns a set that has all the indices of the other set removed.
+func (set IndexSet) Without(other IndexSet) IndexSet {
+	for index, contained := range other {
+		if contained {
+			set[index] = false
+		}
+	}
+	return set
+}
@@ -0,0 +1,254 @@
+package bitmap
+
+import (
+	"image"
+	"sort"
+)
+
+const (
+	histogramBits       = 5
+	histogramSize       = 1 << histogramBits
+	histogramShift      = 8 - histogramBits
+	maxSamplesPerImage  = 64 * 1024
+	paletteRefinePasses = 4
+)
+
+// histogramEntry is a quantized color, together with how often it occurred.
+type histogramEntry struct {
+	color [3]int
+	sum   [3]float64
+	count float64
+}
+
+// colorBox is a set of histogram entries, as used by the median cut algorithm.
+type colorBox struct {
+	entries []*histogramEntry
+}
+
+// GeneratePalette creates a palette that represents the colors of the given images.
+// Only the entries of the given index set are determined; all other entries are taken from the base palette.
+// Colors are first determined with a median cut, and then refined with a few k-means passes.
+// Transparent pixels are ignored. Entries that are not needed keep their base value.
+func GeneratePalette(images []image.Image, indices IndexSet, base Palette) Palette {
+	result := base
+	targetIndices := make([]int, 0, PaletteSize)
+	for index := 0; index < PaletteSize; index++ {
+		if indices.Contains(byte(index)) {
+			targetIndices = append(targetIndices, index)
+		}
+	}
+	entries := colorHistogram(images)
+	if (len(targetIndices) == 0) || (len(entries) == 0) {
+		return result
+	}
+
+	boxes := medianCut(entries, len(targetIndices))
+	centers := make([][3]float64, len(boxes))
+	for index, box := range boxes {
+		centers[index] = box.average()
+	}
+	centers = refineCenters(entries, centers)
+	sort.Slice(centers, func(a, b int) bool {
+		return luminance(centers[a]) < luminance(centers[b])
+	})
+	for index, center := range centers {
+		result[targetIndices[index]] = RGB{
+			Red:   roundedByte(center[0]),
+			Green: roundedByte(center[1]),
+			Blue:  roundedByte(center[2]),
+		}
+	}
+	return result
+}
+
+func colorHistogram(images []image.Image) []*histogramEntry {
+	buckets := make(map[int]*histogramEntry)
+	for _, img := range images {
+		bounds := img.Bounds()
+		pixelCount := bounds.Dx() * bounds.Dy()
+		step := 1
+		if pixelCount > maxSamplesPerImage {
+			step = pixelCount / maxSamplesPerImage
+		}
+		for offset := 0; offset < pixelCount; offset += step {
+			x := bounds.Min.X + offset%bounds.Dx()
+			y := bounds.Min.Y + offset/bounds.Dx()
+			r, g, b, a := img.At(x, y).RGBA()
+			if a == 0 {
+				continue
+			}
+			rgb := [3]int{int(r >> 8), int(g >> 8), int(b >> 8)}
+			key := ((rgb[0] >> histogramShift) << (2 * histogramBits)) |
+				((rgb[1] >> histogramShift) << histogramBits) |
+				(rgb[2] >> histogramShift)
+			entry, existing := buckets[key]
+			if !existing {
+				entry = &histogramEntry{color: [3]int{
+					rgb[0] >> histogramShift, rgb[1] >> histogramShift, rgb[2] >> histogramShift,
+				}}
+				buckets[key] = entry
+			}
+			for channel := range rgb {
+				entry.sum[channel] += float64(rgb[channel])
+			}
+			entry.count++
+		}
+	}
+	keys := make([]int, 0, len(buckets))
+	for key := range buckets {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	entries := make([]*histogramEntry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, buckets[key])
+	}
+	return entries
+}
+
+func medianCut(entries []*histogramEntry, limit int) []colorBox {
+	boxes := []colorBox{{entries: entries}}
+	for len(boxes) < limit {
+		splitIndex := -1
+		splitScore := 0.0
+		for index, box := range boxes {
+			if len(box.entries) < 2 {
+				continue
+			}
+			_, extent := box.widestChannel()
+			score := box.count() * float64(extent)
+			if score > splitScore {
+				splitIndex = index
+				splitScore = score
+			}
+		}
+		if splitIndex < 0 {
+			break
+		}
+		first, second := boxes[splitIndex].split()
+		boxes[splitIndex] = first
+		boxes = append(boxes, second)
+	}
+	return boxes
+}
+
+func (box colorBox) count() float64 {
+	sum := 0.0
+	for _, entry := range box.entries {
+		sum += entry.count
+	}
+	return sum
+}
+
+func (box colorBox) widestChannel() (channel int, extent int) {
+	for candidate := 0; candidate < 3; candidate++ {
+		low, high := histogramSize, -1
+		for _, entry := range box.entries {
+			if entry.color[candidate] < low {
+				low = entry.color[candidate]
+			}
+			if entry.color[candidate] > high {
+				high = entry.color[candidate]
+			}
+		}
+		if (high - low) > extent {
+			channel = candidate
+			extent = high - low
+		}
+	}
+	return
+}
+
+func (box colorBox) split() (colorBox, colorBox) {
+	channel, _ := box.widestChannel()
+	sorted := make([]*histogramEntry, len(box.entries))
+	copy(sorted, box.entries)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return sorted[a].color[channel] < sorted[b].color[channel]
+	})
+	half := box.count() / 2
+	sum := 0.0
+	cut := 1
+	for index, entry := range sorted[:len(sorted)-1] {
+		sum += entry.count
+		cut = index + 1
+		if sum >= half {
+			break
+		}
+	}
+	return colorBox{entries: sorted[:cut]}, colorBox{entries: sorted[cut:]}
+}
+
+func (box colorBox) average() [3]float64 {
+	var sum [3]float64
+	count := 0.0
+	for _, entry := range box.entries {
+		for channel := range sum {
+			sum[channel] += entry.sum[channel]
+		}
+		count += entry.count
+	}
+	for channel := range sum {
+		sum[channel] /= count
+	}
+	return sum
+}
+
+// refineCenters runs k-means passes over the histogram, using the given centers as starting point.
+func refineCenters(entries []*histogramEntry, centers [][3]float64) [][3]float64 {
+	for pass := 0; pass < paletteRefinePasses; pass++ {
+		sums := make([][3]float64, len(centers))
+		counts := make([]float64, len(centers))
+		for _, entry := range entries {
+			var mean [3]float64
+			for channel := range mean {
+				mean[channel] = entry.sum[channel] / entry.count
+			}
+			nearest := nearestCenter(centers, mean)
+			for channel := range mean {
+				sums[nearest][channel] += entry.sum[channel]
+			}
+			counts[nearest] += entry.count
+		}
+		for index := range centers {
+			if counts[index] == 0 {
+				continue
+			}
+			for channel := range sums[index] {
+				centers[index][channel] = sums[index][channel] / counts[index]
+			}
+		}
+	}
+	return centers
+}
+
+func nearestCenter(centers [][3]float64, value [3]float64) int {
+	nearest := 0
+	nearestDistance := -1.0
+	for index, center := range centers {
+		distance := 0.0
+		for channel := range center {
+			delta := center[channel] - value[channel]
+			distance += delta * delta
+		}
+		if (nearestDistance < 0) || (distance < nearestDistance) {
+			nearest = index
+			nearestDistance = distance
+		}
+	}
+	return nearest
+}
+
+func luminance(rgb [3]float64) float64 {
+	return 0.299*rgb[0] + 0.587*rgb[1] + 0.114*rgb[2]
+}
+
+func roundedByte(value float64) byte {
+	if value <= 0 {
+		return 0
+	}
+	if value >= 255 {
+		return 255
+	}
+	return byte(value + 0.5)
+}
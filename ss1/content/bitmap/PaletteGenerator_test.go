@@ -0,0 +1,56 @@
+package bitmap_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+func TestGeneratePaletteKeepsEntriesOutsideOfIndexSet(t *testing.T) {
+	var base bitmap.Palette
+	base[0x00] = bitmap.RGB{Red: 1, Green: 2, Blue: 3}
+	base[0xFF] = bitmap.RGB{Red: 0x9A, Green: 0x35, Blue: 0x35}
+	img := uniformImage(4, 4, color.RGBA{R: 0x10, G: 0x80, B: 0xF0, A: 0xFF})
+
+	pal := bitmap.GeneratePalette([]image.Image{img}, bitmap.IndexRange(0x01, 0xFE), base)
+
+	assert.Equal(t, base[0x00], pal[0x00])
+	assert.Equal(t, base[0xFF], pal[0xFF])
+}
+
+func TestGeneratePaletteFindsDistinctColors(t *testing.T) {
+	colors := []color.RGBA{
+		{R: 0xFF, G: 0x00, B: 0x00, A: 0xFF},
+		{R: 0x00, G: 0xFF, B: 0x00, A: 0xFF},
+		{R: 0x00, G: 0x00, B: 0xFF, A: 0xFF},
+		{R: 0x80, G: 0x80, B: 0x80, A: 0xFF},
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 4, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, colors[x])
+		}
+	}
+
+	pal := bitmap.GeneratePalette([]image.Image{img}, bitmap.IndexRange(0x10, 0x13), bitmap.Palette{})
+
+	for _, clr := range colors {
+		expected := bitmap.RGB{Red: clr.R, Green: clr.G, Blue: clr.B}
+		assert.Contains(t, pal[0x10:0x14], expected)
+	}
+}
+
+func TestGeneratePaletteIgnoresTransparentPixels(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0x00})
+	img.Set(1, 0, color.NRGBA{R: 0x20, G: 0x40, B: 0x60, A: 0xFF})
+
+	pal := bitmap.GeneratePalette([]image.Image{img}, bitmap.IndexRange(0x01, 0x02), bitmap.Palette{})
+
+	assert.Equal(t, bitmap.RGB{Red: 0x20, Green: 0x40, Blue: 0x60}, pal[0x01])
+	assert.Equal(t, bitmap.RGB{}, pal[0x02])
+}
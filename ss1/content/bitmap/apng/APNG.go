@@ -0,0 +1,16 @@
+package apng
+
+import (
+	"image"
+	"time"
+)
+
+// APNG represents an animated PNG.
+type APNG struct {
+	// Image is the list of full-size frames. All frames have the same bounds.
+	Image []image.Image
+	// Delay is the display time of each frame.
+	Delay []time.Duration
+	// LoopCount is the number of times the animation is played. Zero means infinite looping.
+	LoopCount int
+}
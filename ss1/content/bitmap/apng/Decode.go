@@ -0,0 +1,236 @@
+package apng
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+type encodedFrame struct {
+	ctrl frameControl
+	data []byte
+}
+
+// DecodeAll reads an animated PNG from the given reader.
+// A PNG file without animation is returned as an animation with a single frame.
+// If the file is paletted, the frames are returned as paletted images. Otherwise, they are of type NRGBA.
+func DecodeAll(reader io.Reader) (*APNG, error) {
+	raw, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(raw, []byte(pngSignature)) {
+		return nil, errNotAPNG
+	}
+	source := bytes.NewReader(raw[len(pngSignature):])
+	var header, palette, transparency []byte
+	animated := false
+	loopCount := 0
+	var frames []*encodedFrame
+	var current *encodedFrame
+	for done := false; !done; {
+		next, err := readChunk(source)
+		if err != nil {
+			return nil, err
+		}
+		switch next.name {
+		case chunkIHDR:
+			header = next.data
+		case chunkPLTE:
+			palette = next.data
+		case chunkTRNS:
+			transparency = next.data
+		case chunkACTL:
+			if len(next.data) != 8 {
+				return nil, errInvalidFrame
+			}
+			animated = true
+			loopCount = int(binary.BigEndian.Uint32(next.data[4:8]))
+		case chunkFCTL:
+			ctrl, ctrlErr := decodeFrameControl(next.data)
+			if ctrlErr != nil {
+				return nil, ctrlErr
+			}
+			current = &encodedFrame{ctrl: ctrl}
+			frames = append(frames, current)
+		case chunkIDAT:
+			// IDAT data only belongs to the animation if a frame control preceded it.
+			if current != nil {
+				current.data = append(current.data, next.data...)
+			}
+		case chunkFDAT:
+			if (current == nil) || (len(next.data) < 4) {
+				return nil, errInvalidFrame
+			}
+			current.data = append(current.data, next.data[4:]...)
+		case chunkIEND:
+			done = true
+		}
+	}
+	if len(header) != 13 {
+		return nil, errNotAPNG
+	}
+
+	if !animated || (len(frames) == 0) {
+		img, err := png.Decode(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+		return &APNG{Image: []image.Image{img}, Delay: []time.Duration{0}}, nil
+	}
+	decoder := frameDecoder{header: header, palette: palette, transparency: transparency}
+	return decoder.compose(frames, loopCount)
+}
+
+type frameDecoder struct {
+	header       []byte
+	palette      []byte
+	transparency []byte
+}
+
+func (decoder frameDecoder) colorPalette() color.Palette {
+	if (decoder.header[9] != colorTypePaletted) || (len(decoder.palette) == 0) {
+		return nil
+	}
+	result := make(color.Palette, len(decoder.palette)/3)
+	for index := range result {
+		alpha := byte(0xFF)
+		if index < len(decoder.transparency) {
+			alpha = decoder.transparency[index]
+		}
+		result[index] = color.NRGBA{
+			R: decoder.palette[index*3+0],
+			G: decoder.palette[index*3+1],
+			B: decoder.palette[index*3+2],
+			A: alpha,
+		}
+	}
+	return result
+}
+
+func (decoder frameDecoder) decode(frame *encodedFrame) (image.Image, error) {
+	var buf bytes.Buffer
+	buf.WriteString(pngSignature)
+	header := append([]byte{}, decoder.header...)
+	binary.BigEndian.PutUint32(header[0:4], frame.ctrl.width)
+	binary.BigEndian.PutUint32(header[4:8], frame.ctrl.height)
+	_ = writeChunk(&buf, chunkIHDR, header)
+	if len(decoder.palette) > 0 {
+		_ = writeChunk(&buf, chunkPLTE, decoder.palette)
+	}
+	if len(decoder.transparency) > 0 {
+		_ = writeChunk(&buf, chunkTRNS, decoder.transparency)
+	}
+	_ = writeChunk(&buf, chunkIDAT, frame.data)
+	_ = writeChunk(&buf, chunkIEND, nil)
+	return png.Decode(&buf)
+}
+
+func (decoder frameDecoder) compose(frames []*encodedFrame, loopCount int) (*APNG, error) {
+	width := int(binary.BigEndian.Uint32(decoder.header[0:4]))
+	height := int(binary.BigEndian.Uint32(decoder.header[4:8]))
+	if (width > maxCanvasExtent) || (height > maxCanvasExtent) || (width*height > maxCanvasPixels) {
+		return nil, errCanvasTooLarge
+	}
+	canvasRect := image.Rect(0, 0, width, height)
+	palette := decoder.colorPalette()
+	var canvas draw.Image
+	if palette != nil {
+		canvas = image.NewPaletted(canvasRect, palette)
+	} else {
+		canvas = image.NewNRGBA(canvasRect)
+	}
+
+	anim := &APNG{LoopCount: loopCount}
+	for index, frame := range frames {
+		ctrl := frame.ctrl
+		region := image.Rect(int(ctrl.xOffset), int(ctrl.yOffset),
+			int(ctrl.xOffset)+int(ctrl.width), int(ctrl.yOffset)+int(ctrl.height))
+		if region.Empty() || !region.In(canvasRect) {
+			return nil, errInvalidFrame
+		}
+		img, err := decoder.decode(frame)
+		if err != nil {
+			return nil, err
+		}
+		var previous draw.Image
+		if ctrl.disposeOp == disposeOpPrevious {
+			previous = cloneImage(canvas)
+		}
+		blend(canvas, region, img, ctrl.blendOp == blendOpOver)
+
+		anim.Image = append(anim.Image, cloneImage(canvas))
+		anim.Delay = append(anim.Delay, ctrl.delay())
+
+		switch {
+		case (ctrl.disposeOp == disposeOpBackground) || ((ctrl.disposeOp == disposeOpPrevious) && (index == 0)):
+			clearRegion(canvas, region)
+		case ctrl.disposeOp == disposeOpPrevious:
+			canvas = previous
+		}
+	}
+	return anim, nil
+}
+
+func (ctrl frameControl) delay() time.Duration {
+	den := time.Duration(ctrl.delayDen)
+	if den == 0 {
+		den = 100
+	}
+	return time.Duration(ctrl.delayNum) * time.Second / den
+}
+
+func blend(canvas draw.Image, region image.Rectangle, img image.Image, over bool) {
+	paletted, canvasPaletted := canvas.(*image.Paletted)
+	source, sourcePaletted := img.(*image.Paletted)
+	if canvasPaletted && sourcePaletted {
+		for y := 0; y < region.Dy(); y++ {
+			for x := 0; x < region.Dx(); x++ {
+				index := source.ColorIndexAt(source.Rect.Min.X+x, source.Rect.Min.Y+y)
+				if over && (int(index) < len(source.Palette)) {
+					if _, _, _, alpha := source.Palette[index].RGBA(); alpha == 0 {
+						continue
+					}
+				}
+				paletted.SetColorIndex(region.Min.X+x, region.Min.Y+y, index)
+			}
+		}
+		return
+	}
+	op := draw.Src
+	if over {
+		op = draw.Over
+	}
+	draw.Draw(canvas, region, img, img.Bounds().Min, op)
+}
+
+func clearRegion(canvas draw.Image, region image.Rectangle) {
+	if paletted, isPaletted := canvas.(*image.Paletted); isPaletted {
+		for y := region.Min.Y; y < region.Max.Y; y++ {
+			for x := region.Min.X; x < region.Max.X; x++ {
+				paletted.SetColorIndex(x, y, 0)
+			}
+		}
+		return
+	}
+	draw.Draw(canvas, region, image.Transparent, image.Point{}, draw.Src)
+}
+
+func cloneImage(img draw.Image) draw.Image {
+	switch typed := img.(type) {
+	case *image.Paletted:
+		clone := image.NewPaletted(typed.Rect, typed.Palette)
+		copy(clone.Pix, typed.Pix)
+		return clone
+	default:
+		clone := image.NewNRGBA(img.Bounds())
+		draw.Draw(clone, clone.Rect, img, img.Bounds().Min, draw.Src)
+		return clone
+	}
+}
@@ -0,0 +1,147 @@
+package apng_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap/apng"
+)
+
+type testFile struct {
+	bytes.Buffer
+	sequence uint32
+}
+
+func newTestFile(width, height uint32, frames uint32) *testFile {
+	file := &testFile{}
+	file.WriteString("\x89PNG\r\n\x1a\n")
+	header := make([]byte, 13)
+	binary.BigEndian.PutUint32(header[0:4], width)
+	binary.BigEndian.PutUint32(header[4:8], height)
+	header[8] = 8
+	header[9] = 3
+	file.chunk("IHDR", header)
+	palette := make([]byte, 4*3)
+	for index := range palette {
+		palette[index] = byte(index * 20)
+	}
+	file.chunk("PLTE", palette)
+	file.chunk("tRNS", []byte{0x00})
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:4], frames)
+	file.chunk("acTL", actl)
+	return file
+}
+
+func (file *testFile) chunk(name string, data []byte) {
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(len(data)))
+	file.Write(length)
+	file.WriteString(name)
+	file.Write(data)
+	sum := crc32.NewIEEE()
+	sum.Write([]byte(name))
+	sum.Write(data)
+	checksum := make([]byte, 4)
+	binary.BigEndian.PutUint32(checksum, sum.Sum32())
+	file.Write(checksum)
+}
+
+func (file *testFile) frame(rect image.Rectangle, dispose, blend byte, pixels []byte, first bool) {
+	ctrl := make([]byte, 26)
+	binary.BigEndian.PutUint32(ctrl[0:4], file.sequence)
+	file.sequence++
+	binary.BigEndian.PutUint32(ctrl[4:8], uint32(rect.Dx()))
+	binary.BigEndian.PutUint32(ctrl[8:12], uint32(rect.Dy()))
+	binary.BigEndian.PutUint32(ctrl[12:16], uint32(rect.Min.X))
+	binary.BigEndian.PutUint32(ctrl[16:20], uint32(rect.Min.Y))
+	binary.BigEndian.PutUint16(ctrl[20:22], 1)
+	binary.BigEndian.PutUint16(ctrl[22:24], 10)
+	ctrl[24] = dispose
+	ctrl[25] = blend
+	file.chunk("fcTL", ctrl)
+
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	for row := 0; row < rect.Dy(); row++ {
+		writer.Write([]byte{0})
+		writer.Write(pixels[row*rect.Dx() : (row+1)*rect.Dx()])
+	}
+	writer.Close()
+	if first {
+		file.chunk("IDAT", compressed.Bytes())
+	} else {
+		data := make([]byte, 4)
+		binary.BigEndian.PutUint32(data, file.sequence)
+		file.sequence++
+		file.chunk("fdAT", append(data, compressed.Bytes()...))
+	}
+}
+
+func TestDecodeAllComposesPartialFrames(t *testing.T) {
+	file := newTestFile(3, 2, 3)
+	file.frame(image.Rect(0, 0, 3, 2), 0, 0, []byte{1, 1, 1, 1, 1, 1}, true)
+	file.frame(image.Rect(1, 0, 3, 1), 1, 1, []byte{0, 2}, false)
+	file.frame(image.Rect(0, 1, 1, 2), 0, 0, []byte{3}, false)
+	file.chunk("IEND", nil)
+
+	decoded, err := apng.DecodeAll(bytes.NewReader(file.Bytes()))
+	require.Nil(t, err)
+	require.Equal(t, 3, len(decoded.Image))
+	assert.Equal(t, []byte{1, 1, 1, 1, 1, 1}, decoded.Image[0].(*image.Paletted).Pix)
+	assert.Equal(t, []byte{1, 1, 2, 1, 1, 1}, decoded.Image[1].(*image.Paletted).Pix, "blending over keeps transparent pixels")
+	assert.Equal(t, []byte{1, 0, 0, 3, 1, 1}, decoded.Image[2].(*image.Paletted).Pix, "disposal to background clears region")
+	assert.Equal(t, int64(100), decoded.Delay[2].Milliseconds())
+}
+
+func TestDecodeAllRestoresPreviousFrameOnDisposal(t *testing.T) {
+	file := newTestFile(2, 1, 3)
+	file.frame(image.Rect(0, 0, 2, 1), 0, 0, []byte{1, 1}, true)
+	file.frame(image.Rect(0, 0, 1, 1), 2, 0, []byte{2}, false)
+	file.frame(image.Rect(1, 0, 2, 1), 0, 0, []byte{3}, false)
+	file.chunk("IEND", nil)
+
+	decoded, err := apng.DecodeAll(bytes.NewReader(file.Bytes()))
+	require.Nil(t, err)
+	require.Equal(t, 3, len(decoded.Image))
+	assert.Equal(t, []byte{2, 1}, decoded.Image[1].(*image.Paletted).Pix)
+	assert.Equal(t, []byte{1, 3}, decoded.Image[2].(*image.Paletted).Pix)
+}
+
+func TestDecodeAllReturnsErrorForFrameOutsideCanvas(t *testing.T) {
+	file := newTestFile(2, 1, 1)
+	file.frame(image.Rect(1, 0, 3, 1), 0, 0, []byte{1, 1}, true)
+	file.chunk("IEND", nil)
+
+	_, err := apng.DecodeAll(bytes.NewReader(file.Bytes()))
+	assert.Error(t, err)
+}
+
+func TestDecodeAllReturnsErrorForChunkLongerThanInput(t *testing.T) {
+	var file bytes.Buffer
+	file.WriteString("\x89PNG\r\n\x1a\n")
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], 0x7FFFFFF0)
+	file.Write(length[:])
+	file.WriteString("IHDR")
+	file.Write([]byte{0x01, 0x02, 0x03, 0x04})
+
+	_, err := apng.DecodeAll(bytes.NewReader(file.Bytes()))
+	assert.Error(t, err)
+}
+
+func TestDecodeAllReturnsErrorForTooLargeCanvas(t *testing.T) {
+	file := newTestFile(0x10000, 0x10000, 1)
+	file.frame(image.Rect(0, 0, 1, 1), 0, 0, []byte{1}, true)
+	file.chunk("IEND", nil)
+
+	_, err := apng.DecodeAll(bytes.NewReader(file.Bytes()))
+	assert.Error(t, err)
+}
@@ -0,0 +1,173 @@
+package apng
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"time"
+)
+
+// EncodeAll writes the given animation to the writer.
+// If all frames are paletted images sharing the palette of the first frame, the file is stored paletted,
+// which keeps the palette indices. Otherwise, frames are stored as 8-bit RGBA.
+func EncodeAll(writer io.Writer, anim *APNG) error {
+	if len(anim.Image) == 0 {
+		return errNoFrames
+	}
+	bounds := anim.Image[0].Bounds()
+	for _, img := range anim.Image {
+		if (img.Bounds().Dx() != bounds.Dx()) || (img.Bounds().Dy() != bounds.Dy()) {
+			return errFrameBounds
+		}
+	}
+	palette := sharedPalette(anim.Image)
+
+	if _, err := writer.Write([]byte(pngSignature)); err != nil {
+		return err
+	}
+	header := make([]byte, 13)
+	binary.BigEndian.PutUint32(header[0:4], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(header[4:8], uint32(bounds.Dy()))
+	header[8] = 8
+	header[9] = colorTypeTrueAlpha
+	if palette != nil {
+		header[9] = colorTypePaletted
+	}
+	if err := writeChunk(writer, chunkIHDR, header); err != nil {
+		return err
+	}
+	if palette != nil {
+		if err := writePalette(writer, palette); err != nil {
+			return err
+		}
+	}
+	animControl := make([]byte, 8)
+	binary.BigEndian.PutUint32(animControl[0:4], uint32(len(anim.Image)))
+	binary.BigEndian.PutUint32(animControl[4:8], uint32(anim.LoopCount))
+	if err := writeChunk(writer, chunkACTL, animControl); err != nil {
+		return err
+	}
+
+	sequence := uint32(0)
+	for index, img := range anim.Image {
+		var delay time.Duration
+		if index < len(anim.Delay) {
+			delay = anim.Delay[index]
+		}
+		ctrl := frameControl{
+			sequence:  sequence,
+			width:     uint32(bounds.Dx()),
+			height:    uint32(bounds.Dy()),
+			delayNum:  uint16(math.Min(float64(delay.Milliseconds()), math.MaxUint16)),
+			delayDen:  1000,
+			disposeOp: disposeOpNone,
+			blendOp:   blendOpSource,
+		}
+		sequence++
+		if err := writeChunk(writer, chunkFCTL, ctrl.encode()); err != nil {
+			return err
+		}
+		data, err := compressedFrame(img, palette)
+		if err != nil {
+			return err
+		}
+		if index == 0 {
+			err = writeChunk(writer, chunkIDAT, data)
+		} else {
+			frameData := make([]byte, 4, 4+len(data))
+			binary.BigEndian.PutUint32(frameData, sequence)
+			sequence++
+			err = writeChunk(writer, chunkFDAT, append(frameData, data...))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return writeChunk(writer, chunkIEND, nil)
+}
+
+func sharedPalette(images []image.Image) color.Palette {
+	first, isPaletted := images[0].(*image.Paletted)
+	if !isPaletted || (len(first.Palette) == 0) || (len(first.Palette) > 256) {
+		return nil
+	}
+	for _, img := range images[1:] {
+		other, otherPaletted := img.(*image.Paletted)
+		if !otherPaletted || !palettesEqual(first.Palette, other.Palette) {
+			return nil
+		}
+	}
+	return first.Palette
+}
+
+func palettesEqual(a, b color.Palette) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for index := range a {
+		ar, ag, ab, aa := a[index].RGBA()
+		br, bg, bb, ba := b[index].RGBA()
+		if (ar != br) || (ag != bg) || (ab != bb) || (aa != ba) {
+			return false
+		}
+	}
+	return true
+}
+
+func writePalette(writer io.Writer, palette color.Palette) error {
+	entries := make([]byte, 0, len(palette)*3)
+	alphas := make([]byte, len(palette))
+	lastTransparent := -1
+	for index, clr := range palette {
+		nrgba := color.NRGBAModel.Convert(clr).(color.NRGBA)
+		entries = append(entries, nrgba.R, nrgba.G, nrgba.B)
+		alphas[index] = nrgba.A
+		if nrgba.A != 0xFF {
+			lastTransparent = index
+		}
+	}
+	if err := writeChunk(writer, chunkPLTE, entries); err != nil {
+		return err
+	}
+	if lastTransparent >= 0 {
+		return writeChunk(writer, chunkTRNS, alphas[:lastTransparent+1])
+	}
+	return nil
+}
+
+func compressedFrame(img image.Image, palette color.Palette) ([]byte, error) {
+	bounds := img.Bounds()
+	bytesPerPixel := 4
+	if palette != nil {
+		bytesPerPixel = 1
+	}
+	var buf bytes.Buffer
+	compressor := zlib.NewWriter(&buf)
+	row := make([]byte, 1+bounds.Dx()*bytesPerPixel)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		if palette != nil {
+			paletted := img.(*image.Paletted)
+			copy(row[1:], paletted.Pix[paletted.PixOffset(bounds.Min.X, y):])
+		} else {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				nrgba := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+				offset := 1 + (x-bounds.Min.X)*4
+				row[offset+0] = nrgba.R
+				row[offset+1] = nrgba.G
+				row[offset+2] = nrgba.B
+				row[offset+3] = nrgba.A
+			}
+		}
+		if _, err := compressor.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := compressor.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
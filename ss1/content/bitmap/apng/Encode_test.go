@@ -0,0 +1,111 @@
+package apng_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap/apng"
+)
+
+func testPalette() color.Palette {
+	palette := make(color.Palette, 256)
+	for index := range palette {
+		palette[index] = color.RGBA{R: byte(index), G: byte(255 - index), B: byte(index / 2), A: 0xFF}
+	}
+	palette[0] = color.RGBA{}
+	return palette
+}
+
+func palettedFrame(palette color.Palette, width, height int, seed byte) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+	for index := range img.Pix {
+		img.Pix[index] = seed + byte(index)
+	}
+	return img
+}
+
+func TestEncodeAllReturnsErrorWithoutFrames(t *testing.T) {
+	err := apng.EncodeAll(bytes.NewBuffer(nil), &apng.APNG{})
+	assert.Error(t, err)
+}
+
+func TestEncodeAllReturnsErrorForDifferentFrameSizes(t *testing.T) {
+	palette := testPalette()
+	anim := &apng.APNG{Image: []image.Image{palettedFrame(palette, 2, 2, 0), palettedFrame(palette, 3, 2, 0)}}
+	err := apng.EncodeAll(bytes.NewBuffer(nil), anim)
+	assert.Error(t, err)
+}
+
+func TestPalettedAnimationRoundTripKeepsIndicesAndTiming(t *testing.T) {
+	palette := testPalette()
+	anim := &apng.APNG{
+		Image:     []image.Image{palettedFrame(palette, 4, 3, 0), palettedFrame(palette, 4, 3, 20), palettedFrame(palette, 4, 3, 40)},
+		Delay:     []time.Duration{100 * time.Millisecond, 250 * time.Millisecond, 1500 * time.Millisecond},
+		LoopCount: 3,
+	}
+	buf := bytes.NewBuffer(nil)
+	require.Nil(t, apng.EncodeAll(buf, anim))
+
+	decoded, err := apng.DecodeAll(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+	require.Equal(t, 3, len(decoded.Image))
+	assert.Equal(t, anim.Delay, decoded.Delay)
+	assert.Equal(t, 3, decoded.LoopCount)
+	for index, img := range decoded.Image {
+		paletted, isPaletted := img.(*image.Paletted)
+		require.True(t, isPaletted, "paletted frame expected")
+		assert.Equal(t, anim.Image[index].(*image.Paletted).Pix, paletted.Pix, "indices of frame %d differ", index)
+	}
+}
+
+func TestTrueColorAnimationRoundTripKeepsColors(t *testing.T) {
+	first := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	first.SetNRGBA(0, 0, color.NRGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xFF})
+	first.SetNRGBA(1, 0, color.NRGBA{R: 0x40, G: 0x50, B: 0x60, A: 0x80})
+	second := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	second.Set(0, 0, color.RGBA{R: 0xFF, A: 0xFF})
+	anim := &apng.APNG{Image: []image.Image{first, second}, Delay: []time.Duration{time.Second, time.Second}}
+	buf := bytes.NewBuffer(nil)
+	require.Nil(t, apng.EncodeAll(buf, anim))
+
+	decoded, err := apng.DecodeAll(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+	require.Equal(t, 2, len(decoded.Image))
+	assert.Equal(t, color.NRGBA{R: 0x40, G: 0x50, B: 0x60, A: 0x80}, color.NRGBAModel.Convert(decoded.Image[0].At(1, 0)))
+	assert.Equal(t, color.NRGBA{R: 0xFF, A: 0xFF}, color.NRGBAModel.Convert(decoded.Image[1].At(0, 0)))
+	assert.Equal(t, color.NRGBA{}, color.NRGBAModel.Convert(decoded.Image[1].At(1, 0)))
+}
+
+func TestEncodedAnimationIsReadableAsRegularPNG(t *testing.T) {
+	palette := testPalette()
+	frame := palettedFrame(palette, 4, 3, 7)
+	buf := bytes.NewBuffer(nil)
+	require.Nil(t, apng.EncodeAll(buf, &apng.APNG{Image: []image.Image{frame, palettedFrame(palette, 4, 3, 0)}}))
+
+	img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+	assert.Equal(t, frame.Pix, img.(*image.Paletted).Pix)
+}
+
+func TestDecodeAllReturnsSingleFrameForRegularPNG(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	frame := palettedFrame(testPalette(), 5, 5, 1)
+	require.Nil(t, png.Encode(buf, frame))
+
+	decoded, err := apng.DecodeAll(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+	require.Equal(t, 1, len(decoded.Image))
+	assert.Equal(t, frame.Pix, decoded.Image[0].(*image.Paletted).Pix)
+}
+
+func TestDecodeAllReturnsErrorForOtherData(t *testing.T) {
+	_, err := apng.DecodeAll(bytes.NewReader([]byte("GIF89a")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,151 @@
+package apng
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+
+	"github.com/inkyblackness/hacked/ss1"
+)
+
+const (
+	pngSignature = "\x89PNG\r\n\x1a\n"
+
+	chunkIHDR = "IHDR"
+	chunkPLTE = "PLTE"
+	chunkTRNS = "tRNS"
+	chunkIDAT = "IDAT"
+	chunkIEND = "IEND"
+	chunkACTL = "acTL"
+	chunkFCTL = "fcTL"
+	chunkFDAT = "fdAT"
+
+	colorTypePaletted  = 3
+	colorTypeTrueAlpha = 6
+
+	disposeOpNone       = 0
+	disposeOpBackground = 1
+	disposeOpPrevious   = 2
+
+	blendOpSource = 0
+	blendOpOver   = 1
+
+	// maxChunkSize limits the size of chunks accepted for decoding.
+	maxChunkSize = 0x7FFFFFFF
+	// maxCanvasExtent limits width and height of the canvas. Bitmaps store their size as signed 16-bit values.
+	maxCanvasExtent = 0x7FFF
+	// maxCanvasPixels limits the area of the canvas, which is allocated for composing the frames.
+	maxCanvasPixels = 4096 * 4096
+
+	errNotAPNG          ss1.StringError = "not a PNG file"
+	errChunkTooLarge    ss1.StringError = "chunk too large"
+	errChunkTruncated   ss1.StringError = "chunk exceeds available data"
+	errChecksumMismatch ss1.StringError = "chunk checksum mismatch"
+	errInvalidFrame     ss1.StringError = "invalid frame control"
+	errNoFrames         ss1.StringError = "no frames"
+	errCanvasTooLarge   ss1.StringError = "canvas too large"
+	errFrameBounds      ss1.StringError = "frame bounds differ"
+)
+
+type chunk struct {
+	name string
+	data []byte
+}
+
+func writeChunk(writer io.Writer, name string, data []byte) error {
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[0:4], uint32(len(data)))
+	copy(header[4:8], name)
+	checksum := crc32.NewIEEE()
+	_, _ = checksum.Write(header[4:8])
+	_, _ = checksum.Write(data)
+	var footer [4]byte
+	binary.BigEndian.PutUint32(footer[:], checksum.Sum32())
+
+	if _, err := writer.Write(header[:]); err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	_, err := writer.Write(footer[:])
+	return err
+}
+
+// readChunk reads the next chunk from the reader.
+// The length of the chunk is verified against the remaining data before any buffer is allocated for it.
+func readChunk(reader *bytes.Reader) (chunk, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		return chunk{}, err
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length > maxChunkSize {
+		return chunk{}, errChunkTooLarge
+	}
+	if int64(length)+4 > int64(reader.Len()) { // data and checksum
+		return chunk{}, errChunkTruncated
+	}
+	result := chunk{name: string(header[4:8]), data: make([]byte, length)}
+	if _, err := io.ReadFull(reader, result.data); err != nil {
+		return chunk{}, err
+	}
+	var footer [4]byte
+	if _, err := io.ReadFull(reader, footer[:]); err != nil {
+		return chunk{}, err
+	}
+	checksum := crc32.NewIEEE()
+	_, _ = checksum.Write(header[4:8])
+	_, _ = checksum.Write(result.data)
+	if checksum.Sum32() != binary.BigEndian.Uint32(footer[:]) {
+		return chunk{}, errChecksumMismatch
+	}
+	return result, nil
+}
+
+type frameControl struct {
+	sequence  uint32
+	width     uint32
+	height    uint32
+	xOffset   uint32
+	yOffset   uint32
+	delayNum  uint16
+	delayDen  uint16
+	disposeOp byte
+	blendOp   byte
+}
+
+const frameControlSize = 26
+
+func (ctrl frameControl) encode() []byte {
+	data := make([]byte, frameControlSize)
+	binary.BigEndian.PutUint32(data[0:4], ctrl.sequence)
+	binary.BigEndian.PutUint32(data[4:8], ctrl.width)
+	binary.BigEndian.PutUint32(data[8:12], ctrl.height)
+	binary.BigEndian.PutUint32(data[12:16], ctrl.xOffset)
+	binary.BigEndian.PutUint32(data[16:20], ctrl.yOffset)
+	binary.BigEndian.PutUint16(data[20:22], ctrl.delayNum)
+	binary.BigEndian.PutUint16(data[22:24], ctrl.delayDen)
+	data[24] = ctrl.disposeOp
+	data[25] = ctrl.blendOp
+	return data
+}
+
+func decodeFrameControl(data []byte) (frameControl, error) {
+	if len(data) != frameControlSize {
+		return frameControl{}, errInvalidFrame
+	}
+	ctrl := frameControl{
+		sequence:  binary.BigEndian.Uint32(data[0:4]),
+		width:     binary.BigEndian.Uint32(data[4:8]),
+		height:    binary.BigEndian.Uint32(data[8:12]),
+		xOffset:   binary.BigEndian.Uint32(data[12:16]),
+		yOffset:   binary.BigEndian.Uint32(data[16:20]),
+		delayNum:  binary.BigEndian.Uint16(data[20:22]),
+		delayDen:  binary.BigEndian.Uint16(data[22:24]),
+		disposeOp: data[24],
+		blendOp:   data[25],
+	}
+	return ctrl, nil
+}
@@ -0,0 +1,6 @@
+// Package apng handles animated PNG files.
+//
+// Frames are exchanged as full-size images of the animation canvas, similar to how image/gif
+// handles frames. Frames are stored without sub-regions, so a decoded animation can be
+// processed frame by frame without knowing about disposal or blending.
+package apng
@@ -0,0 +1,107 @@
+package bitmap_test
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+// frameImage creates an image resembling a movie frame: smooth gradients with some noise.
+func frameImage(random *rand.Rand, width, height int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	noise := func(value int) byte {
+		value += random.Intn(16) - 8
+		if value < 0 {
+			return 0
+		}
+		if value > 255 {
+			return 255
+		}
+		return byte(value)
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{
+				R: noise(x * 255 / width),
+				G: noise(y * 255 / height),
+				B: noise((x + y) * 255 / (width + height)),
+				A: 0xFF,
+			})
+		}
+	}
+	return img
+}
+
+func benchmarkReferenceMapping(b *testing.B, width, height int) {
+	b.Helper()
+	random := rand.New(rand.NewSource(0)) // nolint:gosec
+	pal := randomPalette(random)
+	img := frameImage(random, width, height)
+	b.ResetTimer()
+	for run := 0; run < b.N; run++ {
+		reference := newReferenceMapper(pal, bitmap.RegularIndices())
+		_ = reference.mapImage(img)
+	}
+}
+
+func benchmarkMapping(b *testing.B, width, height int, mode bitmap.DitherMode) {
+	b.Helper()
+	random := rand.New(rand.NewSource(0)) // nolint:gosec
+	pal := randomPalette(random)
+	img := frameImage(random, width, height)
+	options := bitmap.DefaultMapOptions()
+	options.Dithering = mode
+	b.ResetTimer()
+	for run := 0; run < b.N; run++ {
+		bitmapper := bitmap.NewBitmapperWithOptions(pal, options)
+		_ = bitmapper.Map(img)
+	}
+}
+
+func benchmarkRepeatedMapping(b *testing.B, width, height int) {
+	b.Helper()
+	random := rand.New(rand.NewSource(0)) // nolint:gosec
+	pal := randomPalette(random)
+	img := frameImage(random, width, height)
+	bitmapper := bitmap.NewBitmapper(pal)
+	_ = bitmapper.Map(img)
+	b.ResetTimer()
+	for run := 0; run < b.N; run++ {
+		_ = bitmapper.Map(img)
+	}
+}
+
+func BenchmarkReferenceMapping320x200(b *testing.B) {
+	benchmarkReferenceMapping(b, 320, 200)
+}
+
+func BenchmarkReferenceMapping640x480(b *testing.B) {
+	benchmarkReferenceMapping(b, 640, 480)
+}
+
+func BenchmarkMapping320x200(b *testing.B) {
+	benchmarkMapping(b, 320, 200, bitmap.DitherNone)
+}
+
+func BenchmarkMapping640x480(b *testing.B) {
+	benchmarkMapping(b, 640, 480, bitmap.DitherNone)
+}
+
+func BenchmarkRepeatedMapping320x200(b *testing.B) {
+	benchmarkRepeatedMapping(b, 320, 200)
+}
+
+func BenchmarkRepeatedMapping640x480(b *testing.B) {
+	benchmarkRepeatedMapping(b, 640, 480)
+}
+
+func BenchmarkMappingFloydSteinberg640x480(b *testing.B) {
+	benchmarkMapping(b, 640, 480, bitmap.DitherFloydSteinberg)
+}
+
+func BenchmarkMappingOrdered640x480(b *testing.B) {
+	benchmarkMapping(b, 640, 480, bitmap.DitherOrdered)
+}
@@ -0,0 +1,41 @@
+package bitmap
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// colorCacheBits specifies the number of slots in a color cache, as power of two.
+const colorCacheBits = 17
+
+// colorCache is a fixed-size, direct-mapped cache of palette indices.
+// Keys are at most 55 bits wide: 48 bits of color and up to 7 bits of variant. Colliding keys replace each other.
+// The slots are allocated with the first access, as not every bitmapper maps enough colors to need the cache.
+// The cache is safe for concurrent use without locking.
+type colorCache struct {
+	allocation sync.Once
+	slots      []uint64
+}
+
+func newColorCache() *colorCache {
+	return &colorCache{}
+}
+
+func (cache *colorCache) slotFor(key uint64) *uint64 {
+	cache.allocation.Do(func() {
+		cache.slots = make([]uint64, 1<<colorCacheBits)
+	})
+	return &cache.slots[(key*0x9E3779B97F4A7C15)>>(64-colorCacheBits)]
+}
+
+func (cache *colorCache) get(key uint64) (byte, bool) {
+	value := atomic.LoadUint64(cache.slotFor(key))
+	if ((value & 1) == 0) || ((value >> 9) != key) {
+		return 0, false
+	}
+	return byte(value >> 1), true
+}
+
+func (cache *colorCache) put(key uint64, palIndex byte) {
+	atomic.StoreUint64(cache.slotFor(key), (key<<9)|(uint64(palIndex)<<1)|1)
+}
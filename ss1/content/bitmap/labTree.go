@@ -0,0 +1,124 @@
+package bitmap
+
+import (
+	"math"
+	"sort"
+)
+
+// labTreeSearchTolerance widens the pruning check of the tree search to cover rounding differences
+// between the per-axis difference and the full distance calculation.
+const labTreeSearchTolerance = 1e-9
+
+// labTreeNoDistance is the initial distance of a search. Entries farther away are never matched.
+const labTreeNoDistance = 1000.0
+
+type labTreeNode struct {
+	entry labEntry
+	index byte
+	axis  int
+	left  int
+	right int
+}
+
+// labTree is a k-d tree over palette entries in Lab space.
+// A search in the tree returns the same result as a linear search over all entries in ascending index order.
+type labTree struct {
+	nodes []labTreeNode
+	root  int
+}
+
+type labTreeCandidate struct {
+	entry labEntry
+	index byte
+}
+
+func (entry labEntry) component(axis int) float64 {
+	switch axis {
+	case 0:
+		return entry.l
+	case 1:
+		return entry.a
+	default:
+		return entry.b
+	}
+}
+
+func newLabTree(pal []labEntry, indices IndexSet) *labTree {
+	var candidates []labTreeCandidate
+	for index, entry := range pal {
+		if indices.Contains(byte(index)) {
+			candidates = append(candidates, labTreeCandidate{entry: entry, index: byte(index)})
+		}
+	}
+	tree := &labTree{nodes: make([]labTreeNode, 0, len(candidates))}
+	tree.root = tree.build(candidates, 0)
+	return tree
+}
+
+func (tree *labTree) build(candidates []labTreeCandidate, depth int) int {
+	if len(candidates) == 0 {
+		return -1
+	}
+	axis := depth % 3
+	sort.Slice(candidates, func(a, b int) bool {
+		return candidates[a].entry.component(axis) < candidates[b].entry.component(axis)
+	})
+	median := len(candidates) / 2
+	nodeIndex := len(tree.nodes)
+	tree.nodes = append(tree.nodes, labTreeNode{
+		entry: candidates[median].entry,
+		index: candidates[median].index,
+		axis:  axis,
+	})
+	left := tree.build(candidates[:median], depth+1)
+	right := tree.build(candidates[median+1:], depth+1)
+	tree.nodes[nodeIndex].left = left
+	tree.nodes[nodeIndex].right = right
+	return nodeIndex
+}
+
+// nearest returns the index of the entry closest to given target.
+// Of equally distant entries, the one with the lowest index is returned.
+// If the tree is empty, index 0x00 is returned.
+func (tree *labTree) nearest(target labEntry) byte {
+	search := labTreeSearch{
+		target:          target,
+		distance:        labTreeNoDistance,
+		squaredDistance: labTreeNoDistance * labTreeNoDistance,
+	}
+	search.visit(tree, tree.root)
+	return search.index
+}
+
+type labTreeSearch struct {
+	target          labEntry
+	distance        float64
+	squaredDistance float64
+	index           byte
+}
+
+func (search *labTreeSearch) visit(tree *labTree, nodeIndex int) {
+	if nodeIndex < 0 {
+		return
+	}
+	node := &tree.nodes[nodeIndex]
+	squaredDistance := square(node.entry.l-search.target.l) + square(node.entry.a-search.target.a) + square(node.entry.b-search.target.b)
+	// Only entries that are not clearly farther away need the exact distance, as calculated by a linear search.
+	if squaredDistance <= search.squaredDistance*(1+labTreeSearchTolerance) {
+		distance := node.entry.distanceTo(search.target)
+		if (distance < search.distance) || ((distance == search.distance) && (node.index < search.index)) {
+			search.distance = distance
+			search.squaredDistance = squaredDistance
+			search.index = node.index
+		}
+	}
+	diff := search.target.component(node.axis) - node.entry.component(node.axis)
+	near, far := node.left, node.right
+	if diff > 0 {
+		near, far = far, near
+	}
+	search.visit(tree, near)
+	if math.Abs(diff) <= search.distance*(1+labTreeSearchTolerance)+labTreeSearchTolerance {
+		search.visit(tree, far)
+	}
+}
@@ -0,0 +1,78 @@
+package interpreters
+
+import (
+	"fmt"
+)
+
+// Catalog is a collection of descriptions, identified by name.
+type Catalog map[string]*Description
+
+// CatalogSchema is the declarative form of a catalog.
+type CatalogSchema map[string]DescriptionSchema
+
+// NameValidator returns whether a description of given name may be added to a catalog.
+type NameValidator func(name string) bool
+
+// UnknownDescriptionError is returned for schemas that refer to descriptions that are not part of a catalog.
+type UnknownDescriptionError struct {
+	Name string
+}
+
+// Error returns the description of the problem.
+func (err UnknownDescriptionError) Error() string {
+	return fmt.Sprintf("unknown description %q", err.Name)
+}
+
+// CatalogSchemaError is returned for schemas of a catalog that can not be converted.
+type CatalogSchemaError struct {
+	Name string
+	Err  error
+}
+
+// Error returns the description of the problem.
+func (err CatalogSchemaError) Error() string {
+	return fmt.Sprintf("description %q: %v", err.Name, err.Err)
+}
+
+// Unwrap returns the nested error.
+func (err CatalogSchemaError) Unwrap() error {
+	return err.Err
+}
+
+// Schema returns the declarative form of all descriptions of the catalog.
+func (catalog Catalog) Schema() (CatalogSchema, error) {
+	result := make(CatalogSchema)
+	for name, desc := range catalog {
+		schema, err := desc.Schema()
+		if err != nil {
+			return nil, CatalogSchemaError{Name: name, Err: err}
+		}
+		result[name] = schema
+	}
+	return result, nil
+}
+
+// Merged returns a new catalog that has the given schema applied on top of the descriptions.
+// Descriptions of the schema that are not part of the catalog are created anew, if the validator accepts their name.
+// The validator may be nil, in which case all descriptions of the schema must exist in the catalog.
+func (catalog Catalog) Merged(schema CatalogSchema, isValidName NameValidator) (Catalog, error) {
+	result := make(Catalog)
+	for name, desc := range catalog {
+		result[name] = desc
+	}
+	for name, descSchema := range schema {
+		desc, known := result[name]
+		if !known && ((isValidName == nil) || !isValidName(name)) {
+			return nil, UnknownDescriptionError{Name: name}
+		}
+		if !known {
+			desc = New()
+		}
+		merged, err := desc.Merged(descSchema)
+		if err != nil {
+			return nil, CatalogSchemaError{Name: name, Err: err}
+		}
+		result[name] = merged
+	}
+	return result, nil
+}
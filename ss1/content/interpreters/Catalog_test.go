@@ -0,0 +1,76 @@
+package interpreters_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogMergedAppliesSchemaToNamedDescriptions(t *testing.T) {
+	first := interpreters.New().With("a", 0, 1)
+	second := interpreters.New().With("b", 0, 1)
+	catalog := interpreters.Catalog{"first": first, "second": second}
+
+	merged, err := catalog.Merged(interpreters.CatalogSchema{
+		"second": {Fields: []interpreters.FieldSchema{{Key: "c", Start: 1, Count: 1}}},
+	}, nil)
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, first, merged["first"], "first should be unchanged")
+	assert.Equal(t, []string{"b", "c"}, merged["second"].For([]byte{0x00, 0x00}).Keys())
+	assert.Equal(t, second, catalog["second"], "original catalog must not be modified")
+}
+
+func TestCatalogMergedFailsForUnknownDescriptions(t *testing.T) {
+	catalog := interpreters.Catalog{"first": interpreters.New()}
+
+	_, err := catalog.Merged(interpreters.CatalogSchema{"unknown": {}}, nil)
+
+	assert.Equal(t, interpreters.UnknownDescriptionError{Name: "unknown"}, err)
+}
+
+func TestCatalogMergedFailsForNewDescriptionsWithInvalidName(t *testing.T) {
+	catalog := interpreters.Catalog{"first": interpreters.New()}
+
+	_, err := catalog.Merged(interpreters.CatalogSchema{"invalid": {}}, func(name string) bool { return name == "valid" })
+
+	assert.Equal(t, interpreters.UnknownDescriptionError{Name: "invalid"}, err)
+}
+
+func TestCatalogMergedAddsNewDescriptionsWithValidName(t *testing.T) {
+	catalog := interpreters.Catalog{"first": interpreters.New()}
+
+	merged, err := catalog.Merged(interpreters.CatalogSchema{
+		"valid": {Fields: []interpreters.FieldSchema{{Key: "a", Start: 0, Count: 1}}},
+	}, func(name string) bool { return name == "valid" })
+	require.Nil(t, err, "no error expected")
+
+	require.Contains(t, merged, "valid")
+	assert.Equal(t, []string{"a"}, merged["valid"].For([]byte{0x00}).Keys())
+	assert.NotContains(t, catalog, "valid", "original catalog must not be modified")
+}
+
+func TestCatalogMergedReportsNameOfInvalidSchema(t *testing.T) {
+	catalog := interpreters.Catalog{"first": interpreters.New()}
+
+	_, err := catalog.Merged(interpreters.CatalogSchema{
+		"first": {Fields: []interpreters.FieldSchema{{Key: "a"}}},
+	}, nil)
+	require.NotNil(t, err, "error expected")
+	catalogErr, isCatalogError := err.(interpreters.CatalogSchemaError)
+	require.True(t, isCatalogError, "catalog schema error expected")
+	assert.Equal(t, "first", catalogErr.Name)
+}
+
+func TestCatalogSchemaContainsAllDescriptions(t *testing.T) {
+	catalog := interpreters.Catalog{"first": interpreters.New().With("a", 0, 1), "second": interpreters.New()}
+
+	schema, err := catalog.Schema()
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, 2, len(schema))
+	assert.Equal(t, []interpreters.FieldSchema{{Key: "a", Start: 0, Count: 1}}, schema["first"].Fields)
+}
@@ -0,0 +1,55 @@
+package interpreters
+
+// ConditionTerm is fulfilled if the value of the field with given key is one of the listed values.
+type ConditionTerm struct {
+	Key    string
+	Values []uint32
+}
+
+// Condition is a declarative predicate. It is fulfilled if all of its terms are fulfilled.
+// A condition without any terms is always fulfilled.
+type Condition []ConditionTerm
+
+// Unconditional is the condition that is always fulfilled.
+var Unconditional Condition
+
+// FieldIn returns a condition that is fulfilled if the value of the field with given key
+// is one of the provided values.
+func FieldIn(key string, values ...uint32) Condition {
+	return Condition{{Key: key, Values: values}}
+}
+
+// And returns a condition that requires both this and the other condition to be fulfilled.
+func (cond Condition) And(other Condition) Condition {
+	combined := make(Condition, 0, len(cond)+len(other))
+	combined = append(combined, cond...)
+	return append(combined, other...)
+}
+
+// Matches returns true if the given instance fulfills the condition.
+func (cond Condition) Matches(inst *Instance) bool {
+	for _, term := range cond {
+		if !term.matches(inst) {
+			return false
+		}
+	}
+	return true
+}
+
+func (term ConditionTerm) matches(inst *Instance) bool {
+	value := inst.Get(term.Key)
+	for _, expected := range term.Values {
+		if value == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func (cond Condition) clone() Condition {
+	cloned := make(Condition, 0, len(cond))
+	for _, term := range cond {
+		cloned = append(cloned, ConditionTerm{Key: term.Key, Values: append([]uint32{}, term.Values...)})
+	}
+	return cloned
+}
@@ -0,0 +1,42 @@
+package interpreters_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionUnconditionalMatchesAlways(t *testing.T) {
+	inst := interpreters.New().With("field", 0, 1).For([]byte{0x12})
+
+	assert.True(t, interpreters.Unconditional.Matches(inst))
+}
+
+func TestConditionFieldInMatchesListedValues(t *testing.T) {
+	desc := interpreters.New().With("field", 0, 1)
+	cond := interpreters.FieldIn("field", 1, 3)
+
+	assert.True(t, cond.Matches(desc.For([]byte{0x01})), "1 should match")
+	assert.False(t, cond.Matches(desc.For([]byte{0x02})), "2 should not match")
+	assert.True(t, cond.Matches(desc.For([]byte{0x03})), "3 should match")
+}
+
+func TestConditionAndRequiresAllTerms(t *testing.T) {
+	desc := interpreters.New().With("a", 0, 1).With("b", 1, 1)
+	cond := interpreters.FieldIn("a", 1).And(interpreters.FieldIn("b", 2))
+
+	assert.True(t, cond.Matches(desc.For([]byte{0x01, 0x02})), "both should match")
+	assert.False(t, cond.Matches(desc.For([]byte{0x01, 0x03})), "only a should not match")
+	assert.False(t, cond.Matches(desc.For([]byte{0x00, 0x02})), "only b should not match")
+}
+
+func TestDescriptionRefiningWhenActivatesRefinementByCondition(t *testing.T) {
+	desc := interpreters.New().
+		With("type", 0, 1).
+		RefiningWhen("sub", 1, 1, interpreters.New(), interpreters.FieldIn("type", 5))
+
+	assert.Contains(t, desc.For([]byte{0x05, 0x00}).ActiveRefinements(), "sub")
+	assert.NotContains(t, desc.For([]byte{0x04, 0x00}).ActiveRefinements(), "sub")
+}
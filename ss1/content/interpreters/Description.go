@@ -84,3 +84,18 @@ func (desc *Description) Refining(key string, byteStart int, byteCount int, refi
 
 	return cloned
 }
+
+// RefiningWhen adds another description within the given one. The refined description is
+// active if the given condition is fulfilled. In contrast to Refining, such refinements can be
+// represented in a schema.
+func (desc *Description) RefiningWhen(key string, byteStart int, byteCount int, refined *Description, condition Condition) *Description {
+	cloned := desc.clone()
+	condition = condition.clone()
+	cloned.refinements[key] = &refinement{
+		entry:     entry{start: byteStart, count: byteCount},
+		desc:      refined,
+		predicate: condition.Matches,
+		condition: &condition}
+
+	return cloned
+}
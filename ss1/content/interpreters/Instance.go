@@ -64,6 +64,38 @@ func (inst *Instance) ActiveRefinements() (keys []string) {
 	return
 }
 
+// FieldLayout describes the position of a field within the data of an instance.
+type FieldLayout struct {
+	// Key is the key of the field. Fields of refinements are prefixed by the key of the refinement,
+	// separated by a dot.
+	Key   string
+	Start int
+	Count int
+}
+
+// Layout returns the position of all fields, including those of active refinements, sorted by start index.
+// Fields that are outside the available data are not included.
+func (inst *Instance) Layout() []FieldLayout {
+	var result []FieldLayout
+	for _, key := range sortedSchemaKeys(inst.desc.fields) {
+		e := inst.desc.fields[key]
+		if inst.isValidRange(e) {
+			result = append(result, FieldLayout{Key: key, Start: e.start, Count: e.count})
+		}
+	}
+	for _, key := range inst.ActiveRefinements() {
+		r := inst.desc.refinements[key]
+		if !inst.isValidRange(&r.entry) {
+			continue
+		}
+		for _, nested := range inst.Refined(key).Layout() {
+			result = append(result, FieldLayout{Key: key + "." + nested.Key, Start: r.start + nested.Start, Count: nested.Count})
+		}
+	}
+	sort.SliceStable(result, func(a, b int) bool { return result[a].Start < result[b].Start })
+	return result
+}
+
 // Get returns the value associated with the given key. Should there be no
 // value for the requested key, the function returns 0.
 func (inst *Instance) Get(key string) uint32 {
@@ -79,6 +111,16 @@ func (inst *Instance) Get(key string) uint32 {
 	return value
 }
 
+// Size returns the number of bytes of the value associated with the given key.
+// Should there be no value for the requested key, the function returns 0.
+func (inst *Instance) Size(key string) int {
+	e := inst.desc.fields[key]
+	if e == nil || !inst.isValidRange(e) {
+		return 0
+	}
+	return e.count
+}
+
 // Describe returns the description of a value key.
 func (inst *Instance) Describe(key string, simplifier *Simplifier) {
 	e := inst.desc.fields[key]
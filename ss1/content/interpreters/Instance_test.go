@@ -63,6 +63,16 @@ func (suite *InstanceSuite) TestGetReturnsZeroForKeyBeyondSize() {
 	assert.Equal(suite.T(), uint32(0), result)
 }
 
+func (suite *InstanceSuite) TestSizeReturnsByteCountOfField() {
+	assert.Equal(suite.T(), 2, suite.inst.Size("field2"))
+	assert.Equal(suite.T(), 4, suite.inst.Size("field3"))
+}
+
+func (suite *InstanceSuite) TestSizeReturnsZeroForUnknownOrBeyondKey() {
+	assert.Equal(suite.T(), 0, suite.inst.Size("unknown"))
+	assert.Equal(suite.T(), 0, suite.inst.Size("beyond"))
+}
+
 func (suite *InstanceSuite) TestSetIgnoresMisalignedFields() {
 	suite.inst.Set("misaligned", 0xEEFF)
 
@@ -144,3 +154,23 @@ func (suite *InstanceSuite) TestUndefinedConsidersActiveRefinements() {
 
 	assert.Equal(suite.T(), []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF}, data)
 }
+
+func (suite *InstanceSuite) TestLayoutListsFieldsOfActiveRefinements() {
+	layout := suite.inst.Layout()
+
+	assert.Equal(suite.T(), []interpreters.FieldLayout{
+		{Key: "field0", Start: 0, Count: 1},
+		{Key: "field1", Start: 1, Count: 1},
+		{Key: "field2", Start: 2, Count: 2},
+		{Key: "sub1.subField0", Start: 3, Count: 1},
+		{Key: "field3", Start: 4, Count: 4},
+		{Key: "sub1.subField1", Start: 4, Count: 2},
+	}, layout)
+}
+
+func (suite *InstanceSuite) TestLayoutConsidersActiveRefinements() {
+	suite.inst.Set("field0", 0)
+	layout := suite.inst.Layout()
+
+	assert.Contains(suite.T(), layout, interpreters.FieldLayout{Key: "sub2.subFieldB", Start: 8, Count: 1})
+}
@@ -5,4 +5,6 @@ type refinement struct {
 
 	desc      *Description
 	predicate Predicate
+	// condition is the declarative form of the predicate. It is nil if the predicate is a function.
+	condition *Condition
 }
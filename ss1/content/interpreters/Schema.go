@@ -0,0 +1,294 @@
+package interpreters
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RangeKind identifies the type of a field range in a schema.
+type RangeKind string
+
+// RangeKind constants are listed below.
+const (
+	RangeKindRanged   RangeKind = "Ranged"
+	RangeKindEnum     RangeKind = "Enum"
+	RangeKindBitfield RangeKind = "Bitfield"
+	RangeKindObjectID RangeKind = "ObjectID"
+	RangeKindRotation RangeKind = "Rotation"
+	RangeKindSpecial  RangeKind = "Special"
+)
+
+// RangeSchema is the declarative form of a field range.
+// Formatting functions of ranged values can not be represented and are dropped.
+type RangeSchema struct {
+	Kind RangeKind
+	// Min and Max are used for RangeKindRanged and RangeKindRotation.
+	Min int64 `json:",omitempty"`
+	Max int64 `json:",omitempty"`
+	// Values are used for RangeKindEnum and RangeKindBitfield.
+	Values map[uint32]string `json:",omitempty"`
+	// Special is the type of a RangeKindSpecial, see SpecialValue().
+	Special string `json:",omitempty"`
+}
+
+// FieldSchema is the declarative form of a field.
+type FieldSchema struct {
+	Key   string
+	Start int
+	Count int
+	// Range is nil for fields that have their raw value range.
+	Range *RangeSchema `json:",omitempty"`
+}
+
+// RefinementSchema is the declarative form of a refinement.
+type RefinementSchema struct {
+	Key   string
+	Start int
+	Count int
+	// Condition specifies when the refinement is active. Without a condition, a new refinement is
+	// always active, and a merged refinement keeps its previous condition.
+	Condition   *Condition `json:",omitempty"`
+	Description DescriptionSchema
+}
+
+// DescriptionSchema is the declarative form of a description.
+type DescriptionSchema struct {
+	Fields      []FieldSchema      `json:",omitempty"`
+	Refinements []RefinementSchema `json:",omitempty"`
+}
+
+// SchemaError is returned for schemas that can not be converted.
+type SchemaError struct {
+	// Key is the path of the affected entry, refinements separated by a dot.
+	Key     string
+	Problem string
+}
+
+// Error returns the description of the problem.
+func (err SchemaError) Error() string {
+	return fmt.Sprintf("schema entry %q: %s", err.Key, err.Problem)
+}
+
+// FromSchema returns a new description based on the given schema.
+func FromSchema(schema DescriptionSchema) (*Description, error) {
+	return New().Merged(schema)
+}
+
+// Schema returns the declarative form of the description.
+// Refinements that were added with a predicate function can not be represented and result in an error.
+func (desc *Description) Schema() (DescriptionSchema, error) {
+	return desc.schema("")
+}
+
+func (desc *Description) schema(path string) (DescriptionSchema, error) {
+	var result DescriptionSchema
+	for _, key := range sortedSchemaKeys(desc.fields) {
+		e := desc.fields[key]
+		result.Fields = append(result.Fields, FieldSchema{
+			Key:   key,
+			Start: e.start,
+			Count: e.count,
+			Range: rangeSchemaOf(e.via),
+		})
+	}
+	refinementEntries := make(map[string]*entry)
+	for key, r := range desc.refinements {
+		refinementEntries[key] = &r.entry
+	}
+	for _, key := range sortedSchemaKeys(refinementEntries) {
+		r := desc.refinements[key]
+		refinementPath := schemaPath(path, key)
+		if r.condition == nil {
+			return DescriptionSchema{}, SchemaError{Key: refinementPath, Problem: "refinement has no declarative condition"}
+		}
+		nested, err := r.desc.schema(refinementPath)
+		if err != nil {
+			return DescriptionSchema{}, err
+		}
+		condition := r.condition.clone()
+		result.Refinements = append(result.Refinements, RefinementSchema{
+			Key:         key,
+			Start:       r.start,
+			Count:       r.count,
+			Condition:   &condition,
+			Description: nested,
+		})
+	}
+	return result, nil
+}
+
+// Merged returns a new description that has the given schema applied on top of this description.
+// Fields of the schema replace those with the same key. Refinements of the schema with the same key
+// are merged recursively; A refinement with a count of zero keeps the previous position.
+func (desc *Description) Merged(schema DescriptionSchema) (*Description, error) {
+	return desc.merged("", schema)
+}
+
+func (desc *Description) merged(path string, schema DescriptionSchema) (*Description, error) {
+	result := desc.clone()
+	for _, field := range schema.Fields {
+		fieldPath := schemaPath(path, field.Key)
+		err := verifyEntry(fieldPath, field.Key, field.Start, field.Count)
+		if err != nil {
+			return nil, err
+		}
+		e := &entry{start: field.Start, count: field.Count}
+		if field.Range != nil {
+			e.via, err = field.Range.fieldRange(fieldPath)
+			if err != nil {
+				return nil, err
+			}
+		}
+		result.fields[field.Key] = e
+	}
+	for _, refinementSchema := range schema.Refinements {
+		refinementPath := schemaPath(path, refinementSchema.Key)
+		merged, err := result.mergedRefinement(refinementPath, refinementSchema)
+		if err != nil {
+			return nil, err
+		}
+		result.refinements[refinementSchema.Key] = merged
+	}
+	return result, nil
+}
+
+func (desc *Description) mergedRefinement(path string, schema RefinementSchema) (*refinement, error) {
+	existing := desc.refinements[schema.Key]
+	merged := &refinement{
+		entry:     entry{start: schema.Start, count: schema.Count},
+		desc:      New(),
+		predicate: Always,
+		condition: &Unconditional,
+	}
+	if existing != nil {
+		merged.desc = existing.desc
+		merged.predicate = existing.predicate
+		merged.condition = existing.condition
+		if schema.Count == 0 {
+			merged.entry = existing.entry
+		}
+	}
+	err := verifyEntry(path, schema.Key, merged.start, merged.count)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Condition != nil {
+		condition := schema.Condition.clone()
+		merged.predicate = condition.Matches
+		merged.condition = &condition
+	}
+	merged.desc, err = merged.desc.merged(path, schema.Description)
+	if err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+func verifyEntry(path string, key string, start int, count int) error {
+	if len(key) == 0 {
+		return SchemaError{Key: path, Problem: "key is missing"}
+	}
+	if start < 0 {
+		return SchemaError{Key: path, Problem: "start must not be negative"}
+	}
+	if count <= 0 {
+		return SchemaError{Key: path, Problem: "count must be positive"}
+	}
+	return nil
+}
+
+func (schema RangeSchema) fieldRange(path string) (FieldRange, error) {
+	switch schema.Kind {
+	case RangeKindRanged:
+		if schema.Min > schema.Max {
+			return nil, SchemaError{Key: path, Problem: "minimum is larger than maximum"}
+		}
+		return RangedValue(schema.Min, schema.Max), nil
+	case RangeKindEnum:
+		if len(schema.Values) == 0 {
+			return nil, SchemaError{Key: path, Problem: "enumeration has no values"}
+		}
+		return EnumValue(copiedValues(schema.Values)), nil
+	case RangeKindBitfield:
+		if len(schema.Values) == 0 {
+			return nil, SchemaError{Key: path, Problem: "bitfield has no values"}
+		}
+		return Bitfield(copiedValues(schema.Values)), nil
+	case RangeKindObjectID:
+		return ObjectID(), nil
+	case RangeKindRotation:
+		if schema.Min > schema.Max {
+			return nil, SchemaError{Key: path, Problem: "minimum is larger than maximum"}
+		}
+		return RotationValue(schema.Min, schema.Max), nil
+	case RangeKindSpecial:
+		if len(schema.Special) == 0 {
+			return nil, SchemaError{Key: path, Problem: "special type is missing"}
+		}
+		return SpecialValue(schema.Special), nil
+	default:
+		return nil, SchemaError{Key: path, Problem: fmt.Sprintf("unknown range kind %q", schema.Kind)}
+	}
+}
+
+// rangeSchemaOf describes the given field range to a simplifier that records the details.
+func rangeSchemaOf(via FieldRange) *RangeSchema {
+	if via == nil {
+		return nil
+	}
+	var result *RangeSchema
+	simpl := NewSimplifier(func(minValue, maxValue int64, formatter RawValueFormatter) {
+		result = &RangeSchema{Kind: RangeKindRanged, Min: minValue, Max: maxValue}
+	})
+	simpl.SetEnumValueHandler(func(values map[uint32]string) {
+		result = &RangeSchema{Kind: RangeKindEnum, Values: copiedValues(values)}
+	})
+	simpl.SetBitfieldHandler(func(values map[uint32]string) {
+		result = &RangeSchema{Kind: RangeKindBitfield, Values: copiedValues(values)}
+	})
+	simpl.SetObjectIDHandler(func() {
+		result = &RangeSchema{Kind: RangeKindObjectID}
+	})
+	simpl.SetRotationHandler(func(minValue, maxValue int64) {
+		result = &RangeSchema{Kind: RangeKindRotation, Min: minValue, Max: maxValue}
+	})
+	simpl.anySpecialHandler = func(specialType string) {
+		result = &RangeSchema{Kind: RangeKindSpecial, Special: specialType}
+	}
+	if !via(simpl) {
+		return nil
+	}
+	return result
+}
+
+func copiedValues(values map[uint32]string) map[uint32]string {
+	copied := make(map[uint32]string, len(values))
+	for key, value := range values {
+		copied[key] = value
+	}
+	return copied
+}
+
+func schemaPath(path string, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedSchemaKeys returns the keys sorted by start index, and by name for the same start.
+func sortedSchemaKeys(entries map[string]*entry) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		entryA := entries[keys[a]]
+		entryB := entries[keys[b]]
+		if entryA.start != entryB.start {
+			return entryA.start < entryB.start
+		}
+		return keys[a] < keys[b]
+	})
+	return keys
+}
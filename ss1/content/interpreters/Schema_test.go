@@ -0,0 +1,166 @@
+package interpreters_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptionSchemaExportsFieldsAndRanges(t *testing.T) {
+	desc := interpreters.New().
+		With("raw", 0, 1).
+		With("ranged", 1, 1).As(interpreters.RangedValue(2, 10)).
+		With("enum", 2, 1).As(interpreters.EnumValue(map[uint32]string{1: "one"})).
+		With("bits", 3, 1).As(interpreters.Bitfield(map[uint32]string{0x0F: "low"})).
+		With("object", 4, 2).As(interpreters.ObjectID()).
+		With("rotation", 6, 1).As(interpreters.RotationValue(0, 255)).
+		With("special", 7, 1).As(interpreters.SpecialValue("Unknown"))
+
+	schema, err := desc.Schema()
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []interpreters.FieldSchema{
+		{Key: "raw", Start: 0, Count: 1},
+		{Key: "ranged", Start: 1, Count: 1, Range: &interpreters.RangeSchema{Kind: interpreters.RangeKindRanged, Min: 2, Max: 10}},
+		{Key: "enum", Start: 2, Count: 1, Range: &interpreters.RangeSchema{Kind: interpreters.RangeKindEnum, Values: map[uint32]string{1: "one"}}},
+		{Key: "bits", Start: 3, Count: 1, Range: &interpreters.RangeSchema{Kind: interpreters.RangeKindBitfield, Values: map[uint32]string{0x0F: "low"}}},
+		{Key: "object", Start: 4, Count: 2, Range: &interpreters.RangeSchema{Kind: interpreters.RangeKindObjectID}},
+		{Key: "rotation", Start: 6, Count: 1, Range: &interpreters.RangeSchema{Kind: interpreters.RangeKindRotation, Min: 0, Max: 255}},
+		{Key: "special", Start: 7, Count: 1, Range: &interpreters.RangeSchema{Kind: interpreters.RangeKindSpecial, Special: "Unknown"}},
+	}, schema.Fields)
+}
+
+func TestDescriptionSchemaExportsRefinementsWithCondition(t *testing.T) {
+	desc := interpreters.New().
+		With("type", 0, 1).
+		RefiningWhen("sub", 1, 2, interpreters.New().With("inner", 1, 1), interpreters.FieldIn("type", 3))
+
+	schema, err := desc.Schema()
+	require.Nil(t, err, "no error expected")
+	require.Equal(t, 1, len(schema.Refinements))
+	refinement := schema.Refinements[0]
+	assert.Equal(t, "sub", refinement.Key)
+	assert.Equal(t, 1, refinement.Start)
+	assert.Equal(t, 2, refinement.Count)
+	require.NotNil(t, refinement.Condition)
+	assert.Equal(t, interpreters.FieldIn("type", 3), *refinement.Condition)
+	assert.Equal(t, []interpreters.FieldSchema{{Key: "inner", Start: 1, Count: 1}}, refinement.Description.Fields)
+}
+
+func TestDescriptionSchemaFailsForPredicateFunctions(t *testing.T) {
+	desc := interpreters.New().
+		RefiningWhen("outer", 0, 4, interpreters.New().Refining("inner", 0, 1, interpreters.New(), interpreters.Always),
+			interpreters.Unconditional)
+
+	_, err := desc.Schema()
+	require.NotNil(t, err, "error expected")
+	schemaErr, isSchemaError := err.(interpreters.SchemaError)
+	require.True(t, isSchemaError, "schema error expected")
+	assert.Equal(t, "outer.inner", schemaErr.Key)
+}
+
+func TestFromSchemaCreatesEquivalentDescription(t *testing.T) {
+	original := interpreters.New().
+		With("type", 0, 1).As(interpreters.EnumValue(map[uint32]string{0: "zero", 1: "one"})).
+		RefiningWhen("sub", 1, 1, interpreters.New().With("value", 0, 1).As(interpreters.RangedValue(0, 10)),
+			interpreters.FieldIn("type", 1))
+	schema, err := original.Schema()
+	require.Nil(t, err, "no error expected")
+
+	desc, err := interpreters.FromSchema(schema)
+	require.Nil(t, err, "no error expected")
+	inst := desc.For([]byte{0x01, 0x07})
+
+	assert.Equal(t, uint32(1), inst.Get("type"))
+	assert.Equal(t, []string{"sub"}, inst.ActiveRefinements())
+	assert.Equal(t, uint32(7), inst.Refined("sub").Get("value"))
+	reExported, err := desc.Schema()
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, schema, reExported)
+}
+
+func TestFromSchemaFailsForInvalidEntries(t *testing.T) {
+	tt := []struct {
+		name   string
+		schema interpreters.DescriptionSchema
+		key    string
+	}{
+		{name: "missing key", schema: interpreters.DescriptionSchema{Fields: []interpreters.FieldSchema{{Start: 0, Count: 1}}}},
+		{name: "negative start", schema: interpreters.DescriptionSchema{Fields: []interpreters.FieldSchema{{Key: "a", Start: -1, Count: 1}}}, key: "a"},
+		{name: "zero count", schema: interpreters.DescriptionSchema{Fields: []interpreters.FieldSchema{{Key: "a", Start: 0}}}, key: "a"},
+		{name: "unknown kind", schema: interpreters.DescriptionSchema{Fields: []interpreters.FieldSchema{
+			{Key: "a", Start: 0, Count: 1, Range: &interpreters.RangeSchema{Kind: "Fancy"}}}}, key: "a"},
+		{name: "inverted range", schema: interpreters.DescriptionSchema{Fields: []interpreters.FieldSchema{
+			{Key: "a", Start: 0, Count: 1, Range: &interpreters.RangeSchema{Kind: interpreters.RangeKindRanged, Min: 2, Max: 1}}}}, key: "a"},
+		{name: "empty enum", schema: interpreters.DescriptionSchema{Fields: []interpreters.FieldSchema{
+			{Key: "a", Start: 0, Count: 1, Range: &interpreters.RangeSchema{Kind: interpreters.RangeKindEnum}}}}, key: "a"},
+		{name: "new refinement without position", schema: interpreters.DescriptionSchema{Refinements: []interpreters.RefinementSchema{
+			{Key: "sub"}}}, key: "sub"},
+		{name: "nested field", schema: interpreters.DescriptionSchema{Refinements: []interpreters.RefinementSchema{
+			{Key: "sub", Start: 0, Count: 2, Description: interpreters.DescriptionSchema{
+				Fields: []interpreters.FieldSchema{{Key: "a", Start: 0}}}}}}, key: "sub.a"},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.name, func(t *testing.T) {
+			_, err := interpreters.FromSchema(td.schema)
+			require.NotNil(t, err, "error expected")
+			schemaErr, isSchemaError := err.(interpreters.SchemaError)
+			require.True(t, isSchemaError, "schema error expected")
+			assert.Equal(t, td.key, schemaErr.Key)
+		})
+	}
+}
+
+func TestDescriptionMergedReplacesAndAddsFields(t *testing.T) {
+	original := interpreters.New().
+		With("a", 0, 1).As(interpreters.RangedValue(0, 5)).
+		With("b", 1, 1)
+
+	merged, err := original.Merged(interpreters.DescriptionSchema{Fields: []interpreters.FieldSchema{
+		{Key: "a", Start: 0, Count: 2},
+		{Key: "c", Start: 2, Count: 1},
+	}})
+	require.Nil(t, err, "no error expected")
+	inst := merged.For([]byte{0x01, 0x02, 0x03})
+
+	assert.Equal(t, []string{"a", "b", "c"}, inst.Keys())
+	assert.Equal(t, uint32(0x0201), inst.Get("a"))
+	assert.Equal(t, uint32(0x03), inst.Get("c"))
+	assert.Equal(t, []string{"a", "b"}, original.For([]byte{0x01, 0x02}).Keys(), "original must not be modified")
+}
+
+func TestDescriptionMergedMergesRefinementsRecursively(t *testing.T) {
+	original := interpreters.New().
+		With("type", 0, 1).
+		RefiningWhen("sub", 1, 2, interpreters.New().With("first", 0, 1), interpreters.FieldIn("type", 1))
+
+	merged, err := original.Merged(interpreters.DescriptionSchema{Refinements: []interpreters.RefinementSchema{
+		{Key: "sub", Description: interpreters.DescriptionSchema{Fields: []interpreters.FieldSchema{{Key: "second", Start: 1, Count: 1}}}},
+	}})
+	require.Nil(t, err, "no error expected")
+	inst := merged.For([]byte{0x01, 0x0A, 0x0B})
+
+	assert.Equal(t, []string{"sub"}, inst.ActiveRefinements(), "condition should be kept")
+	sub := inst.Refined("sub")
+	assert.Equal(t, uint32(0x0A), sub.Get("first"))
+	assert.Equal(t, uint32(0x0B), sub.Get("second"))
+	assert.Empty(t, merged.For([]byte{0x02, 0x0A, 0x0B}).ActiveRefinements(), "inactive for other type")
+}
+
+func TestDescriptionMergedReplacesConditionOfRefinement(t *testing.T) {
+	original := interpreters.New().
+		With("type", 0, 1).
+		RefiningWhen("sub", 1, 1, interpreters.New(), interpreters.FieldIn("type", 1))
+	condition := interpreters.FieldIn("type", 2)
+
+	merged, err := original.Merged(interpreters.DescriptionSchema{Refinements: []interpreters.RefinementSchema{
+		{Key: "sub", Condition: &condition},
+	}})
+	require.Nil(t, err, "no error expected")
+
+	assert.Equal(t, []string{"sub"}, merged.For([]byte{0x02, 0x00}).ActiveRefinements())
+}
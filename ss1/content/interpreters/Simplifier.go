@@ -77,6 +77,8 @@ func RotationValue(minValue, maxValue int64) FieldRange {
 // * LevelTexture - index value into level texture list
 // * MaterialOrLevelTexture - index value into level texture list, or material (bit 7 toggles)
 // * VariableKey - for actions
+// * VariableSource - for actions that read a variable
+// * LockVariable - for boolean variables locking doors
 // * VariableCondition - for action conditions
 // * ObjectTriple - for 0x00CCSSTT selection
 // * ObjectHeight - for level height value 0..255
@@ -101,6 +103,8 @@ type Simplifier struct {
 	objectIDHandler  ObjectIDHandler
 	rotationHandler  RotationHandler
 	specialHandler   map[string]SpecialHandler
+	// anySpecialHandler is called for special values without a dedicated handler.
+	anySpecialHandler func(specialType string)
 }
 
 // NewSimplifier returns a new instance of a simplifier, with the minimal
@@ -175,6 +179,9 @@ func (simpl *Simplifier) specialValue(specialType string) (result bool) {
 	if existing && (handler != nil) {
 		handler()
 		result = true
+	} else if simpl.anySpecialHandler != nil {
+		simpl.anySpecialHandler(specialType)
+		result = true
 	}
 	return
 }
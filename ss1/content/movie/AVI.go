@@ -0,0 +1,59 @@
+package movie
+
+import (
+	"image"
+	"strings"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/movie/avi"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// lastSubtitleMinimumDuration is the time a subtitle is shown at least, if no other subtitle follows.
+const lastSubtitleMinimumDuration = 3 * time.Second
+
+// AVIMovie returns the description of an AVI file with given content of a movie.
+// Every language that has subtitles becomes a separate subtitle track.
+// Subtitles are displayed until the next one starts, and subtitles with empty text only end their predecessor.
+func AVIMovie(width, height int, scenes []Scene, sound audio.L8, subtitles Subtitles) avi.Movie {
+	result := avi.Movie{
+		Width:  width,
+		Height: height,
+		Audio:  sound,
+	}
+	var end time.Duration
+	rect := image.Rect(0, 0, width, height)
+	for _, scene := range scenes {
+		colorPalette := scene.Palette.ColorPalette(false)
+		for _, frame := range scene.Frames {
+			img := image.NewPaletted(rect, colorPalette)
+			img.Pix = frame.Pixels
+			result.Frames = append(result.Frames, avi.Frame{Image: img, DisplayTime: frame.DisplayTime})
+			end += frame.DisplayTime
+		}
+	}
+	if audioEnd := time.Duration(float64(sound.Duration()) * float64(time.Second)); end < audioEnd {
+		end = audioEnd
+	}
+	for lang, list := range subtitles.PerLanguage {
+		if len(list.Entries) == 0 {
+			continue
+		}
+		track := avi.SubtitleTrack{Name: resource.Language(lang).String()}
+		for index, entry := range list.Entries {
+			if len(strings.TrimSpace(entry.Text)) == 0 {
+				continue
+			}
+			subtitle := avi.Subtitle{Start: entry.Timestamp, End: end, Text: entry.Text}
+			if (index + 1) < len(list.Entries) {
+				subtitle.End = list.Entries[index+1].Timestamp
+			} else if subtitle.End < (subtitle.Start + lastSubtitleMinimumDuration) {
+				subtitle.End = subtitle.Start + lastSubtitleMinimumDuration
+			}
+			track.Entries = append(track.Entries, subtitle)
+		}
+		result.Subtitles = append(result.Subtitles, track)
+	}
+	return result
+}
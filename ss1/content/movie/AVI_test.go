@@ -0,0 +1,51 @@
+package movie_test
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/movie/avi"
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+func TestAVIMovieContainsFramesOfAllScenes(t *testing.T) {
+	var palette bitmap.Palette
+	palette[1] = bitmap.RGB{Red: 0xFF}
+	scenes := []movie.Scene{
+		{Palette: palette, Frames: []movie.Frame{{Pixels: []byte{0, 1}, DisplayTime: time.Second}}},
+		{Palette: palette, Frames: []movie.Frame{{Pixels: []byte{1, 1}, DisplayTime: 2 * time.Second}}},
+	}
+	result := movie.AVIMovie(2, 1, scenes, audio.L8{}, movie.Subtitles{})
+
+	require.Equal(t, 2, len(result.Frames))
+	assert.Equal(t, 2*time.Second, result.Frames[1].DisplayTime)
+	img, isPaletted := result.Frames[0].Image.(*image.Paletted)
+	require.True(t, isPaletted)
+	assert.Equal(t, []byte{0, 1}, img.Pix)
+	assert.Equal(t, palette[1].Color(0xFF), img.Palette[1])
+}
+
+func TestAVIMovieTimesSubtitlesUntilNextEntry(t *testing.T) {
+	var subtitles movie.Subtitles
+	subtitles.PerLanguage[resource.LangGerman].Entries = []movie.Subtitle{
+		{Timestamp: time.Second, Text: "one"},
+		{Timestamp: 2 * time.Second, Text: ""},
+		{Timestamp: 4 * time.Second, Text: "two"},
+	}
+	sound := audio.L8{SampleRate: 1000, Samples: make([]byte, 10000)}
+	result := movie.AVIMovie(0, 0, nil, sound, subtitles)
+
+	require.Equal(t, 1, len(result.Subtitles))
+	assert.Equal(t, resource.LangGerman.String(), result.Subtitles[0].Name)
+	assert.Equal(t, []avi.Subtitle{
+		{Start: time.Second, End: 2 * time.Second, Text: "one"},
+		{Start: 4 * time.Second, End: 10 * time.Second, Text: "two"},
+	}, result.Subtitles[0].Entries)
+}
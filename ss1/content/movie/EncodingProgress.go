@@ -0,0 +1,42 @@
+package movie
+
+import "github.com/inkyblackness/hacked/ss1/content/movie/internal/compression"
+
+// EncodingProgress describes the state of a running scene compression.
+type EncodingProgress struct {
+	// Stage is a short description of the current step.
+	Stage string
+	// Fraction is the overall completion of the scene, in range [0.0, 1.0].
+	Fraction float64
+}
+
+// EncodingProgressFunc is called with the progress of a running scene compression.
+type EncodingProgressFunc func(EncodingProgress)
+
+type highResStageInfo struct {
+	name   string
+	start  float64
+	weight float64
+}
+
+// highResStages roughly weighs the stages by the time they typically take.
+var highResStages = map[compression.EncodingStage]highResStageInfo{
+	compression.StageTileDeltas:     {name: "Comparing frames", start: 0.00, weight: 0.05},
+	compression.StagePaletteLookup:  {name: "Creating palette lookup", start: 0.05, weight: 0.70},
+	compression.StageTileOperations: {name: "Coloring tiles", start: 0.75, weight: 0.15},
+	compression.StageBitstreams:     {name: "Packing frames", start: 0.90, weight: 0.10},
+}
+
+func highResProgress(progress EncodingProgressFunc) compression.ProgressFunc {
+	if progress == nil {
+		return nil
+	}
+	return func(stage compression.EncodingStage, done, total int) {
+		info := highResStages[stage]
+		fraction := info.start
+		if total > 0 {
+			fraction += info.weight * float64(done) / float64(total)
+		}
+		progress(EncodingProgress{Stage: info.name, Fraction: fraction})
+	}
+}
@@ -0,0 +1,242 @@
+package movie
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1"
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie/internal/format"
+	"github.com/inkyblackness/hacked/ss1/serial/rle"
+)
+
+const (
+	errFrameSizeMismatch  ss1.StringError = "frame size does not match video size"
+	errInvalidBoundingBox ss1.StringError = "bounding box of frame outside of video"
+	errInvalidPackedFrame ss1.StringError = "packed frame data is invalid"
+)
+
+const (
+	lowResBoundingBoxLeft   = 0
+	lowResBoundingBoxTop    = 1
+	lowResBoundingBoxRight  = 2
+	lowResBoundingBoxBottom = 3
+)
+
+// LowResScene is a set of frames with low-resolution compression.
+// Each frame updates a rectangular area of its predecessor, the pixels are run-length encoded.
+type LowResScene struct {
+	palette bitmap.Palette
+	frames  []LowResFrame
+}
+
+// LowResSceneFrom compresses given scene and returns the compression result.
+// The first frame of the scene is always stored completely, so that scenes can be rearranged.
+func LowResSceneFrom(ctx context.Context, scene Scene, width, height int) (LowResScene, error) {
+	return LowResSceneFromWithProgress(ctx, scene, width, height, nil)
+}
+
+// LowResSceneFromWithProgress compresses given scene and returns the compression result.
+// The given function, if not nil, is called with the progress of the compression.
+func LowResSceneFromWithProgress(ctx context.Context, scene Scene, width, height int,
+	progress EncodingProgressFunc) (LowResScene, error) {
+	compressedScene := LowResScene{
+		palette: scene.Palette,
+		frames:  make([]LowResFrame, 0, len(scene.Frames)),
+	}
+	var previous []byte
+	for _, frame := range scene.Frames {
+		if ctx.Err() != nil {
+			return LowResScene{}, ctx.Err()
+		}
+		if len(frame.Pixels) != width*height {
+			return LowResScene{}, errFrameSizeMismatch
+		}
+		compressedScene.frames = append(compressedScene.frames, lowResFrameFrom(frame, previous, width, height))
+		previous = frame.Pixels
+		if progress != nil {
+			progress(EncodingProgress{
+				Stage:    "Packing frames",
+				Fraction: float64(len(compressedScene.frames)) / float64(len(scene.Frames)),
+			})
+		}
+	}
+	return compressedScene, nil
+}
+
+func lowResFrameFrom(frame Frame, previous []byte, width, height int) LowResFrame {
+	left, top, right, bottom := 0, 0, width, height
+	if previous != nil {
+		left, top, right, bottom = changedArea(frame.Pixels, previous, width, height)
+	}
+	areaWidth := right - left
+	data := make([]byte, 0, areaWidth*(bottom-top))
+	reference := make([]byte, 0, cap(data))
+	for row := top; row < bottom; row++ {
+		rowData := frame.Pixels[row*width+left : row*width+right]
+		data = append(data, rowData...)
+		if previous != nil {
+			reference = append(reference, previous[row*width+left:row*width+right]...)
+		}
+	}
+	if previous == nil {
+		// A reference that differs in every pixel prevents any skipping, the frame is stored completely.
+		for _, value := range data {
+			reference = append(reference, ^value)
+		}
+	}
+	packed := bytes.NewBuffer(nil)
+	_ = rle.Compress(packed, data, reference)
+	return LowResFrame{
+		boundingBox: [4]uint16{uint16(left), uint16(top), uint16(right), uint16(bottom)},
+		packed:      packed.Bytes(),
+		displayTime: format.TimestampFromDuration(frame.DisplayTime),
+	}
+}
+
+// changedArea returns the bounding box of all pixels that differ between the two frames.
+// If the frames are identical, the box covers only the first pixel.
+func changedArea(pixels, previous []byte, width, height int) (left, top, right, bottom int) {
+	left, top, right, bottom = width, height, 0, 0
+	for row := 0; row < height; row++ {
+		for column := 0; column < width; column++ {
+			if pixels[row*width+column] == previous[row*width+column] {
+				continue
+			}
+			if column < left {
+				left = column
+			}
+			if column >= right {
+				right = column + 1
+			}
+			if row < top {
+				top = row
+			}
+			bottom = row + 1
+		}
+	}
+	if right <= left {
+		return 0, 0, 1, 1
+	}
+	return
+}
+
+// Palette returns the palette of the scene.
+func (scene LowResScene) Palette() bitmap.Palette {
+	return scene.palette
+}
+
+// WithFrameDisplayTime returns a new scene instance with the given display time set for all frames.
+func (scene LowResScene) WithFrameDisplayTime(displayTime time.Duration) CompressedScene {
+	newScene := scene
+	newFrames := make([]LowResFrame, len(scene.frames))
+	for index, frame := range scene.frames {
+		newFrames[index] = frame.WithDisplayTime(displayTime)
+	}
+	newScene.frames = newFrames
+	return newScene
+}
+
+func (scene LowResScene) duration() format.Timestamp {
+	var sum format.Timestamp
+	for _, frame := range scene.frames {
+		sum = sum.Plus(frame.displayTime)
+	}
+	return sum
+}
+
+func (scene LowResScene) decompress(frameBuffer []byte, width, height int) ([]Frame, error) {
+	frames := make([]Frame, 0, len(scene.frames))
+	for _, compressedFrame := range scene.frames {
+		err := compressedFrame.decode(frameBuffer, width, height)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, Frame{
+			Pixels:      cloneFrameBuffer(frameBuffer),
+			DisplayTime: compressedFrame.displayTime.ToDuration(),
+		})
+	}
+	return frames, nil
+}
+
+func (scene LowResScene) encode(start format.Timestamp, withPalette bool) []format.EntryBucket {
+	buckets := make([]format.EntryBucket, 0, len(scene.frames)+1)
+	if withPalette {
+		buckets = append(buckets,
+			format.EntryBucket{
+				Priority:  format.EntryBucketPriorityVideoControl,
+				Timestamp: start,
+				Entries: []format.Entry{
+					{Timestamp: start, Data: format.PaletteResetEntryData{}},
+					{Timestamp: start, Data: format.PaletteEntryData{Colors: scene.palette}},
+				},
+			})
+	}
+	frameTime := start
+	for _, frame := range scene.frames {
+		buckets = append(buckets, frame.encode(frameTime))
+		frameTime = frameTime.Plus(frame.displayTime)
+	}
+	return buckets
+}
+
+// LowResFrame contains the compressed information of a low-resolution picture in a scene.
+type LowResFrame struct {
+	boundingBox [4]uint16
+	packed      []byte
+	displayTime format.Timestamp
+}
+
+// WithDisplayTime returns a new instance with the given display time set.
+func (frame LowResFrame) WithDisplayTime(displayTime time.Duration) LowResFrame {
+	newFrame := frame
+	newFrame.displayTime = format.TimestampFromDuration(displayTime)
+	return newFrame
+}
+
+func (frame LowResFrame) decode(frameBuffer []byte, width, height int) (err error) {
+	left := int(frame.boundingBox[lowResBoundingBoxLeft])
+	top := int(frame.boundingBox[lowResBoundingBoxTop])
+	right := int(frame.boundingBox[lowResBoundingBoxRight])
+	bottom := int(frame.boundingBox[lowResBoundingBoxBottom])
+	if (left > right) || (top > bottom) || (right > width) || (bottom > height) {
+		return errInvalidBoundingBox
+	}
+	areaWidth := right - left
+	area := make([]byte, 0, areaWidth*(bottom-top))
+	for row := top; row < bottom; row++ {
+		area = append(area, frameBuffer[row*width+left:row*width+right]...)
+	}
+	defer func() {
+		// The decompressor does not verify the data against the output buffer.
+		if recover() != nil {
+			err = errInvalidPackedFrame
+		}
+	}()
+	err = rle.Decompress(bytes.NewReader(frame.packed), area)
+	if err != nil {
+		return err
+	}
+	for row := top; row < bottom; row++ {
+		copy(frameBuffer[row*width+left:row*width+right], area[(row-top)*areaWidth:(row-top+1)*areaWidth])
+	}
+	return nil
+}
+
+func (frame LowResFrame) encode(start format.Timestamp) format.EntryBucket {
+	return format.EntryBucket{
+		Priority:  format.EntryBucketPriorityFrame,
+		Timestamp: start,
+		Entries: []format.Entry{
+			{
+				Timestamp: start,
+				Data: format.LowResVideoEntryData{
+					BoundingBox: frame.boundingBox,
+					Packed:      frame.packed,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package movie_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+)
+
+func TestLowResSceneRoundTrip(t *testing.T) {
+	width, height := 8, 4
+	first := make([]byte, width*height)
+	for index := range first {
+		first[index] = byte(index)
+	}
+	second := append([]byte{}, first...)
+	second[2*width+3] = 0xAA
+	second[3*width+5] = 0xBB
+	third := append([]byte{}, second...)
+
+	var palette bitmap.Palette
+	palette[1] = bitmap.RGB{Red: 10, Green: 20, Blue: 30}
+	scene := movie.Scene{
+		Palette: palette,
+		Frames: []movie.Frame{
+			{Pixels: first, DisplayTime: 100 * time.Millisecond},
+			{Pixels: second, DisplayTime: 200 * time.Millisecond},
+			{Pixels: third, DisplayTime: 300 * time.Millisecond},
+		},
+	}
+	compressed, err := movie.LowResSceneFrom(context.Background(), scene, width, height)
+	require.Nil(t, err, "no error expected compressing")
+
+	var container movie.Container
+	container.Video.Width = uint16(width)
+	container.Video.Height = uint16(height)
+	container.Video.Scenes = []movie.CompressedScene{compressed, compressed}
+	buffer := bytes.NewBuffer(nil)
+	err = movie.Write(buffer, container, text.DefaultCodepage())
+	require.Nil(t, err, "no error expected writing")
+
+	result, err := movie.Read(bytes.NewReader(buffer.Bytes()), text.DefaultCodepage())
+	require.Nil(t, err, "no error expected reading")
+	require.Equal(t, 2, len(result.Video.Scenes), "scene count mismatch")
+	assert.Equal(t, palette, result.Video.Scenes[1].Palette(), "palette mismatch")
+
+	scenes, err := result.Video.Decompress()
+	require.Nil(t, err, "no error expected decompressing")
+	require.Equal(t, 2, len(scenes))
+	for _, decompressed := range scenes {
+		require.Equal(t, 3, len(decompressed.Frames))
+		assert.Equal(t, first, decompressed.Frames[0].Pixels, "first frame mismatch")
+		assert.Equal(t, second, decompressed.Frames[1].Pixels, "second frame mismatch")
+		assert.Equal(t, third, decompressed.Frames[2].Pixels, "third frame mismatch")
+	}
+}
+
+func TestLowResSceneFromRejectsWrongFrameSize(t *testing.T) {
+	scene := movie.Scene{Frames: []movie.Frame{{Pixels: make([]byte, 10)}}}
+	_, err := movie.LowResSceneFrom(context.Background(), scene, 4, 4)
+	assert.NotNil(t, err)
+}
@@ -15,9 +15,8 @@ import (
 )
 
 const (
-	errSourceIsNil             ss1.StringError = "source is nil"
-	errInvalidFormat           ss1.StringError = "not a MOVI format"
-	errLowResVideoNotSupported ss1.StringError = "low-res video not supported"
+	errSourceIsNil   ss1.StringError = "source is nil"
+	errInvalidFormat ss1.StringError = "not a MOVI format"
 )
 
 // Read tries to extract a MOVI container from the provided reader.
@@ -139,20 +138,31 @@ func parseEntries(entries []format.Entry, container *Container,
 	var paletteLookup []byte
 	var controlDictionary []compression.ControlWord
 	var highResScene *HighResScene
+	var lowResScene *LowResScene
 	sceneChanging := true
 	finishScene := func() {
 		if highResScene != nil {
 			container.Video.Scenes = append(container.Video.Scenes, *highResScene)
 		}
+		if lowResScene != nil {
+			container.Video.Scenes = append(container.Video.Scenes, *lowResScene)
+		}
 		highResScene = nil
+		lowResScene = nil
 	}
-	var frame *HighResFrame
+	var highResFrame *HighResFrame
+	var lowResFrame *LowResFrame
 	finishFrame := func(timestamp format.Timestamp) {
-		if frame != nil {
-			frame.displayTime = timestamp.Minus(frame.displayTime)
-			highResScene.frames = append(highResScene.frames, *frame)
+		if highResFrame != nil {
+			highResFrame.displayTime = timestamp.Minus(highResFrame.displayTime)
+			highResScene.frames = append(highResScene.frames, *highResFrame)
+		}
+		if lowResFrame != nil {
+			lowResFrame.displayTime = timestamp.Minus(lowResFrame.displayTime)
+			lowResScene.frames = append(lowResScene.frames, *lowResFrame)
 		}
-		frame = nil
+		highResFrame = nil
+		lowResFrame = nil
 	}
 	for _, entry := range entries {
 		switch data := entry.Data.(type) {
@@ -171,8 +181,17 @@ func parseEntries(entries []format.Entry, container *Container,
 			default:
 			}
 		case format.LowResVideoEntryData:
-			// ignored for now
-			return errLowResVideoNotSupported
+			finishFrame(entry.Timestamp)
+			if sceneChanging || (lowResScene == nil) {
+				finishScene()
+				lowResScene = &LowResScene{palette: palette}
+				sceneChanging = false
+			}
+			lowResFrame = &LowResFrame{
+				boundingBox: data.BoundingBox,
+				packed:      data.Packed,
+				displayTime: entry.Timestamp,
+			}
 		case format.PaletteLookupEntryData:
 			sceneChanging = true
 			paletteLookup = data.List
@@ -187,7 +206,7 @@ func parseEntries(entries []format.Entry, container *Container,
 			palette = data.Colors
 		case format.HighResVideoEntryData:
 			finishFrame(entry.Timestamp)
-			if sceneChanging {
+			if sceneChanging || (highResScene == nil) {
 				finishScene()
 				highResScene = &HighResScene{
 					palette:       palette,
@@ -196,7 +215,7 @@ func parseEntries(entries []format.Entry, container *Container,
 				}
 				sceneChanging = false
 			}
-			frame = &HighResFrame{
+			highResFrame = &HighResFrame{
 				bitstream:   data.Bitstream,
 				maskstream:  data.Maskstream,
 				displayTime: entry.Timestamp,
@@ -0,0 +1,130 @@
+package movie
+
+import (
+	"strings"
+	"time"
+)
+
+func (sub SubtitleList) copied() SubtitleList {
+	return SubtitleList{Entries: append([]Subtitle{}, sub.Entries...)}
+}
+
+func (sub SubtitleList) validIndex(index int) bool {
+	return (index >= 0) && (index < len(sub.Entries))
+}
+
+// EndOf returns the time at which the entry with given index is replaced by the next one.
+// For the last entry, this is the given end of the movie, yet not before the start of the entry.
+func (sub SubtitleList) EndOf(index int, movieEnd time.Duration) time.Duration {
+	if index+1 < len(sub.Entries) {
+		return sub.Entries[index+1].Timestamp
+	}
+	if sub.validIndex(index) && (movieEnd < sub.Entries[index].Timestamp) {
+		return sub.Entries[index].Timestamp
+	}
+	return movieEnd
+}
+
+// WithTimestamp returns a list with the entry of given index moved to the given time.
+// The time is limited to the range of the neighbouring entries, so that the order is kept.
+func (sub SubtitleList) WithTimestamp(index int, timestamp time.Duration) SubtitleList {
+	if !sub.validIndex(index) {
+		return sub
+	}
+	if (index > 0) && (timestamp < sub.Entries[index-1].Timestamp) {
+		timestamp = sub.Entries[index-1].Timestamp
+	}
+	if (index+1 < len(sub.Entries)) && (timestamp > sub.Entries[index+1].Timestamp) {
+		timestamp = sub.Entries[index+1].Timestamp
+	}
+	if timestamp < 0 {
+		timestamp = 0
+	}
+	result := sub.copied()
+	result.Entries[index].Timestamp = timestamp
+	return result
+}
+
+// WithText returns a list with the text of the entry of given index replaced.
+func (sub SubtitleList) WithText(index int, text string) SubtitleList {
+	if !sub.validIndex(index) {
+		return sub
+	}
+	result := sub.copied()
+	result.Entries[index].Text = text
+	return result
+}
+
+// Insert returns a list with a new entry at the given time, placed after any entry of the same time.
+// The second return value is the index of the new entry.
+func (sub SubtitleList) Insert(timestamp time.Duration, text string) (SubtitleList, int) {
+	index := 0
+	for (index < len(sub.Entries)) && (sub.Entries[index].Timestamp <= timestamp) {
+		index++
+	}
+	result := SubtitleList{Entries: make([]Subtitle, 0, len(sub.Entries)+1)}
+	result.Entries = append(result.Entries, sub.Entries[:index]...)
+	result.Entries = append(result.Entries, Subtitle{Timestamp: timestamp, Text: text})
+	result.Entries = append(result.Entries, sub.Entries[index:]...)
+	return result, index
+}
+
+// Remove returns a list without the entry of given index.
+func (sub SubtitleList) Remove(index int) SubtitleList {
+	if !sub.validIndex(index) {
+		return sub
+	}
+	result := SubtitleList{Entries: make([]Subtitle, 0, len(sub.Entries)-1)}
+	result.Entries = append(result.Entries, sub.Entries[:index]...)
+	result.Entries = append(result.Entries, sub.Entries[index+1:]...)
+	return result
+}
+
+// Split returns a list with the entry of given index divided into two at the middle of its time span.
+// Texts with several lines are divided by their lines, single lines at the space closest to their middle.
+func (sub SubtitleList) Split(index int, movieEnd time.Duration) SubtitleList {
+	if !sub.validIndex(index) {
+		return sub
+	}
+	entry := sub.Entries[index]
+	middle := entry.Timestamp + (sub.EndOf(index, movieEnd)-entry.Timestamp)/2
+	first, second := splitText(entry.Text)
+	result, _ := sub.WithText(index, first).Insert(middle, second)
+	return result
+}
+
+func splitText(text string) (first, second string) {
+	lines := strings.Split(text, "\n")
+	if len(lines) > 1 {
+		half := (len(lines) + 1) / 2
+		return strings.Join(lines[:half], "\n"), strings.Join(lines[half:], "\n")
+	}
+	runes := []rune(text)
+	center := len(runes) / 2
+	splitAt := -1
+	for offset := 0; (offset <= center) && (splitAt < 0); offset++ {
+		if (center+offset < len(runes)) && (runes[center+offset] == ' ') {
+			splitAt = center + offset
+		} else if (center-offset >= 0) && (center-offset < len(runes)) && (runes[center-offset] == ' ') {
+			splitAt = center - offset
+		}
+	}
+	if splitAt < 0 {
+		return text, ""
+	}
+	return string(runes[:splitAt]), string(runes[splitAt+1:])
+}
+
+// Merge returns a list with the entry of given index combined with its successor.
+// The texts are joined as separate lines, and the combined entry keeps the earlier time.
+func (sub SubtitleList) Merge(index int) SubtitleList {
+	if !sub.validIndex(index) || !sub.validIndex(index+1) {
+		return sub
+	}
+	first, second := sub.Entries[index].Text, sub.Entries[index+1].Text
+	text := first + "\n" + second
+	if (len(first) == 0) || (len(second) == 0) {
+		text = first + second
+	}
+	return sub.WithText(index, text).Remove(index + 1)
+}
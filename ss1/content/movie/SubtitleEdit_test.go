@@ -0,0 +1,85 @@
+package movie_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+)
+
+func someSubtitles() movie.SubtitleList {
+	return movie.SubtitleList{Entries: []movie.Subtitle{
+		{Timestamp: 1 * time.Second, Text: "one"},
+		{Timestamp: 3 * time.Second, Text: "two"},
+		{Timestamp: 5 * time.Second, Text: ""},
+	}}
+}
+
+func TestSubtitleListEndOf(t *testing.T) {
+	sub := someSubtitles()
+
+	assert.Equal(t, 3*time.Second, sub.EndOf(0, 10*time.Second), "next entry")
+	assert.Equal(t, 10*time.Second, sub.EndOf(2, 10*time.Second), "movie end")
+	assert.Equal(t, 5*time.Second, sub.EndOf(2, 4*time.Second), "not before start")
+}
+
+func TestSubtitleListWithTimestampKeepsOrder(t *testing.T) {
+	sub := someSubtitles()
+
+	assert.Equal(t, 2*time.Second, sub.WithTimestamp(1, 2*time.Second).Entries[1].Timestamp, "free move")
+	assert.Equal(t, 1*time.Second, sub.WithTimestamp(1, 0).Entries[1].Timestamp, "limited by previous")
+	assert.Equal(t, 5*time.Second, sub.WithTimestamp(1, 7*time.Second).Entries[1].Timestamp, "limited by next")
+	assert.Equal(t, time.Duration(0), sub.WithTimestamp(0, -time.Second).Entries[0].Timestamp, "not negative")
+	assert.Equal(t, 3*time.Second, sub.Entries[1].Timestamp, "original must not be modified")
+}
+
+func TestSubtitleListInsert(t *testing.T) {
+	result, index := someSubtitles().Insert(3*time.Second, "new")
+
+	assert.Equal(t, 2, index)
+	assert.Equal(t, []string{"one", "two", "new", ""}, subtitleTexts(result))
+}
+
+func TestSubtitleListRemove(t *testing.T) {
+	result := someSubtitles().Remove(1)
+
+	assert.Equal(t, []string{"one", ""}, subtitleTexts(result))
+}
+
+func TestSubtitleListSplitDividesTimeAndLines(t *testing.T) {
+	sub := movie.SubtitleList{Entries: []movie.Subtitle{
+		{Timestamp: 1 * time.Second, Text: "first\nsecond\nthird"},
+	}}
+	result := sub.Split(0, 5*time.Second)
+
+	assert.Equal(t, []string{"first\nsecond", "third"}, subtitleTexts(result))
+	assert.Equal(t, 3*time.Second, result.Entries[1].Timestamp)
+}
+
+func TestSubtitleListSplitDividesSingleLineAtCenterSpace(t *testing.T) {
+	sub := movie.SubtitleList{Entries: []movie.Subtitle{
+		{Timestamp: 0, Text: "a short line of text"},
+		{Timestamp: 2 * time.Second, Text: "unbroken"},
+	}}
+	result := sub.Split(0, 0).Split(2, 4*time.Second)
+
+	assert.Equal(t, []string{"a short line", "of text", "unbroken", ""}, subtitleTexts(result))
+	assert.Equal(t, time.Second, result.Entries[1].Timestamp)
+}
+
+func TestSubtitleListMerge(t *testing.T) {
+	result := someSubtitles().Merge(0).Merge(0)
+
+	assert.Equal(t, []string{"one\ntwo"}, subtitleTexts(result))
+	assert.Equal(t, 1*time.Second, result.Entries[0].Timestamp)
+}
+
+func subtitleTexts(sub movie.SubtitleList) []string {
+	texts := make([]string, 0, len(sub.Entries))
+	for _, entry := range sub.Entries {
+		texts = append(texts, entry.Text)
+	}
+	return texts
+}
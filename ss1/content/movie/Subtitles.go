@@ -42,14 +42,14 @@ func (sub Subtitles) duration() format.Timestamp {
 	return format.TimestampFromDuration(highest)
 }
 
-func (sub Subtitles) encode(cp text.Codepage) [][]format.EntryBucket {
+func (sub Subtitles) encode(cp text.Codepage, area string) [][]format.EntryBucket {
 	if !sub.ArePresent() {
 		return nil
 	}
 	bucketsPerLanguage := make([][]format.EntryBucket, len(sub.PerLanguage)+1)
 
 	// Ensure a subtitle area is defined.
-	// The area is hardcoded. While the engine respects any area, placing the text in the
+	// The area is hardcoded per resolution. While the engine respects any area, placing the text in the
 	// frame area will have the pixels become overwritten. As such, there are many "wrong" options,
 	// and only a few right ones. There's no need to make them editable.
 	bucketsPerLanguage[0] = []format.EntryBucket{{
@@ -59,7 +59,7 @@ func (sub Subtitles) encode(cp text.Codepage) [][]format.EntryBucket {
 			Timestamp: format.Timestamp{},
 			Data: format.SubtitleEntryData{
 				Control: format.SubtitleArea,
-				Text:    cp.Encode("20 365 620 395 CLR"),
+				Text:    cp.Encode(area),
 			},
 		}},
 	}}
@@ -69,6 +69,13 @@ func (sub Subtitles) encode(cp text.Codepage) [][]format.EntryBucket {
 	return bucketsPerLanguage
 }
 
+func subtitleAreaFor(videoWidth int) string {
+	if (videoWidth > 0) && (videoWidth <= LowResDefaultWidth) {
+		return "10 182 310 197 CLR"
+	}
+	return "20 365 620 395 CLR"
+}
+
 // SubtitleList describes the textual representation of a movie in one language.
 type SubtitleList struct {
 	Entries []Subtitle
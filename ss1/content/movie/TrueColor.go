@@ -0,0 +1,150 @@
+package movie
+
+import (
+	"context"
+	"image"
+	"math"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+)
+
+// DefaultSceneCutThreshold is the recommended threshold for SplitScenes.
+const DefaultSceneCutThreshold = 0.4
+
+const sceneCutHistogramBins = 4
+
+// SourceFrame is a truecolour picture to be imported into a movie.
+type SourceFrame struct {
+	Image       image.Image
+	DisplayTime time.Duration
+}
+
+// SceneImportOptions describe how truecolour frames are converted to scenes.
+type SceneImportOptions struct {
+	// SceneCutThreshold is the difference between two frames, in range [0.0, 1.0], from which on
+	// a new scene is started. A value of 1.0 or greater keeps all frames in one scene.
+	SceneCutThreshold float64
+	// Dithering specifies how the frames are mapped to the palette of their scene.
+	Dithering bitmap.DitherMode
+}
+
+// DefaultSceneImportOptions returns the recommended options.
+func DefaultSceneImportOptions() SceneImportOptions {
+	return SceneImportOptions{
+		SceneCutThreshold: DefaultSceneCutThreshold,
+		Dithering:         bitmap.DitherFloydSteinberg,
+	}
+}
+
+// SceneBackgroundColor is the color for palette index 0x00 of generated scenes.
+var SceneBackgroundColor = bitmap.RGB{Red: 0x00, Green: 0x00, Blue: 0x00}
+
+// SceneSubtitleColor is the color for palette index 0xFF of generated scenes, used for subtitles.
+var SceneSubtitleColor = bitmap.RGB{Red: 0x9A, Green: 0x35, Blue: 0x35}
+
+// SceneColorIndices returns the palette indices that generated scenes may use for their pictures.
+// Index 0x00 is reserved for the background, index 0xFF for subtitles.
+func SceneColorIndices() bitmap.IndexSet {
+	return bitmap.IndexRange(0x01, 0xFE)
+}
+
+// ScenesFromTrueColor splits the given frames into scenes and converts each into paletted frames.
+// Every scene receives its own generated palette.
+// All frames must have the given size.
+func ScenesFromTrueColor(ctx context.Context, frames []SourceFrame, width, height int,
+	options SceneImportOptions) ([]Scene, error) {
+	for _, frame := range frames {
+		bounds := frame.Image.Bounds()
+		if (bounds.Dx() != width) || (bounds.Dy() != height) {
+			return nil, errFrameSizeMismatch
+		}
+	}
+	var scenes []Scene
+	for _, sceneFrames := range SplitScenes(frames, options.SceneCutThreshold) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		scenes = append(scenes, sceneFromTrueColor(sceneFrames, options.Dithering))
+	}
+	return scenes, nil
+}
+
+func sceneFromTrueColor(frames []SourceFrame, dithering bitmap.DitherMode) Scene {
+	images := make([]image.Image, len(frames))
+	for index, frame := range frames {
+		images[index] = frame.Image
+	}
+	var base bitmap.Palette
+	base[0x00] = SceneBackgroundColor
+	base[0xFF] = SceneSubtitleColor
+	palette := bitmap.GeneratePalette(images, SceneColorIndices(), base)
+	bitmapper := bitmap.NewBitmapperWithOptions(&palette, bitmap.MapOptions{
+		Dithering: dithering,
+		Indices:   SceneColorIndices(),
+	})
+	scene := Scene{
+		Palette: palette,
+		Frames:  make([]Frame, len(frames)),
+	}
+	for index, frame := range frames {
+		scene.Frames[index] = Frame{
+			Pixels:      bitmapper.Map(frame.Image).Pixels,
+			DisplayTime: frame.DisplayTime,
+		}
+	}
+	return scene
+}
+
+// SplitScenes groups the given frames into scenes.
+// A new scene is started when the color distribution of a frame differs from its predecessor
+// by at least the given threshold. The difference is in the range of [0.0, 1.0].
+func SplitScenes(frames []SourceFrame, threshold float64) [][]SourceFrame {
+	var scenes [][]SourceFrame
+	var current []SourceFrame
+	var lastHistogram []float64
+	for _, frame := range frames {
+		histogram := colorDistribution(frame.Image)
+		if (lastHistogram != nil) && (histogramDifference(lastHistogram, histogram) >= threshold) {
+			scenes = append(scenes, current)
+			current = nil
+		}
+		current = append(current, frame)
+		lastHistogram = histogram
+	}
+	if len(current) > 0 {
+		scenes = append(scenes, current)
+	}
+	return scenes
+}
+
+// colorDistribution returns a coarse, normalized RGB histogram of the given image.
+func colorDistribution(img image.Image) []float64 {
+	histogram := make([]float64, sceneCutHistogramBins*sceneCutHistogramBins*sceneCutHistogramBins)
+	bounds := img.Bounds()
+	binOf := func(value uint32) int {
+		return int(value>>8) * sceneCutHistogramBins / 256
+	}
+	total := 0.0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			histogram[(binOf(r)*sceneCutHistogramBins+binOf(g))*sceneCutHistogramBins+binOf(b)]++
+			total++
+		}
+	}
+	if total > 0 {
+		for index := range histogram {
+			histogram[index] /= total
+		}
+	}
+	return histogram
+}
+
+func histogramDifference(a, b []float64) float64 {
+	sum := 0.0
+	for index := range a {
+		sum += math.Abs(a[index] - b[index])
+	}
+	return sum / 2
+}
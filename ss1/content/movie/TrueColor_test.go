@@ -0,0 +1,84 @@
+package movie_test
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/bitmap"
+	"github.com/inkyblackness/hacked/ss1/content/movie"
+)
+
+func uniformSourceFrame(width, height int, clr color.Color) movie.SourceFrame {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, clr)
+		}
+	}
+	return movie.SourceFrame{Image: img, DisplayTime: 100 * time.Millisecond}
+}
+
+func TestSplitScenesStartsNewSceneOnCut(t *testing.T) {
+	red := color.RGBA{R: 0xFF, A: 0xFF}
+	blue := color.RGBA{B: 0xFF, A: 0xFF}
+	frames := []movie.SourceFrame{
+		uniformSourceFrame(4, 4, red),
+		uniformSourceFrame(4, 4, red),
+		uniformSourceFrame(4, 4, blue),
+	}
+
+	scenes := movie.SplitScenes(frames, movie.DefaultSceneCutThreshold)
+
+	require.Equal(t, 2, len(scenes))
+	assert.Equal(t, 2, len(scenes[0]))
+	assert.Equal(t, 1, len(scenes[1]))
+}
+
+func TestSplitScenesKeepsAllFramesForHighThreshold(t *testing.T) {
+	frames := []movie.SourceFrame{
+		uniformSourceFrame(4, 4, color.RGBA{R: 0xFF, A: 0xFF}),
+		uniformSourceFrame(4, 4, color.RGBA{B: 0xFF, A: 0xFF}),
+	}
+
+	scenes := movie.SplitScenes(frames, 1.1)
+
+	assert.Equal(t, 1, len(scenes))
+}
+
+func TestScenesFromTrueColorRespectsReservedIndices(t *testing.T) {
+	frames := []movie.SourceFrame{
+		uniformSourceFrame(4, 2, color.RGBA{A: 0xFF}),
+		uniformSourceFrame(4, 2, color.RGBA{R: 0x9A, G: 0x35, B: 0x35, A: 0xFF}),
+	}
+	options := movie.SceneImportOptions{SceneCutThreshold: 1.1, Dithering: bitmap.DitherNone}
+
+	scenes, err := movie.ScenesFromTrueColor(context.Background(), frames, 4, 2, options)
+
+	require.Nil(t, err)
+	require.Equal(t, 1, len(scenes))
+	scene := scenes[0]
+	assert.Equal(t, movie.SceneBackgroundColor, scene.Palette[0x00])
+	assert.Equal(t, movie.SceneSubtitleColor, scene.Palette[0xFF])
+	require.Equal(t, 2, len(scene.Frames))
+	for _, frame := range scene.Frames {
+		require.Equal(t, 8, len(frame.Pixels))
+		for _, pixel := range frame.Pixels {
+			assert.True(t, (pixel != 0x00) && (pixel != 0xFF), "reserved index used")
+		}
+		assert.Equal(t, 100*time.Millisecond, frame.DisplayTime)
+	}
+}
+
+func TestScenesFromTrueColorRejectsWrongSize(t *testing.T) {
+	frames := []movie.SourceFrame{uniformSourceFrame(4, 2, color.RGBA{A: 0xFF})}
+
+	_, err := movie.ScenesFromTrueColor(context.Background(), frames, 8, 2, movie.DefaultSceneImportOptions())
+
+	assert.NotNil(t, err)
+}
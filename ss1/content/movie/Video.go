@@ -13,8 +13,23 @@ import (
 const (
 	HighResDefaultWidth  = 600
 	HighResDefaultHeight = 300
+
+	LowResDefaultWidth  = 300
+	LowResDefaultHeight = 150
 )
 
+// CompressedScene is a set of frames, compressed with one of the supported methods.
+type CompressedScene interface {
+	// Palette returns the palette of the scene.
+	Palette() bitmap.Palette
+	// WithFrameDisplayTime returns a new scene instance with the given display time set for all frames.
+	WithFrameDisplayTime(displayTime time.Duration) CompressedScene
+
+	duration() format.Timestamp
+	encode(start format.Timestamp, withPalette bool) []format.EntryBucket
+	decompress(frameBuffer []byte, width, height int) ([]Frame, error)
+}
+
 // Video describes the visual part of a movie.
 type Video struct {
 	// Width is the width of the video in pixel.
@@ -22,7 +37,7 @@ type Video struct {
 	// Height is the height of the video in pixel.
 	Height uint16
 	// Scenes contain the frames of the video.
-	Scenes []HighResScene
+	Scenes []CompressedScene
 }
 
 // StartPalette returns the palette of the first scene. If no scene is present, a black palette is returned.
@@ -30,7 +45,7 @@ func (video Video) StartPalette() bitmap.Palette {
 	if len(video.Scenes) == 0 {
 		return bitmap.Palette{}
 	}
-	return video.Scenes[0].palette
+	return video.Scenes[0].Palette()
 }
 
 func (video Video) duration() format.Timestamp {
@@ -57,36 +72,24 @@ func (video Video) Decompress() ([]Scene, error) {
 	width := int(video.Width)
 	height := int(video.Height)
 	frameBuffer := make([]byte, width*height)
-	decoderBuilder := compression.NewFrameDecoderBuilder(width, height)
-	decoderBuilder.ForStandardFrame(frameBuffer, width)
-
-	cloneFramebuffer := func() []byte {
-		bufferCopy := make([]byte, len(frameBuffer))
-		copy(bufferCopy, frameBuffer)
-		return bufferCopy
-	}
 
 	for _, compressedScene := range video.Scenes {
-		decoderBuilder.WithControlWords(compressedScene.controlWords)
-		decoderBuilder.WithPaletteLookupList(compressedScene.paletteLookup)
-		decoder := decoderBuilder.Build()
-		var scene Scene
-		scene.Palette = compressedScene.palette
-		for _, compressedFrame := range compressedScene.frames {
-			err := decoder.Decode(compressedFrame.bitstream, compressedFrame.maskstream)
-			if err != nil {
-				return nil, err
-			}
-			scene.Frames = append(scene.Frames, Frame{
-				Pixels:      cloneFramebuffer(),
-				DisplayTime: compressedFrame.displayTime.ToDuration(),
-			})
+		frames, err := compressedScene.decompress(frameBuffer, width, height)
+		if err != nil {
+			return nil, err
 		}
-		scenes = append(scenes, scene)
+		scenes = append(scenes, Scene{Palette: compressedScene.Palette(), Frames: frames})
 	}
 	return scenes, nil
 }
 
+// cloneFrameBuffer returns a copy of the given buffer.
+func cloneFrameBuffer(frameBuffer []byte) []byte {
+	bufferCopy := make([]byte, len(frameBuffer))
+	copy(bufferCopy, frameBuffer)
+	return bufferCopy
+}
+
 // HighResScene is a set of frames with high-resolution compression.
 type HighResScene struct {
 	palette       bitmap.Palette
@@ -97,6 +100,13 @@ type HighResScene struct {
 
 // HighResSceneFrom compresses given scene and returns the compression result.
 func HighResSceneFrom(ctx context.Context, scene Scene, width, height int) (HighResScene, error) {
+	return HighResSceneFromWithProgress(ctx, scene, width, height, nil)
+}
+
+// HighResSceneFromWithProgress compresses given scene and returns the compression result.
+// The given function, if not nil, is called with the progress of the compression.
+func HighResSceneFromWithProgress(ctx context.Context, scene Scene, width, height int,
+	progress EncodingProgressFunc) (HighResScene, error) {
 	encoder := compression.NewSceneEncoder(width, height)
 	for _, frame := range scene.Frames {
 		err := encoder.AddFrame(frame.Pixels)
@@ -107,7 +117,7 @@ func HighResSceneFrom(ctx context.Context, scene Scene, width, height int) (High
 			return HighResScene{}, ctx.Err()
 		}
 	}
-	words, paletteLookup, frames, err := encoder.Encode(ctx)
+	words, paletteLookup, frames, err := encoder.EncodeWithProgress(ctx, highResProgress(progress))
 	if err != nil {
 		return HighResScene{}, err
 	}
@@ -128,8 +138,13 @@ func HighResSceneFrom(ctx context.Context, scene Scene, width, height int) (High
 	return compressedScene, nil
 }
 
+// Palette returns the palette of the scene.
+func (scene HighResScene) Palette() bitmap.Palette {
+	return scene.palette
+}
+
 // WithFrameDisplayTime returns a new scene instance with the given display time set for all frames.
-func (scene HighResScene) WithFrameDisplayTime(displayTime time.Duration) HighResScene {
+func (scene HighResScene) WithFrameDisplayTime(displayTime time.Duration) CompressedScene {
 	newScene := scene
 	newFrames := make([]HighResFrame, len(scene.frames))
 	for index, frame := range scene.frames {
@@ -147,6 +162,26 @@ func (scene HighResScene) duration() format.Timestamp {
 	return sum
 }
 
+func (scene HighResScene) decompress(frameBuffer []byte, width, height int) ([]Frame, error) {
+	decoderBuilder := compression.NewFrameDecoderBuilder(width, height)
+	decoderBuilder.ForStandardFrame(frameBuffer, width)
+	decoderBuilder.WithControlWords(scene.controlWords)
+	decoderBuilder.WithPaletteLookupList(scene.paletteLookup)
+	decoder := decoderBuilder.Build()
+	frames := make([]Frame, 0, len(scene.frames))
+	for _, compressedFrame := range scene.frames {
+		err := decoder.Decode(compressedFrame.bitstream, compressedFrame.maskstream)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, Frame{
+			Pixels:      cloneFrameBuffer(frameBuffer),
+			DisplayTime: compressedFrame.displayTime.ToDuration(),
+		})
+	}
+	return frames, nil
+}
+
 func (scene HighResScene) encode(start format.Timestamp, withPalette bool) []format.EntryBucket {
 	buckets := make([]format.EntryBucket, 0, len(scene.frames)+1)
 	controlEntries := []format.Entry{
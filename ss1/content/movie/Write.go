@@ -38,7 +38,7 @@ func Write(dest io.Writer, container Container, cp text.Codepage) error {
 	var buckets []format.EntryBucket
 	buckets = append(buckets, container.Audio.encode()...)
 	buckets = append(buckets, container.Video.encode()...)
-	subtitleBucketsList := container.Subtitles.encode(cp)
+	subtitleBucketsList := container.Subtitles.encode(cp, subtitleAreaFor(int(container.Video.Width)))
 	for _, subtitleBuckets := range subtitleBucketsList {
 		buckets = append(buckets, subtitleBuckets...)
 	}
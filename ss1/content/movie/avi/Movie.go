@@ -0,0 +1,50 @@
+package avi
+
+import (
+	"image"
+	"time"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+)
+
+// DefaultFrameRate is the frame rate used for movies that do not specify one.
+const DefaultFrameRate = 30
+
+// Movie describes the content of an AVI file.
+type Movie struct {
+	// Width is the width of the video in pixel.
+	Width int
+	// Height is the height of the video in pixel.
+	Height int
+	// FrameRate is the constant rate, in frames per second, of the video stream.
+	// Frames are held or skipped to fit their display times into this rate.
+	FrameRate int
+	// Quality is the JPEG quality for the frames, ranging from 1 to 100. Zero selects the default quality.
+	Quality int
+
+	// Frames are the images of the video. Without frames, the file will only contain audio and subtitles.
+	Frames []Frame
+	// Audio is the sound track. It is omitted if empty.
+	Audio audio.L8
+	// Subtitles are the subtitle tracks, typically one per language.
+	Subtitles []SubtitleTrack
+}
+
+// Frame is one image of a video, with the time it shall be displayed.
+type Frame struct {
+	Image       image.Image
+	DisplayTime time.Duration
+}
+
+// SubtitleTrack is a named list of subtitles.
+type SubtitleTrack struct {
+	Name    string
+	Entries []Subtitle
+}
+
+// Subtitle is a text that is displayed for a span of time.
+type Subtitle struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
@@ -0,0 +1,241 @@
+package avi
+
+import (
+	"fmt"
+	"image/jpeg"
+	"io"
+	"time"
+	"unicode/utf16"
+
+	"github.com/inkyblackness/hacked/ss1"
+)
+
+const errNoContent ss1.StringError = "movie has no content"
+
+// streamInfo collects the properties of a stream while its chunks are written.
+type streamInfo struct {
+	id           string
+	maxChunkSize uint32
+	header       streamHeader
+	formatData   []byte
+	nameData     []byte
+}
+
+// Write serializes the given movie as an AVI file into the writer.
+func Write(writer io.Writer, movie Movie) error {
+	frameRate := movie.FrameRate
+	if frameRate <= 0 {
+		frameRate = DefaultFrameRate
+	}
+	quality := movie.Quality
+	if quality <= 0 {
+		quality = jpeg.DefaultQuality
+	}
+	schedule := frameSchedule(movie.Frames, frameRate)
+	sampleRate := int(movie.Audio.SampleRate)
+	hasAudio := !movie.Audio.Empty() && (sampleRate > 0)
+	totalTicks := len(schedule)
+	if hasAudio {
+		audioTicks := (len(movie.Audio.Samples)*frameRate + sampleRate - 1) / sampleRate
+		if totalTicks < audioTicks {
+			totalTicks = audioTicks
+		}
+	}
+	if (totalTicks == 0) && (len(movie.Subtitles) == 0) {
+		return errNoContent
+	}
+
+	var streams []*streamInfo
+	newStream := func(suffix string) *streamInfo {
+		stream := &streamInfo{id: fmt.Sprintf("%02d%s", len(streams), suffix)}
+		streams = append(streams, stream)
+		return stream
+	}
+	var video, sound *streamInfo
+	if len(schedule) > 0 {
+		video = newStream("dc")
+		video.header = streamHeader{
+			Type:    fourCC("vids"),
+			Handler: fourCC("MJPG"),
+			Scale:   1,
+			Rate:    uint32(frameRate),
+			Length:  uint32(totalTicks),
+			Quality: 0xFFFFFFFF,
+			Frame:   [4]int16{0, 0, int16(movie.Width), int16(movie.Height)},
+		}
+		video.formatData = structData(&bitmapInfoHeader{
+			Size:        40,
+			Width:       int32(movie.Width),
+			Height:      int32(movie.Height),
+			Planes:      1,
+			BitCount:    24,
+			Compression: fourCC("MJPG"),
+			SizeImage:   uint32(movie.Width * movie.Height * 3),
+		})
+	}
+	if hasAudio {
+		sound = newStream("wb")
+		sound.header = streamHeader{
+			Type:       fourCC("auds"),
+			Scale:      1,
+			Rate:       uint32(sampleRate),
+			Length:     uint32(len(movie.Audio.Samples)),
+			Quality:    0xFFFFFFFF,
+			SampleSize: 1,
+		}
+		sound.formatData = structData(&waveFormat{
+			FormatTag:      waveFormatPCM,
+			Channels:       1,
+			SamplesPerSec:  uint32(sampleRate),
+			AvgBytesPerSec: uint32(sampleRate),
+			BlockAlign:     1,
+			BitsPerSample:  8,
+		})
+	}
+	subtitles := make([]*streamInfo, len(movie.Subtitles))
+	for index, track := range movie.Subtitles {
+		stream := newStream("sb")
+		stream.header = streamHeader{
+			Type:    fourCC("txts"),
+			Scale:   1,
+			Rate:    1,
+			Length:  1,
+			Quality: 0xFFFFFFFF,
+		}
+		stream.nameData = append([]byte(track.Name), 0x00)
+		subtitles[index] = stream
+	}
+
+	var movi riffBuffer
+	var index []indexEntry
+	addChunk := func(stream *streamInfo, data []byte, flags uint32) {
+		index = append(index, indexEntry{
+			ID:     fourCC(stream.id),
+			Flags:  flags,
+			Offset: uint32(4 + movi.Len()),
+			Size:   uint32(len(data)),
+		})
+		movi.chunk(stream.id, data)
+		if stream.maxChunkSize < uint32(len(data)) {
+			stream.maxChunkSize = uint32(len(data))
+		}
+	}
+
+	for trackIndex, track := range movie.Subtitles {
+		addChunk(subtitles[trackIndex], gab2Data(track.Name, track.srt()), indexFlagKeyFrame)
+	}
+	for tick := 0; tick < totalTicks; tick++ {
+		if video != nil {
+			if (tick < len(schedule)) && (schedule[tick] >= 0) {
+				var frameData riffBuffer
+				err := jpeg.Encode(&frameData, movie.Frames[schedule[tick]].Image, &jpeg.Options{Quality: quality})
+				if err != nil {
+					return err
+				}
+				addChunk(video, frameData.Bytes(), indexFlagKeyFrame)
+			} else {
+				addChunk(video, nil, 0)
+			}
+		}
+		if sound != nil {
+			start := tick * sampleRate / frameRate
+			end := (tick + 1) * sampleRate / frameRate
+			if end > len(movie.Audio.Samples) {
+				end = len(movie.Audio.Samples)
+			}
+			if start < end {
+				addChunk(sound, movie.Audio.Samples[start:end], indexFlagKeyFrame)
+			}
+		}
+	}
+
+	var maxChunkSize uint32
+	var maxBytesPerSec uint32
+	for _, stream := range streams {
+		if maxChunkSize < stream.maxChunkSize {
+			maxChunkSize = stream.maxChunkSize
+		}
+	}
+	if video != nil {
+		maxBytesPerSec += video.maxChunkSize * uint32(frameRate)
+	}
+	if sound != nil {
+		maxBytesPerSec += uint32(sampleRate)
+	}
+	header := mainHeader{
+		MicroSecPerFrame:    uint32(time.Second / time.Microsecond / time.Duration(frameRate)),
+		MaxBytesPerSec:      maxBytesPerSec,
+		Flags:               mainHeaderFlagHasIndex | mainHeaderFlagIsInterleaved,
+		Streams:             uint32(len(streams)),
+		SuggestedBufferSize: maxChunkSize,
+		Width:               uint32(movie.Width),
+		Height:              uint32(movie.Height),
+	}
+	if video != nil {
+		header.TotalFrames = video.header.Length
+	}
+
+	var file riffBuffer
+	file.list("RIFF", "AVI ", func(content *riffBuffer) {
+		content.list("LIST", "hdrl", func(hdrl *riffBuffer) {
+			hdrl.chunk("avih", structData(&header))
+			for _, stream := range streams {
+				hdrl.list("LIST", "strl", func(strl *riffBuffer) {
+					stream.header.SuggestedBufferSize = stream.maxChunkSize
+					strl.chunk("strh", structData(&stream.header))
+					if stream.formatData != nil {
+						strl.chunk("strf", stream.formatData)
+					}
+					if stream.nameData != nil {
+						strl.chunk("strn", stream.nameData)
+					}
+				})
+			}
+		})
+		content.list("LIST", "movi", func(list *riffBuffer) {
+			list.Write(movi.Bytes())
+		})
+		content.chunk("idx1", structData(index))
+	})
+	_, err := writer.Write(file.Bytes())
+	return err
+}
+
+// frameSchedule returns the index of the frame to show for each tick of given frame rate.
+// An entry of -1 indicates that the previous frame is held.
+func frameSchedule(frames []Frame, frameRate int) []int {
+	var schedule []int
+	var start time.Duration
+	tickOf := func(at time.Duration) int {
+		return int((at*time.Duration(frameRate) + time.Second/2) / time.Second)
+	}
+	extend := func(length int) {
+		for len(schedule) < length {
+			schedule = append(schedule, -1)
+		}
+	}
+	for index, frame := range frames {
+		tick := tickOf(start)
+		extend(tick + 1)
+		schedule[tick] = index
+		start += frame.DisplayTime
+	}
+	if len(frames) > 0 {
+		extend(tickOf(start))
+	}
+	return schedule
+}
+
+// gab2Data returns the payload of a GAB2 subtitle chunk, embedding a subtitle file.
+func gab2Data(name string, content []byte) []byte {
+	var buf riffBuffer
+	nameData := utf16.Encode([]rune(name + "\x00"))
+	buf.WriteString("GAB2\x00")
+	buf.writeValue(uint16(2))
+	buf.writeValue(uint32(len(nameData) * 2))
+	buf.writeValue(nameData)
+	buf.writeValue(uint16(4))
+	buf.writeValue(uint32(len(content)))
+	buf.Write(content)
+	return buf.Bytes()
+}
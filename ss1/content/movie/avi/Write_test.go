@@ -0,0 +1,178 @@
+package avi_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio"
+	"github.com/inkyblackness/hacked/ss1/content/movie/avi"
+)
+
+type testChunk struct {
+	id       string
+	listType string
+	offset   int
+	data     []byte
+	children []testChunk
+}
+
+func parseChunks(t *testing.T, data []byte, baseOffset int) []testChunk {
+	t.Helper()
+	var chunks []testChunk
+	pos := 0
+	for pos < len(data) {
+		require.True(t, pos+8 <= len(data), "chunk header exceeds data")
+		chunk := testChunk{id: string(data[pos : pos+4]), offset: baseOffset + pos}
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		require.True(t, pos+8+size <= len(data), "chunk %v exceeds data", chunk.id)
+		chunk.data = data[pos+8 : pos+8+size]
+		if (chunk.id == "RIFF") || (chunk.id == "LIST") {
+			chunk.listType = string(chunk.data[0:4])
+			chunk.children = parseChunks(t, chunk.data[4:], baseOffset+pos+12)
+		}
+		chunks = append(chunks, chunk)
+		pos += 8 + size + (size % 2)
+	}
+	return chunks
+}
+
+func findChunks(chunks []testChunk, id string) []testChunk {
+	var result []testChunk
+	for _, chunk := range chunks {
+		if (chunk.id == id) || (chunk.listType == id) {
+			result = append(result, chunk)
+		}
+	}
+	return result
+}
+
+func writeMovie(t *testing.T, movie avi.Movie) testChunk {
+	t.Helper()
+	buf := bytes.NewBuffer(nil)
+	err := avi.Write(buf, movie)
+	require.Nil(t, err)
+	chunks := parseChunks(t, buf.Bytes(), 0)
+	require.Equal(t, 1, len(chunks))
+	require.Equal(t, "AVI ", chunks[0].listType)
+	return chunks[0]
+}
+
+func solidImage(clr color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 16; x++ {
+			img.Set(x, y, clr)
+		}
+	}
+	return img
+}
+
+func streamTypes(file testChunk) []string {
+	var types []string
+	for _, strl := range findChunks(findChunks(file.children, "hdrl")[0].children, "strl") {
+		types = append(types, string(findChunks(strl.children, "strh")[0].data[0:4]))
+	}
+	return types
+}
+
+func TestWriteReturnsErrorForEmptyMovie(t *testing.T) {
+	err := avi.Write(bytes.NewBuffer(nil), avi.Movie{})
+	assert.Error(t, err)
+}
+
+func TestWriteCreatesStreamsForAllContent(t *testing.T) {
+	file := writeMovie(t, avi.Movie{
+		Width:     16,
+		Height:    8,
+		Frames:    []avi.Frame{{Image: solidImage(color.White), DisplayTime: time.Second}},
+		Audio:     audio.L8{SampleRate: 22050, Samples: make([]byte, 22050)},
+		Subtitles: []avi.SubtitleTrack{{Name: "English"}, {Name: "German"}},
+	})
+	assert.Equal(t, []string{"vids", "auds", "txts", "txts"}, streamTypes(file))
+}
+
+func TestWriteHoldsFramesAccordingToDisplayTime(t *testing.T) {
+	file := writeMovie(t, avi.Movie{
+		Width:     16,
+		Height:    8,
+		FrameRate: 10,
+		Frames: []avi.Frame{
+			{Image: solidImage(color.White), DisplayTime: 300 * time.Millisecond},
+			{Image: solidImage(color.Black), DisplayTime: 200 * time.Millisecond},
+		},
+	})
+	videoChunks := findChunks(findChunks(file.children, "movi")[0].children, "00dc")
+	require.Equal(t, 5, len(videoChunks))
+	var sizes []bool
+	for _, chunk := range videoChunks {
+		sizes = append(sizes, len(chunk.data) > 0)
+	}
+	assert.Equal(t, []bool{true, false, false, true, false}, sizes)
+
+	img, err := jpeg.Decode(bytes.NewReader(videoChunks[3].data))
+	require.Nil(t, err)
+	r, g, b, _ := img.At(4, 4).RGBA()
+	assert.True(t, (r < 0x1000) && (g < 0x1000) && (b < 0x1000), "second frame should be black")
+}
+
+func TestWriteInterleavesCompleteAudio(t *testing.T) {
+	samples := make([]byte, 1001)
+	for index := range samples {
+		samples[index] = byte(index)
+	}
+	file := writeMovie(t, avi.Movie{
+		FrameRate: 25,
+		Audio:     audio.L8{SampleRate: 8000, Samples: samples},
+	})
+	assert.Equal(t, []string{"auds"}, streamTypes(file))
+	var result []byte
+	for _, chunk := range findChunks(findChunks(file.children, "movi")[0].children, "00wb") {
+		result = append(result, chunk.data...)
+	}
+	assert.Equal(t, samples, result)
+}
+
+func TestWriteIndexReferencesChunks(t *testing.T) {
+	file := writeMovie(t, avi.Movie{
+		Width:     16,
+		Height:    8,
+		Frames:    []avi.Frame{{Image: solidImage(color.White), DisplayTime: 100 * time.Millisecond}},
+		Audio:     audio.L8{SampleRate: 22050, Samples: make([]byte, 3001)},
+		Subtitles: []avi.SubtitleTrack{{Name: "English"}},
+	})
+	movi := findChunks(file.children, "movi")[0]
+	index := findChunks(file.children, "idx1")[0].data
+	require.Equal(t, len(movi.children)*16, len(index))
+	moviStart := movi.offset + 8
+	for entry, chunk := range movi.children {
+		entryData := index[entry*16 : (entry+1)*16]
+		assert.Equal(t, chunk.id, string(entryData[0:4]))
+		assert.Equal(t, chunk.offset-moviStart, int(binary.LittleEndian.Uint32(entryData[8:12])))
+		assert.Equal(t, len(chunk.data), int(binary.LittleEndian.Uint32(entryData[12:16])))
+	}
+}
+
+func TestWriteEmbedsSubtitlesAsSubRip(t *testing.T) {
+	file := writeMovie(t, avi.Movie{
+		Subtitles: []avi.SubtitleTrack{{Name: "English", Entries: []avi.Subtitle{
+			{Start: 1500 * time.Millisecond, End: 62 * time.Second, Text: "first\nline"},
+			{Start: time.Hour, End: time.Hour + time.Second, Text: "second"},
+		}}},
+	})
+	chunks := findChunks(findChunks(file.children, "movi")[0].children, "00sb")
+	require.Equal(t, 1, len(chunks))
+	data := chunks[0].data
+	require.True(t, bytes.HasPrefix(data, []byte("GAB2\x00")))
+	assert.True(t, strings.HasSuffix(string(data),
+		"1\r\n00:00:01,500 --> 00:01:02,000\r\nfirst\r\nline\r\n\r\n"+
+			"2\r\n01:00:00,000 --> 01:00:01,000\r\nsecond\r\n\r\n"))
+}
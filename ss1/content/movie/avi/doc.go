@@ -0,0 +1,6 @@
+// Package avi provides a writer for AVI files.
+//
+// The written files use Motion-JPEG (MJPG) for video, uncompressed 8-bit PCM for audio,
+// and GAB2 text streams that contain the subtitles of one language each in SubRip (SRT) format.
+// Such files can be played with common media players without the game.
+package avi
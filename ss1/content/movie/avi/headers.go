@@ -0,0 +1,77 @@
+package avi
+
+const (
+	mainHeaderFlagHasIndex      = 0x00000010
+	mainHeaderFlagIsInterleaved = 0x00000100
+
+	indexFlagKeyFrame = 0x00000010
+
+	waveFormatPCM = 0x0001
+)
+
+type mainHeader struct {
+	MicroSecPerFrame    uint32
+	MaxBytesPerSec      uint32
+	PaddingGranularity  uint32
+	Flags               uint32
+	TotalFrames         uint32
+	InitialFrames       uint32
+	Streams             uint32
+	SuggestedBufferSize uint32
+	Width               uint32
+	Height              uint32
+	Reserved            [4]uint32
+}
+
+type streamHeader struct {
+	Type                [4]byte
+	Handler             [4]byte
+	Flags               uint32
+	Priority            uint16
+	Language            uint16
+	InitialFrames       uint32
+	Scale               uint32
+	Rate                uint32
+	Start               uint32
+	Length              uint32
+	SuggestedBufferSize uint32
+	Quality             uint32
+	SampleSize          uint32
+	Frame               [4]int16
+}
+
+type bitmapInfoHeader struct {
+	Size          uint32
+	Width         int32
+	Height        int32
+	Planes        uint16
+	BitCount      uint16
+	Compression   [4]byte
+	SizeImage     uint32
+	XPelsPerMeter int32
+	YPelsPerMeter int32
+	ClrUsed       uint32
+	ClrImportant  uint32
+}
+
+type waveFormat struct {
+	FormatTag      uint16
+	Channels       uint16
+	SamplesPerSec  uint32
+	AvgBytesPerSec uint32
+	BlockAlign     uint16
+	BitsPerSample  uint16
+	Size           uint16
+}
+
+type indexEntry struct {
+	ID     [4]byte
+	Flags  uint32
+	Offset uint32
+	Size   uint32
+}
+
+func fourCC(value string) (result [4]byte) {
+	copy(result[:], value)
+	return
+}
@@ -0,0 +1,41 @@
+package avi
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// riffBuffer collects RIFF chunks and lists.
+type riffBuffer struct {
+	bytes.Buffer
+}
+
+func (buf *riffBuffer) writeValue(value interface{}) {
+	_ = binary.Write(buf, binary.LittleEndian, value)
+}
+
+// chunk writes a chunk with given identifier and data, padded to an even size.
+func (buf *riffBuffer) chunk(id string, data []byte) {
+	buf.WriteString(id)
+	buf.writeValue(uint32(len(data)))
+	buf.Write(data)
+	if (len(data) % 2) != 0 {
+		buf.WriteByte(0x00)
+	}
+}
+
+// list writes a list of given type, with the content produced by the given function.
+func (buf *riffBuffer) list(id, listType string, content func(*riffBuffer)) {
+	var inner riffBuffer
+	content(&inner)
+	buf.WriteString(id)
+	buf.writeValue(uint32(4 + inner.Len()))
+	buf.WriteString(listType)
+	buf.Write(inner.Bytes())
+}
+
+func structData(value interface{}) []byte {
+	var buf riffBuffer
+	buf.writeValue(value)
+	return buf.Bytes()
+}
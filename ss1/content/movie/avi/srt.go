@@ -0,0 +1,22 @@
+package avi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func formatSRTTime(value time.Duration) string {
+	ms := value.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", ms/3600000, (ms/60000)%60, (ms/1000)%60, ms%1000)
+}
+
+func (track SubtitleTrack) srt() []byte {
+	buf := bytes.NewBuffer(nil)
+	for index, entry := range track.Entries {
+		text := strings.ReplaceAll(strings.ReplaceAll(strings.TrimSpace(entry.Text), "\r\n", "\n"), "\n", "\r\n")
+		_, _ = fmt.Fprintf(buf, "%d\r\n%s --> %s\r\n%s\r\n\r\n", index+1, formatSRTTime(entry.Start), formatSRTTime(entry.End), text)
+	}
+	return buf.Bytes()
+}
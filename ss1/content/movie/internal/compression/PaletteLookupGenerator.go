@@ -46,6 +46,9 @@ func (lookup *PaletteLookup) Lookup(tile tileDelta) (index int, pal []byte, mask
 
 // PaletteLookupGenerator creates palette lookups based on a set of registered tiles.
 type PaletteLookupGenerator struct {
+	// Progress is an optional callback that is called with the count of processed and total keys.
+	Progress func(done, total int)
+
 	keyUses map[TilePaletteKey]int
 }
 
@@ -59,8 +62,15 @@ func (gen *PaletteLookupGenerator) Generate(ctx context.Context) (PaletteLookup,
 		remainder[key] = struct{}{}
 	}
 
+	type keyedEntry struct {
+		key   TilePaletteKey
+		entry paletteLookupEntry
+	}
+	// sizedEntry holds the candidates of one size. The ordered list contains the same entries as the map,
+	// sorted by their start, which allows quick iteration and removal.
 	type sizedEntry struct {
 		entries    map[TilePaletteKey]paletteLookupEntry
+		ordered    []keyedEntry
 		lastOffset int
 	}
 	sizedEntries := make(map[int]*sizedEntry)
@@ -71,7 +81,6 @@ func (gen *PaletteLookupGenerator) Generate(ctx context.Context) (PaletteLookup,
 		}
 	}
 
-	toDeleteCache := make([]TilePaletteKey, 0, len(remainder))
 	addToBuffer := func(data []byte) {
 		lookup.buffer = append(lookup.buffer, data...)
 
@@ -82,25 +91,24 @@ func (gen *PaletteLookupGenerator) Generate(ctx context.Context) (PaletteLookup,
 			entry := sizedEntries[fitSize]
 
 			// remove all entries beyond a certain limit. as these bytes don't change, retrying won't help.
-			toDelete := toDeleteCache[0:0]
 			limit := newSize - 16 - len(data)
-			for key, entry := range entry.entries {
-				if entry.start < limit {
-					toDelete = append(toDelete, key)
-				}
-			}
-			for _, key := range toDelete {
-				delete(entry.entries, key)
+			removed := 0
+			for (removed < len(entry.ordered)) && (entry.ordered[removed].entry.start < limit) {
+				delete(entry.entries, entry.ordered[removed].key)
+				removed++
 			}
+			entry.ordered = entry.ordered[removed:]
 
 			// find any new keys
 			for start := entry.lastOffset; start < fitLimit; start++ {
 				tempKey := TilePaletteKeyFrom(lookup.buffer[start : start+fitSize])
 				if _, existing := entry.entries[tempKey]; !existing {
-					entry.entries[tempKey] = paletteLookupEntry{
+					newEntry := paletteLookupEntry{
 						start: start,
 						size:  fitSize,
 					}
+					entry.entries[tempKey] = newEntry
+					entry.ordered = append(entry.ordered, keyedEntry{key: tempKey, entry: newEntry})
 				}
 			}
 			if fitLimit > 0 {
@@ -109,21 +117,59 @@ func (gen *PaletteLookupGenerator) Generate(ctx context.Context) (PaletteLookup,
 		}
 	}
 
-	addEarlyEntry := func(key TilePaletteKey, limitSize int) bool {
+	findEarlyEntry := func(key TilePaletteKey, limitSize int) (paletteLookupEntry, bool) {
 		for _, fitSize := range knownSizes {
 			if key.size <= fitSize && fitSize <= limitSize {
 				entry := sizedEntries[fitSize]
-				for tempKey, paletteEntry := range entry.entries {
-					if tempKey.Contains(&key) && (!key.HasColor(0x00) || (lookup.buffer[paletteEntry.start] == 0x00)) {
-						lookup.entries[key] = paletteEntry
-						return true
+				for index := range entry.ordered {
+					candidate := &entry.ordered[index]
+					if candidate.key.Contains(&key) && (!key.HasColor(0x00) || (lookup.buffer[candidate.entry.start] == 0x00)) {
+						return candidate.entry, true
 					}
 				}
 			}
 		}
-		return false
+		return paletteLookupEntry{}, false
+	}
+	// addEarlyEntries maps all remaining keys that are already contained in the buffer.
+	// The search is read-only and thus split among several goroutines; the results are applied afterwards.
+	addEarlyEntries := func(limitSize int) error {
+		keys := make([]TilePaletteKey, 0, len(remainder))
+		for key := range remainder {
+			keys = append(keys, key)
+		}
+		found := make([]paletteLookupEntry, len(keys))
+		isFound := make([]bool, len(keys))
+		const batchSize = 256
+		batches := (len(keys) + batchSize - 1) / batchSize
+		err := forEachParallel(ctx, batches, func(batch int) error {
+			end := (batch + 1) * batchSize
+			if end > len(keys) {
+				end = len(keys)
+			}
+			for index := batch * batchSize; index < end; index++ {
+				found[index], isFound[index] = findEarlyEntry(keys[index], limitSize)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for index, key := range keys {
+			if isFound[index] {
+				lookup.entries[key] = found[index]
+				delete(remainder, key)
+			}
+		}
+		return nil
 	}
 
+	totalKeys := len(remainder)
+	reportProgress := func() {
+		if gen.Progress != nil {
+			gen.Progress(totalKeys-len(remainder), totalKeys)
+		}
+	}
 	sizeLimitForSize := map[int]int{3: 4, 4: 8, 5: 8, 6: 8, 7: 8, 8: 8, 9: 16, 10: 16, 11: 16, 12: 16, 13: 16, 14: 16, 15: 16, 16: 16}
 	for size := PixelPerTile; (size > 2) && (ctx.Err() == nil); size-- {
 		keysInSize := make([]TilePaletteKey, 0, len(remainder))
@@ -134,30 +180,29 @@ func (gen *PaletteLookupGenerator) Generate(ctx context.Context) (PaletteLookup,
 		}
 		sort.Slice(keysInSize, func(a, b int) bool { return keysInSize[a].LessThan(&keysInSize[b]) })
 
-		// fmt.Printf("Working on key size %v, have %v sized, %v total remaining\n", size, len(keysInSize), len(remainder))
+		// The early entries only change if the buffer was extended, or the size limit changes.
+		bufferChanged := true
 		for _, sizedKey := range keysInSize {
 			if ctx.Err() != nil {
 				return PaletteLookup{}, ctx.Err()
 			}
 
-			{
-				var earlyRemoved []TilePaletteKey
-				for key := range remainder {
-					if addEarlyEntry(key, sizeLimitForSize[size]) {
-						earlyRemoved = append(earlyRemoved, key)
-					}
-				}
-				for _, key := range earlyRemoved {
-					delete(remainder, key)
+			if bufferChanged {
+				err := addEarlyEntries(sizeLimitForSize[size])
+				if err != nil {
+					return PaletteLookup{}, err
 				}
+				bufferChanged = false
 			}
 			if _, stillRemaining := remainder[sizedKey]; stillRemaining {
 				bytes := sizedKey.Buffer()
 				lookup.entries[sizedKey] = paletteLookupEntry{start: len(lookup.buffer), size: len(bytes)}
 				addToBuffer(bytes)
+				bufferChanged = true
 
 				delete(remainder, sizedKey)
 			}
+			reportProgress()
 		}
 	}
 
@@ -0,0 +1,112 @@
+package compression
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// EncodingStage identifies a step of encoding a scene.
+type EncodingStage int
+
+// EncodingStage constants, in the order they are processed.
+const (
+	// StageTileDeltas determines the changed tiles of each frame.
+	StageTileDeltas EncodingStage = iota
+	// StagePaletteLookup creates the palette lookup buffer for all tiles.
+	StagePaletteLookup
+	// StageTileOperations determines the coloring operations of the tiles.
+	StageTileOperations
+	// StageBitstreams packs the control words and creates the bitstreams of the frames.
+	StageBitstreams
+)
+
+// ProgressFunc is called while a scene is encoded.
+// Done and total are the counts of work items within the given stage.
+// The function is called sequentially, yet possibly from different goroutines.
+type ProgressFunc func(stage EncodingStage, done, total int)
+
+// progressReporter serializes progress calls from concurrent workers.
+type progressReporter struct {
+	mutex    sync.Mutex
+	callback ProgressFunc
+	stage    EncodingStage
+	done     int
+	total    int
+}
+
+func newProgressReporter(callback ProgressFunc) *progressReporter {
+	return &progressReporter{callback: callback}
+}
+
+func (reporter *progressReporter) start(stage EncodingStage, total int) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	reporter.stage = stage
+	reporter.done = 0
+	reporter.total = total
+	reporter.report()
+}
+
+func (reporter *progressReporter) advance(count int) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	reporter.done += count
+	reporter.report()
+}
+
+func (reporter *progressReporter) update(done int) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+	reporter.done = done
+	reporter.report()
+}
+
+func (reporter *progressReporter) report() {
+	if reporter.callback != nil {
+		reporter.callback(reporter.stage, reporter.done, reporter.total)
+	}
+}
+
+// forEachParallel calls the given function for each index in [0, count), distributed over as many workers as
+// goroutines may run simultaneously.
+// Processing stops at the first error, or when the context is cancelled.
+func forEachParallel(ctx context.Context, count int, work func(index int) error) error {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > count {
+		workers = count
+	}
+	var wg sync.WaitGroup
+	var errMutex sync.Mutex
+	var firstErr error
+	failed := func() bool {
+		errMutex.Lock()
+		defer errMutex.Unlock()
+		return firstErr != nil
+	}
+	wg.Add(workers)
+	for worker := 0; worker < workers; worker++ {
+		go func(first int) {
+			defer wg.Done()
+			for index := first; index < count; index += workers {
+				if failed() {
+					return
+				}
+				err := ctx.Err()
+				if err == nil {
+					err = work(index)
+				}
+				if err != nil {
+					errMutex.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					errMutex.Unlock()
+					return
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+	return firstErr
+}
@@ -17,14 +17,15 @@ type frameDelta struct {
 }
 
 // SceneEncoder encodes an entire scene of bitmaps sharing the same palette.
+// The work of encoding is distributed over all available processors.
 type SceneEncoder struct {
 	hTiles     int
 	vTiles     int
 	lineStride int
 	tileStride int
+	frameSize  int
 
-	lastFrame []byte
-	deltas    []frameDelta
+	frames [][]byte
 }
 
 // NewSceneEncoder returns a new instance.
@@ -35,38 +36,42 @@ func NewSceneEncoder(width, height int) *SceneEncoder {
 		lineStride: width,
 	}
 	e.tileStride = e.lineStride * TileSideLength
-	e.lastFrame = make([]byte, e.vTiles*TileSideLength*e.lineStride)
+	e.frameSize = e.vTiles * TileSideLength * e.lineStride
 	return e
 }
 
 // AddFrame registers a further frame to the scene.
 func (e *SceneEncoder) AddFrame(frame []byte) error {
-	if len(frame) != len(e.lastFrame) {
+	if len(frame) != e.frameSize {
 		return errInvalidFrameSize
 	}
-	var delta frameDelta
-	isFirstFrame := len(e.deltas) == 0
+	frameCopy := make([]byte, len(frame))
+	copy(frameCopy, frame)
+	e.frames = append(e.frames, frameCopy)
+	return nil
+}
+
+func (e *SceneEncoder) deltaFrame(frame, lastFrame []byte) frameDelta {
+	delta := frameDelta{tiles: make([]tileDelta, 0, e.vTiles*e.hTiles)}
 	vStart := 0
 	for vTile := 0; vTile < e.vTiles; vTile++ {
 		tileStart := vStart
 		for hTile := 0; hTile < e.hTiles; hTile++ {
-			delta.tiles = append(delta.tiles, e.deltaTile(isFirstFrame, tileStart, frame))
+			delta.tiles = append(delta.tiles, e.deltaTile(tileStart, frame, lastFrame))
 			tileStart += TileSideLength
 		}
 		vStart += e.tileStride
 	}
-	e.deltas = append(e.deltas, delta)
-	copy(e.lastFrame, frame)
-	return nil
+	return delta
 }
 
-func (e *SceneEncoder) deltaTile(isFirstFrame bool, offset int, frame []byte) tileDelta {
+func (e *SceneEncoder) deltaTile(offset int, frame, lastFrame []byte) tileDelta {
 	var delta tileDelta
 	for y := 0; y < TileSideLength; y++ {
 		start := offset + (y * e.lineStride)
 		for x := 0; x < TileSideLength; x++ {
 			pixel := frame[start+x]
-			if isFirstFrame || (pixel != e.lastFrame[start+x]) {
+			if (lastFrame == nil) || (pixel != lastFrame[start+x]) {
 				delta[y*TileSideLength+x] = pixel
 			}
 		}
@@ -77,104 +82,152 @@ func (e *SceneEncoder) deltaTile(isFirstFrame bool, offset int, frame []byte) ti
 // Encode processes all the previously registered frames and creates the necessary components for decoding.
 func (e *SceneEncoder) Encode(ctx context.Context) (
 	words []ControlWord, paletteLookupBuffer []byte, frames []EncodedFrame, err error) {
-	var wordSequencer ControlWordSequencer
-	tileColorOpsPerFrame := make([][]TileColorOp, len(e.deltas))
-	paletteLookup, err := e.createPaletteLookup(ctx)
+	return e.EncodeWithProgress(ctx, nil)
+}
+
+// EncodeWithProgress processes all the previously registered frames and creates the necessary components
+// for decoding. The given function, if not nil, is called with the progress of each stage.
+// The encoding is aborted with the error of the context, should the context be cancelled.
+func (e *SceneEncoder) EncodeWithProgress(ctx context.Context, progress ProgressFunc) (
+	words []ControlWord, paletteLookupBuffer []byte, frames []EncodedFrame, err error) {
+	reporter := newProgressReporter(progress)
+
+	deltas, err := e.createDeltas(ctx, reporter)
 	if err != nil {
-		return
+		return nil, nil, nil, err
+	}
+	paletteLookup, err := e.createPaletteLookup(ctx, reporter, deltas)
+	if err != nil {
+		return nil, nil, nil, err
 	}
-
 	paletteLookupBuffer = paletteLookup.Buffer()
 	if len(paletteLookupBuffer) > 0x1FFFF {
-		err = paletteLookupTooBigError{Size: len(paletteLookupBuffer)}
-		return
+		return nil, nil, nil, paletteLookupTooBigError{Size: len(paletteLookupBuffer)}
 	}
 
-	frames = make([]EncodedFrame, len(e.deltas))
-	for frameIndex := 0; frameIndex < len(e.deltas); frameIndex++ {
-		var maskstreamWriter MaskstreamWriter
-		outFrame := &frames[frameIndex]
-		delta := e.deltas[frameIndex]
-
-		lastOp := TileColorOp{Type: CtrlUnknown}
-		for tileIndex, tile := range delta.tiles {
-			var op TileColorOp
-			paletteIndex, pal, mask := paletteLookup.Lookup(tile)
-			palSize := len(pal)
-
-			switch {
-			case palSize == 1 && (pal[0] == 0x00):
-				op.Type = CtrlSkip
-			case palSize == 1:
-				op.Type = CtrlColorTile2ColorsStatic
-				op.Offset = uint32(pal[0])<<8 | uint32(pal[0])
-			case palSize == 2 && mask == 0xAAAA && (pal[0] != 0x00) && (pal[1] != 0x00):
-				op.Type = CtrlColorTile2ColorsStatic
-				op.Offset = uint32(pal[1])<<8 | uint32(pal[0])
-			case palSize == 2 && mask == 0x5555 && (pal[0] != 0x00) && (pal[1] != 0x00):
-				op.Type = CtrlColorTile2ColorsStatic
-				op.Offset = uint32(pal[0])<<8 | uint32(pal[1])
-			case palSize <= 2:
-				op.Type = CtrlColorTile2ColorsMasked
-				if palSize == 2 {
-					op.Offset = uint32(pal[1])
-					op.Offset <<= 8
-				}
-				if palSize > 0 {
-					op.Offset |= uint32(pal[0])
-				}
-
-				_ = maskstreamWriter.Write(2, mask)
-			case palSize <= 4:
-				op.Type = CtrlColorTile4ColorsMasked
-				op.Offset = uint32(paletteIndex)
-				_ = maskstreamWriter.Write(4, mask)
-			case palSize <= 8:
-				op.Type = CtrlColorTile8ColorsMasked
-				op.Offset = uint32(paletteIndex)
-				_ = maskstreamWriter.Write(6, mask)
-			default:
-				op.Type = CtrlColorTile16ColorsMasked
-				op.Offset = uint32(paletteIndex)
-				_ = maskstreamWriter.Write(8, mask)
-			}
-
-			if op.Type != CtrlSkip && (tileIndex%e.hTiles) != 0 && lastOp == op {
-				op = TileColorOp{Type: CtrlRepeatPrevious}
-			} else {
-				lastOp = op
-			}
+	frames = make([]EncodedFrame, len(deltas))
+	tileColorOpsPerFrame := make([][]TileColorOp, len(deltas))
+	reporter.start(StageTileOperations, len(deltas))
+	err = forEachParallel(ctx, len(deltas), func(frameIndex int) error {
+		tileColorOpsPerFrame[frameIndex], frames[frameIndex].Maskstream = e.tileColorOps(&paletteLookup, deltas[frameIndex])
+		reporter.advance(1)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
+	reporter.start(StageBitstreams, len(deltas)+1)
+	var wordSequencer ControlWordSequencer
+	for _, ops := range tileColorOpsPerFrame {
+		for _, op := range ops {
 			err = wordSequencer.Add(op)
 			if err != nil {
 				return nil, nil, nil, err
 			}
-			tileColorOpsPerFrame[frameIndex] = append(tileColorOpsPerFrame[frameIndex], op)
 		}
-		outFrame.Maskstream = maskstreamWriter.Buffer
 	}
-
 	wordSequence, err := wordSequencer.Sequence()
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	wordSequence.HTiles = uint32(e.hTiles)
 	words = wordSequence.ControlWords()
-	for frameIndex, ops := range tileColorOpsPerFrame {
-		frames[frameIndex].Bitstream, err = wordSequence.BitstreamFor(ops)
-		if err != nil {
-			return nil, nil, nil, err
+	reporter.advance(1)
+	err = forEachParallel(ctx, len(tileColorOpsPerFrame), func(frameIndex int) error {
+		bitstream, bitstreamErr := wordSequence.BitstreamFor(tileColorOpsPerFrame[frameIndex])
+		frames[frameIndex].Bitstream = bitstream
+		reporter.advance(1)
+		return bitstreamErr
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return words, paletteLookupBuffer, frames, nil
+}
+
+func (e *SceneEncoder) createDeltas(ctx context.Context, reporter *progressReporter) ([]frameDelta, error) {
+	deltas := make([]frameDelta, len(e.frames))
+	reporter.start(StageTileDeltas, len(e.frames))
+	err := forEachParallel(ctx, len(e.frames), func(frameIndex int) error {
+		var lastFrame []byte
+		if frameIndex > 0 {
+			lastFrame = e.frames[frameIndex-1]
 		}
+		deltas[frameIndex] = e.deltaFrame(e.frames[frameIndex], lastFrame)
+		reporter.advance(1)
+		return nil
+	})
+	return deltas, err
+}
+
+func (e *SceneEncoder) tileColorOps(paletteLookup *PaletteLookup, delta frameDelta) ([]TileColorOp, []byte) {
+	var maskstreamWriter MaskstreamWriter
+	ops := make([]TileColorOp, 0, len(delta.tiles))
+	lastOp := TileColorOp{Type: CtrlUnknown}
+	for tileIndex, tile := range delta.tiles {
+		var op TileColorOp
+		paletteIndex, pal, mask := paletteLookup.Lookup(tile)
+		palSize := len(pal)
+
+		switch {
+		case palSize == 1 && (pal[0] == 0x00):
+			op.Type = CtrlSkip
+		case palSize == 1:
+			op.Type = CtrlColorTile2ColorsStatic
+			op.Offset = uint32(pal[0])<<8 | uint32(pal[0])
+		case palSize == 2 && mask == 0xAAAA && (pal[0] != 0x00) && (pal[1] != 0x00):
+			op.Type = CtrlColorTile2ColorsStatic
+			op.Offset = uint32(pal[1])<<8 | uint32(pal[0])
+		case palSize == 2 && mask == 0x5555 && (pal[0] != 0x00) && (pal[1] != 0x00):
+			op.Type = CtrlColorTile2ColorsStatic
+			op.Offset = uint32(pal[0])<<8 | uint32(pal[1])
+		case palSize <= 2:
+			op.Type = CtrlColorTile2ColorsMasked
+			if palSize == 2 {
+				op.Offset = uint32(pal[1])
+				op.Offset <<= 8
+			}
+			if palSize > 0 {
+				op.Offset |= uint32(pal[0])
+			}
+
+			_ = maskstreamWriter.Write(2, mask)
+		case palSize <= 4:
+			op.Type = CtrlColorTile4ColorsMasked
+			op.Offset = uint32(paletteIndex)
+			_ = maskstreamWriter.Write(4, mask)
+		case palSize <= 8:
+			op.Type = CtrlColorTile8ColorsMasked
+			op.Offset = uint32(paletteIndex)
+			_ = maskstreamWriter.Write(6, mask)
+		default:
+			op.Type = CtrlColorTile16ColorsMasked
+			op.Offset = uint32(paletteIndex)
+			_ = maskstreamWriter.Write(8, mask)
+		}
+
+		if op.Type != CtrlSkip && (tileIndex%e.hTiles) != 0 && lastOp == op {
+			op = TileColorOp{Type: CtrlRepeatPrevious}
+		} else {
+			lastOp = op
+		}
+		ops = append(ops, op)
 	}
-	return
+	return ops, maskstreamWriter.Buffer
 }
 
-func (e *SceneEncoder) createPaletteLookup(ctx context.Context) (PaletteLookup, error) {
+func (e *SceneEncoder) createPaletteLookup(ctx context.Context, reporter *progressReporter,
+	deltas []frameDelta) (PaletteLookup, error) {
 	var paletteLookupGenerator PaletteLookupGenerator
-	for _, delta := range e.deltas {
+	for _, delta := range deltas {
 		for _, tile := range delta.tiles {
 			paletteLookupGenerator.Add(tile)
 		}
 	}
+	reporter.start(StagePaletteLookup, len(paletteLookupGenerator.keyUses))
+	paletteLookupGenerator.Progress = func(done, _ int) {
+		reporter.update(done)
+	}
 	return paletteLookupGenerator.Generate(ctx)
 }
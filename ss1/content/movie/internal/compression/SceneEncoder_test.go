@@ -0,0 +1,133 @@
+package compression_test
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/movie/internal/compression"
+)
+
+func randomFrames(width, height, count int) [][]byte {
+	r := rand.New(rand.NewSource(0)) // nolint: gosec
+	frames := make([][]byte, count)
+	for index := range frames {
+		frames[index] = make([]byte, width*height)
+		for pixel := range frames[index] {
+			frames[index][pixel] = byte(r.Intn(32))
+		}
+	}
+	return frames
+}
+
+// changingFrames returns frames of which some tiles change from one frame to the next, using only non-zero pixel values.
+func changingFrames(width, height, count int) [][]byte {
+	r := rand.New(rand.NewSource(1)) // nolint: gosec
+	frames := make([][]byte, count)
+	for index := range frames {
+		frames[index] = make([]byte, width*height)
+		if index > 0 {
+			copy(frames[index], frames[index-1])
+		}
+		for pixel := range frames[index] {
+			if (index == 0) || (r.Intn(4) == 0) {
+				frames[index][pixel] = byte(1 + r.Intn(16))
+			}
+		}
+	}
+	return frames
+}
+
+func encodeFrames(t *testing.T, width, height int, frames [][]byte) ([]compression.ControlWord, []byte, []compression.EncodedFrame) {
+	t.Helper()
+	encoder := compression.NewSceneEncoder(width, height)
+	for _, frame := range frames {
+		require.Nil(t, encoder.AddFrame(frame))
+	}
+	words, paletteLookup, encoded, err := encoder.Encode(context.Background())
+	require.Nil(t, err)
+	return words, paletteLookup, encoded
+}
+
+func TestSceneEncoderResultDecodesToOriginalFrames(t *testing.T) {
+	width, height := 32, 16
+	frames := changingFrames(width, height, 4)
+	words, paletteLookup, encoded := encodeFrames(t, width, height, frames)
+	require.Equal(t, len(frames), len(encoded))
+
+	decoderBuilder := compression.NewFrameDecoderBuilder(width, height)
+	decoderBuilder.WithControlWords(words)
+	decoderBuilder.WithPaletteLookupList(paletteLookup)
+	decoded := make([]byte, width*height)
+	decoderBuilder.ForStandardFrame(decoded, width)
+	decoder := decoderBuilder.Build()
+	for index, frame := range encoded {
+		err := decoder.Decode(frame.Bitstream, frame.Maskstream)
+		require.Nil(t, err, "no error expected decoding frame %d", index)
+		assert.Equal(t, frames[index], decoded, "frame %d differs", index)
+	}
+}
+
+func TestSceneEncoderProducesSameResultInParallelAsSequentially(t *testing.T) {
+	width, height := 64, 32
+	frames := changingFrames(width, height, 6)
+
+	previous := runtime.GOMAXPROCS(1)
+	defer runtime.GOMAXPROCS(previous)
+	sequentialWords, sequentialLookup, sequentialFrames := encodeFrames(t, width, height, frames)
+	runtime.GOMAXPROCS(4)
+	parallelWords, parallelLookup, parallelFrames := encodeFrames(t, width, height, frames)
+
+	assert.Equal(t, sequentialWords, parallelWords, "control words differ")
+	assert.Equal(t, sequentialLookup, parallelLookup, "palette lookup differs")
+	assert.Equal(t, sequentialFrames, parallelFrames, "frames differ")
+}
+
+func TestSceneEncoderReportsProgressOfAllStages(t *testing.T) {
+	width, height := 32, 16
+	encoder := compression.NewSceneEncoder(width, height)
+	for _, frame := range randomFrames(width, height, 3) {
+		require.Nil(t, encoder.AddFrame(frame))
+	}
+	lastDone := make(map[compression.EncodingStage]int)
+	totals := make(map[compression.EncodingStage]int)
+	lastStage := compression.StageTileDeltas
+	_, _, _, err := encoder.EncodeWithProgress(context.Background(), func(stage compression.EncodingStage, done, total int) {
+		assert.True(t, stage >= lastStage, "stages must not go back")
+		lastStage = stage
+		lastDone[stage] = done
+		totals[stage] = total
+	})
+	require.Nil(t, err)
+	for _, stage := range []compression.EncodingStage{
+		compression.StageTileDeltas, compression.StagePaletteLookup,
+		compression.StageTileOperations, compression.StageBitstreams,
+	} {
+		assert.Equal(t, totals[stage], lastDone[stage], "stage %v not completed", stage)
+	}
+}
+
+func TestSceneEncoderHonoursCancellation(t *testing.T) {
+	width, height := 32, 16
+	encoder := compression.NewSceneEncoder(width, height)
+	for _, frame := range randomFrames(width, height, 3) {
+		require.Nil(t, encoder.AddFrame(frame))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	_, _, _, err := encoder.EncodeWithProgress(ctx, func(stage compression.EncodingStage, done, total int) {
+		if stage == compression.StagePaletteLookup {
+			cancel()
+		}
+	})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestSceneEncoderRejectsFramesOfWrongSize(t *testing.T) {
+	encoder := compression.NewSceneEncoder(32, 16)
+	err := encoder.AddFrame(make([]byte, 10))
+	assert.NotNil(t, err)
+}
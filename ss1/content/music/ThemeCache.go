@@ -0,0 +1,65 @@
+package music
+
+import (
+	"io/ioutil"
+
+	"github.com/inkyblackness/hacked/ss1/content/audio/xmi"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// ThemeCache retrieves music themes stored as XMI files from a localizer and
+// keeps them cached until they are invalidated.
+type ThemeCache struct {
+	localizer resource.Localizer
+
+	themes map[int]xmi.File
+}
+
+// NewThemeCache returns a new instance.
+func NewThemeCache(localizer resource.Localizer) *ThemeCache {
+	cache := &ThemeCache{
+		localizer: localizer,
+
+		themes: make(map[int]xmi.File),
+	}
+	return cache
+}
+
+// InvalidateResources lets the cache remove any themes from resources that are specified in the given slice.
+func (cache *ThemeCache) InvalidateResources(modifiedIDs []resource.ID) {
+	for _, id := range modifiedIDs {
+		delete(cache.themes, int(id.Value())-int(ids.MusicThemesStart.Value()))
+	}
+}
+
+// Theme retrieves and caches the identified theme.
+func (cache *ThemeCache) Theme(index int) (xmi.File, error) {
+	value, existing := cache.themes[index]
+	if existing {
+		return value, nil
+	}
+	key := resource.KeyOf(ids.MusicThemesStart.Plus(index), resource.LangAny, 0)
+	selector := cache.localizer.LocalizedResources(key.Lang)
+	view, err := selector.Select(key.ID)
+	if err != nil {
+		return xmi.File{}, err
+	}
+	if view.ContentType() != resource.Music {
+		return xmi.File{}, resource.ErrWrongType(key, resource.Music)
+	}
+	reader, err := view.Block(0)
+	if err != nil {
+		return xmi.File{}, err
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return xmi.File{}, err
+	}
+	theme, err := xmi.Load(data)
+	if err != nil {
+		return xmi.File{}, err
+	}
+	cache.themes[index] = theme
+	return theme, nil
+}
@@ -0,0 +1,96 @@
+package balance
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/object/objprop"
+)
+
+// Armament is a weapon, optionally combined with a type of ammo it can fire.
+type Armament struct {
+	Weapon object.Triple
+	// Ammo is only valid if HasAmmo is set.
+	Ammo    object.Triple
+	HasAmmo bool
+
+	Attack Attack
+	// FireRate is the raw fire rate value of the weapon, the delay between two shots.
+	FireRate int
+}
+
+// ShotsPerSecond returns how often the armament fires, given the rate of fire rate units per second.
+// It returns 0 if the fire rate is unknown.
+func (armament Armament) ShotsPerSecond(fireRateUnitsPerSecond float64) float64 {
+	if armament.FireRate <= 0 {
+		return 0
+	}
+	return fireRateUnitsPerSecond / float64(armament.FireRate)
+}
+
+// DamagePerSecond returns the damage dealt to the given target within a second of continuous fire.
+func (armament Armament) DamagePerSecond(target Target, fireRateUnitsPerSecond float64) float64 {
+	return float64(armament.Attack.DamageAgainst(target)) * armament.ShotsPerSecond(fireRateUnitsPerSecond)
+}
+
+// Armaments returns all weapons of the table, one entry for each compatible type of ammo.
+// Weapons that fire ammo take the attack of the ammo, the others their own.
+func Armaments(table object.PropertiesTable) []Armament {
+	var result []Armament
+	for _, weapon := range table.TriplesInClass(object.ClassGun) {
+		weaponProp, _ := table.ForObject(weapon)
+		generic := objprop.GenericProperties(object.ClassGun, weaponProp.Generic)
+		base := Armament{
+			Weapon:   weapon,
+			FireRate: int(generic.Get("FireRate")),
+			Attack:   attackFrom(objprop.SpecificProperties(weapon, weaponProp.Specific).Refined("BasicWeapon")),
+		}
+		ammoTypes := generic.Get("AmmoType")
+		ammoSubclass := int(ammoTypes >> 4)
+		withAmmo := false
+		for ammoType := 0; ammoType < 4; ammoType++ {
+			if (ammoTypes & (1 << uint(ammoType))) == 0 {
+				continue
+			}
+			ammo := object.TripleFrom(int(object.ClassAmmo), ammoSubclass, ammoType)
+			ammoProp, err := table.ForObject(ammo)
+			if err != nil {
+				continue
+			}
+			armament := base
+			armament.Ammo = ammo
+			armament.HasAmmo = true
+			armament.Attack = attackFrom(objprop.GenericProperties(object.ClassAmmo, ammoProp.Generic).Refined("BasicWeapon"))
+			result = append(result, armament)
+			withAmmo = true
+		}
+		if !withAmmo {
+			result = append(result, base)
+		}
+	}
+	return result
+}
+
+func attackFrom(basicWeapon *interpreters.Instance) Attack {
+	return Attack{
+		Damage:      int(basicWeapon.Get("Damage")),
+		DamageType:  object.DamageTypeMask(basicWeapon.Get("DamageType")),
+		Special:     object.SpecialDamageType(basicWeapon.Get("SpecialDamageType")),
+		Penetration: int(basicWeapon.Get("ArmorPenetration")),
+	}
+}
+
+// Critter is a target that is a critter.
+type Critter struct {
+	Triple object.Triple
+	Target Target
+}
+
+// Critters returns all critters of the table.
+func Critters(table object.PropertiesTable) []Critter {
+	var result []Critter
+	for _, triple := range table.TriplesInClass(object.ClassCritter) {
+		prop, _ := table.ForObject(triple)
+		result = append(result, Critter{Triple: triple, Target: TargetFrom(*prop)})
+	}
+	return result
+}
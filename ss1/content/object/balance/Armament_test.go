@@ -0,0 +1,78 @@
+package balance_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/object/balance"
+	"github.com/inkyblackness/hacked/ss1/content/object/objprop"
+)
+
+func TestArmamentsPairsWeaponsWithCompatibleAmmo(t *testing.T) {
+	table := object.StandardPropertiesTable()
+	pistol := object.TripleFrom(int(object.ClassGun), 0, 1)
+	pistolProp, _ := table.ForObject(pistol)
+	generic := objprop.GenericProperties(object.ClassGun, pistolProp.Generic)
+	generic.Set("FireRate", 20)
+	generic.Set("AmmoType", 0x10|0x01|0x02)
+	ammo := object.TripleFrom(int(object.ClassAmmo), 1, 1)
+	ammoProp, _ := table.ForObject(ammo)
+	objprop.GenericProperties(object.ClassAmmo, ammoProp.Generic).Refined("BasicWeapon").Set("Damage", 15)
+
+	armaments := balance.Armaments(table)
+
+	var pistolArmaments []balance.Armament
+	for _, armament := range armaments {
+		if armament.Weapon == pistol {
+			pistolArmaments = append(pistolArmaments, armament)
+		}
+	}
+	require.Equal(t, 2, len(pistolArmaments), "two ammo types expected")
+	assert.Equal(t, object.TripleFrom(int(object.ClassAmmo), 1, 0), pistolArmaments[0].Ammo)
+	assert.Equal(t, ammo, pistolArmaments[1].Ammo)
+	assert.Equal(t, 15, pistolArmaments[1].Attack.Damage)
+	assert.Equal(t, 20, pistolArmaments[1].FireRate)
+}
+
+func TestArmamentsUsesOwnAttackOfWeaponsWithoutAmmo(t *testing.T) {
+	table := object.StandardPropertiesTable()
+	beam := object.TripleFrom(int(object.ClassGun), 4, 0)
+	beamProp, _ := table.ForObject(beam)
+	objprop.SpecificProperties(beam, beamProp.Specific).Refined("BasicWeapon").Set("Damage", 30)
+
+	armaments := balance.Armaments(table)
+
+	found := false
+	for _, armament := range armaments {
+		if armament.Weapon == beam {
+			found = true
+			assert.False(t, armament.HasAmmo, "no ammo expected")
+			assert.Equal(t, 30, armament.Attack.Damage)
+		}
+	}
+	assert.True(t, found, "beam weapon expected")
+}
+
+func TestArmamentDamagePerSecond(t *testing.T) {
+	armament := balance.Armament{Attack: balance.Attack{Damage: 10}, FireRate: 5}
+
+	assert.InDelta(t, 20.0, armament.DamagePerSecond(balance.Target{}, 10), 0.001)
+	assert.InDelta(t, 0.0, balance.Armament{}.DamagePerSecond(balance.Target{}, 10), 0.001)
+}
+
+func TestCrittersListsAllCritters(t *testing.T) {
+	table := object.StandardPropertiesTable()
+	critter := object.TripleFrom(int(object.ClassCritter), 0, 0)
+	prop, _ := table.ForObject(critter)
+	prop.Common.Hitpoints = 120
+	prop.Common.Armor = 3
+
+	critters := balance.Critters(table)
+
+	require.Equal(t, len(table.TriplesInClass(object.ClassCritter)), len(critters))
+	assert.Equal(t, critter, critters[0].Triple)
+	assert.Equal(t, balance.Target{Hitpoints: 120, Armor: 3}, critters[0].Target)
+}
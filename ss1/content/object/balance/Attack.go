@@ -0,0 +1,89 @@
+// Package balance derives combat statistics from object properties.
+// The calculations are a simplified model of the engine: They ignore randomness, criticals,
+// and the hit chance, and are meant to compare weapons and critters against each other.
+package balance
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+// Attack describes the damage potential of a single hit.
+type Attack struct {
+	Damage      int
+	DamageType  object.DamageTypeMask
+	Special     object.SpecialDamageType
+	Penetration int
+}
+
+// Target describes the defensive properties of an object.
+type Target struct {
+	Hitpoints              int
+	Armor                  int
+	Toughness              int
+	Vulnerabilities        object.DamageTypeMask
+	SpecialVulnerabilities object.SpecialDamageType
+}
+
+// TargetFrom returns the target description of the given properties.
+func TargetFrom(prop object.Properties) Target {
+	return Target{
+		Hitpoints:              int(prop.Common.Hitpoints),
+		Armor:                  int(prop.Common.Armor),
+		Toughness:              int(prop.Common.Toughness),
+		Vulnerabilities:        prop.Common.Vulnerabilities,
+		SpecialVulnerabilities: prop.Common.SpecialVulnerabilities,
+	}
+}
+
+// SpecialFactor returns the damage multiplier from matching special damage types.
+// A matching primary type doubles the damage, a matching super type quadruples it.
+func (attack Attack) SpecialFactor(target Target) int {
+	factor := 1
+	if (attack.Special.PrimaryValue() != 0) && (attack.Special.PrimaryValue() == target.SpecialVulnerabilities.PrimaryValue()) {
+		factor *= 2
+	}
+	if (attack.Special.SuperValue() != 0) && (attack.Special.SuperValue() == target.SpecialVulnerabilities.SuperValue()) {
+		factor *= 4
+	}
+	return factor
+}
+
+// ArmorReduction returns how much damage is absorbed by armor that is not penetrated.
+func (attack Attack) ArmorReduction(target Target) int {
+	if attack.Penetration >= target.Armor {
+		return 0
+	}
+	return target.Armor - attack.Penetration
+}
+
+// DamageAgainst returns the damage a single hit deals to the target.
+// Special damage is applied first, then armor absorbs its share, and the remainder is divided by
+// two to the power of the toughness. Targets with a toughness of object.ToughnessNoDamage take no damage.
+func (attack Attack) DamageAgainst(target Target) int {
+	if target.Toughness >= object.ToughnessNoDamage {
+		return 0
+	}
+	damage := attack.Damage*attack.SpecialFactor(target) - attack.ArmorReduction(target)
+	if damage <= 0 {
+		return 0
+	}
+	return damage >> uint(target.Toughness)
+}
+
+// HitsToKill returns the number of hits necessary to destroy the target.
+// It returns 0 if the attack does not harm the target.
+func (attack Attack) HitsToKill(target Target) int {
+	damage := attack.DamageAgainst(target)
+	if damage <= 0 {
+		return 0
+	}
+	if target.Hitpoints <= 0 {
+		return 1
+	}
+	return (target.Hitpoints + damage - 1) / damage
+}
+
+// IsVulnerable returns true if the target is vulnerable to any of the damage types of the attack.
+func (attack Attack) IsVulnerable(target Target) bool {
+	return (attack.DamageType & target.Vulnerabilities) != 0
+}
@@ -0,0 +1,43 @@
+package balance_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/object/balance"
+)
+
+func TestAttackDamageAgainstAppliesArmorAndToughness(t *testing.T) {
+	attack := balance.Attack{Damage: 20, Penetration: 5}
+
+	assert.Equal(t, 20, attack.DamageAgainst(balance.Target{Armor: 5}), "penetrated armor")
+	assert.Equal(t, 15, attack.DamageAgainst(balance.Target{Armor: 10}), "absorbed by armor")
+	assert.Equal(t, 7, attack.DamageAgainst(balance.Target{Armor: 10, Toughness: 1}), "toughness")
+	assert.Equal(t, 0, attack.DamageAgainst(balance.Target{Toughness: object.ToughnessNoDamage}), "no damage")
+	assert.Equal(t, 0, attack.DamageAgainst(balance.Target{Armor: 100}), "fully absorbed")
+}
+
+func TestAttackDamageAgainstAppliesSpecialDamage(t *testing.T) {
+	attack := balance.Attack{Damage: 10, Special: object.SpecialDamageType(0).WithPrimaryValue(2).WithSuperValue(3)}
+
+	assert.Equal(t, 20, attack.DamageAgainst(balance.Target{SpecialVulnerabilities: object.SpecialDamageType(0).WithPrimaryValue(2)}))
+	assert.Equal(t, 40, attack.DamageAgainst(balance.Target{SpecialVulnerabilities: object.SpecialDamageType(0).WithSuperValue(3)}))
+	assert.Equal(t, 10, attack.DamageAgainst(balance.Target{}))
+}
+
+func TestAttackHitsToKill(t *testing.T) {
+	attack := balance.Attack{Damage: 10}
+
+	assert.Equal(t, 3, attack.HitsToKill(balance.Target{Hitpoints: 25}))
+	assert.Equal(t, 2, attack.HitsToKill(balance.Target{Hitpoints: 20}))
+	assert.Equal(t, 0, attack.HitsToKill(balance.Target{Hitpoints: 20, Armor: 50}), "no harm")
+}
+
+func TestAttackIsVulnerable(t *testing.T) {
+	attack := balance.Attack{DamageType: object.DamageTypeMask(0).With(object.DamageTypeGas)}
+
+	assert.True(t, attack.IsVulnerable(balance.Target{Vulnerabilities: object.DamageTypeMask(0).With(object.DamageTypeGas)}))
+	assert.False(t, attack.IsVulnerable(balance.Target{Vulnerabilities: object.DamageTypeMask(0).With(object.DamageTypeBio)}))
+}
@@ -6,7 +6,7 @@ import (
 )
 
 var ammoClipGenerics = interpreters.New().
-	Refining("BasicWeapon", 0, 8, basicWeapon, interpreters.Always).
+	RefiningWhen("BasicWeapon", 0, 8, basicWeapon, interpreters.Unconditional).
 	With("CartrigeSize", 8, 1).
 	With("BulletMass", 9, 1).
 	With("BulletSpeed", 10, 2).As(interpreters.RangedValue(-10000, +10000)).
@@ -19,8 +19,8 @@ var critterAttackInfo = interpreters.New().
 	With("Projectile", 0x0011, 4).As(interpreters.SpecialValue("ObjectTriple"))
 
 var critterGenerics = interpreters.New().
-	Refining("PrimaryAttack", 0x0001, 21, critterAttackInfo, interpreters.Always).
-	Refining("SecondaryAttack", 0x0016, 21, critterAttackInfo, interpreters.Always).
+	RefiningWhen("PrimaryAttack", 0x0001, 21, critterAttackInfo, interpreters.Unconditional).
+	RefiningWhen("SecondaryAttack", 0x0016, 21, critterAttackInfo, interpreters.Unconditional).
 	With("Perception", 0x002B, 1).
 	With("Defence", 0x002C, 1).
 	With("ProjectileSourceHeightOffset", 0x002D, 1).As(interpreters.RangedValue(-128, 127)).
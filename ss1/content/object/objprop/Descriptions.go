@@ -0,0 +1,104 @@
+package objprop
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+var builtInGenericDescriptions map[object.Class]*interpreters.Description
+var builtInSpecificDescriptions map[object.Triple]*interpreters.Description
+
+func genericName(objClass object.Class) string {
+	return fmt.Sprintf("Generic/%d", objClass)
+}
+
+func specificName(triple object.Triple) string {
+	if triple.Type == anyObjectType {
+		return fmt.Sprintf("Specific/%d/%d", triple.Class, triple.Subclass)
+	}
+	return fmt.Sprintf("Specific/%d/%d/%d", triple.Class, triple.Subclass, triple.Type)
+}
+
+func keepBuiltInDescriptions() {
+	builtInGenericDescriptions = genericDescriptions
+	builtInSpecificDescriptions = specificDescriptions
+}
+
+// BuiltInDescriptions returns the descriptions of object properties as they are known by this package.
+// Generic properties are named "Generic/<class>", specific properties "Specific/<class>/<subclass>",
+// or "Specific/<class>/<subclass>/<type>" if they only apply to one type.
+func BuiltInDescriptions() interpreters.Catalog {
+	catalog := make(interpreters.Catalog)
+	for objClass, desc := range builtInGenericDescriptions {
+		catalog[genericName(objClass)] = desc
+	}
+	for triple, desc := range builtInSpecificDescriptions {
+		catalog[specificName(triple)] = desc
+	}
+	return catalog
+}
+
+// IsDescriptionName returns whether the given name identifies object properties, as used by BuiltInDescriptions().
+// This includes names of descriptions that are not built in, such as for classes without generic properties.
+func IsDescriptionName(name string) bool {
+	_, isGeneric := parseGenericName(name)
+	_, isSpecific := parseSpecificName(name)
+	return isGeneric || isSpecific
+}
+
+func parseGenericName(name string) (object.Class, bool) {
+	parts := strings.Split(name, "/")
+	if (len(parts) != 2) || (parts[0] != "Generic") {
+		return 0, false
+	}
+	objClass, valid := parseNameNumber(parts[1], object.ClassCount-1)
+	return object.Class(objClass), valid
+}
+
+func parseSpecificName(name string) (object.Triple, bool) {
+	parts := strings.Split(name, "/")
+	if (len(parts) < 3) || (len(parts) > 4) || (parts[0] != "Specific") {
+		return object.Triple{}, false
+	}
+	objClass, classValid := parseNameNumber(parts[1], object.ClassCount-1)
+	objSubclass, subclassValid := parseNameNumber(parts[2], 0xFF)
+	objType, typeValid := anyObjectType, true
+	if len(parts) == 4 {
+		objType, typeValid = parseNameNumber(parts[3], anyObjectType-1)
+	}
+	return object.TripleFrom(objClass, objSubclass, objType), classValid && subclassValid && typeValid
+}
+
+// parseNameNumber returns the value of a number within a name. Only the plain decimal form is accepted.
+func parseNameNumber(text string, max int) (int, bool) {
+	value, err := strconv.Atoi(text)
+	return value, (err == nil) && (value >= 0) && (value <= max) && (strconv.Itoa(value) == text)
+}
+
+// UseDescriptions sets the descriptions to use for object properties, named as in BuiltInDescriptions().
+// Descriptions that are not part of the catalog are reset to the built-in ones.
+// Descriptions of the catalog that are not built in are added, if their name is valid.
+// This function must not be called while data is interpreted.
+func UseDescriptions(catalog interpreters.Catalog) {
+	generic := make(map[object.Class]*interpreters.Description)
+	for objClass, desc := range builtInGenericDescriptions {
+		generic[objClass] = desc
+	}
+	specific := make(map[object.Triple]*interpreters.Description)
+	for triple, desc := range builtInSpecificDescriptions {
+		specific[triple] = desc
+	}
+	for name, desc := range catalog {
+		if objClass, isGeneric := parseGenericName(name); isGeneric {
+			generic[objClass] = desc
+		} else if triple, isSpecific := parseSpecificName(name); isSpecific {
+			specific[triple] = desc
+		}
+	}
+	genericDescriptions = generic
+	specificDescriptions = specific
+}
@@ -0,0 +1,61 @@
+package objprop_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/object/objprop"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltInDescriptionsContainGenericAndSpecificProperties(t *testing.T) {
+	catalog := objprop.BuiltInDescriptions()
+
+	assert.Contains(t, catalog, "Generic/0")
+	assert.Contains(t, catalog, "Specific/0/2")
+}
+
+func TestUseDescriptionsOverridesAndResets(t *testing.T) {
+	defer objprop.UseDescriptions(nil)
+	catalog, err := objprop.BuiltInDescriptions().Merged(interpreters.CatalogSchema{
+		"Generic/0": {Fields: []interpreters.FieldSchema{{Key: "NewField", Start: 0, Count: 1}}},
+	}, nil)
+	require.Nil(t, err, "no error expected")
+
+	objprop.UseDescriptions(catalog)
+	assert.Contains(t, objprop.GenericProperties(object.ClassGun, make([]byte, 2)).Keys(), "NewField")
+
+	objprop.UseDescriptions(nil)
+	assert.NotContains(t, objprop.GenericProperties(object.ClassGun, make([]byte, 2)).Keys(), "NewField")
+}
+
+func TestIsDescriptionName(t *testing.T) {
+	for _, name := range []string{"Generic/0", "Generic/4", "Generic/14", "Specific/0/2", "Specific/3/1", "Specific/3/1/5"} {
+		assert.True(t, objprop.IsDescriptionName(name), "%s should be valid", name)
+	}
+	for _, name := range []string{"Generic", "Generic/15", "Generic/04", "Generic/a", "Specific/3", "Specific/3/1/255",
+		"Specific/3/1/2/0", "Other/1"} {
+		assert.False(t, objprop.IsDescriptionName(name), "%s should be invalid", name)
+	}
+}
+
+func TestUseDescriptionsAddsDescriptionsThatAreNotBuiltIn(t *testing.T) {
+	defer objprop.UseDescriptions(nil)
+	require.NotContains(t, objprop.BuiltInDescriptions(), "Generic/4", "test requires a class without built-in description")
+	catalog, err := objprop.BuiltInDescriptions().Merged(interpreters.CatalogSchema{
+		"Generic/4":      {Fields: []interpreters.FieldSchema{{Key: "NewGeneric", Start: 0, Count: 1}}},
+		"Specific/4/0/1": {Fields: []interpreters.FieldSchema{{Key: "NewSpecific", Start: 0, Count: 1}}},
+	}, objprop.IsDescriptionName)
+	require.Nil(t, err, "no error expected")
+
+	objprop.UseDescriptions(catalog)
+	assert.Equal(t, []string{"NewGeneric"}, objprop.GenericProperties(object.ClassDrug, make([]byte, 1)).Keys())
+	assert.Equal(t, []string{"NewSpecific"},
+		objprop.SpecificProperties(object.TripleFrom(int(object.ClassDrug), 0, 1), make([]byte, 1)).Keys())
+
+	objprop.UseDescriptions(nil)
+	assert.Empty(t, objprop.GenericProperties(object.ClassDrug, make([]byte, 1)).Keys())
+}
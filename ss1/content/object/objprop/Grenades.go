@@ -6,7 +6,7 @@ import (
 )
 
 var grenadeGenerics = interpreters.New().
-	Refining("BasicWeapon", 0, 8, basicWeapon, interpreters.Always).
+	RefiningWhen("BasicWeapon", 0, 8, basicWeapon, interpreters.Unconditional).
 	With("Touchiness", 8, 1).
 	With("BlastRadius", 9, 1).
 	With("BlastCoreRange", 10, 1).
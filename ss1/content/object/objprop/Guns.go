@@ -22,28 +22,28 @@ var weaponGenerics = interpreters.New().
 	0xF0: "AmmoSubclass"}))
 
 var projectileWeapons = interpreters.New().
-	Refining("BasicWeapon", 0, 8, basicWeapon, interpreters.Always).
+	RefiningWhen("BasicWeapon", 0, 8, basicWeapon, interpreters.Unconditional).
 	With("ProjectileTravelSpeed", 8, 1).
 	With("Projectile", 9, 4).As(interpreters.SpecialValue("ObjectTriple")).
 	With("AttackMass", 13, 1).
 	With("AttackSpeed", 14, 2).As(interpreters.RangedValue(-10000, +10000))
 
 var meleeWeapons = interpreters.New().
-	Refining("BasicWeapon", 0, 8, basicWeapon, interpreters.Always).
+	RefiningWhen("BasicWeapon", 0, 8, basicWeapon, interpreters.Unconditional).
 	With("EnergyUsage", 8, 1).
 	With("AttackMass", 9, 1).
 	With("Range", 10, 1).
 	With("AttackSpeed", 11, 2).As(interpreters.RangedValue(-10000, +10000))
 
 var energyBeamWeapons = interpreters.New().
-	Refining("BasicWeapon", 0, 8, basicWeapon, interpreters.Always).
+	RefiningWhen("BasicWeapon", 0, 8, basicWeapon, interpreters.Unconditional).
 	With("MaxCharge", 8, 1).
 	With("AttackMass", 9, 1).
 	With("Range", 10, 1).
 	With("AttackSpeed", 11, 2).As(interpreters.RangedValue(-10000, +10000))
 
 var energyProjectileWeapons = interpreters.New().
-	Refining("BasicWeapon", 0, 8, basicWeapon, interpreters.Always).
+	RefiningWhen("BasicWeapon", 0, 8, basicWeapon, interpreters.Unconditional).
 	With("MaxCharge", 8, 1).
 	With("AttackMass", 9, 1).
 	With("AttackSpeed", 10, 2).As(interpreters.RangedValue(-10000, +10000)).
@@ -13,7 +13,7 @@ var projectileGenerics = interpreters.New().
 	0x08: "BounceOffProjectiles"}))
 
 var cyberProjectiles = interpreters.New().
-	Refining("ColorScheme", 0, 6, cyberColorScheme, interpreters.Always)
+	RefiningWhen("ColorScheme", 0, 6, cyberColorScheme, interpreters.Unconditional)
 
 func initPhysics() {
 	objClass := object.Class(2)
@@ -0,0 +1,346 @@
+package objprop
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1"
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+)
+
+// Sheet column names identifying the object of a row.
+const (
+	SheetColumnClass    = "Class"
+	SheetColumnSubclass = "Subclass"
+	SheetColumnType     = "Type"
+)
+
+const (
+	commonColumnPrefix   = "Common."
+	genericColumnPrefix  = "Generic."
+	specificColumnPrefix = "Specific."
+)
+
+const (
+	errSheetHasNoHeader     ss1.StringError = "sheet has no header row"
+	errSheetHasNoIdentifier ss1.StringError = "sheet has no Class, Subclass, and Type columns"
+)
+
+// SheetCellError describes a problem with a specific cell of a sheet.
+type SheetCellError struct {
+	// Row is the zero-based index of the row, including the header.
+	Row     int
+	Column  string
+	Value   string
+	Problem string
+}
+
+// Error implements the error interface.
+func (err SheetCellError) Error() string {
+	return fmt.Sprintf("row %d, column %s, value '%s': %s", err.Row+1, err.Column, err.Value, err.Problem)
+}
+
+type commonColumn struct {
+	name     string
+	minValue int64
+	maxValue int64
+	get      func(*object.CommonProperties) int64
+	set      func(*object.CommonProperties, int64)
+}
+
+var commonColumns = []commonColumn{
+	{name: "Mass", minValue: math.MinInt32, maxValue: math.MaxInt32,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.Mass) },
+		set: func(prop *object.CommonProperties, value int64) { prop.Mass = int32(value) }},
+	{name: "Hitpoints", minValue: math.MinInt16, maxValue: math.MaxInt16,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.Hitpoints) },
+		set: func(prop *object.CommonProperties, value int64) { prop.Hitpoints = int16(value) }},
+	{name: "Armor", maxValue: math.MaxUint8,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.Armor) },
+		set: func(prop *object.CommonProperties, value int64) { prop.Armor = byte(value) }},
+	{name: "RenderType", maxValue: math.MaxUint8,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.RenderType) },
+		set: func(prop *object.CommonProperties, value int64) { prop.RenderType = object.RenderType(value) }},
+	{name: "PhysicsModel", maxValue: math.MaxUint8,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.PhysicsModel) },
+		set: func(prop *object.CommonProperties, value int64) { prop.PhysicsModel = object.PhysicsModel(value) }},
+	{name: "Hardness", maxValue: object.HardnessLimit,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.Hardness) },
+		set: func(prop *object.CommonProperties, value int64) { prop.Hardness = byte(value) }},
+	{name: "PhysicsXR", maxValue: object.PhysicsXRLimit,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.PhysicsXR) },
+		set: func(prop *object.CommonProperties, value int64) { prop.PhysicsXR = byte(value) }},
+	{name: "PhysicsZ", maxValue: math.MaxUint8,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.PhysicsZ) },
+		set: func(prop *object.CommonProperties, value int64) { prop.PhysicsZ = byte(value) }},
+	{name: "Vulnerabilities", maxValue: math.MaxUint8,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.Vulnerabilities) },
+		set: func(prop *object.CommonProperties, value int64) { prop.Vulnerabilities = object.DamageTypeMask(value) }},
+	{name: "SpecialVulnerabilities", maxValue: math.MaxUint8,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.SpecialVulnerabilities) },
+		set: func(prop *object.CommonProperties, value int64) {
+			prop.SpecialVulnerabilities = object.SpecialDamageType(value)
+		}},
+	{name: "Defense", maxValue: math.MaxUint8,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.Defense) },
+		set: func(prop *object.CommonProperties, value int64) { prop.Defense = byte(value) }},
+	{name: "Toughness", maxValue: math.MaxUint8,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.Toughness) },
+		set: func(prop *object.CommonProperties, value int64) { prop.Toughness = byte(value) }},
+	{name: "Flags", maxValue: math.MaxUint16,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.Flags) },
+		set: func(prop *object.CommonProperties, value int64) { prop.Flags = object.CommonFlagField(value) }},
+	{name: "MfdOrMeshID", maxValue: math.MaxUint16,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.MfdOrMeshID) },
+		set: func(prop *object.CommonProperties, value int64) { prop.MfdOrMeshID = uint16(value) }},
+	{name: "Bitmap3D", maxValue: math.MaxUint16,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.Bitmap3D) },
+		set: func(prop *object.CommonProperties, value int64) { prop.Bitmap3D = object.Bitmap3D(value) }},
+	{name: "DestroyEffect", maxValue: math.MaxUint8,
+		get: func(prop *object.CommonProperties) int64 { return int64(prop.DestroyEffect) },
+		set: func(prop *object.CommonProperties, value int64) { prop.DestroyEffect = object.DestroyEffect(value) }},
+}
+
+// valueLimits describes which values a field of an interpreter may hold.
+type valueLimits struct {
+	minValue int64
+	maxValue int64
+	enum     map[uint32]string
+}
+
+func limitsOf(inst *interpreters.Instance, key string) valueLimits {
+	var limits valueLimits
+	simplifier := interpreters.NewSimplifier(func(minValue, maxValue int64, formatter interpreters.RawValueFormatter) {
+		limits.minValue = minValue
+		limits.maxValue = maxValue
+	})
+	simplifier.SetEnumValueHandler(func(values map[uint32]string) {
+		limits.enum = values
+	})
+	simplifier.SetBitfieldHandler(func(values map[uint32]string) {
+		limits.maxValue = int64(uint64(1)<<(uint(inst.Size(key))*8)) - 1
+	})
+	inst.Describe(key, simplifier)
+	return limits
+}
+
+func (limits valueLimits) check(value int64) string {
+	if limits.enum != nil {
+		if _, known := limits.enum[uint32(value)]; !known {
+			return "not a known enumeration value"
+		}
+		return ""
+	}
+	if (value < limits.minValue) || (value > limits.maxValue) {
+		return fmt.Sprintf("out of range [%d, %d]", limits.minValue, limits.maxValue)
+	}
+	return ""
+}
+
+// signedValue returns the value of the field, sign-extended if the field may hold negative values.
+func signedValue(inst *interpreters.Instance, key string, limits valueLimits) int64 {
+	raw := inst.Get(key)
+	size := inst.Size(key)
+	if (limits.minValue >= 0) || (limits.enum != nil) || (size < 1) || (size > 4) {
+		return int64(raw)
+	}
+	shift := uint(32 - size*8)
+	return int64(int32(raw<<shift) >> shift)
+}
+
+// resolvedInstance returns the instance of a dot-separated key path, and the final key.
+func resolvedInstance(inst *interpreters.Instance, path string) (*interpreters.Instance, string) {
+	keys := strings.Split(path, ".")
+	for _, refinement := range keys[:len(keys)-1] {
+		inst = inst.Refined(refinement)
+	}
+	return inst, keys[len(keys)-1]
+}
+
+// keyPaths returns the dot-separated paths of all fields, including those of active refinements.
+func keyPaths(inst *interpreters.Instance) []string {
+	paths := append([]string{}, inst.Keys()...)
+	for _, refinement := range inst.ActiveRefinements() {
+		for _, subPath := range keyPaths(inst.Refined(refinement)) {
+			paths = append(paths, refinement+"."+subPath)
+		}
+	}
+	return paths
+}
+
+// ClassSheet returns all properties of the objects of given class as a table of text cells.
+// The first row holds the column names. Each further row describes one object, identified by the
+// Class, Subclass, and Type columns. Properties that do not apply to an object are left empty.
+// Generic and specific properties are named as per their interpreter descriptions, with refined
+// properties written as "refinement.key".
+func ClassSheet(table object.PropertiesTable, class object.Class) [][]string {
+	triples := table.TriplesInClass(class)
+	header := []string{SheetColumnClass, SheetColumnSubclass, SheetColumnType}
+	for _, column := range commonColumns {
+		header = append(header, commonColumnPrefix+column.name)
+	}
+	columnIndex := make(map[string]int)
+	addColumns := func(prefix string, paths []string) {
+		for _, path := range paths {
+			name := prefix + path
+			if _, known := columnIndex[name]; !known {
+				columnIndex[name] = len(header)
+				header = append(header, name)
+			}
+		}
+	}
+	for _, triple := range triples {
+		prop, _ := table.ForObject(triple)
+		addColumns(genericColumnPrefix, keyPaths(GenericProperties(class, prop.Generic)))
+	}
+	for _, triple := range triples {
+		prop, _ := table.ForObject(triple)
+		addColumns(specificColumnPrefix, keyPaths(SpecificProperties(triple, prop.Specific)))
+	}
+
+	sheet := [][]string{header}
+	for _, triple := range triples {
+		prop, _ := table.ForObject(triple)
+		row := make([]string, len(header))
+		row[0] = strconv.Itoa(int(triple.Class))
+		row[1] = strconv.Itoa(int(triple.Subclass))
+		row[2] = strconv.Itoa(int(triple.Type))
+		for index, column := range commonColumns {
+			row[3+index] = strconv.FormatInt(column.get(&prop.Common), 10)
+		}
+		fillRow := func(prefix string, root *interpreters.Instance) {
+			for _, path := range keyPaths(root) {
+				inst, key := resolvedInstance(root, path)
+				row[columnIndex[prefix+path]] = strconv.FormatInt(signedValue(inst, key, limitsOf(inst, key)), 10)
+			}
+		}
+		fillRow(genericColumnPrefix, GenericProperties(class, prop.Generic))
+		fillRow(specificColumnPrefix, SpecificProperties(triple, prop.Specific))
+		sheet = append(sheet, row)
+	}
+	return sheet
+}
+
+// ApplyClassSheet reads a sheet as created by ClassSheet and returns the properties of all objects
+// that the sheet modifies. The given table is not modified.
+// Empty cells and columns with unknown names are ignored, as are rows without any object identifier.
+// Values that differ from the current ones are validated against the ranges of their description.
+func ApplyClassSheet(table object.PropertiesTable, class object.Class, sheet [][]string) (map[object.Triple]object.Properties, error) {
+	if len(sheet) == 0 {
+		return nil, errSheetHasNoHeader
+	}
+	header := sheet[0]
+	columnIndex := make(map[string]int)
+	for index, name := range header {
+		columnIndex[strings.TrimSpace(name)] = index
+	}
+	identifierColumns := []string{SheetColumnClass, SheetColumnSubclass, SheetColumnType}
+	for _, name := range identifierColumns {
+		if _, known := columnIndex[name]; !known {
+			return nil, errSheetHasNoIdentifier
+		}
+	}
+
+	result := make(map[object.Triple]object.Properties)
+	for rowIndex, row := range sheet[1:] {
+		rowIndex++
+		cell := func(name string) string {
+			index := columnIndex[name]
+			if index >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[index])
+		}
+		if (cell(SheetColumnClass) == "") && (cell(SheetColumnSubclass) == "") && (cell(SheetColumnType) == "") {
+			continue
+		}
+		var identifier [3]int
+		for index, name := range identifierColumns {
+			value, err := strconv.Atoi(cell(name))
+			if (err != nil) || (value < 0) || (value > 0xFF) {
+				return nil, SheetCellError{Row: rowIndex, Column: name, Value: cell(name), Problem: "not an identifier"}
+			}
+			identifier[index] = value
+		}
+		triple := object.TripleFrom(identifier[0], identifier[1], identifier[2])
+		current, err := table.ForObject(triple)
+		if (err != nil) || (triple.Class != class) {
+			return nil, SheetCellError{Row: rowIndex, Column: SheetColumnType, Value: cell(SheetColumnType),
+				Problem: fmt.Sprintf("no object %d/%d/%d in class %d", identifier[0], identifier[1], identifier[2], class)}
+		}
+		prop := current.Clone()
+		if existing, modified := result[triple]; modified {
+			prop = existing
+		}
+
+		parse := func(name string, currentValue int64, limits valueLimits) (int64, bool, error) {
+			text := cell(name)
+			if text == "" {
+				return 0, false, nil
+			}
+			value, parseErr := strconv.ParseInt(text, 10, 64)
+			if parseErr != nil {
+				return 0, false, SheetCellError{Row: rowIndex, Column: name, Value: text, Problem: "not a number"}
+			}
+			if value == currentValue {
+				return 0, false, nil
+			}
+			if problem := limits.check(value); problem != "" {
+				return 0, false, SheetCellError{Row: rowIndex, Column: name, Value: text, Problem: problem}
+			}
+			return value, true, nil
+		}
+
+		for _, column := range commonColumns {
+			name := commonColumnPrefix + column.name
+			if _, known := columnIndex[name]; !known {
+				continue
+			}
+			value, changed, err := parse(name, column.get(&prop.Common), valueLimits{minValue: column.minValue, maxValue: column.maxValue})
+			if err != nil {
+				return nil, err
+			}
+			if changed {
+				column.set(&prop.Common, value)
+			}
+		}
+		applyInterpreter := func(prefix string, root *interpreters.Instance) error {
+			for _, name := range header {
+				name = strings.TrimSpace(name)
+				if !strings.HasPrefix(name, prefix) {
+					continue
+				}
+				inst, key := resolvedInstance(root, strings.TrimPrefix(name, prefix))
+				if inst.Size(key) == 0 {
+					continue
+				}
+				limits := limitsOf(inst, key)
+				value, changed, err := parse(name, signedValue(inst, key, limits), limits)
+				if err != nil {
+					return err
+				}
+				if changed {
+					inst.Set(key, uint32(value))
+				}
+			}
+			return nil
+		}
+		err = applyInterpreter(genericColumnPrefix, GenericProperties(class, prop.Generic))
+		if err != nil {
+			return nil, err
+		}
+		err = applyInterpreter(specificColumnPrefix, SpecificProperties(triple, prop.Specific))
+		if err != nil {
+			return nil, err
+		}
+
+		if (prop.Common != current.Common) || !bytes.Equal(prop.Generic, current.Generic) || !bytes.Equal(prop.Specific, current.Specific) {
+			result[triple] = prop
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package objprop_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/object/objprop"
+)
+
+func TestClassSheetListsAllObjectsOfClass(t *testing.T) {
+	table := object.StandardPropertiesTable()
+	sheet := objprop.ClassSheet(table, object.ClassGun)
+
+	require.True(t, len(sheet) > 1, "rows expected")
+	assert.Equal(t, len(table.TriplesInClass(object.ClassGun))+1, len(sheet))
+	assert.Equal(t, []string{"Class", "Subclass", "Type"}, sheet[0][:3])
+	assert.Contains(t, sheet[0], "Common.Hitpoints")
+	assert.Contains(t, sheet[0], "Generic.FireRate")
+	assert.Contains(t, sheet[0], "Specific.BasicWeapon.Damage")
+}
+
+func TestApplyClassSheetReturnsNothingForUnchangedSheet(t *testing.T) {
+	table := object.StandardPropertiesTable()
+	sheet := objprop.ClassSheet(table, object.ClassGun)
+
+	result, err := objprop.ApplyClassSheet(table, object.ClassGun, sheet)
+
+	require.Nil(t, err, "no error expected")
+	assert.Empty(t, result)
+}
+
+func TestApplyClassSheetReturnsModifiedObjects(t *testing.T) {
+	table := object.StandardPropertiesTable()
+	sheet := objprop.ClassSheet(table, object.ClassGun)
+	row := rowOf(sheet, "0", "2", "0")
+	setCell(sheet, row, "Common.Hitpoints", "42")
+	setCell(sheet, row, "Specific.AttackSpeed", "-50")
+
+	result, err := objprop.ApplyClassSheet(table, object.ClassGun, sheet)
+
+	require.Nil(t, err, "no error expected")
+	triple := object.TripleFrom(int(object.ClassGun), 2, 0)
+	require.Contains(t, result, triple)
+	assert.Equal(t, int16(42), result[triple].Common.Hitpoints)
+	assert.Equal(t, uint32(0xFFCE), objprop.SpecificProperties(triple, result[triple].Specific).Get("AttackSpeed"))
+	original, _ := table.ForObject(triple)
+	assert.NotEqual(t, int16(42), original.Common.Hitpoints, "table must not be modified")
+
+	assert.Equal(t, "-50", cell(objprop.ClassSheet(tableWith(table, result), object.ClassGun), row, "Specific.AttackSpeed"))
+}
+
+func TestApplyClassSheetRejectsValuesOutOfRange(t *testing.T) {
+	table := object.StandardPropertiesTable()
+	sheet := objprop.ClassSheet(table, object.ClassGun)
+	row := rowOf(sheet, "0", "2", "0")
+	setCell(sheet, row, "Specific.BasicWeapon.Damage", "40000")
+
+	_, err := objprop.ApplyClassSheet(table, object.ClassGun, sheet)
+
+	cellErr, isCellErr := err.(objprop.SheetCellError)
+	require.True(t, isCellErr, "cell error expected")
+	assert.Equal(t, row, cellErr.Row)
+	assert.Equal(t, "Specific.BasicWeapon.Damage", cellErr.Column)
+}
+
+func TestApplyClassSheetRejectsObjectsOfOtherClass(t *testing.T) {
+	table := object.StandardPropertiesTable()
+	sheet := objprop.ClassSheet(table, object.ClassGun)
+	setCell(sheet, 1, "Class", "1")
+
+	_, err := objprop.ApplyClassSheet(table, object.ClassGun, sheet)
+
+	assert.Error(t, err)
+}
+
+func TestApplyClassSheetIgnoresUnknownColumnsAndEmptyCells(t *testing.T) {
+	table := object.StandardPropertiesTable()
+	sheet := [][]string{
+		{"Class", "Subclass", "Type", "Name", "Common.Mass"},
+		{"0", "0", "0", "Some name", ""},
+		{"", "", "", "", ""},
+	}
+
+	result, err := objprop.ApplyClassSheet(table, object.ClassGun, sheet)
+
+	require.Nil(t, err, "no error expected")
+	assert.Empty(t, result)
+}
+
+func rowOf(sheet [][]string, class, subclass, objType string) int {
+	for index, row := range sheet {
+		if (row[0] == class) && (row[1] == subclass) && (row[2] == objType) {
+			return index
+		}
+	}
+	return -1
+}
+
+func columnOf(sheet [][]string, name string) int {
+	for index, column := range sheet[0] {
+		if column == name {
+			return index
+		}
+	}
+	return -1
+}
+
+func setCell(sheet [][]string, row int, name string, value string) {
+	sheet[row][columnOf(sheet, name)] = value
+}
+
+func cell(sheet [][]string, row int, name string) string {
+	return sheet[row][columnOf(sheet, name)]
+}
+
+func tableWith(table object.PropertiesTable, changes map[object.Triple]object.Properties) object.PropertiesTable {
+	for triple, prop := range changes {
+		target, _ := table.ForObject(triple)
+		*target = prop
+	}
+	return table
+}
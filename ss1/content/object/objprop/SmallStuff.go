@@ -6,7 +6,7 @@ import (
 )
 
 var cyberItems = interpreters.New().
-	Refining("ColorScheme", 0, 6, cyberColorScheme, interpreters.Always)
+	RefiningWhen("ColorScheme", 0, 6, cyberColorScheme, interpreters.Unconditional)
 
 func initSmallStuff() {
 	objClass := object.Class(8)
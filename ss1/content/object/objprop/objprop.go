@@ -37,6 +37,8 @@ func init() {
 	initSmallStuff()
 	initAnimating()
 	initCritters()
+
+	keepBuiltInDescriptions()
 }
 
 func setSpecific(objClass object.Class, objSubclass int, desc *interpreters.Description) {
@@ -0,0 +1,105 @@
+package raw
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1"
+)
+
+const (
+	errNotEnoughData ss1.StringError = "not enough data"
+	errValueRange    ss1.StringError = "value out of range"
+)
+
+// Interpretation describes how a sequence of bytes is read as an integer value.
+type Interpretation struct {
+	// Size is the number of bytes, either 1, 2, or 4.
+	Size      int
+	Signed    bool
+	BigEndian bool
+}
+
+// Interpretations lists all supported interpretations, ordered by size.
+var Interpretations = []Interpretation{
+	{Size: 1, Signed: false},
+	{Size: 1, Signed: true},
+	{Size: 2, Signed: false},
+	{Size: 2, Signed: true},
+	{Size: 2, Signed: false, BigEndian: true},
+	{Size: 2, Signed: true, BigEndian: true},
+	{Size: 4, Signed: false},
+	{Size: 4, Signed: true},
+	{Size: 4, Signed: false, BigEndian: true},
+	{Size: 4, Signed: true, BigEndian: true},
+}
+
+// String returns a short name, such as "int16 BE".
+func (interpretation Interpretation) String() string {
+	name := "int"
+	if !interpretation.Signed {
+		name = "uint"
+	}
+	name += fmt.Sprintf("%d", interpretation.Size*8)
+	if interpretation.Size > 1 {
+		if interpretation.BigEndian {
+			name += " BE"
+		} else {
+			name += " LE"
+		}
+	}
+	return name
+}
+
+// Limits returns the minimum and maximum value of the interpretation.
+func (interpretation Interpretation) Limits() (minValue, maxValue int64) {
+	bits := uint(interpretation.Size * 8)
+	if interpretation.Signed {
+		return -(1 << (bits - 1)), (1 << (bits - 1)) - 1
+	}
+	return 0, (1 << bits) - 1
+}
+
+// Read returns the value at given offset. It returns false if there is not enough data.
+func (interpretation Interpretation) Read(data []byte, offset int) (int64, bool) {
+	if !interpretation.fits(data, offset) {
+		return 0, false
+	}
+	var value uint64
+	for i := 0; i < interpretation.Size; i++ {
+		value = (value << 8) | uint64(data[offset+interpretation.byteIndex(i)])
+	}
+	result := int64(value)
+	if interpretation.Signed {
+		bits := uint(64 - interpretation.Size*8)
+		result = (result << bits) >> bits
+	}
+	return result, true
+}
+
+// Write stores the value at given offset.
+func (interpretation Interpretation) Write(data []byte, offset int, value int64) error {
+	if !interpretation.fits(data, offset) {
+		return errNotEnoughData
+	}
+	minValue, maxValue := interpretation.Limits()
+	if (value < minValue) || (value > maxValue) {
+		return errValueRange
+	}
+	for i := interpretation.Size - 1; i >= 0; i-- {
+		data[offset+interpretation.byteIndex(i)] = byte(value)
+		value >>= 8
+	}
+	return nil
+}
+
+// byteIndex returns the offset of the byte for given significance, with 0 being the most significant.
+func (interpretation Interpretation) byteIndex(significance int) int {
+	if interpretation.BigEndian {
+		return significance
+	}
+	return interpretation.Size - 1 - significance
+}
+
+func (interpretation Interpretation) fits(data []byte, offset int) bool {
+	return (offset >= 0) && ((offset + interpretation.Size) <= len(data))
+}
@@ -0,0 +1,80 @@
+package raw_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/raw"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpretationString(t *testing.T) {
+	assert.Equal(t, "uint8", raw.Interpretation{Size: 1}.String())
+	assert.Equal(t, "int16 LE", raw.Interpretation{Size: 2, Signed: true}.String())
+	assert.Equal(t, "uint32 BE", raw.Interpretation{Size: 4, BigEndian: true}.String())
+}
+
+func TestInterpretationRead(t *testing.T) {
+	data := []byte{0x00, 0xFE, 0xFF, 0x12, 0x34}
+	tt := []struct {
+		interpretation raw.Interpretation
+		offset         int
+		expected       int64
+	}{
+		{interpretation: raw.Interpretation{Size: 1}, offset: 1, expected: 0xFE},
+		{interpretation: raw.Interpretation{Size: 1, Signed: true}, offset: 1, expected: -2},
+		{interpretation: raw.Interpretation{Size: 2}, offset: 3, expected: 0x3412},
+		{interpretation: raw.Interpretation{Size: 2, BigEndian: true}, offset: 3, expected: 0x1234},
+		{interpretation: raw.Interpretation{Size: 2, Signed: true}, offset: 1, expected: -2},
+		{interpretation: raw.Interpretation{Size: 2, Signed: true, BigEndian: true}, offset: 1, expected: -257},
+		{interpretation: raw.Interpretation{Size: 4}, offset: 1, expected: 0x3412FFFE},
+		{interpretation: raw.Interpretation{Size: 4, Signed: true, BigEndian: true}, offset: 0, expected: 0x00FEFF12},
+		{interpretation: raw.Interpretation{Size: 4, Signed: true, BigEndian: true}, offset: 1, expected: -16838092},
+	}
+	for _, tc := range tt {
+		td := tc
+		t.Run(td.interpretation.String(), func(t *testing.T) {
+			value, ok := td.interpretation.Read(data, td.offset)
+			require.True(t, ok, "value expected")
+			assert.Equal(t, td.expected, value)
+		})
+	}
+}
+
+func TestInterpretationReadFailsBeyondData(t *testing.T) {
+	_, ok := raw.Interpretation{Size: 4}.Read([]byte{0x00, 0x01, 0x02, 0x03}, 1)
+
+	assert.False(t, ok)
+}
+
+func TestInterpretationWriteStoresValue(t *testing.T) {
+	data := make([]byte, 4)
+	err := raw.Interpretation{Size: 2, Signed: true, BigEndian: true}.Write(data, 1, -2)
+
+	require.Nil(t, err, "no error expected")
+	assert.Equal(t, []byte{0x00, 0xFF, 0xFE, 0x00}, data)
+}
+
+func TestInterpretationWriteReadRoundTrip(t *testing.T) {
+	for _, interpretation := range raw.Interpretations {
+		data := make([]byte, 4)
+		minValue, maxValue := interpretation.Limits()
+		for _, value := range []int64{minValue, maxValue, 1} {
+			err := interpretation.Write(data, 0, value)
+			require.Nil(t, err, "no error expected for %v", interpretation)
+			read, _ := interpretation.Read(data, 0)
+			assert.Equal(t, value, read, "mismatch for %v", interpretation)
+		}
+	}
+}
+
+func TestInterpretationWriteFailsForValuesOutOfRange(t *testing.T) {
+	data := make([]byte, 2)
+
+	assert.NotNil(t, raw.Interpretation{Size: 1}.Write(data, 0, 256), "error expected for too large value")
+	assert.NotNil(t, raw.Interpretation{Size: 1}.Write(data, 0, -1), "error expected for negative unsigned")
+	assert.NotNil(t, raw.Interpretation{Size: 1, Signed: true}.Write(data, 0, 128), "error expected for too large signed")
+	assert.NotNil(t, raw.Interpretation{Size: 2}.Write(data, 1, 0), "error expected beyond data")
+	assert.Equal(t, []byte{0x00, 0x00}, data, "data must not be modified")
+}
@@ -0,0 +1,110 @@
+package raw
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+)
+
+// Field is a named range of bytes within a block.
+type Field struct {
+	Name  string
+	Start int
+	Count int
+}
+
+// Overlay describes the known structure of a block of data.
+type Overlay struct {
+	// Fields are the known fields, sorted by start.
+	Fields []Field
+	// Undefined has a bit set for every bit of the data that is not covered by a field.
+	// It is nil if the structure of the data is not known at all.
+	Undefined []byte
+}
+
+// NewOverlay returns an overlay for data of given size, with all bits undefined.
+func NewOverlay(size int) Overlay {
+	undefined := make([]byte, size)
+	for i := range undefined {
+		undefined[i] = 0xFF
+	}
+	return Overlay{Undefined: undefined}
+}
+
+// AddField registers a field of which all bits are defined.
+func (overlay *Overlay) AddField(name string, start, count int) {
+	if !overlay.isValidRange(start, count) {
+		return
+	}
+	overlay.insert(Field{Name: name, Start: start, Count: count})
+	for i := start; i < start+count; i++ {
+		overlay.Undefined[i] = 0x00
+	}
+}
+
+// AddInstance registers all fields of the instance, including active refinements, located at given offset.
+// The names of the fields are prefixed with the given string.
+// Bits are considered defined according to the instance.
+func (overlay *Overlay) AddInstance(inst *interpreters.Instance, offset int, prefix string) {
+	if !overlay.isValidRange(offset, len(inst.Raw())) {
+		return
+	}
+	for _, layout := range inst.Layout() {
+		overlay.insert(Field{Name: prefix + layout.Key, Start: offset + layout.Start, Count: layout.Count})
+	}
+	for index, mask := range inst.Undefined() {
+		overlay.Undefined[offset+index] = mask
+	}
+}
+
+// FieldAt returns the index of the first field that covers the given offset.
+// It returns -1 if there is no such field.
+func (overlay Overlay) FieldAt(offset int) int {
+	for index, field := range overlay.Fields {
+		if field.Start > offset {
+			break
+		}
+		if offset < field.Start+field.Count {
+			return index
+		}
+	}
+	return -1
+}
+
+// UndefinedBits returns the mask of undefined bits at given offset.
+// It returns zero if the structure is not known at all.
+func (overlay Overlay) UndefinedBits(offset int) byte {
+	if (offset < 0) || (offset >= len(overlay.Undefined)) {
+		return 0x00
+	}
+	return overlay.Undefined[offset]
+}
+
+// UndefinedRanges returns the ranges of bytes that contain undefined bits. The returned fields have no name.
+func (overlay Overlay) UndefinedRanges() []Field {
+	var ranges []Field
+	for offset, mask := range overlay.Undefined {
+		if mask == 0x00 {
+			continue
+		}
+		last := len(ranges) - 1
+		if (last >= 0) && (ranges[last].Start+ranges[last].Count == offset) {
+			ranges[last].Count++
+		} else {
+			ranges = append(ranges, Field{Start: offset, Count: 1})
+		}
+	}
+	return ranges
+}
+
+func (overlay *Overlay) isValidRange(start, count int) bool {
+	return (start >= 0) && (count >= 0) && ((start + count) <= len(overlay.Undefined))
+}
+
+func (overlay *Overlay) insert(field Field) {
+	at := len(overlay.Fields)
+	for (at > 0) && (overlay.Fields[at-1].Start > field.Start) {
+		at--
+	}
+	overlay.Fields = append(overlay.Fields, Field{})
+	copy(overlay.Fields[at+1:], overlay.Fields[at:])
+	overlay.Fields[at] = field
+}
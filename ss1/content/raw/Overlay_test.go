@@ -0,0 +1,57 @@
+package raw_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/raw"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverlayWithoutStructureHasNoUndefinedBits(t *testing.T) {
+	var overlay raw.Overlay
+
+	assert.Equal(t, byte(0x00), overlay.UndefinedBits(0))
+	assert.Equal(t, -1, overlay.FieldAt(0))
+}
+
+func TestOverlayAddFieldDefinesBytes(t *testing.T) {
+	overlay := raw.NewOverlay(4)
+	overlay.AddField("second", 2, 1)
+	overlay.AddField("first", 0, 2)
+
+	assert.Equal(t, []raw.Field{{Name: "first", Start: 0, Count: 2}, {Name: "second", Start: 2, Count: 1}}, overlay.Fields)
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0xFF}, overlay.Undefined)
+	assert.Equal(t, 0, overlay.FieldAt(1))
+	assert.Equal(t, 1, overlay.FieldAt(2))
+	assert.Equal(t, -1, overlay.FieldAt(3))
+}
+
+func TestOverlayAddFieldIgnoresFieldsBeyondData(t *testing.T) {
+	overlay := raw.NewOverlay(2)
+	overlay.AddField("outside", 1, 2)
+
+	assert.Empty(t, overlay.Fields)
+}
+
+func TestOverlayAddInstanceRegistersFieldsAtOffset(t *testing.T) {
+	data := []byte{0x01, 0x02, 0x03}
+	inst := interpreters.New().
+		With("a", 0, 1).
+		RefiningWhen("sub", 1, 2, interpreters.New().With("b", 1, 1), interpreters.Unconditional).
+		For(data)
+	overlay := raw.NewOverlay(6)
+	overlay.AddInstance(inst, 2, "entry.")
+
+	assert.Equal(t, []raw.Field{{Name: "entry.a", Start: 2, Count: 1}, {Name: "entry.sub.b", Start: 4, Count: 1}}, overlay.Fields)
+	assert.Equal(t, []byte{0xFF, 0xFF, 0x00, 0xFF, 0x00, 0xFF}, overlay.Undefined)
+}
+
+func TestOverlayUndefinedRanges(t *testing.T) {
+	overlay := raw.NewOverlay(6)
+	overlay.AddField("a", 1, 2)
+	overlay.AddField("b", 4, 1)
+
+	assert.Equal(t, []raw.Field{{Start: 0, Count: 1}, {Start: 3, Count: 1}, {Start: 5, Count: 1}}, overlay.UndefinedRanges())
+}
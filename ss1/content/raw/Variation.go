@@ -0,0 +1,41 @@
+package raw
+
+// Variation returns, for each offset, whether the blocks have different values at that offset.
+// The result has the length of the longest block. Blocks that are shorter than an offset
+// are considered to differ from those that have a value there. Nil blocks are ignored.
+func Variation(blocks [][]byte) []bool {
+	maxLength := 0
+	for _, block := range blocks {
+		if len(block) > maxLength {
+			maxLength = len(block)
+		}
+	}
+	result := make([]bool, maxLength)
+	for offset := 0; offset < maxLength; offset++ {
+		result[offset] = variesAt(blocks, offset)
+	}
+	return result
+}
+
+func variesAt(blocks [][]byte, offset int) bool {
+	first := true
+	var reference byte
+	referenceExists := false
+	for _, block := range blocks {
+		if block == nil {
+			continue
+		}
+		exists := offset < len(block)
+		var value byte
+		if exists {
+			value = block[offset]
+		}
+		if first {
+			reference, referenceExists = value, exists
+			first = false
+		} else if (exists != referenceExists) || (value != reference) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package raw_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/raw"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariationMarksDifferingOffsets(t *testing.T) {
+	result := raw.Variation([][]byte{{0x01, 0x02, 0x03}, {0x01, 0x00, 0x03}, {0x01, 0x02, 0x03}})
+
+	assert.Equal(t, []bool{false, true, false}, result)
+}
+
+func TestVariationConsidersShorterBlocksDifferent(t *testing.T) {
+	result := raw.Variation([][]byte{{0x01}, {0x01, 0x02}})
+
+	assert.Equal(t, []bool{false, true}, result)
+}
+
+func TestVariationIgnoresNilBlocks(t *testing.T) {
+	result := raw.Variation([][]byte{nil, {0x01, 0x02}, nil, {0x01, 0x02}})
+
+	assert.Equal(t, []bool{false, false}, result)
+}
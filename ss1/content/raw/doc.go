@@ -0,0 +1,2 @@
+// Package raw provides helpers to inspect binary data of which the structure is not (fully) known.
+package raw
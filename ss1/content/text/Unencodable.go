@@ -0,0 +1,19 @@
+package text
+
+// UnencodableRunes returns the characters of the given value that the codepage can not represent.
+// Each character is reported once, in the order of first appearance.
+func UnencodableRunes(cp Codepage, value string) []rune {
+	var result []rune
+	checked := make(map[rune]bool)
+	for _, r := range value {
+		if _, known := checked[r]; known {
+			continue
+		}
+		encodable := cp.Decode(cp.Encode(string(r))) == string(r)
+		checked[r] = encodable
+		if !encodable {
+			result = append(result, r)
+		}
+	}
+	return result
+}
@@ -0,0 +1,21 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/text"
+)
+
+func TestUnencodableRunesIsEmptyForKnownCharacters(t *testing.T) {
+	result := text.UnencodableRunes(text.DefaultCodepage(), "Grüße?\nÉ")
+
+	assert.Empty(t, result)
+}
+
+func TestUnencodableRunesReportsEachUnknownCharacterOnce(t *testing.T) {
+	result := text.UnencodableRunes(text.DefaultCodepage(), "„quoted” and „again”")
+
+	assert.Equal(t, []rune{'„', '”'}, result)
+}
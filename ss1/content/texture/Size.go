@@ -0,0 +1,32 @@
+package texture
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// Size describes the side length, in pixels, of one of the square bitmaps of a world texture.
+type Size int
+
+// Size constants.
+const (
+	SizeIcon   Size = 16
+	SizeSmall  Size = 32
+	SizeMedium Size = 64
+	SizeLarge  Size = 128
+)
+
+// Sizes returns all sizes of a world texture, from largest to smallest.
+func Sizes() []Size {
+	return []Size{SizeLarge, SizeMedium, SizeSmall, SizeIcon}
+}
+
+// ScaledImage returns a copy of the source image, resampled to the given size.
+// The whole source is scaled, non-square images are stretched.
+// A Catmull-Rom filter is used, which keeps details when downscaling high-resolution images.
+func ScaledImage(source image.Image, size Size) image.Image {
+	result := image.NewNRGBA(image.Rect(0, 0, int(size), int(size)))
+	draw.CatmullRom.Scale(result, result.Bounds(), source, source.Bounds(), draw.Src, nil)
+	return result
+}
@@ -0,0 +1,35 @@
+package texture_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/content/texture"
+)
+
+func TestSizesAreOrderedFromLargestToSmallest(t *testing.T) {
+	assert.Equal(t, []texture.Size{texture.SizeLarge, texture.SizeMedium, texture.SizeSmall, texture.SizeIcon}, texture.Sizes())
+}
+
+func TestScaledImageHasRequestedSize(t *testing.T) {
+	source := image.NewNRGBA(image.Rect(10, 20, 522, 276))
+	for _, size := range texture.Sizes() {
+		result := texture.ScaledImage(source, size)
+		assert.Equal(t, image.Rect(0, 0, int(size), int(size)), result.Bounds(), "wrong bounds for size %d", size)
+	}
+}
+
+func TestScaledImageKeepsUniformColor(t *testing.T) {
+	source := image.NewNRGBA(image.Rect(0, 0, 512, 512))
+	clr := color.NRGBA{R: 0x40, G: 0x80, B: 0xC0, A: 0xFF}
+	for y := 0; y < 512; y++ {
+		for x := 0; x < 512; x++ {
+			source.SetNRGBA(x, y, clr)
+		}
+	}
+	result := texture.ScaledImage(source, texture.SizeIcon)
+	assert.Equal(t, clr, color.NRGBAModel.Convert(result.At(8, 8)))
+}
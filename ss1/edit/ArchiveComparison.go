@@ -0,0 +1,101 @@
+package edit
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"github.com/inkyblackness/hacked/ss1"
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+const errNotAnArchive ss1.StringError = "file does not contain an archive"
+
+// LevelComparison describes how one level differs between two archives.
+type LevelComparison struct {
+	ID          int
+	Differences level.Differences
+}
+
+// ArchiveComparison describes how two archives, such as savegames, differ.
+type ArchiveComparison struct {
+	State  []archive.GameStateDifference
+	Levels []LevelComparison
+}
+
+// IsEmpty returns true if there are no differences.
+func (comparison ArchiveComparison) IsEmpty() bool {
+	return (len(comparison.State) == 0) && (len(comparison.Levels) == 0)
+}
+
+// LoadArchiveFile reads the given file and returns its resources, if it contains an archive.
+// The resources are available for any language.
+func LoadArchiveFile(filename string) (resource.Localizer, error) {
+	fileData, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := lgres.ReaderFrom(bytes.NewReader(fileData))
+	if err != nil {
+		return nil, err
+	}
+	list := resource.LocalizedResourcesList{{ID: filename, Language: resource.LangAny, Viewer: reader}}
+	if len(gameStateDataFrom(list)) == 0 {
+		return nil, errNotAnArchive
+	}
+	return list, nil
+}
+
+// CompareArchives returns the differences between the two archives.
+// Only levels that differ are listed.
+func CompareArchives(oldArchive, newArchive resource.Localizer, cp text.Codepage) ArchiveComparison {
+	var comparison ArchiveComparison
+	comparison.State = archive.CompareGameStates(gameStateFrom(oldArchive), gameStateFrom(newArchive), cp)
+	for levelID := 0; levelID < archive.MaxLevels; levelID++ {
+		oldAvailable := isLevelIn(oldArchive, levelID)
+		newAvailable := isLevelIn(newArchive, levelID)
+		if !oldAvailable && !newAvailable {
+			continue
+		}
+		diff := level.CompareLevels(
+			level.NewLevel(ids.LevelResourcesStart, levelID, oldArchive),
+			level.NewLevel(ids.LevelResourcesStart, levelID, newArchive))
+		if !diff.IsEmpty() {
+			comparison.Levels = append(comparison.Levels, LevelComparison{ID: levelID, Differences: diff})
+		}
+	}
+	return comparison
+}
+
+func isLevelIn(localizer resource.Localizer, levelID int) bool {
+	_, err := localizer.LocalizedResources(resource.LangAny).Select(ids.LevelResourcesStart.Plus(lvlids.PerLevel*levelID + lvlids.Information))
+	return err == nil
+}
+
+func gameStateFrom(localizer resource.Localizer) *archive.GameState {
+	data := gameStateDataFrom(localizer)
+	if len(data) == 0 {
+		return archive.DefaultGameState()
+	}
+	return archive.NewGameState(data)
+}
+
+func gameStateDataFrom(localizer resource.Localizer) []byte {
+	view, err := localizer.LocalizedResources(resource.LangAny).Select(ids.GameState)
+	if (err != nil) || (view.BlockCount() < 1) {
+		return nil
+	}
+	reader, err := view.Block(0)
+	if err != nil {
+		return nil
+	}
+	data := archive.ZeroGameStateData()
+	_, _ = io.ReadFull(reader, data)
+	return data
+}
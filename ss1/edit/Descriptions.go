@@ -0,0 +1,137 @@
+package edit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlobj"
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/content/object/objprop"
+)
+
+// Prefixes of the descriptions of the packages in the combined catalog.
+const (
+	descriptionPrefixArchive          = "Archive/"
+	descriptionPrefixLevelObjects     = "LevelObjects/"
+	descriptionPrefixObjectProperties = "ObjectProperties/"
+)
+
+// DescriptionFileError is returned for description schema files that could not be used.
+type DescriptionFileError struct {
+	Filename string
+	Err      error
+}
+
+// Error returns the description of the problem.
+func (err DescriptionFileError) Error() string {
+	return err.Filename + ": " + err.Err.Error()
+}
+
+// Unwrap returns the nested error.
+func (err DescriptionFileError) Unwrap() error {
+	return err.Err
+}
+
+// BuiltInDescriptions returns the descriptions of all interpreted data, as they are known by the editor.
+// The names of the descriptions are prefixed by the area they apply to.
+func BuiltInDescriptions() interpreters.Catalog {
+	catalog := make(interpreters.Catalog)
+	addPrefixed(catalog, descriptionPrefixArchive, archive.BuiltInDescriptions())
+	addPrefixed(catalog, descriptionPrefixLevelObjects, lvlobj.BuiltInDescriptions())
+	addPrefixed(catalog, descriptionPrefixObjectProperties, objprop.BuiltInDescriptions())
+	return catalog
+}
+
+func addPrefixed(catalog interpreters.Catalog, prefix string, source interpreters.Catalog) {
+	for name, desc := range source {
+		catalog[prefix+name] = desc
+	}
+}
+
+func withoutPrefix(catalog interpreters.Catalog, prefix string) interpreters.Catalog {
+	result := make(interpreters.Catalog)
+	for name, desc := range catalog {
+		if strings.HasPrefix(name, prefix) {
+			result[strings.TrimPrefix(name, prefix)] = desc
+		}
+	}
+	return result
+}
+
+// isDescriptionName returns whether a description of given name can be used, even if it is not built in.
+func isDescriptionName(name string) bool {
+	switch {
+	case strings.HasPrefix(name, descriptionPrefixLevelObjects):
+		return lvlobj.IsDescriptionName(strings.TrimPrefix(name, descriptionPrefixLevelObjects))
+	case strings.HasPrefix(name, descriptionPrefixObjectProperties):
+		return objprop.IsDescriptionName(strings.TrimPrefix(name, descriptionPrefixObjectProperties))
+	default:
+		return false
+	}
+}
+
+// SaveBuiltInDescriptions writes the schema of all built-in descriptions into the given file.
+// The file serves as a reference for writing overrides.
+func SaveBuiltInDescriptions(filename string) error {
+	schema, err := BuiltInDescriptions().Schema()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0640)
+}
+
+// LoadDescriptionSchema reads a schema file, as written by SaveBuiltInDescriptions.
+// Override files contain only the descriptions, fields, and refinements that shall be changed.
+func LoadDescriptionSchema(filename string) (interpreters.CatalogSchema, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var schema interpreters.CatalogSchema
+	err = json.Unmarshal(data, &schema)
+	if err != nil {
+		return nil, DescriptionFileError{Filename: filename, Err: err}
+	}
+	return schema, nil
+}
+
+// UseDescriptionFiles merges the schemas of the given files, in order, on top of the built-in descriptions,
+// and uses the result for all further interpretation of data.
+// Should any file fail to load, the built-in descriptions are used and the error is returned.
+func UseDescriptionFiles(filenames []string) error {
+	catalog := BuiltInDescriptions()
+	var err error
+	for _, filename := range filenames {
+		catalog, err = mergedDescriptionFile(catalog, filename)
+		if err != nil {
+			useDescriptions(BuiltInDescriptions())
+			return err
+		}
+	}
+	useDescriptions(catalog)
+	return nil
+}
+
+func mergedDescriptionFile(catalog interpreters.Catalog, filename string) (interpreters.Catalog, error) {
+	schema, err := LoadDescriptionSchema(filename)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := catalog.Merged(schema, isDescriptionName)
+	if err != nil {
+		return nil, DescriptionFileError{Filename: filename, Err: err}
+	}
+	return merged, nil
+}
+
+func useDescriptions(catalog interpreters.Catalog) {
+	archive.UseDescriptions(withoutPrefix(catalog, descriptionPrefixArchive))
+	lvlobj.UseDescriptions(withoutPrefix(catalog, descriptionPrefixLevelObjects))
+	objprop.UseDescriptions(withoutPrefix(catalog, descriptionPrefixObjectProperties))
+}
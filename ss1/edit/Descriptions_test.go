@@ -0,0 +1,86 @@
+package edit_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/interpreters"
+	"github.com/inkyblackness/hacked/ss1/edit"
+)
+
+func descriptionFile(t *testing.T, dir string, schema interpreters.CatalogSchema) string {
+	t.Helper()
+	data, err := json.Marshal(schema)
+	require.Nil(t, err)
+	filename := filepath.Join(dir, "descriptions.json")
+	err = ioutil.WriteFile(filename, data, 0640)
+	require.Nil(t, err)
+	return filename
+}
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "descriptions")
+	require.Nil(t, err)
+	return dir
+}
+
+func currentLevelSize() int {
+	return archive.NewGameState(archive.ZeroGameStateData()).Size("Current Level")
+}
+
+func TestUseDescriptionFilesAppliesOverride(t *testing.T) {
+	dir := tempDir(t)
+	defer func() { _ = os.RemoveAll(dir) }()
+	defer func() { _ = edit.UseDescriptionFiles(nil) }()
+	filename := descriptionFile(t, dir, interpreters.CatalogSchema{
+		"Archive/GameState": {Fields: []interpreters.FieldSchema{{Key: "Current Level", Start: 0x0039, Count: 2}}},
+	})
+
+	err := edit.UseDescriptionFiles([]string{filename})
+
+	require.Nil(t, err)
+	assert.Equal(t, 2, currentLevelSize(), "overridden field expected")
+}
+
+func TestUseDescriptionFilesRejectsUnknownPrefix(t *testing.T) {
+	dir := tempDir(t)
+	defer func() { _ = os.RemoveAll(dir) }()
+	defer func() { _ = edit.UseDescriptionFiles(nil) }()
+	filename := descriptionFile(t, dir, interpreters.CatalogSchema{
+		"Unknown/GameState": {Fields: []interpreters.FieldSchema{{Key: "Current Level", Start: 0x0039, Count: 2}}},
+	})
+
+	err := edit.UseDescriptionFiles([]string{filename})
+
+	require.NotNil(t, err, "error expected")
+	fileErr, isFileError := err.(edit.DescriptionFileError)
+	require.True(t, isFileError, "description file error expected")
+	assert.Equal(t, interpreters.UnknownDescriptionError{Name: "Unknown/GameState"}, fileErr.Err)
+	assert.Equal(t, 1, currentLevelSize(), "built-in field expected")
+}
+
+func TestBuiltInDescriptionsSchemaCanBeMergedFromJSON(t *testing.T) {
+	builtIn := edit.BuiltInDescriptions()
+	schema, err := builtIn.Schema()
+	require.Nil(t, err)
+	data, err := json.Marshal(schema)
+	require.Nil(t, err)
+	var loaded interpreters.CatalogSchema
+	err = json.Unmarshal(data, &loaded)
+	require.Nil(t, err)
+
+	merged, err := builtIn.Merged(loaded, nil)
+
+	require.Nil(t, err)
+	mergedSchema, err := merged.Schema()
+	require.Nil(t, err)
+	assert.Equal(t, schema, mergedSchema)
+}
@@ -0,0 +1,91 @@
+package edit
+
+import (
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+)
+
+// GameVariableAccess identifies an object in a level that accesses a game variable.
+type GameVariableAccess struct {
+	LevelID  int
+	ObjectID level.ObjectID
+	Access   level.VariableAccess
+}
+
+// GameVariableUsage lists all accesses to one game variable.
+type GameVariableUsage struct {
+	Reads  []GameVariableAccess
+	Writes []GameVariableAccess
+}
+
+// IsUsed returns true if the variable is accessed at all.
+func (usage GameVariableUsage) IsUsed() bool {
+	return (len(usage.Reads) > 0) || (len(usage.Writes) > 0)
+}
+
+// IsWrittenOnly returns true if the variable is written, yet never read.
+func (usage GameVariableUsage) IsWrittenOnly() bool {
+	return (len(usage.Writes) > 0) && (len(usage.Reads) == 0)
+}
+
+// IsReadOnly returns true if the variable is read, yet never written.
+func (usage GameVariableUsage) IsReadOnly() bool {
+	return (len(usage.Reads) > 0) && (len(usage.Writes) == 0)
+}
+
+// GameVariableUsageIndex lists the usage of game variables by level objects, across all levels.
+type GameVariableUsageIndex struct {
+	usages map[level.VariableReference]*GameVariableUsage
+}
+
+// NewGameVariableUsageIndex scans all available levels and returns the resulting index.
+// Levels are scanned in order, and objects in the order of their level.
+func NewGameVariableUsageIndex(levels *EditableLevels) GameVariableUsageIndex {
+	index := GameVariableUsageIndex{usages: make(map[level.VariableReference]*GameVariableUsage)}
+	for levelID := 0; levelID < archive.MaxLevels; levelID++ {
+		if !levels.IsLevelAvailable(levelID) {
+			continue
+		}
+		currentLevelID := levelID
+		levels.Level(levelID).ForEachVariableAccess(func(id level.ObjectID, access level.VariableAccess) {
+			index.add(GameVariableAccess{LevelID: currentLevelID, ObjectID: id, Access: access})
+		})
+	}
+	return index
+}
+
+func (index GameVariableUsageIndex) add(access GameVariableAccess) {
+	usage, existing := index.usages[access.Access.Variable]
+	if !existing {
+		usage = &GameVariableUsage{}
+		index.usages[access.Access.Variable] = usage
+	}
+	if access.Access.Kind == level.VariableWrite {
+		usage.Writes = append(usage.Writes, access)
+	} else {
+		usage.Reads = append(usage.Reads, access)
+	}
+}
+
+// IsAvailable returns true if the index was created from levels.
+func (index GameVariableUsageIndex) IsAvailable() bool {
+	return index.usages != nil
+}
+
+// BooleanVariable returns the usage of the identified boolean variable.
+func (index GameVariableUsageIndex) BooleanVariable(varIndex int) GameVariableUsage {
+	return index.usageOf(level.VariableReference{Integer: false, Index: varIndex})
+}
+
+// IntegerVariable returns the usage of the identified integer variable.
+func (index GameVariableUsageIndex) IntegerVariable(varIndex int) GameVariableUsage {
+	return index.usageOf(level.VariableReference{Integer: true, Index: varIndex})
+}
+
+func (index GameVariableUsageIndex) usageOf(ref level.VariableReference) GameVariableUsage {
+	usage, existing := index.usages[ref]
+	if !existing {
+		return GameVariableUsage{}
+	}
+	return *usage
+}
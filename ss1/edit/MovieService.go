@@ -8,6 +8,7 @@ import (
 	"github.com/inkyblackness/hacked/ss1/content/text"
 	"github.com/inkyblackness/hacked/ss1/edit/media"
 	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
 )
 
 // MovieService provides read/write functionality.
@@ -76,7 +77,7 @@ func (service MovieService) swapScenes(setter media.MovieBlockSetter, key resour
 	if (sceneB < 0) || (sceneB >= (len(baseContainer.Video.Scenes))) {
 		return
 	}
-	scenes := make([]movie.HighResScene, len(baseContainer.Video.Scenes))
+	scenes := make([]movie.CompressedScene, len(baseContainer.Video.Scenes))
 	copy(scenes, baseContainer.Video.Scenes)
 	scenes[sceneA] = baseContainer.Video.Scenes[sceneB]
 	scenes[sceneB] = baseContainer.Video.Scenes[sceneA]
@@ -85,9 +86,14 @@ func (service MovieService) swapScenes(setter media.MovieBlockSetter, key resour
 }
 
 // AddScene adds the given scene at the end of the movie.
-func (service MovieService) AddScene(setter media.MovieBlockSetter, key resource.Key, scene movie.HighResScene) {
+func (service MovieService) AddScene(setter media.MovieBlockSetter, key resource.Key, scene movie.CompressedScene) {
+	service.AddScenes(setter, key, []movie.CompressedScene{scene})
+}
+
+// AddScenes adds the given scenes, in order, at the end of the movie.
+func (service MovieService) AddScenes(setter media.MovieBlockSetter, key resource.Key, scenes []movie.CompressedScene) {
 	baseContainer := service.getBaseContainer(key)
-	baseContainer.Video.Scenes = append(baseContainer.Video.Scenes, scene)
+	baseContainer.Video.Scenes = append(baseContainer.Video.Scenes, scenes...)
 	service.movieSetter.Set(setter, key, baseContainer)
 }
 
@@ -97,7 +103,7 @@ func (service MovieService) RemoveScene(setter media.MovieBlockSetter, key resou
 	if (scene < 0) || (scene >= len(baseContainer.Video.Scenes)) {
 		return
 	}
-	scenes := make([]movie.HighResScene, len(baseContainer.Video.Scenes)-1)
+	scenes := make([]movie.CompressedScene, len(baseContainer.Video.Scenes)-1)
 	copy(scenes[0:scene], baseContainer.Video.Scenes[0:scene])
 	copy(scenes[scene:], baseContainer.Video.Scenes[scene+1:])
 	baseContainer.Video.Scenes = scenes
@@ -150,6 +156,14 @@ func (service MovieService) getBaseContainer(key resource.Key) movie.Container {
 				Height: movie.HighResDefaultHeight,
 			},
 		}
+		if isLowResMovie(key.ID) {
+			container.Video.Width = movie.LowResDefaultWidth
+			container.Video.Height = movie.LowResDefaultHeight
+		}
 	}
 	return container
 }
+
+func isLowResMovie(id resource.ID) bool {
+	return (id == ids.LowResMovieIntro) || (id == ids.LowResMovieDeath) || (id == ids.LowResMovieEnd)
+}
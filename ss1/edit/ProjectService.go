@@ -2,6 +2,7 @@ package edit
 
 import (
 	"bytes"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"time"
@@ -26,6 +27,8 @@ const (
 type ProjectSettings struct {
 	ModFiles []string
 	Manifest []ManifestEntrySettings
+	// DescriptionFiles are schema files with overrides of the data interpreters, applied in order.
+	DescriptionFiles []string `json:",omitempty"`
 }
 
 // ManifestEntrySettings describe the properties of one manifest entry in a project.
@@ -61,15 +64,31 @@ type ProjectService struct {
 
 	mod     *world.Mod
 	modPath string
+	// storedFiles lists the resource files of the mod, as they were last loaded or saved.
+	storedFiles map[string]storedFileState
+	// descriptionFiles are the absolute paths of the schema files for the data interpreters.
+	descriptionFiles []string
+	// descriptionErr is the problem of the last attempt to apply the description files, if any.
+	descriptionErr error
+	// salvagedModFiles are the reports of the damaged files of the mod, as they were found when loading it.
+	salvagedModFiles map[world.FileLocation]lgres.SalvageReport
 
 	stateFilename string
 }
 
+// storedFileState identifies the version of a file on disk.
+// Such a file holds all the resources of the mod that were not changed since.
+type storedFileState struct {
+	size    int64
+	modTime time.Time
+}
+
 // NewProjectService returns a new instance of a service for given mod.
 func NewProjectService(commander cmd.Registry, mod *world.Mod) *ProjectService {
 	return &ProjectService{
-		commander: commander,
-		mod:       mod,
+		commander:   commander,
+		mod:         mod,
+		storedFiles: make(map[string]storedFileState),
 	}
 }
 
@@ -113,6 +132,7 @@ func (service ProjectService) CurrentSettings() ProjectSettings {
 	}
 
 	settings.ModFiles = service.relativeToSettings(service.mod.AllAbsoluteFilenames(service.modPath)...)
+	settings.DescriptionFiles = service.relativeToSettings(service.descriptionFiles...)
 
 	return settings
 }
@@ -153,6 +173,8 @@ func (service *ProjectService) RestoreProject(settings ProjectSettings, stateFil
 		}
 	}
 
+	// A problem with the description files is kept and available via DescriptionFilesError().
+	_ = service.SetDescriptionFiles(service.absoluteFromSettings(settings.DescriptionFiles...))
 	_ = service.TryLoadModFrom(service.absoluteFromSettings(settings.ModFiles...))
 }
 
@@ -160,9 +182,35 @@ func (service *ProjectService) RestoreProject(settings ProjectSettings, stateFil
 func (service *ProjectService) ResetProject() {
 	service.setActiveMod("", nil, nil, nil)
 	service.mod.World().Reset()
+	_ = service.SetDescriptionFiles(nil)
 	service.stateFilename = ""
 }
 
+// DescriptionFiles returns the schema files for the data interpreters of the project.
+func (service ProjectService) DescriptionFiles() []string {
+	return append([]string{}, service.descriptionFiles...)
+}
+
+// SetDescriptionFiles sets the schema files for the data interpreters of the project, and applies them.
+// The list is kept even if a file can not be used. In that case, the built-in descriptions are used
+// and the error is returned.
+func (service *ProjectService) SetDescriptionFiles(filenames []string) error {
+	service.descriptionFiles = append([]string{}, filenames...)
+	return service.ReloadDescriptionFiles()
+}
+
+// ReloadDescriptionFiles applies the current schema files again, for example after they were modified.
+func (service *ProjectService) ReloadDescriptionFiles() error {
+	service.descriptionErr = UseDescriptionFiles(service.descriptionFiles)
+	return service.descriptionErr
+}
+
+// DescriptionFilesError returns the problem of the last attempt to apply the schema files.
+// It is nil if the schema files are in use.
+func (service ProjectService) DescriptionFilesError() error {
+	return service.descriptionErr
+}
+
 // AddManifestEntry attempts to insert the given manifest entry at given index.
 func (service *ProjectService) AddManifestEntry(at int, entry *world.ManifestEntry) error {
 	return service.commander.Register(
@@ -262,13 +310,26 @@ func (service *ProjectService) TryLoadModFrom(names []string) error {
 	}
 
 	service.setActiveMod(modPath, locs, loaded.ObjectProperties, loaded.TextureProperties)
+	if len(loaded.Salvaged) > 0 {
+		service.salvagedModFiles = loaded.Salvaged
+	}
 	return nil
 }
 
+// SalvagedModFiles returns the reports of the damaged files of the mod, from which only some resources could be loaded.
+func (service ProjectService) SalvagedModFiles() map[world.FileLocation]lgres.SalvageReport {
+	return service.salvagedModFiles
+}
+
 func (service *ProjectService) setActiveMod(modPath string, resources []*world.LocalizedResources,
 	objectProperties object.PropertiesTable, textureProperties texture.PropertiesList) {
 	service.setModPath(modPath)
 	service.mod.Reset(resources, objectProperties, textureProperties)
+	service.storedFiles = make(map[string]storedFileState)
+	service.salvagedModFiles = nil
+	for _, loc := range resources {
+		service.recordStoredFile(loc.File.AbsolutePathFrom(modPath))
+	}
 	// fix list resources for any "old" mod.
 	service.mod.FixListResources()
 }
@@ -305,6 +366,9 @@ func (service *ProjectService) SaveMod() error {
 
 // SaveModUnder will store the currently active mod in the given path.
 func (service *ProjectService) SaveModUnder(modPath string) error {
+	if modPath != service.modPath {
+		service.storedFiles = make(map[string]storedFileState)
+	}
 	service.mod.FixListResources()
 	err := service.saveModResourcesTo(modPath)
 	if err != nil {
@@ -329,8 +393,24 @@ func (service *ProjectService) saveModResourcesTo(modPath string) error {
 	}
 
 	for _, loc := range localized {
-		if shallBeSaved(loc.File.Name) {
-			err := saveResourcesTo(loc.Store, loc.File.AbsolutePathFrom(modPath))
+		if shallBeSaved(loc.File.Name) && ids.MusicThemes().Matches(loc.File.Name) {
+			err := service.saveMusicThemeTo(loc.Store, loc.File.AbsolutePathFrom(modPath), service.mod.ChangedResourceIDs(loc.File.Name))
+			if err != nil {
+				return err
+			}
+		} else if shallBeSaved(loc.File.Name) {
+			var viewer resource.Viewer = loc.Store
+			changed := service.mod.ChangedResourceIDs(loc.File.Name)
+			if mapping := ids.StoredIDMapping(loc.File.Name); mapping != nil {
+				reversed := mapping.Reversed()
+				viewer = resource.NewMappedViewer(loc.Store, reversed)
+				for index, id := range changed {
+					if storedID, mapped := reversed[id]; mapped {
+						changed[index] = storedID
+					}
+				}
+			}
+			err := service.saveResourcesTo(viewer, loc.File.AbsolutePathFrom(modPath), changed)
 			if err != nil {
 				return err
 			}
@@ -353,16 +433,85 @@ func (service *ProjectService) saveModResourcesTo(modPath string) error {
 	return nil
 }
 
-func saveResourcesTo(viewer resource.Viewer, absFilename string) error {
+// saveResourcesTo writes the resources into the given file.
+// If the file is still in the state it was last loaded or saved in, the unchanged resources are copied from it
+// as they are stored, which avoids compressing them again.
+func (service *ProjectService) saveResourcesTo(viewer resource.Viewer, absFilename string, changed []resource.ID) error {
+	var previous *lgres.Reader
+	if service.isStoredFileUnchanged(absFilename) {
+		previousData, err := ioutil.ReadFile(absFilename)
+		if err == nil {
+			previous, _ = lgres.ReaderFrom(bytes.NewReader(previousData))
+		}
+	}
+	delete(service.storedFiles, absFilename)
+
 	file, err := os.Create(absFilename)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		_ = file.Close() // nolint: gas
-	}()
-	err = lgres.Write(file, viewer)
-	return err
+	err = lgres.WriteReusing(file, viewer, previous, changed)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	service.recordStoredFile(absFilename)
+	return nil
+}
+
+func (service *ProjectService) recordStoredFile(absFilename string) {
+	info, err := os.Stat(absFilename)
+	if (err != nil) || !info.Mode().IsRegular() {
+		return
+	}
+	service.storedFiles[absFilename] = storedFileState{size: info.Size(), modTime: info.ModTime()}
+}
+
+func (service *ProjectService) isStoredFileUnchanged(absFilename string) bool {
+	state, known := service.storedFiles[absFilename]
+	if !known {
+		return false
+	}
+	info, err := os.Stat(absFilename)
+	return (err == nil) && (info.Size() == state.size) && info.ModTime().Equal(state.modTime)
+}
+
+// saveMusicThemeTo writes the data of the contained music theme as it is, as these files are no resource files.
+// The file is not written again if it still holds the theme as it was last loaded or saved.
+func (service *ProjectService) saveMusicThemeTo(store resource.Store, absFilename string, changed []resource.ID) error {
+	storedIDs := store.IDs()
+	if len(storedIDs) == 0 {
+		return nil
+	}
+	if (len(changed) == 0) && service.isStoredFileUnchanged(absFilename) {
+		return nil
+	}
+	res, err := store.Resource(storedIDs[0])
+	if err != nil {
+		return err
+	}
+	data, err := res.BlockRaw(0)
+	if err != nil {
+		return err
+	}
+	delete(service.storedFiles, absFilename)
+	file, err := os.Create(absFilename)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	service.recordStoredFile(absFilename)
+	return nil
 }
 
 func saveTexturePropertiesTo(list texture.PropertiesList, absFilename string) error {
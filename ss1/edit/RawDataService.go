@@ -0,0 +1,259 @@
+package edit
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlobj"
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/content/raw"
+	"github.com/inkyblackness/hacked/ss1/edit/undoable/cmd"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+// objectMainEntryFields describes the layout of level.ObjectMainEntry, without the extra data.
+var objectMainEntryFields = []raw.Field{
+	{Name: "InUse", Start: 0, Count: 1},
+	{Name: "Class", Start: 1, Count: 1},
+	{Name: "Subclass", Start: 2, Count: 1},
+	{Name: "ClassTableIndex", Start: 3, Count: 2},
+	{Name: "CrossReferenceTableIndex", Start: 5, Count: 2},
+	{Name: "Next", Start: 7, Count: 2},
+	{Name: "Prev", Start: 9, Count: 2},
+	{Name: "X", Start: 11, Count: 2},
+	{Name: "Y", Start: 13, Count: 2},
+	{Name: "Z", Start: 15, Count: 1},
+	{Name: "XRotation", Start: 16, Count: 1},
+	{Name: "ZRotation", Start: 17, Count: 1},
+	{Name: "YRotation", Start: 18, Count: 1},
+	{Name: "Type", Start: 20, Count: 1},
+	{Name: "Hitpoints", Start: 21, Count: 2},
+}
+
+const objectMainEntryExtraStart = 23
+
+// objectClassEntryHeaderFields describes the layout of the header of level.ObjectClassEntry.
+var objectClassEntryHeaderFields = []raw.Field{
+	{Name: "ObjectID", Start: 0, Count: 2},
+	{Name: "Next", Start: 2, Count: 2},
+	{Name: "Prev", Start: 4, Count: 2},
+}
+
+// RawDataService provides access to the raw data of resources, and the structure known about it.
+type RawDataService struct {
+	registry cmd.Registry
+	mod      *world.Mod
+	levels   *EditableLevels
+}
+
+// NewRawDataService returns a new instance.
+func NewRawDataService(registry cmd.Registry, mod *world.Mod, levels *EditableLevels) *RawDataService {
+	return &RawDataService{
+		registry: registry,
+		mod:      mod,
+		levels:   levels,
+	}
+}
+
+// BlockCount returns the number of blocks of the identified resource. It returns 0 if the resource does not exist.
+func (service RawDataService) BlockCount(lang resource.Language, id resource.ID) int {
+	view, err := service.mod.LocalizedResources(lang).Select(id)
+	if err != nil {
+		return 0
+	}
+	return view.BlockCount()
+}
+
+// Block returns a copy of the data of the identified block.
+func (service RawDataService) Block(lang resource.Language, id resource.ID, index int) ([]byte, error) {
+	view, err := service.mod.LocalizedResources(lang).Select(id)
+	if err != nil {
+		return nil, err
+	}
+	return blockData(view, index)
+}
+
+func blockData(view resource.View, index int) ([]byte, error) {
+	reader, err := view.Block(index)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(reader)
+}
+
+// SetBlock changes the data of the identified block, as an undoable action.
+// Resources that are not yet part of the mod are copied into it.
+func (service RawDataService) SetBlock(lang resource.Language, id resource.ID, index int, data []byte) error {
+	if service.mod.ModifiedResource(lang, id) != nil {
+		patch, changed, err := service.mod.CreateBlockPatch(lang, id, index, data)
+		if err == nil {
+			if !changed {
+				return nil
+			}
+			return service.registry.Register(cmd.Named("SetRawBlock"),
+				cmd.Forward(patchTask(lang, patch, patch.ForwardData)),
+				cmd.Reverse(patchTask(lang, patch, patch.ReverseData)))
+		}
+	}
+	view, err := service.mod.LocalizedResources(lang).Select(id)
+	if err != nil {
+		return err
+	}
+	newBlocks := make([][]byte, view.BlockCount())
+	for blockIndex := range newBlocks {
+		newBlocks[blockIndex], err = blockData(view, blockIndex)
+		if err != nil {
+			return err
+		}
+	}
+	if (index < 0) || (index >= len(newBlocks)) {
+		return resource.ErrBlockNotFound(index, len(newBlocks))
+	}
+	newBlocks[index] = data
+	oldBlocks := service.mod.ModifiedBlocks(lang, id)
+	return service.registry.Register(cmd.Named("SetRawBlock"),
+		cmd.Forward(func(modder world.Modder) error {
+			modder.SetResourceBlocks(lang, id, newBlocks)
+			return nil
+		}),
+		cmd.Reverse(func(modder world.Modder) error {
+			if oldBlocks == nil {
+				modder.DelResource(lang, id)
+			} else {
+				modder.SetResourceBlocks(lang, id, oldBlocks)
+			}
+			return nil
+		}))
+}
+
+// DeleteResource removes the identified resource from the mod, as an undoable action.
+// The data of the world becomes visible again.
+func (service RawDataService) DeleteResource(lang resource.Language, id resource.ID) error {
+	if service.mod.ModifiedResource(lang, id) == nil {
+		return nil
+	}
+	oldBlocks := service.mod.ModifiedBlocks(lang, id)
+	return service.registry.Register(cmd.Named("DeleteResource"),
+		cmd.Forward(func(modder world.Modder) error {
+			modder.DelResource(lang, id)
+			return nil
+		}),
+		cmd.Reverse(func(modder world.Modder) error {
+			modder.SetResourceBlocks(lang, id, oldBlocks)
+			return nil
+		}))
+}
+
+func patchTask(lang resource.Language, patch world.BlockPatch, data []byte) cmd.Task {
+	return func(modder world.Modder) error {
+		modder.PatchResourceBlock(lang, patch.ID, patch.BlockIndex, patch.BlockLength, data)
+		return nil
+	}
+}
+
+// LevelResourceOf returns the level and the level-specific resource for given identifier.
+// It returns false if the identifier is not one of a level.
+func LevelResourceOf(id resource.ID) (levelIndex int, levelResource int, isLevel bool) {
+	offset := int(id.Value()) - int(ids.LevelResourcesStart.Value())
+	if (offset < 0) || (offset >= archive.MaxLevels*lvlids.PerLevel) {
+		return 0, 0, false
+	}
+	return offset / lvlids.PerLevel, offset % lvlids.PerLevel, true
+}
+
+// LevelResourceID returns the identifier of a level-specific resource.
+func LevelResourceID(levelIndex int, levelResource int) resource.ID {
+	return ids.LevelResourcesStart.Plus(levelIndex*lvlids.PerLevel + levelResource)
+}
+
+// BlockAcrossLevels returns the data of the same block of a level-specific resource, for all levels.
+// Entries are nil for levels that do not have the block.
+func (service RawDataService) BlockAcrossLevels(levelResource int, index int) [][]byte {
+	blocks := make([][]byte, archive.MaxLevels)
+	for levelIndex := range blocks {
+		data, err := service.Block(resource.LangAny, LevelResourceID(levelIndex, levelResource), index)
+		if err == nil {
+			blocks[levelIndex] = data
+		}
+	}
+	return blocks
+}
+
+// Overlay returns the known structure of the given data of the identified block.
+// The overlay has no fields and no undefined bits if the structure is not known.
+func (service RawDataService) Overlay(id resource.ID, index int, data []byte) raw.Overlay {
+	if index != 0 {
+		return raw.Overlay{}
+	}
+	if id == ids.GameState {
+		overlay := raw.NewOverlay(len(data))
+		overlay.AddInstance(archive.NewGameState(data).Instance, 0, "")
+		return overlay
+	}
+	levelIndex, levelResource, isLevel := LevelResourceOf(id)
+	if !isLevel || !service.levels.IsLevelAvailable(levelIndex) {
+		return raw.Overlay{}
+	}
+	lvl := service.levels.Level(levelIndex)
+	switch {
+	case levelResource == lvlids.ObjectMainTable:
+		return objectMainTableOverlay(lvl, data)
+	case (levelResource >= lvlids.ObjectClassTablesStart) && (levelResource < lvlids.ObjectClassTablesStart+object.ClassCount):
+		return objectClassTableOverlay(lvl, object.Class(levelResource-lvlids.ObjectClassTablesStart), data)
+	default:
+		return raw.Overlay{}
+	}
+}
+
+func objectMainTableOverlay(lvl *level.Level, data []byte) raw.Overlay {
+	overlay := raw.NewOverlay(len(data))
+	extraFactory := lvlobj.RealWorldExtra
+	if lvl.IsCyberspace() {
+		extraFactory = lvlobj.CyberspaceExtra
+	}
+	for start := 0; start+level.ObjectMainEntrySize <= len(data); start += level.ObjectMainEntrySize {
+		index := start / level.ObjectMainEntrySize
+		prefix := fmt.Sprintf("[%d].", index)
+		for _, field := range objectMainEntryFields {
+			overlay.AddField(prefix+field.Name, start+field.Start, field.Count)
+		}
+		if data[start] == 0 {
+			continue
+		}
+		triple := object.TripleFrom(int(data[start+1]), int(data[start+2]), int(data[start+20]))
+		extraStart := start + objectMainEntryExtraStart
+		extra := extraFactory(triple, data[extraStart:start+level.ObjectMainEntrySize])
+		overlay.AddInstance(extra, extraStart, prefix+"Extra.")
+	}
+	return overlay
+}
+
+func objectClassTableOverlay(lvl *level.Level, class object.Class, data []byte) raw.Overlay {
+	overlay := raw.NewOverlay(len(data))
+	classFactory := lvlobj.ForRealWorld
+	if lvl.IsCyberspace() {
+		classFactory = lvlobj.ForCyberspace
+	}
+	entrySize := level.ObjectClassEntryHeaderSize + level.ObjectClassInfoFor(class).DataSize
+	for start := 0; start+entrySize <= len(data); start += entrySize {
+		index := start / entrySize
+		prefix := fmt.Sprintf("[%d].", index)
+		for _, field := range objectClassEntryHeaderFields {
+			overlay.AddField(prefix+field.Name, start+field.Start, field.Count)
+		}
+		id := level.ObjectID(uint16(data[start]) | uint16(data[start+1])<<8)
+		obj := lvl.Object(id)
+		if (index == 0) || (obj == nil) || (obj.InUse == 0) || (obj.Class != class) || (int(obj.ClassTableIndex) != index) {
+			continue
+		}
+		dataStart := start + level.ObjectClassEntryHeaderSize
+		classData := classFactory(obj.Triple(), data[dataStart:start+entrySize])
+		overlay.AddInstance(classData, dataStart, fmt.Sprintf("%s%v.", prefix, obj.Triple()))
+	}
+	return overlay
+}
@@ -0,0 +1,165 @@
+package edit
+
+import (
+	"os"
+
+	"github.com/inkyblackness/hacked/ss1"
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/citadel"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+const (
+	errLevelNotAvailable ss1.StringError = "level not available"
+	errPositionNotOnMap  ss1.StringError = "position not on map"
+)
+
+// hackerHeightAboveFloor is the height, in tiles, the hacker is placed above the floor in a test start.
+// The engine lets the hacker settle from there.
+const hackerHeightAboveFloor = 1.0
+
+// TestStartSettings describe the test start presets of a project.
+type TestStartSettings struct {
+	Presets []archive.TestStart `json:",omitempty"`
+}
+
+// TestStartService manages the test start presets of a project and creates savegames from them.
+type TestStartService struct {
+	mod    *world.Mod
+	levels *EditableLevels
+	cp     text.Codepage
+
+	presets []archive.TestStart
+}
+
+// NewTestStartService returns a new instance.
+func NewTestStartService(mod *world.Mod, levels *EditableLevels, cp text.Codepage) *TestStartService {
+	return &TestStartService{
+		mod:    mod,
+		levels: levels,
+		cp:     cp,
+	}
+}
+
+// CurrentSettings returns the snapshot of the project.
+func (service TestStartService) CurrentSettings() TestStartSettings {
+	return TestStartSettings{
+		Presets: service.Presets(),
+	}
+}
+
+// RestoreSettings loads the given snapshot.
+func (service *TestStartService) RestoreSettings(settings TestStartSettings) {
+	service.presets = append([]archive.TestStart{}, settings.Presets...)
+}
+
+// Presets returns a copy of the current presets.
+func (service TestStartService) Presets() []archive.TestStart {
+	return append([]archive.TestStart{}, service.presets...)
+}
+
+// AddPreset appends the given preset and returns its index.
+func (service *TestStartService) AddPreset(preset archive.TestStart) int {
+	service.presets = append(service.presets, preset)
+	return len(service.presets) - 1
+}
+
+// SetPreset replaces the preset at given index. Invalid indices are ignored.
+func (service *TestStartService) SetPreset(index int, preset archive.TestStart) {
+	if (index < 0) || (index >= len(service.presets)) {
+		return
+	}
+	service.presets[index] = preset
+}
+
+// RemovePreset removes the preset at given index. Invalid indices are ignored.
+func (service *TestStartService) RemovePreset(index int) {
+	if (index < 0) || (index >= len(service.presets)) {
+		return
+	}
+	service.presets = append(service.presets[:index], service.presets[index+1:]...)
+}
+
+// Savegame returns the resources of an archive that starts as described by the preset.
+// The archive contains all the levels as they currently are. The game state is based on the one of the
+// archive, or the default game state of Citadel if the archive leaves it to the engine.
+func (service TestStartService) Savegame(preset archive.TestStart) (resource.Store, error) {
+	var store resource.Store
+	if !service.levels.IsLevelAvailable(preset.Level) {
+		return store, errLevelNotAvailable
+	}
+	lvl := service.levels.Level(preset.Level)
+	tile := lvl.Tile(level.TilePosition{X: preset.X.Tile(), Y: preset.Y.Tile()})
+	if (tile == nil) || (tile.Type == level.TileTypeSolid) {
+		return store, errPositionNotOnMap
+	}
+
+	state := citadel.DefaultGameState()
+	if stateData := gameStateDataFrom(service.mod); len(stateData) > 0 {
+		baseState := archive.NewGameState(stateData)
+		if !baseState.IsDefaulting() {
+			state = baseState
+		}
+	}
+	preset.ApplyTo(state, service.cp)
+	_, _, heightShift := lvl.Size()
+	floorHeight, err := heightShift.ValueFromTileHeight(tile.Floor.AbsoluteHeight())
+	if err == nil {
+		state.Set("Hacker Position Z", uint32((floorHeight+hackerHeightAboveFloor)*0x010000))
+	}
+	stateInfo, _ := ids.Info(ids.GameState)
+	stateResource := resource.Resource{
+		Properties: resource.Properties{
+			Compound:    stateInfo.Compound,
+			ContentType: stateInfo.ContentType,
+			Compressed:  stateInfo.Compressed,
+		},
+		Blocks: resource.BlocksFrom([][]byte{state.Raw()}),
+	}
+
+	selector := service.mod.LocalizedResources(resource.LangAny)
+	archiveIDs := []resource.ID{ids.ArchiveName, ids.GameState}
+	for levelIndex := 0; levelIndex < archive.MaxLevels; levelIndex++ {
+		for offset := 0; offset < lvlids.PerLevel; offset++ {
+			archiveIDs = append(archiveIDs, ids.LevelResourcesStart.Plus(levelIndex*lvlids.PerLevel+offset))
+		}
+	}
+	for _, id := range archiveIDs {
+		var view resource.View = stateResource
+		if id != ids.GameState {
+			view, err = selector.Select(id)
+			if err != nil {
+				continue
+			}
+		}
+		err = store.Put(id, view)
+		if err != nil {
+			return store, err
+		}
+	}
+	return store, nil
+}
+
+// SaveSavegame writes the savegame of given preset into the given file.
+func (service TestStartService) SaveSavegame(preset archive.TestStart, absFilename string) error {
+	store, err := service.Savegame(preset)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(absFilename)
+	if err != nil {
+		return err
+	}
+	err = lgres.Write(file, store)
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
@@ -0,0 +1,100 @@
+package edit_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level"
+	"github.com/inkyblackness/hacked/ss1/content/archive/level/lvlids"
+	"github.com/inkyblackness/hacked/ss1/content/text"
+	"github.com/inkyblackness/hacked/ss1/edit"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+const testStartLevel = 1
+
+const testStartFloorHeight level.TileHeightUnit = 8
+
+func aTestStartService(t *testing.T) (*edit.TestStartService, *edit.EditableLevels) {
+	t.Helper()
+	mod := world.NewMod(func([]resource.ID, []resource.ID) {}, func() {})
+	levelData := level.EmptyLevelData(level.EmptyLevelParameters{
+		MapModifier: func(x, y int, entry *level.TileMapEntry) {
+			if (x == 32) && (y == 32) {
+				entry.Type = level.TileTypeOpen
+				entry.Floor = entry.Floor.WithAbsoluteHeight(testStartFloorHeight)
+			}
+		},
+	})
+	levelIDBegin := ids.LevelResourcesStart.Plus(lvlids.PerLevel * testStartLevel)
+	mod.Modify(func(modder world.Modder) {
+		for offset, data := range &levelData {
+			if (offset >= lvlids.FirstUsed) && (offset < lvlids.FirstUnused) && (len(data) > 0) {
+				modder.SetResourceBlock(resource.LangAny, levelIDBegin.Plus(offset), 0, data)
+			}
+		}
+	})
+	levels := edit.NewEditableLevels(nil, mod)
+	return edit.NewTestStartService(mod, levels, text.DefaultCodepage()), levels
+}
+
+func aTestStart() archive.TestStart {
+	preset := archive.NewTestStart("test")
+	preset.Level = testStartLevel
+	return preset
+}
+
+func TestTestStartServiceSavegameReturnsErrorForUnavailableLevel(t *testing.T) {
+	service, _ := aTestStartService(t)
+	preset := aTestStart()
+	preset.Level = archive.MaxLevels
+
+	_, err := service.Savegame(preset)
+
+	assert.NotNil(t, err)
+}
+
+func TestTestStartServiceSavegameReturnsErrorForSolidTile(t *testing.T) {
+	service, _ := aTestStartService(t)
+	preset := aTestStart()
+	preset.X = level.CoordinateAt(10, 0x80)
+
+	_, err := service.Savegame(preset)
+
+	assert.NotNil(t, err)
+}
+
+func TestTestStartServiceSavegameReturnsSavegame(t *testing.T) {
+	service, _ := aTestStartService(t)
+
+	store, err := service.Savegame(aTestStart())
+
+	require.Nil(t, err)
+	stateView, err := store.View(ids.GameState)
+	require.Nil(t, err, "game state expected")
+	assert.True(t, archive.IsSavegame(stateView), "savegame expected")
+}
+
+func TestTestStartServiceSavegamePlacesHackerAboveFloor(t *testing.T) {
+	service, levels := aTestStartService(t)
+
+	store, err := service.Savegame(aTestStart())
+
+	require.Nil(t, err)
+	stateView, err := store.View(ids.GameState)
+	require.Nil(t, err, "game state expected")
+	reader, err := stateView.Block(0)
+	require.Nil(t, err)
+	data, err := ioutil.ReadAll(reader)
+	require.Nil(t, err)
+	_, _, heightShift := levels.Level(testStartLevel).Size()
+	floorHeight, err := heightShift.ValueFromTileHeight(testStartFloorHeight)
+	require.Nil(t, err)
+	assert.Equal(t, uint32((floorHeight+1.0)*0x010000), archive.NewGameState(data).Get("Hacker Position Z"))
+}
@@ -57,7 +57,7 @@ func (service MovieService) RequestMoveSceneLater(key resource.Key, scene int, r
 }
 
 // RequestAddScene queues to add the given scene at the end of the movie.
-func (service MovieService) RequestAddScene(key resource.Key, scene movie.HighResScene, restoreFunc func()) {
+func (service MovieService) RequestAddScene(key resource.Key, scene movie.CompressedScene, restoreFunc func()) {
 	service.requestCommand(
 		func(setter media.MovieBlockSetter) {
 			service.wrapped.AddScene(setter, key, scene)
@@ -66,6 +66,16 @@ func (service MovieService) RequestAddScene(key resource.Key, scene movie.HighRe
 		restoreFunc)
 }
 
+// RequestAddScenes queues to add the given scenes at the end of the movie.
+func (service MovieService) RequestAddScenes(key resource.Key, scenes []movie.CompressedScene, restoreFunc func()) {
+	service.requestCommand(
+		func(setter media.MovieBlockSetter) {
+			service.wrapped.AddScenes(setter, key, scenes)
+		},
+		service.wrapped.RestoreFunc(key),
+		restoreFunc)
+}
+
 // RequestRemoveScene queues to remove the identified scene.
 func (service MovieService) RequestRemoveScene(key resource.Key, scene int, restoreFunc func()) {
 	service.requestCommand(
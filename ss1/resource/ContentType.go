@@ -15,6 +15,7 @@ var contentTypeNames = map[ContentType]string{
 	Geometry:  "Geometry",
 	Movie:     "Movie",
 	Archive:   "Archive",
+	Music:     "Music",
 }
 
 // String returns the textual representation of the type.
@@ -45,4 +46,6 @@ const (
 	Movie = ContentType(0x11)
 	// Archive refers to archive data.
 	Archive = ContentType(0x30)
+	// Music refers to XMI music themes. These are not stored in resource files, so the value is not from the engine.
+	Music = ContentType(0x80)
 )
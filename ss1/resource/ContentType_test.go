@@ -23,6 +23,7 @@ func TestContentTypeString(t *testing.T) {
 		{resource.Geometry, "Geometry"},
 		{resource.Movie, "Movie"},
 		{resource.Archive, "Archive"},
+		{resource.Music, "Music"},
 		{resource.ContentType(254), "UnknownFE"},
 	}
 
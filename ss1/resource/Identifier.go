@@ -36,6 +36,12 @@ func (marker *IDMarkerMap) Add(id ID) {
 	marker.ids[id] = struct{}{}
 }
 
+// Contains returns true if the given ID was added to the map.
+func (marker IDMarkerMap) Contains(id ID) bool {
+	_, existing := marker.ids[id]
+	return existing
+}
+
 // ToList converts the map to a de-duplicated list.
 func (marker IDMarkerMap) ToList() []ID {
 	result := make([]ID, 0, len(marker.ids))
@@ -22,3 +22,40 @@ func (list List) Joined(other List) List {
 	copy(newList[listLen:], other)
 	return newList
 }
+
+// BlockProviders returns for each block the index of the resource that provides the data of a view on this list.
+// If merged is set, the list is considered to be viewed as merged compound list: The block is taken from the last
+// resource that has a non-empty block. Otherwise, all blocks are provided by the last resource.
+// The index is -1 for blocks that no resource provides.
+func (list List) BlockProviders(merged bool) []int {
+	if len(list) == 0 {
+		return nil
+	}
+	if !merged {
+		last := len(list) - 1
+		providers := make([]int, list[last].BlockCount())
+		for index := range providers {
+			providers[index] = last
+		}
+		return providers
+	}
+	providers := make([]int, listMerger{list: list}.BlockCount())
+	for index := range providers {
+		providers[index] = list.blockProvider(index)
+	}
+	return providers
+}
+
+func (list List) blockProvider(index int) int {
+	for layer := len(list) - 1; layer >= 0; layer-- {
+		reader, err := list[layer].Block(index)
+		if err == nil {
+			var buf [1]byte
+			read, _ := reader.Read(buf[:])
+			if read == 1 {
+				return layer
+			}
+		}
+	}
+	return -1
+}
@@ -45,18 +45,9 @@ func (view listMerger) Block(index int) (reader io.Reader, err error) {
 		return nil, ErrBlockNotFound(index, blockCount)
 	}
 
-	for layer := len(view.list) - 1; (layer >= 0) && (reader == nil); layer-- {
-		tempReader, tempErr := view.list[layer].Block(index)
-		if tempErr == nil {
-			var buf [1]byte
-			read, _ := tempReader.Read(buf[:])
-			if read == 1 {
-				reader, err = view.list[layer].Block(index)
-			}
-		}
-	}
-	if (reader == nil) && (err == nil) {
-		reader = bytes.NewBuffer(nil)
+	layer := view.list.blockProvider(index)
+	if layer < 0 {
+		return bytes.NewBuffer(nil), nil
 	}
-	return
+	return view.list[layer].Block(index)
 }
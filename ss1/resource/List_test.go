@@ -50,3 +50,20 @@ func TestListJoined(t *testing.T) {
 		assert.Nil(t, result)
 	})
 }
+
+func TestListBlockProviders(t *testing.T) {
+	var empty resource.List
+	layered := empty.
+		With(resource.Resource{Blocks: resource.BlocksFrom([][]byte{{0x01}, {0x02}, {}})}).
+		With(resource.Resource{Blocks: resource.BlocksFrom([][]byte{{}, {0x12}})})
+
+	t.Run("empty list has no providers", func(t *testing.T) {
+		assert.Nil(t, empty.BlockProviders(true))
+	})
+	t.Run("last resource provides all blocks if not merged", func(t *testing.T) {
+		assert.Equal(t, []int{1, 1}, layered.BlockProviders(false))
+	})
+	t.Run("last non-empty block is provider if merged", func(t *testing.T) {
+		assert.Equal(t, []int{0, 1, -1}, layered.BlockProviders(true))
+	})
+}
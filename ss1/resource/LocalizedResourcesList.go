@@ -16,3 +16,12 @@ func (list LocalizedResourcesList) Filter(lang Language, id ID) List {
 	}
 	return result
 }
+
+// LocalizedResources returns a selector for the given language, which takes the last matching resource.
+// This allows a list to be used as a Localizer.
+func (list LocalizedResourcesList) LocalizedResources(lang Language) Selector {
+	return Selector{
+		Lang: lang,
+		From: list,
+	}
+}
@@ -0,0 +1,52 @@
+package resource
+
+// MappedViewer provides the resources of a wrapped viewer under different identifier.
+// Identifier that are not part of the mapping are provided unchanged.
+type MappedViewer struct {
+	wrapped Viewer
+	mapping map[ID]ID
+	reverse map[ID]ID
+}
+
+// NewMappedViewer returns a viewer that provides the resources of given viewer with mapped identifier.
+// The mapping is from the identifier of the wrapped viewer to the identifier it shall provide.
+func NewMappedViewer(wrapped Viewer, mapping map[ID]ID) MappedViewer {
+	viewer := MappedViewer{
+		wrapped: wrapped,
+		mapping: mapping,
+		reverse: make(map[ID]ID, len(mapping)),
+	}
+	for from, to := range mapping {
+		viewer.reverse[to] = from
+	}
+	return viewer
+}
+
+// IDs returns the mapped identifier of the wrapped viewer.
+func (viewer MappedViewer) IDs() []ID {
+	wrappedIDs := viewer.wrapped.IDs()
+	ids := make([]ID, len(wrappedIDs))
+	for index, id := range wrappedIDs {
+		ids[index] = mapID(viewer.mapping, id)
+	}
+	return ids
+}
+
+// View returns the view of the wrapped viewer for given mapped identifier.
+func (viewer MappedViewer) View(id ID) (View, error) {
+	wrappedID, isMapped := viewer.reverse[id]
+	if !isMapped {
+		if _, isHidden := viewer.mapping[id]; isHidden {
+			return nil, ErrNotFound(id)
+		}
+		wrappedID = id
+	}
+	return viewer.wrapped.View(wrappedID)
+}
+
+func mapID(mapping map[ID]ID, id ID) ID {
+	if mapped, isMapped := mapping[id]; isMapped {
+		return mapped
+	}
+	return id
+}
@@ -0,0 +1,28 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+func TestMappedViewerProvidesResourcesUnderMappedIdentifier(t *testing.T) {
+	var store resource.Store
+	require.Nil(t, store.Put(resource.ID(10), resource.Resource{Properties: resource.Properties{ContentType: resource.Movie}}))
+	require.Nil(t, store.Put(resource.ID(20), resource.Resource{Properties: resource.Properties{ContentType: resource.Text}}))
+
+	viewer := resource.NewMappedViewer(store, map[resource.ID]resource.ID{resource.ID(10): resource.ID(30)})
+
+	assert.ElementsMatch(t, []resource.ID{resource.ID(20), resource.ID(30)}, viewer.IDs())
+	view, err := viewer.View(resource.ID(30))
+	require.Nil(t, err)
+	assert.Equal(t, resource.Movie, view.ContentType())
+	view, err = viewer.View(resource.ID(20))
+	require.Nil(t, err)
+	assert.Equal(t, resource.Text, view.ContentType())
+	_, err = viewer.View(resource.ID(10))
+	assert.Error(t, err)
+}
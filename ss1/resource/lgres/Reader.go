@@ -150,6 +150,21 @@ func (reader *Reader) findEntry(id uint16) (startOffset uint32, entry *resourceD
 	return
 }
 
+// rawResource returns the directory entry and the stored data of the identified resource.
+// The data is returned as it is stored, including a possible compression.
+// Resources that are not fully described by their entry, such as oversized cutscenes, are not available.
+func (reader *Reader) rawResource(id resource.ID) (resourceDirectoryEntry, *io.SectionReader, bool) {
+	resourceStartOffset, entry := reader.findEntry(id.Value())
+	if entry == nil {
+		return resourceDirectoryEntry{}, nil, false
+	}
+	isCutscene := (resource.ContentType(entry.contentType()) == resource.Movie) && (len(reader.directory) == 1)
+	if isCutscene && (reader.directoryOffset-resourceStartOffset != entry.packedLength()) {
+		return resourceDirectoryEntry{}, nil, false
+	}
+	return *entry, io.NewSectionReader(reader.source, int64(resourceStartOffset), int64(entry.packedLength())), true
+}
+
 type blockListEntry struct {
 	start uint32
 	size  uint32
@@ -0,0 +1,320 @@
+package lgres
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres/internal/compression"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres/internal/format"
+)
+
+// SalvageIssue describes one inconsistency found while salvaging a resource file.
+type SalvageIssue struct {
+	// Offset is the byte position in the file the issue refers to.
+	Offset int64
+	// ID is the identifier of the affected resource. It is only valid if HasID is set.
+	ID resource.ID
+	// HasID is set if the issue refers to a specific resource.
+	HasID bool
+	// Description explains the issue.
+	Description string
+}
+
+// String returns a single-line textual representation of the issue.
+func (issue SalvageIssue) String() string {
+	subject := "file"
+	if issue.HasID {
+		subject = issue.ID.String()
+	}
+	return fmt.Sprintf("0x%08X %-4s %s", issue.Offset, subject, issue.Description)
+}
+
+// SalvageReport summarizes the result of salvaging a resource file.
+type SalvageReport struct {
+	// Size is the length of the file in bytes.
+	Size int64
+	// DirectoryOffset is the position of the resource directory that was used.
+	DirectoryOffset int64
+	// Listed is the count of resources in the directory.
+	Listed int
+	// Salvaged lists the identifiers of all recovered resources, in order of the file.
+	Salvaged []resource.ID
+	// Issues lists all inconsistencies, in order of detection.
+	Issues []SalvageIssue
+}
+
+// Intact returns true if no issues were found.
+func (report SalvageReport) Intact() bool {
+	return len(report.Issues) == 0
+}
+
+// Lost returns the count of listed resources that could not be recovered.
+// Entries that are missing from a truncated directory are not counted, they are described by an issue.
+func (report SalvageReport) Lost() int {
+	return report.Listed - len(report.Salvaged)
+}
+
+// String returns a multi-line textual representation of the report, meant for console output.
+func (report SalvageReport) String() string {
+	var builder strings.Builder
+	_, _ = fmt.Fprintf(&builder, "size: %d bytes, directory at 0x%08X\n", report.Size, report.DirectoryOffset)
+	_, _ = fmt.Fprintf(&builder, "resources: %d listed, %d salvaged\n", report.Listed, len(report.Salvaged))
+	if report.Intact() {
+		builder.WriteString("no issues found\n")
+		return builder.String()
+	}
+	_, _ = fmt.Fprintf(&builder, "issues: %d\n", len(report.Issues))
+	for _, issue := range report.Issues {
+		builder.WriteString(issue.String())
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func (report *SalvageReport) addIssue(offset int64, description string, args ...interface{}) {
+	report.Issues = append(report.Issues, SalvageIssue{Offset: offset, Description: fmt.Sprintf(description, args...)})
+}
+
+func (report *SalvageReport) addResourceIssue(offset int64, id resource.ID, description string, args ...interface{}) {
+	report.Issues = append(report.Issues, SalvageIssue{
+		Offset:      offset,
+		ID:          id,
+		HasID:       true,
+		Description: fmt.Sprintf(description, args...),
+	})
+}
+
+// Salvage reads as many resources as possible from a potentially corrupted resource file of given size.
+// Unlike ReaderFrom, it does not stop at the first inconsistency. Every resource is fully decoded
+// to verify it, and each problem is listed in the returned report. The returned store contains
+// all resources that could be recovered, with compound resources possibly missing some blocks.
+func Salvage(source io.ReaderAt, size int64) (resource.Store, SalvageReport) {
+	var store resource.Store
+	report := SalvageReport{Size: size}
+	if source == nil {
+		report.addIssue(0, "source is nil")
+		return store, report
+	}
+
+	salvager := salvager{source: source, size: size, report: &report}
+	salvager.verifyHeader()
+	firstResourceOffset, directory, found := salvager.locateDirectory()
+	if !found {
+		return store, report
+	}
+	report.Listed = len(directory)
+
+	seen := make(map[uint16]bool)
+	startOffset := int64(firstResourceOffset)
+	for index, entry := range directory {
+		if index > 0 {
+			startOffset += int64(directory[index-1].packedLength())
+			startOffset += (format.BoundarySize - (startOffset % format.BoundarySize)) % format.BoundarySize
+		}
+		id := resource.ID(entry.ID)
+		if seen[entry.ID] {
+			report.addResourceIssue(startOffset, id, "duplicate entry, ignored")
+			continue
+		}
+		seen[entry.ID] = true
+		res, ok := salvager.salvageResource(id, entry, startOffset)
+		if ok {
+			_ = store.Put(id, res)
+			report.Salvaged = append(report.Salvaged, id)
+		}
+	}
+	return store, report
+}
+
+type salvager struct {
+	source          io.ReaderAt
+	size            int64
+	directoryOffset int64
+	report          *SalvageReport
+}
+
+func (salvager *salvager) read(offset int64, length int) ([]byte, bool) {
+	if (offset < 0) || (length < 0) || (offset+int64(length) > salvager.size) {
+		return nil, false
+	}
+	data := make([]byte, length)
+	_, err := salvager.source.ReadAt(data, offset)
+	if (err != nil) && !errors.Is(err, io.EOF) {
+		return nil, false
+	}
+	return data, true
+}
+
+func (salvager *salvager) verifyHeader() {
+	expected := append([]byte(format.HeaderString), format.CommentTerminator)
+	data, ok := salvager.read(0, len(expected))
+	if !ok || !bytes.Equal(data, expected) {
+		salvager.report.addIssue(0, "header string mismatch")
+	}
+}
+
+func (salvager *salvager) locateDirectory() (uint32, []resourceDirectoryEntry, bool) {
+	report := salvager.report
+	pointer, ok := salvager.read(format.ResourceDirectoryFileOffsetPos, 4)
+	if ok {
+		dirOffset := int64(binary.LittleEndian.Uint32(pointer))
+		if first, directory, valid := salvager.readDirectory(dirOffset, true); valid {
+			return first, directory, true
+		}
+		report.addIssue(format.ResourceDirectoryFileOffsetPos,
+			"directory offset 0x%08X does not point to a valid directory", dirOffset)
+	} else {
+		report.addIssue(format.ResourceDirectoryFileOffsetPos, "file too short for directory offset")
+	}
+
+	// The directory is written at the end of the file. Try to find one that exactly ends there.
+	headerSize := int64(binary.Size(resourceDirectoryHeader{}))
+	entrySize := int64(binary.Size(resourceDirectoryEntry{}))
+	for count := int64(0); headerSize+count*entrySize <= salvager.size; count++ {
+		candidate := salvager.size - headerSize - count*entrySize
+		if first, directory, valid := salvager.readDirectory(candidate, false); valid && int64(len(directory)) == count {
+			report.addIssue(candidate, "directory with %d entries found at end of file", count)
+			return first, directory, true
+		}
+	}
+	report.addIssue(0, "no directory found")
+	return 0, nil, false
+}
+
+// readDirectory reads the directory at given offset. If the directory is longer than the file,
+// the entries that are available are returned, if allowed.
+func (salvager *salvager) readDirectory(offset int64, allowTruncated bool) (uint32, []resourceDirectoryEntry, bool) {
+	headerSize := binary.Size(resourceDirectoryHeader{})
+	entrySize := binary.Size(resourceDirectoryEntry{})
+	headerData, ok := salvager.read(offset, headerSize)
+	if !ok || (offset < format.ResourceDirectoryFileOffsetPos+4) {
+		return 0, nil, false
+	}
+	var header resourceDirectoryHeader
+	_ = binary.Read(bytes.NewReader(headerData), binary.LittleEndian, &header)
+	if (int64(header.FirstResourceOffset) < format.ResourceDirectoryFileOffsetPos+4) ||
+		(int64(header.FirstResourceOffset) > offset) {
+		return 0, nil, false
+	}
+	count := int(header.ResourceCount)
+	available := int(salvager.size-offset-int64(headerSize)) / entrySize
+	if available < count {
+		if !allowTruncated {
+			return 0, nil, false
+		}
+		salvager.report.addIssue(offset, "directory lists %d entries, file only holds %d", count, available)
+		count = available
+	}
+	directory := make([]resourceDirectoryEntry, count)
+	listData, _ := salvager.read(offset+int64(headerSize), count*entrySize)
+	_ = binary.Read(bytes.NewReader(listData), binary.LittleEndian, directory)
+	salvager.directoryOffset = offset
+	salvager.report.DirectoryOffset = offset
+	return header.FirstResourceOffset, directory, true
+}
+
+func (salvager *salvager) salvageResource(id resource.ID, entry resourceDirectoryEntry, start int64) (resource.Resource, bool) {
+	report := salvager.report
+	resourceType := entry.resourceType()
+	res := resource.Resource{
+		Properties: resource.Properties{
+			Compound:    (resourceType & format.ResourceTypeFlagCompound) != 0,
+			ContentType: resource.ContentType(entry.contentType()),
+			Compressed:  (resourceType & format.ResourceTypeFlagCompressed) != 0,
+		},
+	}
+	packedLength := int64(entry.packedLength())
+	end := start + packedLength
+	// Oversized cutscenes extend up to the directory; See Reader for details.
+	isCutscene := (res.Properties.ContentType == resource.Movie) && (report.Listed == 1)
+	if isCutscene && (start < salvager.directoryOffset) {
+		end = salvager.directoryOffset
+	}
+	if end > salvager.directoryOffset {
+		report.addResourceIssue(start, id, "packed length %d exceeds data area ending at 0x%08X",
+			packedLength, salvager.directoryOffset)
+		return res, false
+	}
+	packed, ok := salvager.read(start, int(end-start))
+	if !ok {
+		report.addResourceIssue(start, id, "data not readable")
+		return res, false
+	}
+
+	if res.Properties.Compound {
+		return salvager.salvageCompound(id, entry, start, packed, res)
+	}
+	data := packed
+	if res.Properties.Compressed {
+		var err error
+		data, err = decompressLimited(packed, int64(entry.unpackedLength()))
+		if err != nil {
+			report.addResourceIssue(start, id, "decompression failed: %v", err)
+			return res, false
+		}
+	}
+	if int64(len(data)) < int64(entry.unpackedLength()) {
+		report.addResourceIssue(start, id, "data holds %d bytes, expected %d", len(data), entry.unpackedLength())
+		return res, false
+	}
+	res.Blocks = resource.BlocksFrom([][]byte{data[:entry.unpackedLength()]})
+	return res, true
+}
+
+func (salvager *salvager) salvageCompound(id resource.ID, entry resourceDirectoryEntry, start int64,
+	packed []byte, res resource.Resource) (resource.Resource, bool) {
+	report := salvager.report
+	firstBlockOffset, blockList, err := (&Reader{}).readBlockList(bytes.NewReader(packed))
+	if err != nil {
+		report.addResourceIssue(start, id, "block list not readable: %v", err)
+		return res, false
+	}
+	listSize := uint32(2 + (len(blockList)+1)*4)
+	if (firstBlockOffset < listSize) || (firstBlockOffset > entry.unpackedLength()) {
+		report.addResourceIssue(start+2, id, "invalid offset of first block: %d", firstBlockOffset)
+		return res, false
+	}
+
+	var data []byte
+	if res.Properties.Compressed {
+		if int(firstBlockOffset) > len(packed) {
+			report.addResourceIssue(start, id, "first block at %d beyond packed length %d", firstBlockOffset, len(packed))
+			return res, false
+		}
+		data, err = decompressLimited(packed[firstBlockOffset:], int64(entry.unpackedLength()-firstBlockOffset))
+		if err != nil {
+			report.addResourceIssue(start+int64(firstBlockOffset), id, "decompression failed: %v", err)
+		}
+	} else if int(firstBlockOffset) <= len(packed) {
+		data = packed[firstBlockOffset:]
+	}
+
+	blocks := make([][]byte, len(blockList))
+	for index, block := range blockList {
+		blockStart := int64(block.start) - int64(firstBlockOffset)
+		blockEnd := blockStart + int64(block.size)
+		if (block.start < firstBlockOffset) || (block.start+block.size < block.start) {
+			report.addResourceIssue(start+int64(6+index*4), id, "block %d has invalid offsets", index)
+			continue
+		}
+		if blockEnd > int64(len(data)) {
+			report.addResourceIssue(start+int64(6+index*4), id, "block %d exceeds available data, cleared", index)
+			continue
+		}
+		blocks[index] = data[blockStart:blockEnd]
+	}
+	res.Blocks = resource.BlocksFrom(blocks)
+	return res, true
+}
+
+// decompressLimited decompresses the given data up to the given amount of bytes.
+// Decompression of corrupted data can produce arbitrary amounts of data, so the limit is necessary.
+func decompressLimited(data []byte, limit int64) ([]byte, error) {
+	return ioutil.ReadAll(io.LimitReader(compression.NewDecompressor(bytes.NewReader(data)), limit))
+}
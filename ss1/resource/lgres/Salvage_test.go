@@ -0,0 +1,112 @@
+package lgres_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres/internal/format"
+)
+
+func salvageOf(data []byte) (resource.Store, lgres.SalvageReport) {
+	return lgres.Salvage(bytes.NewReader(data), int64(len(data)))
+}
+
+func salvagedBlocks(t *testing.T, store resource.Store, id resource.ID) [][]byte {
+	t.Helper()
+	view, err := store.View(id)
+	require.Nil(t, err, "resource expected")
+	var blocks [][]byte
+	for index := 0; index < view.BlockCount(); index++ {
+		reader, blockErr := view.Block(index)
+		require.Nil(t, blockErr, "no error expected for block")
+		data, readErr := ioutil.ReadAll(reader)
+		require.Nil(t, readErr, "no error expected reading block")
+		blocks = append(blocks, data)
+	}
+	return blocks
+}
+
+func TestSalvageOfIntactFileRecoversAllResources(t *testing.T) {
+	store, report := salvageOf(exampleResourceFile())
+
+	assert.True(t, report.Intact(), "no issues expected: "+report.String())
+	assert.Equal(t, 4, report.Listed)
+	assert.Equal(t, []resource.ID{exampleResourceIDSingleBlockResource, exampleResourceIDSingleBlockResourceCompressed,
+		exampleResourceIDCompoundResource, exampleResourceIDCompoundResourceCompressed}, report.Salvaged)
+	assert.Equal(t, [][]byte{{0x02, 0x02}}, salvagedBlocks(t, store, exampleResourceIDSingleBlockResourceCompressed))
+	assert.Equal(t, [][]byte{{0x40, 0x40}, {0x41, 0x41, 0x41, 0x41}, {0x42}},
+		salvagedBlocks(t, store, exampleResourceIDCompoundResourceCompressed))
+}
+
+func TestSalvageFindsDirectoryWithBrokenPointer(t *testing.T) {
+	data := exampleResourceFile()
+	binary.LittleEndian.PutUint32(data[format.ResourceDirectoryFileOffsetPos:], 0xFFFFFF00)
+
+	_, report := salvageOf(data)
+
+	assert.Equal(t, 4, len(report.Salvaged))
+	require.Equal(t, 2, len(report.Issues), report.String())
+	assert.Equal(t, int64(format.ResourceDirectoryFileOffsetPos), report.Issues[0].Offset)
+	assert.Equal(t, report.DirectoryOffset, report.Issues[1].Offset)
+}
+
+func TestSalvageReportsCorruptedResourceAndKeepsOthers(t *testing.T) {
+	data := exampleResourceFile()
+	// The second resource follows the first (3 bytes, aligned to 4) at the first resource offset.
+	corruptedOffset := format.ResourceDirectoryFileOffsetPos + 4 + 4
+	data[corruptedOffset] = 0xFF
+	data[corruptedOffset+1] = 0xFF
+
+	store, report := salvageOf(data)
+
+	assert.Equal(t, []resource.ID{exampleResourceIDSingleBlockResource,
+		exampleResourceIDCompoundResource, exampleResourceIDCompoundResourceCompressed}, report.Salvaged)
+	require.Equal(t, 1, len(report.Issues), report.String())
+	issue := report.Issues[0]
+	assert.Equal(t, int64(corruptedOffset), issue.Offset)
+	assert.True(t, issue.HasID)
+	assert.Equal(t, exampleResourceIDSingleBlockResourceCompressed, issue.ID)
+	assert.Equal(t, [][]byte{{0x30, 0x30, 0x30, 0x30}, {0x31, 0x31, 0x31}},
+		salvagedBlocks(t, store, exampleResourceIDCompoundResource))
+}
+
+func TestSalvageLimitsDirectoryToFileSize(t *testing.T) {
+	data := exampleResourceFile()
+	directoryOffset := binary.LittleEndian.Uint32(data[format.ResourceDirectoryFileOffsetPos:])
+	binary.LittleEndian.PutUint16(data[directoryOffset:], 10)
+
+	_, report := salvageOf(data)
+
+	assert.Equal(t, 4, report.Listed)
+	assert.Equal(t, 4, len(report.Salvaged))
+	require.Equal(t, 1, len(report.Issues), report.String())
+	assert.Equal(t, int64(directoryOffset), report.Issues[0].Offset)
+}
+
+func TestSalvageReportsMissingDirectory(t *testing.T) {
+	data := exampleResourceFile()[:0x90]
+
+	_, report := salvageOf(data)
+
+	assert.Empty(t, report.Salvaged)
+	assert.False(t, report.Intact())
+	assert.Contains(t, report.String(), "no directory found")
+}
+
+func TestSalvageReportCountsLostResources(t *testing.T) {
+	data := exampleResourceFile()
+	corruptedOffset := format.ResourceDirectoryFileOffsetPos + 4 + 4
+	data[corruptedOffset] = 0xFF
+	data[corruptedOffset+1] = 0xFF
+
+	_, report := salvageOf(data)
+
+	assert.Equal(t, 1, report.Lost())
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres/internal/format"
 )
 
 type resourceInconsistentError struct {
@@ -19,44 +20,83 @@ func (err resourceInconsistentError) Error() string {
 // Write serializes the resources from given source into the target.
 // It is a convenience function for using Writer.
 func Write(target io.WriteSeeker, source resource.Viewer) error {
+	return WriteReusing(target, source, nil, nil)
+}
+
+// WriteReusing serializes the resources from given source into the target, reusing stored data of a previous file.
+// Resources that are not in the list of changed identifiers are copied from the previous reader as they are stored,
+// without decompressing and compressing them again. This requires the previous file to hold the same resources,
+// with the same properties, as the source does for all unchanged identifiers.
+// Changed resources, as well as those not found in the previous reader, are serialized from the source.
+// The previous reader may be nil, in which case all resources are serialized from the source.
+func WriteReusing(target io.WriteSeeker, source resource.Viewer, previous *Reader, changed []resource.ID) error {
 	writer, writerErr := NewWriter(target)
 	if writerErr != nil {
 		return writerErr
 	}
 
+	var changedIDs resource.IDMarkerMap
+	for _, id := range changed {
+		changedIDs.Add(id)
+	}
+
 	for _, id := range source.IDs() {
 		entry, resourceErr := source.View(id)
 		if resourceErr != nil {
 			return resourceErr
 		}
 
-		switch {
-		case entry.Compound():
-			resourceWriter, resourceWriterErr := writer.CreateCompoundResource(id, entry.ContentType(), entry.Compressed())
-			if resourceWriterErr != nil {
-				return resourceWriterErr
-			}
-			copyErr := copyBlocks(entry, func() io.Writer { return resourceWriter.CreateBlock() })
+		if (previous != nil) && !changedIDs.Contains(id) {
+			copied, copyErr := copyRawResource(writer, previous, id, entry)
 			if copyErr != nil {
 				return copyErr
 			}
-		case entry.BlockCount() == 1:
-			blockWriter, resourceWriterErr := writer.CreateResource(id, entry.ContentType(), entry.Compressed())
-			if resourceWriterErr != nil {
-				return resourceWriterErr
+			if copied {
+				continue
 			}
-			copyErr := copyBlocks(entry, func() io.Writer { return blockWriter })
-			if copyErr != nil {
-				return copyErr
-			}
-		default:
-			return resourceInconsistentError{ID: id, BlockCount: entry.BlockCount()}
+		}
+		err := writeResource(writer, id, entry)
+		if err != nil {
+			return err
 		}
 	}
 
 	return writer.Finish()
 }
 
+func copyRawResource(writer *Writer, previous *Reader, id resource.ID, view resource.View) (bool, error) {
+	rawEntry, data, available := previous.rawResource(id)
+	if !available {
+		return false, nil
+	}
+	resourceType := rawEntry.resourceType()
+	if (view.Compound() != ((resourceType & format.ResourceTypeFlagCompound) != 0)) ||
+		(view.Compressed() != ((resourceType & format.ResourceTypeFlagCompressed) != 0)) ||
+		(view.ContentType() != resource.ContentType(rawEntry.contentType())) {
+		return false, nil
+	}
+	return true, writer.copyRawResource(rawEntry, data)
+}
+
+func writeResource(writer *Writer, id resource.ID, entry resource.View) error {
+	switch {
+	case entry.Compound():
+		resourceWriter, resourceWriterErr := writer.CreateCompoundResource(id, entry.ContentType(), entry.Compressed())
+		if resourceWriterErr != nil {
+			return resourceWriterErr
+		}
+		return copyBlocks(entry, func() io.Writer { return resourceWriter.CreateBlock() })
+	case entry.BlockCount() == 1:
+		blockWriter, resourceWriterErr := writer.CreateResource(id, entry.ContentType(), entry.Compressed())
+		if resourceWriterErr != nil {
+			return resourceWriterErr
+		}
+		return copyBlocks(entry, func() io.Writer { return blockWriter })
+	default:
+		return resourceInconsistentError{ID: id, BlockCount: entry.BlockCount()}
+	}
+}
+
 func copyBlocks(source resource.BlockProvider, nextWriter func() io.Writer) error {
 	for blockIndex := 0; blockIndex < source.BlockCount(); blockIndex++ {
 		blockReader, blockErr := source.Block(blockIndex)
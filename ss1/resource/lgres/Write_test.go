@@ -2,6 +2,7 @@ package lgres_test
 
 import (
 	"bytes"
+	"io/ioutil"
 	"testing"
 
 	"github.com/inkyblackness/hacked/ss1/resource"
@@ -9,6 +10,7 @@ import (
 	"github.com/inkyblackness/hacked/ss1/serial"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWrite(t *testing.T) {
@@ -42,3 +44,56 @@ func TestWrite(t *testing.T) {
 
 	assert.Equal(t, []resource.ID{resource.ID(1), resource.ID(3), resource.ID(2), resource.ID(4)}, reader.IDs())
 }
+
+func TestWriteReusingCopiesUnchangedResourcesFromPrevious(t *testing.T) {
+	aResource := func(compressed bool, compound bool, blocks ...[]byte) resource.View {
+		return resource.Resource{
+			Properties: resource.Properties{
+				Compressed:  compressed,
+				ContentType: resource.Text,
+				Compound:    compound,
+			},
+			Blocks: resource.BlocksFrom(blocks),
+		}
+	}
+	var previousStore resource.Store
+	_ = previousStore.Put(resource.ID(1), aResource(true, true, []byte{0x10, 0x10, 0x10}, []byte{0x11}))
+	_ = previousStore.Put(resource.ID(2), aResource(true, false, []byte{0x20, 0x20, 0x20}))
+	_ = previousStore.Put(resource.ID(3), aResource(false, false, []byte{0x30}))
+	previousData := serial.NewByteStore()
+	err := lgres.Write(previousData, previousStore)
+	require.Nil(t, err, "no error expected writing previous")
+	previous, err := lgres.ReaderFrom(bytes.NewReader(previousData.Data()))
+	require.Nil(t, err, "no error expected reading previous")
+
+	// The source deliberately differs for resource 1, which is not marked as changed, to verify it was copied.
+	var source resource.Store
+	_ = source.Put(resource.ID(1), aResource(true, true, []byte{0xFF}))
+	_ = source.Put(resource.ID(2), aResource(true, false, []byte{0x21, 0x22}))
+	_ = source.Put(resource.ID(3), aResource(true, false, []byte{0x31}))
+	_ = source.Put(resource.ID(4), aResource(false, true, []byte{0x41}))
+	target := serial.NewByteStore()
+	err = lgres.WriteReusing(target, source, previous, []resource.ID{resource.ID(2)})
+	require.Nil(t, err, "no error expected writing")
+
+	reader, err := lgres.ReaderFrom(bytes.NewReader(target.Data()))
+	require.Nil(t, err, "no error expected reading")
+	assert.Equal(t, []resource.ID{1, 2, 3, 4}, reader.IDs())
+	blocksOf := func(id resource.ID) [][]byte {
+		view, viewErr := reader.View(id)
+		require.Nil(t, viewErr, "no error expected viewing")
+		var blocks [][]byte
+		for index := 0; index < view.BlockCount(); index++ {
+			blockReader, blockErr := view.Block(index)
+			require.Nil(t, blockErr, "no error expected for block")
+			data, readErr := ioutil.ReadAll(blockReader)
+			require.Nil(t, readErr, "no error expected reading block")
+			blocks = append(blocks, data)
+		}
+		return blocks
+	}
+	assert.Equal(t, [][]byte{{0x10, 0x10, 0x10}, {0x11}}, blocksOf(1), "unchanged resource should be copied")
+	assert.Equal(t, [][]byte{{0x21, 0x22}}, blocksOf(2), "changed resource should be written")
+	assert.Equal(t, [][]byte{{0x31}}, blocksOf(3), "resource with different properties should be written")
+	assert.Equal(t, [][]byte{{0x41}}, blocksOf(4), "new resource should be written")
+}
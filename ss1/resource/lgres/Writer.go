@@ -103,6 +103,25 @@ func (writer *Writer) CreateCompoundResource(id resource.ID, contentType resourc
 	return resourceWriter, nil
 }
 
+// copyRawResource adds a resource with the given directory entry, taking its stored data as is.
+func (writer *Writer) copyRawResource(entry resourceDirectoryEntry, data io.Reader) error {
+	if writer.encoder == nil {
+		return ErrWriterFinished
+	}
+
+	writer.finishLastResource()
+	if writer.encoder.FirstError() != nil {
+		return writer.encoder.FirstError()
+	}
+
+	writer.directory = append(writer.directory, &entry)
+	_, err := io.Copy(writer.encoder, data)
+	if err != nil {
+		return err
+	}
+	return writer.encoder.FirstError()
+}
+
 // Finish finalizes the resource file. After calling this function, the
 // writer becomes unusable.
 func (writer *Writer) Finish() (err error) {
@@ -6,26 +6,38 @@ import (
 	"github.com/inkyblackness/hacked/ss1/serial"
 )
 
+// hashTableBits determines the size of the dictionary hash table.
+// The table has at least twice as many slots as the dictionary can hold entries, keeping probe sequences short.
+const hashTableBits = 15
+
+const hashTableMask = (1 << hashTableBits) - 1
+
+// hashSlot stores one dictionary entry. An entry extends the sequence of the prefix word by one byte.
+// Slots of a different generation are considered empty, which allows resetting the dictionary without clearing it.
+type hashSlot struct {
+	sequence   uint32
+	key        Word
+	generation uint16
+}
+
 type compressor struct {
 	coder  serial.Coder
 	writer *WordWriter
 
-	dictBuffer     dictEntryBuffer
-	overtime       int
-	dictionary     *dictEntry
+	table          [1 << hashTableBits]hashSlot
+	generation     uint16
 	dictionarySize int
-	curEntry       *dictEntry
+	overtime       int
+	current        Word
 }
 
 // NewCompressor creates a new compressor instance over a writer.
 func NewCompressor(target io.Writer) io.WriteCloser {
 	coder := serial.NewEncoder(target)
 	obj := &compressor{
-		coder:          coder,
-		writer:         NewWordWriter(coder),
-		dictionary:     rootDictEntry(),
-		dictionarySize: 0,
-		overtime:       0}
+		coder:  coder,
+		writer: NewWordWriter(coder),
+	}
 
 	obj.resetDictionary()
 
@@ -34,14 +46,16 @@ func NewCompressor(target io.Writer) io.WriteCloser {
 
 func (obj *compressor) resetDictionary() {
 	obj.dictionarySize = 0
-	for i := 0; i < 0x100; i++ {
-		obj.dictionary.Add(byte(i), Word(i), obj.dictBuffer.entry(Word(i)))
+	obj.generation++
+	if obj.generation == 0 {
+		obj.table = [1 << hashTableBits]hashSlot{}
+		obj.generation = 1
 	}
-	obj.curEntry = obj.dictionary
+	obj.current = Reset
 }
 
 func (obj *compressor) Close() error {
-	obj.writer.Write(obj.curEntry.key)
+	obj.writer.Write(obj.current)
 	obj.writer.Close()
 
 	return obj.coder.FirstError()
@@ -56,22 +70,35 @@ func (obj *compressor) Write(p []byte) (n int, err error) {
 }
 
 func (obj *compressor) addByte(value byte) {
-	nextEntry := obj.curEntry.next[int(value)]
-	if nextEntry != nil {
-		obj.curEntry = nextEntry
-	} else {
-		obj.writer.Write(obj.curEntry.key)
-
-		key := Word(int(literalLimit) + obj.dictionarySize)
-		if key < Reset {
-			obj.curEntry.Add(value, key, obj.dictBuffer.entry(key))
-			obj.dictionarySize++
-		} else {
-			obj.onKeySaturation()
+	if obj.current == Reset {
+		obj.current = Word(value)
+		return
+	}
+	sequence := uint32(obj.current)<<8 | uint32(value)
+	index := hashOf(sequence)
+	for {
+		slot := &obj.table[index]
+		if slot.generation != obj.generation {
+			break
 		}
+		if slot.sequence == sequence {
+			obj.current = slot.key
+			return
+		}
+		index = (index + 1) & hashTableMask
+	}
 
-		obj.curEntry = obj.dictionary.next[value]
+	obj.writer.Write(obj.current)
+
+	key := Word(int(literalLimit) + obj.dictionarySize)
+	if key < Reset {
+		obj.table[index] = hashSlot{sequence: sequence, key: key, generation: obj.generation}
+		obj.dictionarySize++
+	} else {
+		obj.onKeySaturation()
 	}
+
+	obj.current = Word(value)
 }
 
 func (obj *compressor) onKeySaturation() {
@@ -82,3 +109,7 @@ func (obj *compressor) onKeySaturation() {
 		obj.overtime = 0
 	}
 }
+
+func hashOf(sequence uint32) uint32 {
+	return (sequence * 2654435761) >> (32 - hashTableBits)
+}
@@ -69,6 +69,20 @@ func (suite *DecompressorSuite) TestDecompressTestRandom() {
 	}
 }
 
+func (suite *DecompressorSuite) TestDecompressLargeDataWithSaturatedDictionary() {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	input := make([]byte, 512*1024)
+	for i := 0; i < len(input); i++ {
+		if (i/4096)%2 == 0 {
+			input[i] = byte(r.Intn(256))
+		} else {
+			input[i] = byte(r.Intn(4) + i%7)
+		}
+	}
+
+	suite.verify(input)
+}
+
 func (suite *DecompressorSuite) TestDecompressHandlesDictionaryResets() {
 	suite.writeWords(0x0001, 0x0002, 0x0100, compression.Reset, 0x0003, 0x0004, 0x0100, compression.EndOfStream)
 
@@ -0,0 +1,170 @@
+package compression // nolint: testpackage
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/serial"
+)
+
+// treeCompressor is the previous implementation of the compressor, walking a tree of dictionary entries.
+// It is kept as reference for the output and the performance of the hash-based compressor.
+type treeCompressor struct {
+	coder  serial.Coder
+	writer *WordWriter
+
+	dictBuffer     dictEntryBuffer
+	overtime       int
+	dictionary     *dictEntry
+	dictionarySize int
+	curEntry       *dictEntry
+}
+
+func newTreeCompressor(target io.Writer) io.WriteCloser {
+	coder := serial.NewEncoder(target)
+	obj := &treeCompressor{
+		coder:          coder,
+		writer:         NewWordWriter(coder),
+		dictionary:     rootDictEntry(),
+		dictionarySize: 0,
+		overtime:       0}
+
+	obj.resetDictionary()
+
+	return obj
+}
+
+func (obj *treeCompressor) resetDictionary() {
+	obj.dictionarySize = 0
+	for i := 0; i < 0x100; i++ {
+		obj.dictionary.Add(byte(i), Word(i), obj.dictBuffer.entry(Word(i)))
+	}
+	obj.curEntry = obj.dictionary
+}
+
+func (obj *treeCompressor) Close() error {
+	obj.writer.Write(obj.curEntry.key)
+	obj.writer.Close()
+
+	return obj.coder.FirstError()
+}
+
+func (obj *treeCompressor) Write(p []byte) (n int, err error) {
+	for _, input := range p {
+		obj.addByte(input)
+	}
+
+	return len(p), obj.coder.FirstError()
+}
+
+func (obj *treeCompressor) addByte(value byte) {
+	nextEntry := obj.curEntry.next[int(value)]
+	if nextEntry != nil {
+		obj.curEntry = nextEntry
+	} else {
+		obj.writer.Write(obj.curEntry.key)
+
+		key := Word(int(literalLimit) + obj.dictionarySize)
+		if key < Reset {
+			obj.curEntry.Add(value, key, obj.dictBuffer.entry(key))
+			obj.dictionarySize++
+		} else {
+			obj.onKeySaturation()
+		}
+
+		obj.curEntry = obj.dictionary.next[value]
+	}
+}
+
+func (obj *treeCompressor) onKeySaturation() {
+	obj.overtime++
+	if obj.overtime > 1000 {
+		obj.writer.Write(Reset)
+		obj.resetDictionary()
+		obj.overtime = 0
+	}
+}
+
+func compressWith(t testing.TB, create func(io.Writer) io.WriteCloser, data []byte) []byte {
+	t.Helper()
+	buffer := bytes.NewBuffer(nil)
+	compressor := create(buffer)
+	_, err := compressor.Write(data)
+	require.Nil(t, err, "no error expected writing")
+	err = compressor.Close()
+	require.Nil(t, err, "no error expected closing")
+	return buffer.Bytes()
+}
+
+func randomData(size int) []byte {
+	data := make([]byte, size)
+	rand.New(rand.NewSource(int64(size))).Read(data) // nolint: gosec
+	return data
+}
+
+func repetitiveData(size int) []byte {
+	data := make([]byte, size)
+	r := rand.New(rand.NewSource(int64(size))) // nolint: gosec
+	for index := range data {
+		data[index] = byte(r.Intn(4)) + byte(index%7)
+	}
+	return data
+}
+
+func TestCompressorProducesSameOutputAsTreeCompressor(t *testing.T) {
+	cases := map[string][]byte{
+		"empty":              nil,
+		"single":             {0x42},
+		"repeated":           bytes.Repeat([]byte{0xAA}, 100000),
+		"random small":       randomData(1000),
+		"random saturating":  randomData(512 * 1024),
+		"repetitive":         repetitiveData(256 * 1024),
+		"mixed with resets":  append(randomData(300*1024), repetitiveData(300*1024)...),
+		"zero runs in noise": append(append(randomData(64*1024), make([]byte, 64*1024)...), randomData(64*1024+1)...),
+	}
+	for name, data := range cases {
+		expected := compressWith(t, newTreeCompressor, data)
+		result := compressWith(t, NewCompressor, data)
+		assert.Equal(t, expected, result, "output differs for case "+name)
+	}
+}
+
+func benchmarkCompressor(b *testing.B, create func(io.Writer) io.WriteCloser, data []byte) {
+	b.Helper()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for run := 0; run < b.N; run++ {
+		compressor := create(serial.NewByteStore())
+		_, _ = compressor.Write(data)
+		_ = compressor.Close()
+	}
+}
+
+func BenchmarkTreeCompressorRandom16KB(b *testing.B) {
+	benchmarkCompressor(b, newTreeCompressor, randomData(16*1024))
+}
+
+func BenchmarkHashCompressorRandom16KB(b *testing.B) {
+	benchmarkCompressor(b, NewCompressor, randomData(16*1024))
+}
+
+func BenchmarkTreeCompressorRandom1024KB(b *testing.B) {
+	benchmarkCompressor(b, newTreeCompressor, randomData(1024*1024))
+}
+
+func BenchmarkHashCompressorRandom1024KB(b *testing.B) {
+	benchmarkCompressor(b, NewCompressor, randomData(1024*1024))
+}
+
+func BenchmarkTreeCompressorRepetitive1024KB(b *testing.B) {
+	benchmarkCompressor(b, newTreeCompressor, repetitiveData(1024*1024))
+}
+
+func BenchmarkHashCompressorRepetitive1024KB(b *testing.B) {
+	benchmarkCompressor(b, NewCompressor, repetitiveData(1024*1024))
+}
@@ -0,0 +1,225 @@
+package world
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// Names of the property tables in a conflict report.
+const (
+	ObjectPropertiesTable  = "Object properties"
+	TexturePropertiesTable = "Texture properties"
+)
+
+// ResourceConflict describes a resource that is provided by more than one manifest entry.
+type ResourceConflict struct {
+	ID       resource.ID
+	Language resource.Language
+	// Entries are the indices of the manifest entries that provide the resource, in order.
+	Entries []int
+	// Winner is the index of the topmost entry, of which the resource is used.
+	// List resources are merged block by block instead, see BlockEntries. Their properties are
+	// taken from the first entry that provides them.
+	Winner int
+	// List is set for resources that are merged block by block.
+	List bool
+	// BlockEntries contains, for list resources, the index of the entry that provides each block.
+	// The index is -1 for blocks that are empty in all entries.
+	BlockEntries []int
+}
+
+// IsPartial returns true for list resources of which the blocks are provided by more than one entry.
+func (conflict ResourceConflict) IsPartial() bool {
+	return len(conflict.BlockCounts()) > 1
+}
+
+// BlockCounts returns how many blocks each entry provides, for list resources.
+func (conflict ResourceConflict) BlockCounts() map[int]int {
+	counts := make(map[int]int)
+	for _, entry := range conflict.BlockEntries {
+		if entry >= 0 {
+			counts[entry]++
+		}
+	}
+	return counts
+}
+
+// PropertiesConflict describes a property table that is provided by more than one manifest entry.
+// Property tables are not merged, the last entry replaces all others.
+type PropertiesConflict struct {
+	Table   string
+	Entries []int
+	Winner  int
+}
+
+// ConflictReport lists everything that manifest entries provide in competition to each other.
+type ConflictReport struct {
+	// EntryIDs are the identifiers of all manifest entries.
+	EntryIDs   []string
+	Resources  []ResourceConflict
+	Properties []PropertiesConflict
+}
+
+// PartialCount returns the number of list resources that are partially overridden.
+func (report ConflictReport) PartialCount() int {
+	count := 0
+	for _, conflict := range report.Resources {
+		if conflict.IsPartial() {
+			count++
+		}
+	}
+	return count
+}
+
+// EntryName returns a short description of the identified entry.
+func (report ConflictReport) EntryName(index int) string {
+	if (index < 0) || (index >= len(report.EntryIDs)) {
+		return "(none)"
+	}
+	return fmt.Sprintf("[%d] %s", index, report.EntryIDs[index])
+}
+
+// Text returns the report in a human-readable form.
+func (report ConflictReport) Text() string {
+	var text strings.Builder
+	text.WriteString("Manifest override conflicts\n\nEntries, from bottom to top:\n")
+	for index := range report.EntryIDs {
+		text.WriteString("  " + report.EntryName(index) + "\n")
+	}
+	text.WriteString(fmt.Sprintf("\nResources: %d, partially overridden lists: %d\n", len(report.Resources), report.PartialCount()))
+	for _, conflict := range report.Resources {
+		text.WriteString(fmt.Sprintf("  %s (%s)", conflict.ID, conflict.Language))
+		if conflict.List {
+			text.WriteString(", list")
+		}
+		text.WriteString(": provided by " + report.entryNames(conflict.Entries))
+		if conflict.IsPartial() {
+			text.WriteString("; partial override: " + report.BlockDistribution(conflict))
+		} else if conflict.List {
+			text.WriteString("; blocks from " + report.BlockDistribution(conflict))
+		} else {
+			text.WriteString("; used from " + report.EntryName(conflict.Winner))
+		}
+		text.WriteString("\n")
+	}
+	text.WriteString(fmt.Sprintf("\nProperty tables: %d\n", len(report.Properties)))
+	for _, conflict := range report.Properties {
+		text.WriteString(fmt.Sprintf("  %s: provided by %s; used from %s\n",
+			conflict.Table, report.entryNames(conflict.Entries), report.EntryName(conflict.Winner)))
+	}
+	return text.String()
+}
+
+func (report ConflictReport) entryNames(entries []int) string {
+	names := make([]string, len(entries))
+	for index, entry := range entries {
+		names[index] = report.EntryName(entry)
+	}
+	return strings.Join(names, ", ")
+}
+
+// BlockDistribution returns a short description of which entries provide how many blocks of a list resource.
+func (report ConflictReport) BlockDistribution(conflict ResourceConflict) string {
+	counts := conflict.BlockCounts()
+	parts := make([]string, 0, len(counts))
+	for _, entry := range conflict.Entries {
+		if count, provides := counts[entry]; provides {
+			parts = append(parts, fmt.Sprintf("%s %d block(s)", report.EntryName(entry), count))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+type localizedID struct {
+	lang resource.Language
+	id   resource.ID
+}
+
+// Conflicts returns the report of all resources and property tables that are provided by more than one entry.
+func (manifest Manifest) Conflicts() ConflictReport {
+	var report ConflictReport
+	entriesByID := make(map[resource.ID]map[int]struct{})
+	localizedIDs := make(map[localizedID]struct{})
+	for index, entry := range manifest.entries {
+		report.EntryIDs = append(report.EntryIDs, entry.ID)
+		for _, localized := range entry.Resources {
+			for _, id := range localized.Viewer.IDs() {
+				if entriesByID[id] == nil {
+					entriesByID[id] = make(map[int]struct{})
+				}
+				entriesByID[id][index] = struct{}{}
+				localizedIDs[localizedID{lang: localized.Language, id: id}] = struct{}{}
+			}
+		}
+	}
+	for key := range localizedIDs {
+		if len(entriesByID[key.id]) < 2 {
+			continue
+		}
+		if conflict, isConflict := manifest.resourceConflict(key.lang, key.id); isConflict {
+			report.Resources = append(report.Resources, conflict)
+		}
+	}
+	sort.Slice(report.Resources, func(a, b int) bool {
+		conflictA := report.Resources[a]
+		conflictB := report.Resources[b]
+		if conflictA.ID != conflictB.ID {
+			return conflictA.ID < conflictB.ID
+		}
+		return conflictA.Language < conflictB.Language
+	})
+
+	var objectEntries []int
+	var textureEntries []int
+	for index, entry := range manifest.entries {
+		if len(entry.ObjectProperties) > 0 {
+			objectEntries = append(objectEntries, index)
+		}
+		if len(entry.TextureProperties) > 0 {
+			textureEntries = append(textureEntries, index)
+		}
+	}
+	if len(objectEntries) > 1 {
+		report.Properties = append(report.Properties,
+			PropertiesConflict{Table: ObjectPropertiesTable, Entries: objectEntries, Winner: objectEntries[len(objectEntries)-1]})
+	}
+	if len(textureEntries) > 1 {
+		report.Properties = append(report.Properties,
+			PropertiesConflict{Table: TexturePropertiesTable, Entries: textureEntries, Winner: textureEntries[len(textureEntries)-1]})
+	}
+	return report
+}
+
+func (manifest Manifest) resourceConflict(lang resource.Language, id resource.ID) (ResourceConflict, bool) {
+	sources := manifest.Sources(lang, id)
+	conflict := ResourceConflict{
+		ID:       id,
+		Language: lang,
+		List:     ResourceViewStrategy().IsCompoundList(id),
+	}
+	list := make(resource.List, len(sources))
+	for index, source := range sources {
+		list[index] = source.View
+		if (len(conflict.Entries) == 0) || (conflict.Entries[len(conflict.Entries)-1] != source.Index) {
+			conflict.Entries = append(conflict.Entries, source.Index)
+		}
+	}
+	if len(conflict.Entries) < 2 {
+		return ResourceConflict{}, false
+	}
+	conflict.Winner = sources[len(sources)-1].Index
+	if conflict.List {
+		providers := list.BlockProviders(true)
+		conflict.BlockEntries = make([]int, len(providers))
+		for index, provider := range providers {
+			conflict.BlockEntries[index] = -1
+			if provider >= 0 {
+				conflict.BlockEntries[index] = sources[provider].Index
+			}
+		}
+	}
+	return conflict, true
+}
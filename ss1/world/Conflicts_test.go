@@ -0,0 +1,89 @@
+package world_test
+
+import (
+	"testing"
+
+	"github.com/inkyblackness/hacked/ss1/content/object"
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/world"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func conflictEntry(id string, lang resource.Language, stored map[resource.ID][][]byte) *world.ManifestEntry {
+	var store resource.Store
+	for resID, data := range stored {
+		_ = store.Put(resID, resource.Resource{Blocks: resource.BlocksFrom(data)})
+	}
+	return &world.ManifestEntry{
+		ID:        id,
+		Resources: resource.LocalizedResourcesList{{ID: id + ".res", Language: lang, Viewer: store}},
+	}
+}
+
+func TestManifestConflictsListsOnlySharedResources(t *testing.T) {
+	manifest := world.NewManifest(func([]resource.ID, []resource.ID) {})
+	err := manifest.InsertEntry(0,
+		conflictEntry("base", resource.LangAny, map[resource.ID][][]byte{0x0800: {{0x01}}, 0x0801: {{0x02}}}),
+		conflictEntry("modA", resource.LangAny, map[resource.ID][][]byte{0x0800: {{0x11}}}),
+		conflictEntry("modB", resource.LangAny, map[resource.ID][][]byte{0x0800: {{0x21}}}))
+	require.Nil(t, err)
+
+	report := manifest.Conflicts()
+	assert.Equal(t, []string{"base", "modA", "modB"}, report.EntryIDs)
+	require.Equal(t, 1, len(report.Resources), "only one conflict expected")
+	conflict := report.Resources[0]
+	assert.Equal(t, resource.ID(0x0800), conflict.ID)
+	assert.Equal(t, []int{0, 1, 2}, conflict.Entries)
+	assert.Equal(t, 2, conflict.Winner)
+	assert.False(t, conflict.IsPartial(), "simple resources are not partial")
+}
+
+func TestManifestConflictsHighlightsPartialListOverrides(t *testing.T) {
+	manifest := world.NewManifest(func([]resource.ID, []resource.ID) {})
+	err := manifest.InsertEntry(0,
+		conflictEntry("base", resource.LangDefault, map[resource.ID][][]byte{0x0024: {{0x01}, {0x02}, {0x03}}}),
+		conflictEntry("mod", resource.LangDefault, map[resource.ID][][]byte{0x0024: {{}, {0x12}}}))
+	require.Nil(t, err)
+
+	report := manifest.Conflicts()
+	require.Equal(t, 1, len(report.Resources), "one conflict expected")
+	conflict := report.Resources[0]
+	assert.True(t, conflict.List, "list resource expected")
+	assert.Equal(t, []int{0, 1, 0}, conflict.BlockEntries)
+	assert.True(t, conflict.IsPartial(), "partial override expected")
+	assert.Equal(t, 1, report.PartialCount())
+	assert.Equal(t, "[0] base 2 block(s), [1] mod 1 block(s)", report.BlockDistribution(conflict))
+}
+
+func TestManifestConflictsListsReplacedPropertyTables(t *testing.T) {
+	manifest := world.NewManifest(func([]resource.ID, []resource.ID) {})
+	base := conflictEntry("base", resource.LangAny, nil)
+	base.ObjectProperties = object.StandardPropertiesTable()
+	other := conflictEntry("other", resource.LangAny, nil)
+	mod := conflictEntry("mod", resource.LangAny, nil)
+	mod.ObjectProperties = object.StandardPropertiesTable()
+	err := manifest.InsertEntry(0, base, other, mod)
+	require.Nil(t, err)
+
+	report := manifest.Conflicts()
+	require.Equal(t, 1, len(report.Properties), "one property conflict expected")
+	assert.Equal(t, world.PropertiesConflict{Table: world.ObjectPropertiesTable, Entries: []int{0, 2}, Winner: 2}, report.Properties[0])
+	assert.Contains(t, report.Text(), "Object properties: provided by [0] base, [2] mod; used from [2] mod")
+}
+
+func TestManifestConflictsNamesBlockProviderOfCompleteListOverrides(t *testing.T) {
+	manifest := world.NewManifest(func([]resource.ID, []resource.ID) {})
+	err := manifest.InsertEntry(0,
+		conflictEntry("base", resource.LangDefault, map[resource.ID][][]byte{0x0024: {{0x01}, {0x02}}}),
+		conflictEntry("mod", resource.LangDefault, map[resource.ID][][]byte{0x0024: {{}, {}}}))
+	require.Nil(t, err)
+
+	report := manifest.Conflicts()
+	require.Equal(t, 1, len(report.Resources), "one conflict expected")
+	conflict := report.Resources[0]
+	assert.False(t, conflict.IsPartial(), "all blocks provided by one entry")
+	assert.Equal(t, 1, conflict.Winner)
+	assert.Contains(t, report.Text(), "blocks from [0] base 2 block(s)")
+}
@@ -11,6 +11,7 @@ import (
 	"sync"
 
 	"github.com/inkyblackness/hacked/ss1/content/archive"
+	"github.com/inkyblackness/hacked/ss1/content/audio/xmi"
 	"github.com/inkyblackness/hacked/ss1/content/object"
 	"github.com/inkyblackness/hacked/ss1/content/texture"
 	"github.com/inkyblackness/hacked/ss1/resource"
@@ -19,7 +20,7 @@ import (
 	"github.com/inkyblackness/hacked/ss1/world/ids"
 )
 
-var fileAllowlist = ids.FilenameList{
+var fileAllowlist = append(ids.FilenameList{
 	ids.Archive,
 	ids.CybStrng,
 	ids.CitALog,
@@ -40,7 +41,7 @@ var fileAllowlist = ids.FilenameList{
 	ids.SvgaIntr,
 	ids.Texture,
 	ids.VidMail,
-}
+}, append(ids.LowResVideos(), ids.MusicThemes()...)...)
 
 type fileLoader struct {
 	resultMutex sync.Mutex
@@ -55,6 +56,8 @@ type FileLoadResult struct {
 	FailedFiles int
 	Savegames   map[FileLocation]resource.Viewer
 	Resources   map[FileLocation]resource.Viewer
+	// Salvaged contains the reports of damaged resource files, of which only some resources could be recovered.
+	Salvaged map[FileLocation]lgres.SalvageReport
 
 	ObjectProperties  object.PropertiesTable
 	TextureProperties texture.PropertiesList
@@ -67,6 +70,7 @@ func LoadFiles(allowZips bool, names []string) FileLoadResult {
 		result: FileLoadResult{
 			Resources: make(map[FileLocation]resource.Viewer),
 			Savegames: make(map[FileLocation]resource.Viewer),
+			Salvaged:  make(map[FileLocation]lgres.SalvageReport),
 		},
 	}
 	loader.loadAll(names)
@@ -160,15 +164,28 @@ func (loader *fileLoader) loadFile(name string, isOnlyStagedFile bool, file io.R
 		loader.markFailedFile()
 	}
 
-	reader, err := lgres.ReaderFrom(bytes.NewReader(fileData))
 	filename := filepath.Base(name)
+	if store, isTheme := musicThemeStore(filename, fileData); isTheme {
+		location := FileLocation{DirPath: filepath.Dir(name), Name: filename}
+		loader.modify(func() { loader.result.Resources[location] = store })
+		return
+	}
+
+	reader, salvageReport, err := readResources(fileData)
 	if (err == nil) && (isOnlyStagedFile || fileAllowlist.Matches(filename)) {
 		location := FileLocation{DirPath: filepath.Dir(name), Name: filename}
+		viewer := reader
+		if mapping := ids.StoredIDMapping(filename); mapping != nil {
+			viewer = resource.NewMappedViewer(reader, mapping)
+		}
 		loader.modify(func() {
+			if salvageReport != nil {
+				loader.result.Salvaged[location] = *salvageReport
+			}
 			if stateView, stateErr := reader.View(ids.GameState); (stateErr == nil) && archive.IsSavegame(stateView) {
 				loader.result.Savegames[location] = reader
 			} else {
-				loader.result.Resources[location] = reader
+				loader.result.Resources[location] = viewer
 			}
 		})
 	}
@@ -197,6 +214,40 @@ func (loader *fileLoader) loadFile(name string, isOnlyStagedFile bool, file io.R
 	}
 }
 
+// readResources reads the resource file of given data.
+// Should the file be damaged, as many resources as possible are salvaged and the returned report
+// describes what was recovered and what was lost. The report is nil for intact files.
+func readResources(data []byte) (resource.Viewer, *lgres.SalvageReport, error) {
+	reader, err := lgres.ReaderFrom(bytes.NewReader(data))
+	if err == nil {
+		return reader, nil, nil
+	}
+	store, report := lgres.Salvage(bytes.NewReader(data), int64(len(data)))
+	if len(report.Salvaged) == 0 {
+		return nil, nil, err
+	}
+	return store, &report, nil
+}
+
+// musicThemeStore wraps the data of a music theme file as a resource.
+// Such files are no resource files, yet they are handled as one to be part of a mod.
+func musicThemeStore(filename string, data []byte) (resource.Store, bool) {
+	var store resource.Store
+	if !xmi.IsXMI(data) {
+		return store, false
+	}
+	for index := 0; index < ids.MusicThemeCount; index++ {
+		if ids.MusicThemeFile(index).Matches(filename) {
+			_ = store.Put(ids.MusicThemesStart.Plus(index), resource.Resource{
+				Properties: resource.Properties{ContentType: resource.Music},
+				Blocks:     resource.BlocksFrom([][]byte{data}),
+			})
+			return store, true
+		}
+	}
+	return store, false
+}
+
 func (loader *fileLoader) markFailedFile() {
 	loader.modify(func() { loader.result.FailedFiles++ })
 }
@@ -0,0 +1,62 @@
+package world_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres"
+	"github.com/inkyblackness/hacked/ss1/serial"
+	"github.com/inkyblackness/hacked/ss1/world"
+)
+
+func TestLoadFilesSalvagesTruncatedResourceFile(t *testing.T) {
+	var store resource.Store
+	_ = store.Put(0x0100, resource.Resource{Blocks: resource.BlocksFrom([][]byte{{0x01, 0x02}})})
+	_ = store.Put(0x0200, resource.Resource{Blocks: resource.BlocksFrom([][]byte{{0x03, 0x04}})})
+	target := serial.NewByteStore()
+	require.Nil(t, lgres.Write(target, store))
+	// Cut off the directory entry of the last resource.
+	data := target.Data()[:len(target.Data())-10]
+
+	dir, err := ioutil.TempDir("", "hacked-loader")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	filename := filepath.Join(dir, "damaged.res")
+	require.Nil(t, ioutil.WriteFile(filename, data, 0600))
+
+	result := world.LoadFiles(false, []string{filename})
+
+	location := world.FileLocation{DirPath: dir, Name: "damaged.res"}
+	assert.Equal(t, 0, result.FailedFiles)
+	viewer, loaded := result.Resources[location]
+	require.True(t, loaded, "resources of damaged file expected")
+	assert.Equal(t, []resource.ID{0x0100}, viewer.IDs())
+	report, salvaged := result.Salvaged[location]
+	require.True(t, salvaged, "salvage report expected")
+	assert.Equal(t, []resource.ID{0x0100}, report.Salvaged)
+	assert.False(t, report.Intact(), "issues expected")
+}
+
+func TestLoadFilesDoesNotReportIntactResourceFile(t *testing.T) {
+	var store resource.Store
+	_ = store.Put(0x0100, resource.Resource{Blocks: resource.BlocksFrom([][]byte{{0x01, 0x02}})})
+	target := serial.NewByteStore()
+	require.Nil(t, lgres.Write(target, store))
+
+	dir, err := ioutil.TempDir("", "hacked-loader")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	filename := filepath.Join(dir, "intact.res")
+	require.Nil(t, ioutil.WriteFile(filename, target.Data(), 0600))
+
+	result := world.LoadFiles(false, []string{filename})
+
+	assert.Equal(t, 1, len(result.Resources))
+	assert.Empty(t, result.Salvaged)
+}
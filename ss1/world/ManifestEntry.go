@@ -4,6 +4,7 @@ import (
 	"github.com/inkyblackness/hacked/ss1/content/object"
 	"github.com/inkyblackness/hacked/ss1/content/texture"
 	"github.com/inkyblackness/hacked/ss1/resource"
+	"github.com/inkyblackness/hacked/ss1/resource/lgres"
 	"github.com/inkyblackness/hacked/ss1/world/ids"
 )
 
@@ -15,6 +16,9 @@ type ManifestEntry struct {
 
 	ObjectProperties  object.PropertiesTable
 	TextureProperties texture.PropertiesList
+
+	// Salvaged contains the reports of damaged files, of which only some resources could be recovered.
+	Salvaged map[FileLocation]lgres.SalvageReport
 }
 
 // NewManifestEntryFrom attempts to create a manifest in memory from the given set of files.
@@ -41,6 +45,9 @@ func NewManifestEntryFrom(names []string) (*ManifestEntry, error) {
 	}
 	entry.ObjectProperties = loaded.ObjectProperties
 	entry.TextureProperties = loaded.TextureProperties
+	if len(loaded.Salvaged) > 0 {
+		entry.Salvaged = loaded.Salvaged
+	}
 	return entry, nil
 }
 
@@ -25,8 +25,9 @@ type Mod struct {
 	resourcesChanged resource.ModificationCallback
 	resetCallback    ModResetCallback
 
-	lastChangeTime time.Time
-	changedFiles   map[string]struct{}
+	lastChangeTime   time.Time
+	changedFiles     map[string]struct{}
+	changedResources map[string]*resource.IDMarkerMap
 
 	data ModData
 }
@@ -37,9 +38,11 @@ func NewMod(resourcesChanged resource.ModificationCallback, resetCallback ModRes
 		resourcesChanged: resourcesChanged,
 		resetCallback:    resetCallback,
 		changedFiles:     make(map[string]struct{}),
+		changedResources: make(map[string]*resource.IDMarkerMap),
 	}
 	mod.worldManifest = NewManifest(mod.worldChanged)
 	mod.data.FileChangeCallback = mod.markFileChanged
+	mod.data.ResourceChangeCallback = mod.markResourceChanged
 
 	return mod
 }
@@ -64,6 +67,16 @@ func (mod Mod) ModifiedFilenames() []string {
 	return result
 }
 
+// ChangedResourceIDs returns the identifiers of all resources of given file that were changed since
+// the mod was last reset or saved. Resources of the file that are not listed are in the state they were loaded or saved.
+func (mod Mod) ChangedResourceIDs(filename string) []resource.ID {
+	marker, existing := mod.changedResources[filename]
+	if !existing {
+		return nil
+	}
+	return marker.ToList()
+}
+
 // AllAbsoluteFilenames returns the list of all filenames currently loaded in the mod.
 func (mod Mod) AllAbsoluteFilenames(reference string) []string {
 	var result []string
@@ -90,9 +103,10 @@ func (mod *Mod) ResetLastChangeTime() {
 	mod.lastChangeTime = time.Time{}
 }
 
-// MarkSave clears the list of modified filenames.
+// MarkSave clears the list of modified filenames and resources.
 func (mod *Mod) MarkSave() {
 	mod.changedFiles = make(map[string]struct{})
+	mod.changedResources = make(map[string]*resource.IDMarkerMap)
 	mod.lastChangeTime = time.Time{}
 }
 
@@ -290,6 +304,7 @@ func (mod *Mod) Reset(newResources []*LocalizedResources, objectProperties objec
 	mod.data.ObjectProperties = objectProperties
 	mod.data.TextureProperties = textureProperties
 	mod.changedFiles = make(map[string]struct{})
+	mod.changedResources = make(map[string]*resource.IDMarkerMap)
 	mod.lastChangeTime = time.Time{}
 	mod.resetCallback()
 	mod.resourcesChanged(modifiedIDs.ToList(), nil)
@@ -300,6 +315,15 @@ func (mod *Mod) markFileChanged(filename string) {
 	mod.lastChangeTime = time.Now()
 }
 
+func (mod *Mod) markResourceChanged(filename string, id resource.ID) {
+	marker, existing := mod.changedResources[filename]
+	if !existing {
+		marker = new(resource.IDMarkerMap)
+		mod.changedResources[filename] = marker
+	}
+	marker.Add(id)
+}
+
 // FixListResources ensures all resources that contain resource lists to
 // have maximum size. This is done to ensure compatibility with layered modding in the
 // Source Port branch of engines.
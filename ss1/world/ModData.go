@@ -27,7 +27,8 @@ type LocalizedResources struct {
 
 // ModData contains the core information about a mod.
 type ModData struct {
-	FileChangeCallback func(string)
+	FileChangeCallback     func(string)
+	ResourceChangeCallback func(string, resource.ID)
 
 	LocalizedResources []*LocalizedResources
 	ObjectProperties   object.PropertiesTable
@@ -44,7 +45,7 @@ type ModData struct {
 func (data *ModData) SetResourceBlock(lang resource.Language, id resource.ID, index int, blockData []byte) {
 	loc, res := data.ensureResource(lang, id)
 	res.SetBlock(index, blockData)
-	data.notifyFileChanged(loc.File.Name)
+	data.notifyResourceChanged(loc.File.Name, id)
 }
 
 // PatchResourceBlock modifies an existing block.
@@ -55,7 +56,7 @@ func (data *ModData) PatchResourceBlock(lang resource.Language, id resource.ID,
 	raw, err := res.BlockRaw(index)
 	if (err == nil) && (len(raw) == expectedLength) {
 		_ = rle.Decompress(bytes.NewReader(patch), raw)
-		data.notifyFileChanged(loc.File.Name)
+		data.notifyResourceChanged(loc.File.Name, id)
 	}
 }
 
@@ -64,14 +65,14 @@ func (data *ModData) PatchResourceBlock(lang resource.Language, id resource.ID,
 func (data *ModData) SetResourceBlocks(lang resource.Language, id resource.ID, blocks [][]byte) {
 	loc, res := data.ensureResource(lang, id)
 	res.Set(blocks)
-	data.notifyFileChanged(loc.File.Name)
+	data.notifyResourceChanged(loc.File.Name, id)
 }
 
 // DelResource removes a resource from the mod in the given language.
 func (data *ModData) DelResource(lang resource.Language, id resource.ID) {
 	for _, loc := range data.LocalizedResources {
 		if (loc.Language == lang) && loc.Store.Del(id) {
-			data.notifyFileChanged(loc.File.Name)
+			data.notifyResourceChanged(loc.File.Name, id)
 		}
 	}
 }
@@ -147,6 +148,13 @@ func (data *ModData) ensureStore(lang resource.Language, filename string) *Local
 	return loc
 }
 
+func (data ModData) notifyResourceChanged(filename string, id resource.ID) {
+	if data.ResourceChangeCallback != nil {
+		data.ResourceChangeCallback(filename, id)
+	}
+	data.notifyFileChanged(filename)
+}
+
 func (data ModData) notifyFileChanged(filename string) {
 	if data.FileChangeCallback != nil {
 		data.FileChangeCallback(filename)
@@ -146,6 +146,66 @@ func (suite *ModSuite) TestModifiedBlocksCanBeRetrievedBulk() {
 	assert.Equal(suite.T(), [][]byte{{0xBB}, {0xCC}}, suite.mod.ModifiedBlocks(resource.LangAny, 0x0800))
 }
 
+func (suite *ModSuite) TestChangedResourceIDsAreTrackedPerFileUntilSave() {
+	suite.whenModifyingBy(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, 0x0800, 0, []byte{0xBB})
+		modder.SetResourceBlocks(resource.LangAny, 0x0801, [][]byte{{0xCC}})
+	})
+
+	localized := suite.mod.ModifiedResources()
+	require.Equal(suite.T(), 1, len(localized), "One file expected")
+	filename := localized[0].File.Name
+	assert.Equal(suite.T(), []resource.ID{0x0800, 0x0801}, suite.mod.ChangedResourceIDs(filename))
+	assert.Empty(suite.T(), suite.mod.ChangedResourceIDs("other.res"), "no changes expected in other file")
+
+	suite.mod.MarkSave()
+	assert.Empty(suite.T(), suite.mod.ChangedResourceIDs(filename), "no changes expected after save")
+}
+
+func (suite *ModSuite) TestProvenanceOfSimpleResourceIsLastLayer() {
+	suite.givenWorldHas(
+		suite.someLocalizedResources(resource.LangAny,
+			suite.storing(0x0800, [][]byte{{0xAA}, {0xAB}})))
+	suite.givenModifiedBy(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, 0x0800, 0, []byte{0xBB})
+	})
+
+	provenance := suite.mod.Provenance(resource.LangAny, 0x0800)
+	require.Equal(suite.T(), 2, len(provenance.Sources), "two sources expected")
+	assert.Equal(suite.T(), 0, provenance.Sources[0].Index)
+	assert.Equal(suite.T(), "entry-0", provenance.Sources[0].EntryID)
+	assert.Equal(suite.T(), world.ModLayer, provenance.Sources[1].Index)
+	assert.Equal(suite.T(), []int{1}, provenance.BlockSources, "mod resource hides all blocks")
+}
+
+func (suite *ModSuite) TestProvenanceOfListResourceIsPerBlock() {
+	suite.givenWorldHas(
+		suite.someLocalizedResources(resource.LangDefault,
+			suite.storing(0x0024, [][]byte{{0xAA}, {0xAB}, {0xAC}})))
+	suite.givenModifiedBy(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangDefault, 0x0024, 1, []byte{0xBB})
+	})
+
+	provenance := suite.mod.Provenance(resource.LangDefault, 0x0024)
+	require.Equal(suite.T(), 2, len(provenance.Sources), "two sources expected")
+	assert.Equal(suite.T(), []int{0, 1, 0}, provenance.BlockSources)
+}
+
+func (suite *ModSuite) TestResourceFilesListAllLayers() {
+	suite.givenWorldHas(
+		suite.someLocalizedResources(resource.LangAny,
+			suite.storing(0x0800, [][]byte{{0xAA}})))
+	suite.givenModifiedBy(func(modder world.Modder) {
+		modder.SetResourceBlock(resource.LangAny, 0x0801, 0, []byte{0xBB})
+	})
+
+	files := suite.mod.ResourceFiles()
+	require.Equal(suite.T(), 2, len(files), "two files expected")
+	assert.Equal(suite.T(), []resource.ID{0x0800}, files[0].IDs)
+	assert.Equal(suite.T(), world.ModLayer, files[1].Index)
+	assert.Equal(suite.T(), []resource.ID{0x0801}, files[1].IDs)
+}
+
 func (suite *ModSuite) givenWorldHas(res ...resource.LocalizedResources) {
 	suite.whenWorldIsExtendedWith(res...)
 	suite.lastModifiedIDs = nil
@@ -0,0 +1,142 @@
+package world
+
+import (
+	"fmt"
+
+	"github.com/inkyblackness/hacked/ss1/resource"
+)
+
+// ModLayer is the layer index of resources provided by the mod itself.
+const ModLayer = -1
+
+// ResourceLayer identifies a file of resources within the layers of a mod.
+// Layers are the entries of the world manifest, in order, with the mod on top.
+type ResourceLayer struct {
+	// Index is the index of the manifest entry, or ModLayer for the mod.
+	Index int
+	// EntryID is the identifier of the manifest entry. It is empty for the mod.
+	EntryID string
+	// Filename is the name of the file within the layer.
+	Filename string
+	// Language is the language of the file.
+	Language resource.Language
+}
+
+// String returns a short description of the layer.
+func (layer ResourceLayer) String() string {
+	if layer.Index == ModLayer {
+		return "mod: " + layer.Filename
+	}
+	return fmt.Sprintf("[%d] %s: %s", layer.Index, layer.EntryID, layer.Filename)
+}
+
+// ResourceSource is a resource as provided by one layer.
+type ResourceSource struct {
+	ResourceLayer
+	View resource.View
+}
+
+// ResourceFile lists the resources contained in a file of one layer.
+type ResourceFile struct {
+	ResourceLayer
+	IDs []resource.ID
+}
+
+// ResourceProvenance describes which layers provide a resource, and its blocks.
+type ResourceProvenance struct {
+	// Sources are all the layers that have the resource, in the order they are merged.
+	Sources []ResourceSource
+	// BlockSources contains for each block the index into Sources that provides the data.
+	// The index is -1 for blocks that are empty.
+	BlockSources []int
+}
+
+// Sources returns the resources of all entries that match the given parameters, in the same order as Filter.
+func (manifest Manifest) Sources(lang resource.Language, id resource.ID) []ResourceSource {
+	var sources []ResourceSource
+	for index, entry := range manifest.entries {
+		for _, localized := range entry.Resources {
+			if !localized.Language.Includes(lang) {
+				continue
+			}
+			if view, err := localized.Viewer.View(id); err == nil {
+				sources = append(sources, ResourceSource{
+					ResourceLayer: ResourceLayer{Index: index, EntryID: entry.ID, Filename: localized.ID, Language: localized.Language},
+					View:          view,
+				})
+			}
+		}
+	}
+	return sources
+}
+
+// ResourceFiles returns the files of all entries.
+func (manifest Manifest) ResourceFiles() []ResourceFile {
+	var files []ResourceFile
+	for index, entry := range manifest.entries {
+		for _, localized := range entry.Resources {
+			files = append(files, ResourceFile{
+				ResourceLayer: ResourceLayer{Index: index, EntryID: entry.ID, Filename: localized.ID, Language: localized.Language},
+				IDs:           localized.Viewer.IDs(),
+			})
+		}
+	}
+	return files
+}
+
+// Sources returns the resources of all layers that match the given parameters, in the same order as Filter.
+func (mod Mod) Sources(lang resource.Language, id resource.ID) []ResourceSource {
+	sources := mod.worldManifest.Sources(lang, id)
+	if source, found := mod.modifiedSource(resource.LangAny, id); found {
+		sources = append(sources, source)
+	}
+	for _, worldLang := range resource.Languages() {
+		if worldLang.Includes(lang) {
+			if source, found := mod.modifiedSource(lang, id); found {
+				sources = append(sources, source)
+			}
+		}
+	}
+	return sources
+}
+
+func (mod Mod) modifiedSource(lang resource.Language, id resource.ID) (ResourceSource, bool) {
+	for _, entry := range mod.data.LocalizedResources {
+		if entry.Language == lang {
+			res, err := entry.Store.Resource(id)
+			if err == nil {
+				return ResourceSource{
+					ResourceLayer: ResourceLayer{Index: ModLayer, Filename: entry.File.Name, Language: entry.Language},
+					View:          res,
+				}, true
+			}
+		}
+	}
+	return ResourceSource{}, false
+}
+
+// ResourceFiles returns the files of all layers, including those of the mod.
+func (mod Mod) ResourceFiles() []ResourceFile {
+	files := mod.worldManifest.ResourceFiles()
+	for _, entry := range mod.data.LocalizedResources {
+		files = append(files, ResourceFile{
+			ResourceLayer: ResourceLayer{Index: ModLayer, Filename: entry.File.Name, Language: entry.Language},
+			IDs:           entry.Store.IDs(),
+		})
+	}
+	return files
+}
+
+// Provenance returns which layers provide the identified resource, and its blocks, as it is seen
+// through LocalizedResources().
+func (mod Mod) Provenance(lang resource.Language, id resource.ID) ResourceProvenance {
+	sources := mod.Sources(lang, id)
+	list := make(resource.List, len(sources))
+	for index, source := range sources {
+		list[index] = source.View
+	}
+	return ResourceProvenance{
+		Sources:      sources,
+		BlockSources: list.BlockProviders(ResourceViewStrategy().IsCompoundList(id)),
+	}
+}
@@ -1,6 +1,7 @@
 package ids
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/inkyblackness/hacked/ss1/resource"
@@ -96,6 +97,23 @@ var Splash = AnyLanguage("splash.res")
 // SplshPal contains the splash screen palettes.
 var SplshPal = AnyLanguage("splspal.res")
 
+// MusicThemeCount is the number of supported music theme files.
+const MusicThemeCount = 16
+
+// MusicThemeFile returns the filename descriptor of the identified music theme.
+func MusicThemeFile(index int) Filename {
+	return AnyLanguage(fmt.Sprintf("thm%d.xmi", index))
+}
+
+// MusicThemes returns the filename descriptors of all music themes.
+func MusicThemes() FilenameList {
+	list := make(FilenameList, MusicThemeCount)
+	for index := range list {
+		list[index] = MusicThemeFile(index)
+	}
+	return list
+}
+
 // LowResVideos returns the filename descriptors of all low-res videos.
 func LowResVideos() FilenameList {
 	return []Filename{LowIntr, LowDeth, LowEnd, Intro, Start1, Win1}
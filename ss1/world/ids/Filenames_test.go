@@ -49,6 +49,7 @@ func TestLocalizedResources(t *testing.T) {
 		{"svgaend.res", resource.LangAny},
 		{"vidmail.res", resource.LangAny},
 		{"win1.res", resource.LangAny},
+		{"thm0.xmi", resource.LangAny},
 
 		{"citalog.res", resource.LangDefault},
 		{"citbark.res", resource.LangDefault},
@@ -71,3 +72,11 @@ func TestLocalizedResources(t *testing.T) {
 		assert.Equal(t, tc.expected, result, "Wrong language for <"+tc.filename+">")
 	}
 }
+
+func TestMusicThemes(t *testing.T) {
+	themes := ids.MusicThemes()
+	assert.True(t, themes.Matches("thm0.xmi"))
+	assert.True(t, themes.Matches("THM15.XMI"))
+	assert.False(t, themes.Matches("thm16.xmi"))
+	assert.Equal(t, "thm3.xmi", ids.MusicThemeFile(3).For(resource.LangAny))
+}
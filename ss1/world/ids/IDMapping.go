@@ -0,0 +1,29 @@
+package ids
+
+import "github.com/inkyblackness/hacked/ss1/resource"
+
+// IDMapping maps resource identifier to other identifier.
+type IDMapping map[resource.ID]resource.ID
+
+// Reversed returns the mapping for the opposite direction.
+func (mapping IDMapping) Reversed() IDMapping {
+	reversed := make(IDMapping, len(mapping))
+	for from, to := range mapping {
+		reversed[to] = from
+	}
+	return reversed
+}
+
+// StoredIDMapping returns the mapping from the identifier, as they are stored in the given file,
+// to the identifier that are used while the resources are loaded.
+// The result is nil for files that need no mapping.
+func StoredIDMapping(filename string) IDMapping {
+	if LowResVideos().Matches(filename) {
+		return IDMapping{
+			MovieIntro: LowResMovieIntro,
+			MovieDeath: LowResMovieDeath,
+			MovieEnd:   LowResMovieEnd,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package ids_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/inkyblackness/hacked/ss1/world/ids"
+)
+
+func TestStoredIDMappingOfLowResVideosUsesSeparateIdentifier(t *testing.T) {
+	mapping := ids.StoredIDMapping("LOWINTR.RES")
+	assert.Equal(t, ids.LowResMovieIntro, mapping[ids.MovieIntro])
+	assert.Equal(t, ids.MovieIntro, mapping.Reversed()[ids.LowResMovieIntro])
+}
+
+func TestStoredIDMappingOfOtherFilesIsEmpty(t *testing.T) {
+	assert.Empty(t, ids.StoredIDMapping("svgaintr.res"))
+	assert.Empty(t, ids.StoredIDMapping("citalog.res"))
+}
@@ -45,6 +45,15 @@ const (
 	MovieEnd   resource.ID = 0x0BD8
 )
 
+// Low-resolution movie identifier are listed below.
+// The low-res movie files store their movies under the same identifier as the high-res files.
+// To keep them apart, these identifier are used for them while loaded. See StoredIDMapping().
+const (
+	LowResMovieIntro resource.ID = 0x8BD6
+	LowResMovieDeath resource.ID = 0x8BD7
+	LowResMovieEnd   resource.ID = 0x8BD8
+)
+
 // Text identifier are listed below.
 const (
 	PaperTextsStart      resource.ID = 0x003C
@@ -79,6 +88,13 @@ const (
 	SoundEffectsAudioStart resource.ID = 0x00C9
 )
 
+// Music identifier are listed below.
+// The music themes are stored in XMI files, not in resource files. While loaded, each theme file
+// is handled as one resource with these identifier. See MusicThemeFile().
+const (
+	MusicThemesStart resource.ID = 0x9000
+)
+
 // Archive identifier are listed below.
 const (
 	ArchiveName resource.ID = 0x0FA0
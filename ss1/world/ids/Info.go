@@ -46,6 +46,17 @@ func init() {
 	for _, info := range infoList {
 		register(info)
 	}
+	for index := 0; index < MusicThemeCount; index++ {
+		register(ResourceInfo{
+			StartID: MusicThemesStart.Plus(index),
+			EndID:   MusicThemesStart.Plus(index + 1),
+
+			ContentType: resource.Music,
+			MaxCount:    1,
+
+			ResFile: MusicThemeFile(index),
+		})
+	}
 	levelInfo := func(lvl int, lvlResID int, compressed bool) ResourceInfo {
 		resID := LevelResourcesStart.Plus(lvl*lvlids.PerLevel + lvlResID)
 		return ResourceInfo{
@@ -130,6 +141,9 @@ var infoList = []ResourceInfo{
 	{MovieIntro, MovieIntro.Plus(1), resource.Movie, false, false, false, 1, SvgaIntr},
 	{MovieDeath, MovieDeath.Plus(1), resource.Movie, false, false, false, 1, SvgaDeth},
 	{MovieEnd, MovieEnd.Plus(1), resource.Movie, false, false, false, 1, SvgaEnd},
+	{LowResMovieIntro, LowResMovieIntro.Plus(1), resource.Movie, false, false, false, 1, LowIntr},
+	{LowResMovieDeath, LowResMovieDeath.Plus(1), resource.Movie, false, false, false, 1, LowDeth},
+	{LowResMovieEnd, LowResMovieEnd.Plus(1), resource.Movie, false, false, false, 1, LowEnd},
 
 	{PaperTextsStart, PaperTextsStart.Plus(16), resource.Text, true, false, false, 16, CybStrng},
 